@@ -0,0 +1,317 @@
+package apitests_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	testhelpers "github.com/gsaaraujo/pay-bank-api/internal/test_helpers"
+	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
+	"github.com/gsaaraujo/pay-bank-api/internal/utils"
+	"github.com/stretchr/testify/suite"
+)
+
+type AsyncTransferSuite struct {
+	suite.Suite
+	customerDAO        daos.CustomerDAO
+	accountDAO         daos.AccountDAO
+	ledgerDAO          daos.LedgerDAO
+	transferDAO        daos.TransferDAO
+	outboxEventDAO     daos.OutboxEventDAO
+	webhookDeliveryDAO daos.WebhookDeliveryDAO
+	testEnvironment    *testhelpers.TestEnvironment
+}
+
+func (a *AsyncTransferSuite) SetupSuite() {
+	a.testEnvironment = testhelpers.NewTestEnvironment()
+	a.testEnvironment.Start()
+	a.customerDAO = daos.NewCustomerDAO(a.testEnvironment.PgxPool())
+	a.accountDAO = daos.NewAccountDAO(a.testEnvironment.PgxPool())
+	a.ledgerDAO = daos.NewLedgerDAO(a.testEnvironment.PgxPool())
+	a.transferDAO = daos.NewTransferDAO(a.testEnvironment.PgxPool())
+	a.outboxEventDAO = daos.NewOutboxEventDAO(a.testEnvironment.PgxPool())
+	a.webhookDeliveryDAO = daos.NewWebhookDeliveryDAO(a.testEnvironment.PgxPool())
+}
+
+func (a *AsyncTransferSuite) SetupTest() {
+	utils.ThrowOnError(a.customerDAO.DeleteAll(context.Background()))
+	utils.ThrowOnError(a.outboxEventDAO.DeleteAll(context.Background()))
+
+	a.createCustomer(uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"), uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"),
+		"john.doe@gmail.com", 12500)
+	a.createCustomer(uuid.MustParse("a06f5c45-f824-4cb1-a666-805035ae2ae1"), uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d"),
+		"richard.smith@gmail.com", 3200)
+}
+
+func (a *AsyncTransferSuite) createCustomer(customerId uuid.UUID, accountId uuid.UUID, email string, balance int64) {
+	utils.ThrowOnError(a.customerDAO.Create(context.Background(), daos.CustomerSchema{
+		Id:        customerId,
+		Name:      "John Doe",
+		Email:     email,
+		Password:  "$2a$10$asLIHej6kxd3Fsdc76QHieBugwCGvsYJeLiZmP1K7/t1GbIbUy.pK",
+		Status:    daos.CustomerStatusActive,
+		UpdatedAt: time.Now().UTC(),
+		CreatedAt: time.Now().UTC(),
+	}))
+	utils.ThrowOnError(a.accountDAO.Create(context.Background(), daos.AccountSchema{
+		Id:         accountId,
+		CustomerId: customerId,
+		Balance:    balance,
+		UpdatedAt:  time.Now().UTC(),
+		CreatedAt:  time.Now().UTC(),
+	}))
+}
+
+func (a *AsyncTransferSuite) transfer(amount int64, idempotencyKey string, respondAsync bool) (*http.Response, string) {
+	request := utils.GetOrThrow(http.NewRequest("POST", a.testEnvironment.BaseUrl()+"/v1/transfer", strings.NewReader(fmt.Sprintf(`
+		{
+			"customerReceiverId": "a06f5c45-f824-4cb1-a666-805035ae2ae1",
+			"amount": %d
+		}
+	`, amount))))
+	accessToken := testhelpers.TestGenerateAccessToken(uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"))
+	request.Header.Add("Content-Type", "application/json")
+	request.Header.Add("Authorization", "Bearer "+accessToken)
+	request.Header.Add("Idempotency-Key", idempotencyKey)
+
+	if respondAsync {
+		request.Header.Add("Prefer", "respond-async")
+	}
+
+	response := utils.GetOrThrow(a.testEnvironment.Client().Do(request))
+	body := utils.GetOrThrow(io.ReadAll(response.Body))
+
+	return response, string(body)
+}
+
+func (a *AsyncTransferSuite) getTransfer(customerId uuid.UUID, transferId string) (*http.Response, string) {
+	request := utils.GetOrThrow(http.NewRequest("GET", a.testEnvironment.BaseUrl()+"/v1/transfers/"+transferId, nil))
+	request.Header.Add("Authorization", "Bearer "+testhelpers.TestGenerateAccessToken(customerId))
+
+	response := utils.GetOrThrow(a.testEnvironment.Client().Do(request))
+	body := utils.GetOrThrow(io.ReadAll(response.Body))
+
+	return response, string(body)
+}
+
+func (a *AsyncTransferSuite) acceptedTransferId(body string) uuid.UUID {
+	var accepted struct {
+		Data struct {
+			Id     uuid.UUID `json:"id"`
+			Status string    `json:"status"`
+		} `json:"data"`
+	}
+	utils.ThrowOnError(json.Unmarshal([]byte(body), &accepted))
+	a.Require().Equal("pending", accepted.Data.Status)
+
+	return accepted.Data.Id
+}
+
+func (a *AsyncTransferSuite) process(transferId uuid.UUID) usecases.ProcessTransferUsecaseOutput {
+	processTransferUsecase := usecases.NewProcessTransferUsecase(a.testEnvironment.PgxPool(), a.accountDAO, a.ledgerDAO, a.transferDAO,
+		a.outboxEventDAO, a.webhookDeliveryDAO)
+
+	return utils.GetOrThrow(processTransferUsecase.Execute(context.Background(), usecases.ProcessTransferUsecaseInput{
+		TransferId: transferId,
+	}))
+}
+
+func (a *AsyncTransferSuite) Test1() {
+	a.Run("given prefer respond-async, when transferring, then returns 202 and the transfer completes once processed", func() {
+		response, body := a.transfer(2500, "2108b394-b875-40cf-9ee6-1d8bd6fb1ec5", true)
+		a.Require().Equal(202, response.StatusCode)
+		a.Equal("respond-async", response.Header.Get("Preference-Applied"))
+
+		transferId := a.acceptedTransferId(body)
+		a.Equal("/v1/transfers/"+transferId.String(), response.Header.Get("Location"))
+
+		response, body = a.getTransfer(uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"), transferId.String())
+		a.Require().Equal(200, response.StatusCode)
+		a.Contains(body, `"status":"pending"`)
+
+		accountSender := utils.GetOrThrow(a.accountDAO.FindOneById(context.Background(), uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d")))
+		a.Require().Equal(int64(12500), accountSender.Balance)
+
+		outboxEventsSchema := utils.GetOrThrow(a.outboxEventDAO.FindAll(context.Background()))
+		a.Require().Len(outboxEventsSchema, 1)
+		a.Equal(usecases.EventTypeTransferRequested, outboxEventsSchema[0].EventType)
+
+		a.Equal(daos.TransferStatusCompleted, a.process(transferId).Status)
+		a.Equal(daos.TransferStatusCompleted, a.process(transferId).Status)
+
+		accountSender = utils.GetOrThrow(a.accountDAO.FindOneById(context.Background(), uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d")))
+		a.Require().Equal(int64(10000), accountSender.Balance)
+
+		accountReceiver := utils.GetOrThrow(a.accountDAO.FindOneById(context.Background(), uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d")))
+		a.Require().Equal(int64(5700), accountReceiver.Balance)
+
+		transferSchema := utils.GetOrThrow(a.transferDAO.FindOneById(context.Background(), transferId))
+
+		response, body = a.getTransfer(uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"), transferId.String())
+		a.Require().Equal(200, response.StatusCode)
+		a.JSONEq(fmt.Sprintf(`
+			{
+				"data": {
+					"id": "%s",
+					"customerReceiverId": "a06f5c45-f824-4cb1-a666-805035ae2ae1",
+					"amount": 2500,
+					"status": "completed",
+					"transactionId": "%s",
+					"failure": null,
+					"createdAt": "%s",
+					"updatedAt": "%s"
+				}
+			}
+		`, transferId, transferId, transferSchema.CreatedAt.UTC().Format(time.RFC3339), transferSchema.UpdatedAt.UTC().Format(time.RFC3339)), body)
+	})
+}
+
+func (a *AsyncTransferSuite) Test2() {
+	a.Run("given an insufficient balance, when the transfer is processed, then it fails with the reason and no money moves", func() {
+		response, body := a.transfer(20000, "2108b394-b875-40cf-9ee6-1d8bd6fb1ec5", true)
+		a.Require().Equal(202, response.StatusCode)
+
+		transferId := a.acceptedTransferId(body)
+		a.Equal(daos.TransferStatusFailed, a.process(transferId).Status)
+
+		response, body = a.getTransfer(uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"), transferId.String())
+		a.Require().Equal(200, response.StatusCode)
+		a.Contains(body, `"status":"failed"`)
+		a.Contains(body, `"failure":{"code":"insufficient_balance"`)
+
+		accountSender := utils.GetOrThrow(a.accountDAO.FindOneById(context.Background(), uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d")))
+		a.Require().Equal(int64(12500), accountSender.Balance)
+
+		accountReceiver := utils.GetOrThrow(a.accountDAO.FindOneById(context.Background(), uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d")))
+		a.Require().Equal(int64(3200), accountReceiver.Balance)
+	})
+}
+
+func (a *AsyncTransferSuite) Test3() {
+	a.Run("when retrying an accepted transfer with the same idempotency key, even synchronously, then returns the same transfer", func() {
+		response, firstBody := a.transfer(2500, "2108b394-b875-40cf-9ee6-1d8bd6fb1ec5", true)
+		a.Require().Equal(202, response.StatusCode)
+
+		response, secondBody := a.transfer(2500, "2108b394-b875-40cf-9ee6-1d8bd6fb1ec5", true)
+		a.Require().Equal(202, response.StatusCode)
+		a.JSONEq(firstBody, secondBody)
+
+		response, thirdBody := a.transfer(2500, "2108b394-b875-40cf-9ee6-1d8bd6fb1ec5", false)
+		a.Require().Equal(202, response.StatusCode)
+		a.Equal("respond-async", response.Header.Get("Preference-Applied"))
+		a.JSONEq(firstBody, thirdBody)
+
+		outboxEventsSchema := utils.GetOrThrow(a.outboxEventDAO.FindAll(context.Background()))
+		a.Require().Len(outboxEventsSchema, 1)
+	})
+}
+
+func (a *AsyncTransferSuite) Test4() {
+	a.Run("given a synchronous transfer, when getting it, then it is completed", func() {
+		response, _ := a.transfer(2500, "2108b394-b875-40cf-9ee6-1d8bd6fb1ec5", false)
+		a.Require().Equal(201, response.StatusCode)
+
+		var transferId uuid.UUID
+		utils.ThrowOnError(a.testEnvironment.PgxPool().QueryRow(context.Background(), "SELECT id FROM transfers").Scan(&transferId))
+
+		response, body := a.getTransfer(uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"), transferId.String())
+		a.Require().Equal(200, response.StatusCode)
+		a.Contains(body, `"status":"completed"`)
+	})
+}
+
+func (a *AsyncTransferSuite) Test5() {
+	a.Run("when getting a transfer sent by another customer, then returns 404", func() {
+		response, body := a.transfer(2500, "2108b394-b875-40cf-9ee6-1d8bd6fb1ec5", true)
+		a.Require().Equal(202, response.StatusCode)
+
+		transferId := a.acceptedTransferId(body)
+
+		response, body = a.getTransfer(uuid.MustParse("a06f5c45-f824-4cb1-a666-805035ae2ae1"), transferId.String())
+		a.Equal(404, response.StatusCode)
+		a.JSONEq(fmt.Sprintf(`
+			{
+				"type": "/problems/transfer_not_found",
+				"title": "Not Found",
+				"status": 404,
+				"detail": "transfer not found",
+				"instance": "%s",
+				"code": "transfer_not_found"
+			}
+		`, response.Header.Get("X-Request-Id")), body)
+	})
+}
+
+func (a *AsyncTransferSuite) requeue() usecases.RequeuePendingTransfersUsecaseOutput {
+	requeuePendingTransfersUsecase := usecases.NewRequeuePendingTransfersUsecase(a.testEnvironment.PgxPool(), a.transferDAO, a.outboxEventDAO)
+
+	return utils.GetOrThrow(requeuePendingTransfersUsecase.Execute(context.Background(), usecases.RequeuePendingTransfersUsecaseInput{
+		PendingFor: 5 * time.Minute,
+		BatchSize:  100,
+	}))
+}
+
+func (a *AsyncTransferSuite) Test6() {
+	a.Run("given a transfer pending for too long, when requeuing, then writes a new transfer requested event once", func() {
+		response, body := a.transfer(2500, "2108b394-b875-40cf-9ee6-1d8bd6fb1ec5", true)
+		a.Require().Equal(202, response.StatusCode)
+
+		transferId := a.acceptedTransferId(body)
+		a.Equal(0, a.requeue().Requeued)
+
+		_ = utils.GetOrThrow(a.testEnvironment.PgxPool().Exec(context.Background(),
+			"UPDATE transfers SET updated_at = updated_at - INTERVAL '10 minutes' WHERE id = $1", transferId))
+
+		a.Equal(1, a.requeue().Requeued)
+		a.Equal(0, a.requeue().Requeued)
+
+		outboxEventsSchema := utils.GetOrThrow(a.outboxEventDAO.FindAll(context.Background()))
+		a.Require().Len(outboxEventsSchema, 2)
+		a.Equal(usecases.EventTypeTransferRequested, outboxEventsSchema[1].EventType)
+		a.Equal(transferId, outboxEventsSchema[1].AggregateId)
+		a.NotEqual(outboxEventsSchema[0].Id, outboxEventsSchema[1].Id)
+
+		var transferRequestedEvent usecases.TransferRequestedEvent
+		a.Require().NoError(json.Unmarshal(outboxEventsSchema[1].Payload, &transferRequestedEvent))
+		a.Equal(transferId, transferRequestedEvent.TransferId)
+		a.Equal(int64(2500), transferRequestedEvent.Amount)
+
+		a.Equal(daos.TransferStatusCompleted, a.process(transferId).Status)
+
+		_ = utils.GetOrThrow(a.testEnvironment.PgxPool().Exec(context.Background(),
+			"UPDATE transfers SET updated_at = updated_at - INTERVAL '10 minutes' WHERE id = $1", transferId))
+		a.Equal(0, a.requeue().Requeued)
+	})
+}
+
+func (a *AsyncTransferSuite) Test7() {
+	a.Run("given a transfer that kept failing to be processed, when giving up on it, then it fails with the reason and no money moves", func() {
+		response, body := a.transfer(2500, "2108b394-b875-40cf-9ee6-1d8bd6fb1ec5", true)
+		a.Require().Equal(202, response.StatusCode)
+
+		transferId := a.acceptedTransferId(body)
+		failTransferUsecase := usecases.NewFailTransferUsecase(a.testEnvironment.PgxPool(), a.transferDAO)
+		a.Require().NoError(failTransferUsecase.Execute(context.Background(), usecases.FailTransferUsecaseInput{TransferId: transferId}))
+
+		response, body = a.getTransfer(uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"), transferId.String())
+		a.Require().Equal(200, response.StatusCode)
+		a.Contains(body, `"status":"failed"`)
+		a.Contains(body, `"failure":{"code":"transfer_processing_failed"`)
+
+		a.Equal(daos.TransferStatusFailed, a.process(transferId).Status)
+
+		accountSender := utils.GetOrThrow(a.accountDAO.FindOneById(context.Background(), uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d")))
+		a.Equal(int64(12500), accountSender.Balance)
+	})
+}
+
+func TestAsyncTransfer(t *testing.T) {
+	suite.Run(t, new(AsyncTransferSuite))
+}
@@ -0,0 +1,232 @@
+package apitests_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	testhelpers "github.com/gsaaraujo/pay-bank-api/internal/test_helpers"
+	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
+	"github.com/gsaaraujo/pay-bank-api/internal/utils"
+	"github.com/stretchr/testify/suite"
+)
+
+type CustomerRolesSuite struct {
+	suite.Suite
+	customerDAO     daos.CustomerDAO
+	customerRoleDAO daos.CustomerRoleDAO
+	testEnvironment *testhelpers.TestEnvironment
+}
+
+func (c *CustomerRolesSuite) SetupSuite() {
+	c.testEnvironment = testhelpers.NewTestEnvironment()
+	c.testEnvironment.Start()
+	c.customerDAO = daos.NewCustomerDAO(c.testEnvironment.PgxPool())
+	c.customerRoleDAO = daos.NewCustomerRoleDAO(c.testEnvironment.PgxPool())
+}
+
+func (c *CustomerRolesSuite) SetupTest() {
+	utils.ThrowOnError(c.customerDAO.DeleteAll(context.Background()))
+	utils.ThrowOnError(c.testEnvironment.RedisClient().FlushAll(context.Background()).Err())
+
+	for _, customerSchema := range []daos.CustomerSchema{
+		{
+			Id:       uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
+			Name:     "John Doe",
+			Email:    "john.doe@gmail.com",
+			Password: "$2a$10$asLIHej6kxd3Fsdc76QHieBugwCGvsYJeLiZmP1K7/t1GbIbUy.pK",
+			Status:   daos.CustomerStatusActive,
+		},
+		{
+			Id:       uuid.MustParse("9a4bd6e4-6f0c-4c55-a7a6-3a1f0a6a8c21"),
+			Name:     "Richard Smith",
+			Email:    "richard.smith@gmail.com",
+			Password: "$2a$10$asLIHej6kxd3Fsdc76QHieBugwCGvsYJeLiZmP1K7/t1GbIbUy.pK",
+			Status:   daos.CustomerStatusActive,
+		},
+	} {
+		customerSchema.CreatedAt = time.Now().UTC()
+		customerSchema.UpdatedAt = time.Now().UTC()
+		utils.ThrowOnError(c.customerDAO.Create(context.Background(), customerSchema))
+	}
+}
+
+func (c *CustomerRolesSuite) do(method string, path string, accessToken string) (*http.Response, string) {
+	request := utils.GetOrThrow(http.NewRequest(method, c.testEnvironment.BaseUrl()+path, nil))
+	request.Header.Add("Authorization", "Bearer "+accessToken)
+
+	response := utils.GetOrThrow(c.testEnvironment.Client().Do(request))
+	body := utils.GetOrThrow(io.ReadAll(response.Body))
+
+	return response, string(body)
+}
+
+func (c *CustomerRolesSuite) Test1() {
+	c.Run("given a customer token, when calling the admin routes, then returns 403", func() {
+		accessToken := testhelpers.TestGenerateAccessToken(uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"))
+
+		for _, route := range [][]string{
+			{"GET", "/v1/admin/customers/9a4bd6e4-6f0c-4c55-a7a6-3a1f0a6a8c21/roles"},
+			{"PUT", "/v1/admin/customers/f59207c8-e837-4159-b67d-78c716510747/roles/admin"},
+			{"DELETE", "/v1/admin/customers/9a4bd6e4-6f0c-4c55-a7a6-3a1f0a6a8c21/roles/admin"},
+			{"DELETE", "/v1/admin/customers/9a4bd6e4-6f0c-4c55-a7a6-3a1f0a6a8c21/sessions"},
+		} {
+			response, body := c.do(route[0], route[1], accessToken)
+
+			c.Equal(403, response.StatusCode)
+			c.JSONEq(fmt.Sprintf(`
+				{
+					"type": "about:blank",
+					"title": "Forbidden",
+					"status": 403,
+					"instance": "%s",
+					"code": "forbidden"
+				}
+			`, response.Header.Get("X-Request-Id")), body)
+		}
+
+		customerRolesSchema := utils.GetOrThrow(c.customerRoleDAO.FindAllByCustomerId(context.Background(),
+			uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747")))
+		c.Empty(customerRolesSchema)
+	})
+}
+
+func (c *CustomerRolesSuite) Test2() {
+	c.Run("given an admin token, when assigning a role, then returns 204 and the role goes in the next access token", func() {
+		accessToken := testhelpers.TestGenerateAccessTokenWithRoles(uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
+			usecases.RoleCustomer, usecases.RoleAdmin)
+
+		response, _ := c.do("PUT", "/v1/admin/customers/9a4bd6e4-6f0c-4c55-a7a6-3a1f0a6a8c21/roles/support", accessToken)
+		c.Equal(204, response.StatusCode)
+
+		response, body := c.do("GET", "/v1/admin/customers/9a4bd6e4-6f0c-4c55-a7a6-3a1f0a6a8c21/roles", accessToken)
+		c.Equal(200, response.StatusCode)
+		c.JSONEq(`{"data": {"roles": ["customer", "support"]}}`, body)
+
+		response = utils.GetOrThrow(c.testEnvironment.Client().Post(c.testEnvironment.BaseUrl()+"/v1/login", "application/json",
+			strings.NewReader(`{"email": "richard.smith@gmail.com", "password": "123456"}`)))
+		c.Require().Equal(200, response.StatusCode)
+
+		loginBody := utils.ParseJSONBody[map[string]map[string]any](response.Body)
+		token := utils.GetOrThrow(jwt.ParseWithClaims(loginBody["data"]["accessToken"].(string), &usecases.JwtAccessTokenClaims{},
+			func(token *jwt.Token) (any, error) {
+				return testhelpers.TestAccessTokenVerificationKey(), nil
+			}))
+		c.Equal([]string{"customer", "support"}, token.Claims.(*usecases.JwtAccessTokenClaims).Roles)
+	})
+}
+
+func (c *CustomerRolesSuite) Test3() {
+	c.Run("given an auditor token, when reading and assigning roles, then can only read", func() {
+		accessToken := testhelpers.TestGenerateAccessTokenWithRoles(uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
+			usecases.RoleCustomer, usecases.RoleAuditor)
+
+		response, body := c.do("GET", "/v1/admin/customers/9a4bd6e4-6f0c-4c55-a7a6-3a1f0a6a8c21/roles", accessToken)
+		c.Equal(200, response.StatusCode)
+		c.JSONEq(`{"data": {"roles": ["customer"]}}`, body)
+
+		response, _ = c.do("PUT", "/v1/admin/customers/9a4bd6e4-6f0c-4c55-a7a6-3a1f0a6a8c21/roles/admin", accessToken)
+		c.Equal(403, response.StatusCode)
+
+		response, _ = c.do("DELETE", "/v1/admin/customers/9a4bd6e4-6f0c-4c55-a7a6-3a1f0a6a8c21/sessions", accessToken)
+		c.Equal(403, response.StatusCode)
+	})
+}
+
+func (c *CustomerRolesSuite) Test4() {
+	c.Run("given a support agent, when the admin unassigns the role, then returns 204 and the agent's access tokens are revoked", func() {
+		utils.ThrowOnError(c.customerRoleDAO.Create(context.Background(), daos.CustomerRoleSchema{
+			CustomerId: uuid.MustParse("9a4bd6e4-6f0c-4c55-a7a6-3a1f0a6a8c21"),
+			Role:       usecases.RoleSupport,
+			CreatedAt:  time.Now().UTC(),
+		}))
+
+		adminAccessToken := testhelpers.TestGenerateAccessTokenWithRoles(uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
+			usecases.RoleCustomer, usecases.RoleAdmin)
+		supportAccessToken := testhelpers.TestGenerateAccessTokenWithRoles(uuid.MustParse("9a4bd6e4-6f0c-4c55-a7a6-3a1f0a6a8c21"),
+			usecases.RoleCustomer, usecases.RoleSupport)
+
+		response, _ := c.do("DELETE", "/v1/admin/customers/9a4bd6e4-6f0c-4c55-a7a6-3a1f0a6a8c21/roles/support", adminAccessToken)
+		c.Equal(204, response.StatusCode)
+
+		customerRolesSchema := utils.GetOrThrow(c.customerRoleDAO.FindAllByCustomerId(context.Background(),
+			uuid.MustParse("9a4bd6e4-6f0c-4c55-a7a6-3a1f0a6a8c21")))
+		c.Empty(customerRolesSchema)
+
+		response, body := c.do("DELETE", "/v1/admin/customers/f59207c8-e837-4159-b67d-78c716510747/sessions", supportAccessToken)
+		c.Equal(401, response.StatusCode)
+		c.Contains(body, `"code":"access_token_revoked"`)
+	})
+}
+
+func (c *CustomerRolesSuite) Test5() {
+	c.Run("given a support token, when revoking the sessions of a customer, then returns 204", func() {
+		accessToken := testhelpers.TestGenerateAccessTokenWithRoles(uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
+			usecases.RoleCustomer, usecases.RoleSupport)
+
+		response, _ := c.do("DELETE", "/v1/admin/customers/9a4bd6e4-6f0c-4c55-a7a6-3a1f0a6a8c21/sessions", accessToken)
+		c.Equal(204, response.StatusCode)
+
+		exists := utils.GetOrThrow(c.testEnvironment.RedisClient().Exists(context.Background(),
+			"revoked_access_tokens:customer:9a4bd6e4-6f0c-4c55-a7a6-3a1f0a6a8c21").Result())
+		c.Equal(int64(1), exists)
+	})
+}
+
+func (c *CustomerRolesSuite) Test6() {
+	c.Run("given an admin token, when the customer does not exist or the path is invalid, then returns 404 or 400", func() {
+		accessToken := testhelpers.TestGenerateAccessTokenWithRoles(uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
+			usecases.RoleCustomer, usecases.RoleAdmin)
+
+		response, body := c.do("PUT", "/v1/admin/customers/1b0f5bde-7f43-4b8e-9d4b-0c1e6c7f9f11/roles/support", accessToken)
+		c.Equal(404, response.StatusCode)
+		c.JSONEq(fmt.Sprintf(`
+			{
+				"type": "/problems/customer_not_found",
+				"title": "Not Found",
+				"status": 404,
+				"detail": "customer not found",
+				"instance": "%s",
+				"code": "customer_not_found"
+			}
+		`, response.Header.Get("X-Request-Id")), body)
+
+		response, body = c.do("PUT", "/v1/admin/customers/abc/roles/owner", accessToken)
+		c.Equal(400, response.StatusCode)
+		c.JSONEq(fmt.Sprintf(`
+			{
+				"type": "/problems/validation_failed",
+				"title": "Bad Request",
+				"status": 400,
+				"detail": "the request has one or more invalid fields",
+				"instance": "%s",
+				"code": "validation_failed",
+				"errors": [
+					{"field": "customerId", "message": "customerId must be uuidv4"},
+					{"field": "role", "message": "role must be one of support, admin, auditor"}
+				]
+			}
+		`, response.Header.Get("X-Request-Id")), body)
+	})
+}
+
+func (c *CustomerRolesSuite) Test7() {
+	c.Run("when calling the admin routes without an access token, then returns 401", func() {
+		response := utils.GetOrThrow(c.testEnvironment.Client().Get(c.testEnvironment.BaseUrl() +
+			"/v1/admin/customers/9a4bd6e4-6f0c-4c55-a7a6-3a1f0a6a8c21/roles"))
+		utils.ThrowOnError(response.Body.Close())
+
+		c.Equal(401, response.StatusCode)
+	})
+}
+
+func TestCustomerRoles(t *testing.T) {
+	suite.Run(t, new(CustomerRolesSuite))
+}
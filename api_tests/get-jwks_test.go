@@ -0,0 +1,115 @@
+package apitests_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	testhelpers "github.com/gsaaraujo/pay-bank-api/internal/test_helpers"
+	"github.com/gsaaraujo/pay-bank-api/internal/utils"
+	"github.com/stretchr/testify/suite"
+)
+
+type GetJWKSSuite struct {
+	suite.Suite
+	customerDAO     daos.CustomerDAO
+	testEnvironment *testhelpers.TestEnvironment
+}
+
+func (g *GetJWKSSuite) SetupSuite() {
+	g.testEnvironment = testhelpers.NewTestEnvironment()
+	g.testEnvironment.Start()
+	g.customerDAO = daos.NewCustomerDAO(g.testEnvironment.PgxPool())
+}
+
+func (g *GetJWKSSuite) SetupTest() {
+	utils.ThrowOnError(g.customerDAO.DeleteAll(context.Background()))
+
+	response := utils.GetOrThrow(g.testEnvironment.Client().Post(g.testEnvironment.BaseUrl()+"/v1/sign-up", "application/json",
+		strings.NewReader(`{"name": "John Doe", "email": "john.doe@gmail.com", "password": "S3cret-pass"}`)))
+	utils.ThrowOnError(response.Body.Close())
+	g.Require().Equal(204, response.StatusCode)
+}
+
+func (g *GetJWKSSuite) getTransactionsHistory(accessToken string) int {
+	request := utils.GetOrThrow(http.NewRequest("GET", g.testEnvironment.BaseUrl()+"/v1/transactions-history", nil))
+	request.Header.Add("Authorization", "Bearer "+accessToken)
+
+	response := utils.GetOrThrow(g.testEnvironment.Client().Do(request))
+	utils.ThrowOnError(response.Body.Close())
+
+	return response.StatusCode
+}
+
+func (g *GetJWKSSuite) Test1() {
+	g.Run("when getting the jwks, then returns 200 with every verification key", func() {
+		response := utils.GetOrThrow(g.testEnvironment.Client().Get(g.testEnvironment.BaseUrl() + "/.well-known/jwks.json"))
+		body := utils.GetOrThrow(io.ReadAll(response.Body))
+
+		g.Equal(200, response.StatusCode)
+		g.Equal("public, max-age=300", response.Header.Get("Cache-Control"))
+		g.JSONEq(`
+			{
+				"keys": [
+					{
+						"kty": "OKP",
+						"crv": "Ed25519",
+						"x": "V2a6t2pfc4ceM6PiiSPwcG5PFt9UFtCeabzboB1lyCE",
+						"kid": "test-key-1",
+						"use": "sig",
+						"alg": "EdDSA"
+					},
+					{
+						"kty": "OKP",
+						"crv": "Ed25519",
+						"x": "dfePGW_39XRonwbtbdxpzqsxQ7c0FIsh4-vacl8XPFY",
+						"kid": "test-key-2",
+						"use": "sig",
+						"alg": "EdDSA"
+					}
+				]
+			}
+		`, string(body))
+	})
+}
+
+func (g *GetJWKSSuite) Test2() {
+	g.Run("given a token signed with the current or the previous key, when calling a protected route, then accepts it", func() {
+		customerSchema := utils.GetOrThrow(g.customerDAO.FindOneByEmail(context.Background(), "john.doe@gmail.com"))
+
+		g.Equal(200, g.getTransactionsHistory(testhelpers.TestGenerateAccessToken(customerSchema.Id)))
+		g.Equal(200, g.getTransactionsHistory(testhelpers.TestGenerateAccessTokenWithKey(customerSchema.Id,
+			testhelpers.TestAccessTokenPreviousKeyId, testhelpers.TestAccessTokenPreviousPrivateKey)))
+	})
+}
+
+func (g *GetJWKSSuite) Test3() {
+	g.Run("given a token with an unknown kid or signed with a shared secret, when calling a protected route, then returns 401", func() {
+		customerSchema := utils.GetOrThrow(g.customerDAO.FindOneByEmail(context.Background(), "john.doe@gmail.com"))
+
+		g.Equal(401, g.getTransactionsHistory(testhelpers.TestGenerateAccessTokenWithKey(customerSchema.Id,
+			"unknown-key", testhelpers.TestAccessTokenPreviousPrivateKey)))
+		g.Equal(401, g.getTransactionsHistory(testhelpers.TestGenerateAccessTokenWithKey(customerSchema.Id,
+			testhelpers.TestAccessTokenSigningKeyId, testhelpers.TestAccessTokenPreviousPrivateKey)))
+
+		hmacToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   customerSchema.Id.String(),
+			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(30 * time.Minute)),
+		})
+		hmacToken.Header["kid"] = testhelpers.TestAccessTokenSigningKeyId
+
+		g.Equal(401, g.getTransactionsHistory(utils.GetOrThrow(hmacToken.SignedString([]byte(testhelpers.TestAccessTokenSigningPublicKey)))))
+	})
+}
+
+func TestGetJWKS(t *testing.T) {
+	suite.Run(t, new(GetJWKSSuite))
+}
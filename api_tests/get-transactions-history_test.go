@@ -1,6 +1,8 @@
 package apitests_test
 
 import (
+	"context"
+	"fmt"
 	"io"
 	"net/http"
 	"testing"
@@ -30,76 +32,76 @@ func (g *GetTransactionsHistorySuite) SetupSuite() {
 }
 
 func (g *GetTransactionsHistorySuite) SetupTest() {
-	g.customerDAO.DeleteAll()
-	g.accountDAO.DeleteAll()
-	g.transactionDAO.DeleteAll()
+	utils.ThrowOnError(g.customerDAO.DeleteAll(context.Background()))
+	utils.ThrowOnError(g.accountDAO.DeleteAll(context.Background()))
+	utils.ThrowOnError(g.transactionDAO.DeleteAll(context.Background()))
 }
 
 func (g *GetTransactionsHistorySuite) Test1() {
-	g.Run("given that there's transactions, when getting transaction history, then returns 200", func() {
-		g.customerDAO.Create(daos.CustomerSchema{
+	g.Run("given that there's transactions, when getting transaction history, then returns 200 newest first", func() {
+		utils.ThrowOnError(g.customerDAO.Create(context.Background(), daos.CustomerSchema{
 			Id:        uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
 			Name:      "John Doe",
 			Email:     "john.doe@gmail.com",
 			Password:  "$2a$10$asLIHej6kxd3Fsdc76QHieBugwCGvsYJeLiZmP1K7/t1GbIbUy.pK",
+			Status:    daos.CustomerStatusActive,
 			UpdatedAt: time.Now().UTC(),
 			CreatedAt: time.Now().UTC(),
-		})
-		g.customerDAO.Create(daos.CustomerSchema{
+		}))
+		utils.ThrowOnError(g.customerDAO.Create(context.Background(), daos.CustomerSchema{
 			Id:        uuid.MustParse("a06f5c45-f824-4cb1-a666-805035ae2ae1"),
 			Name:      "Richard Smith",
 			Email:     "richard.smith@gmail.com",
 			Password:  "$2a$10$1dS5NaFw0pZgGA.SvQ5awOm5jr36Z5pE2wl51mHHIQTz5fO9wwBTC",
+			Status:    daos.CustomerStatusActive,
 			UpdatedAt: time.Now().UTC(),
 			CreatedAt: time.Now().UTC(),
-		})
-		g.accountDAO.Create(daos.AccountSchema{
+		}))
+		utils.ThrowOnError(g.accountDAO.Create(context.Background(), daos.AccountSchema{
 			Id:         uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"),
 			CustomerId: uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
 			Balance:    12500,
 			UpdatedAt:  time.Now().UTC(),
 			CreatedAt:  time.Now().UTC(),
-		})
-		g.accountDAO.Create(daos.AccountSchema{
+		}))
+		utils.ThrowOnError(g.accountDAO.Create(context.Background(), daos.AccountSchema{
 			Id:         uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d"),
 			CustomerId: uuid.MustParse("a06f5c45-f824-4cb1-a666-805035ae2ae1"),
 			Balance:    3200,
 			UpdatedAt:  time.Now().UTC(),
 			CreatedAt:  time.Now().UTC(),
-		})
-		g.transactionDAO.Create(daos.TransactionSchema{
+		}))
+		utils.ThrowOnError(g.transactionDAO.Create(context.Background(), daos.TransactionSchema{
 			Id:                uuid.MustParse("7e7fc500-0699-4e21-895c-dc8908da9329"),
 			AccountSenderId:   uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"),
 			AccountReceiverId: uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d"),
 			IdempotencyKey:    "2108b394-b875-40cf-9ee6-1d8bd6fb1ec5",
 			Amount:            4900,
-			UpdatedAt:         time.Now().UTC(),
-			CreatedAt:         time.Now().UTC(),
-		})
-		g.transactionDAO.Create(daos.TransactionSchema{
+			UpdatedAt:         time.Date(2025, 11, 10, 10, 0, 0, 0, time.UTC),
+			CreatedAt:         time.Date(2025, 11, 10, 10, 0, 0, 0, time.UTC),
+		}))
+		utils.ThrowOnError(g.transactionDAO.Create(context.Background(), daos.TransactionSchema{
 			Id:                uuid.MustParse("661d6052-ba0b-4d53-80b4-0e0b1e78623e"),
 			AccountSenderId:   uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"),
 			AccountReceiverId: uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d"),
 			IdempotencyKey:    "03066c51-ce8d-420a-a21b-b905e1b37b2a",
 			Amount:            78594,
-			UpdatedAt:         time.Now().UTC(),
-			CreatedAt:         time.Now().UTC(),
-		})
-		g.transactionDAO.Create(daos.TransactionSchema{
+			UpdatedAt:         time.Date(2025, 11, 11, 10, 0, 0, 0, time.UTC),
+			CreatedAt:         time.Date(2025, 11, 11, 10, 0, 0, 0, time.UTC),
+		}))
+		utils.ThrowOnError(g.transactionDAO.Create(context.Background(), daos.TransactionSchema{
 			Id:                uuid.MustParse("b648c932-becb-48ca-89e1-3fda8677e7dd"),
 			AccountSenderId:   uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"),
 			AccountReceiverId: uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d"),
 			IdempotencyKey:    "d7e7b27b-2ec7-4215-8f7d-31f8fa73e662",
 			Amount:            2539,
-			UpdatedAt:         time.Now().UTC(),
-			CreatedAt:         time.Now().UTC(),
-		})
+			UpdatedAt:         time.Date(2025, 11, 12, 10, 0, 0, 0, time.UTC),
+			CreatedAt:         time.Date(2025, 11, 12, 10, 0, 0, 0, time.UTC),
+		}))
 
 		request := utils.GetOrThrow(http.NewRequest("GET", g.testEnvironment.BaseUrl()+"/v1/transactions-history", nil))
 		accessToken := testhelpers.TestGenerateAccessToken(uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"))
-		request.Header.Add("Content-Type", "application/json")
 		request.Header.Add("Authorization", "Bearer "+accessToken)
-		request.Header.Add("Idempotency-Key", "2108b394-b875-40cf-9ee6-1d8bd6fb1ec5")
 
 		response := utils.GetOrThrow(g.testEnvironment.Client().Do(request))
 
@@ -109,7 +111,8 @@ func (g *GetTransactionsHistorySuite) Test1() {
 		{
 			"data": [
 				{
-					"id": "7e7fc500-0699-4e21-895c-dc8908da9329",
+					"id": "b648c932-becb-48ca-89e1-3fda8677e7dd",
+					"direction": "sent",
 					"customerSender": {
 						"id": "f59207c8-e837-4159-b67d-78c716510747",
 						"name": "John Doe"
@@ -124,10 +127,12 @@ func (g *GetTransactionsHistorySuite) Test1() {
 					"accountReceiver": {
 						"id": "c7333b68-6f2a-46db-89c8-fd833fd3546d"
 					},
-					"amount": 4900
+					"amount": 2539,
+					"createdAt": "2025-11-12T10:00:00Z"
 				},
 				{
 					"id": "661d6052-ba0b-4d53-80b4-0e0b1e78623e",
+					"direction": "sent",
 					"customerSender": {
 						"id": "f59207c8-e837-4159-b67d-78c716510747",
 						"name": "John Doe"
@@ -142,10 +147,96 @@ func (g *GetTransactionsHistorySuite) Test1() {
 					"accountReceiver": {
 						"id": "c7333b68-6f2a-46db-89c8-fd833fd3546d"
 					},
-					"amount": 78594
+					"amount": 78594,
+					"createdAt": "2025-11-11T10:00:00Z"
 				},
 				{
-					"id": "b648c932-becb-48ca-89e1-3fda8677e7dd",
+					"id": "7e7fc500-0699-4e21-895c-dc8908da9329",
+					"direction": "sent",
+					"customerSender": {
+						"id": "f59207c8-e837-4159-b67d-78c716510747",
+						"name": "John Doe"
+					},
+					"customerReceiver": {
+						"id": "a06f5c45-f824-4cb1-a666-805035ae2ae1",
+						"name": "Richard Smith"
+					},
+					"accountSender": {
+						"id": "2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"
+					},
+					"accountReceiver": {
+						"id": "c7333b68-6f2a-46db-89c8-fd833fd3546d"
+					},
+					"amount": 4900,
+					"createdAt": "2025-11-10T10:00:00Z"
+				}
+			],
+			"pagination": {
+				"nextCursor": null
+			}
+		}
+	`, string(body))
+	})
+}
+
+func (g *GetTransactionsHistorySuite) Test2() {
+	g.Run("given that the customer has received a transaction, when getting transaction history, then returns 200 with direction received", func() {
+		utils.ThrowOnError(g.customerDAO.Create(context.Background(), daos.CustomerSchema{
+			Id:        uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
+			Name:      "John Doe",
+			Email:     "john.doe@gmail.com",
+			Password:  "$2a$10$asLIHej6kxd3Fsdc76QHieBugwCGvsYJeLiZmP1K7/t1GbIbUy.pK",
+			Status:    daos.CustomerStatusActive,
+			UpdatedAt: time.Now().UTC(),
+			CreatedAt: time.Now().UTC(),
+		}))
+		utils.ThrowOnError(g.customerDAO.Create(context.Background(), daos.CustomerSchema{
+			Id:        uuid.MustParse("a06f5c45-f824-4cb1-a666-805035ae2ae1"),
+			Name:      "Richard Smith",
+			Email:     "richard.smith@gmail.com",
+			Password:  "$2a$10$1dS5NaFw0pZgGA.SvQ5awOm5jr36Z5pE2wl51mHHIQTz5fO9wwBTC",
+			Status:    daos.CustomerStatusActive,
+			UpdatedAt: time.Now().UTC(),
+			CreatedAt: time.Now().UTC(),
+		}))
+		utils.ThrowOnError(g.accountDAO.Create(context.Background(), daos.AccountSchema{
+			Id:         uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"),
+			CustomerId: uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
+			Balance:    12500,
+			UpdatedAt:  time.Now().UTC(),
+			CreatedAt:  time.Now().UTC(),
+		}))
+		utils.ThrowOnError(g.accountDAO.Create(context.Background(), daos.AccountSchema{
+			Id:         uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d"),
+			CustomerId: uuid.MustParse("a06f5c45-f824-4cb1-a666-805035ae2ae1"),
+			Balance:    3200,
+			UpdatedAt:  time.Now().UTC(),
+			CreatedAt:  time.Now().UTC(),
+		}))
+		utils.ThrowOnError(g.transactionDAO.Create(context.Background(), daos.TransactionSchema{
+			Id:                uuid.MustParse("7e7fc500-0699-4e21-895c-dc8908da9329"),
+			AccountSenderId:   uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"),
+			AccountReceiverId: uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d"),
+			IdempotencyKey:    "2108b394-b875-40cf-9ee6-1d8bd6fb1ec5",
+			Amount:            4900,
+			UpdatedAt:         time.Date(2025, 11, 10, 10, 0, 0, 0, time.UTC),
+			CreatedAt:         time.Date(2025, 11, 10, 10, 0, 0, 0, time.UTC),
+		}))
+
+		request := utils.GetOrThrow(http.NewRequest("GET", g.testEnvironment.BaseUrl()+"/v1/transactions-history", nil))
+		accessToken := testhelpers.TestGenerateAccessToken(uuid.MustParse("a06f5c45-f824-4cb1-a666-805035ae2ae1"))
+		request.Header.Add("Authorization", "Bearer "+accessToken)
+
+		response := utils.GetOrThrow(g.testEnvironment.Client().Do(request))
+
+		body := utils.GetOrThrow(io.ReadAll(response.Body))
+		g.Equal(200, response.StatusCode)
+		g.JSONEq(`
+		{
+			"data": [
+				{
+					"id": "7e7fc500-0699-4e21-895c-dc8908da9329",
+					"direction": "received",
 					"customerSender": {
 						"id": "f59207c8-e837-4159-b67d-78c716510747",
 						"name": "John Doe"
@@ -160,14 +251,331 @@ func (g *GetTransactionsHistorySuite) Test1() {
 					"accountReceiver": {
 						"id": "c7333b68-6f2a-46db-89c8-fd833fd3546d"
 					},
-					"amount": 2539
+					"amount": 4900,
+					"createdAt": "2025-11-10T10:00:00Z"
 				}
-			]
+			],
+			"pagination": {
+				"nextCursor": null
+			}
+		}
+	`, string(body))
+	})
+}
+
+func (g *GetTransactionsHistorySuite) Test3() {
+	g.Run("given that there's transactions between other customers, when getting transaction history, then returns 200 without them", func() {
+		utils.ThrowOnError(g.customerDAO.Create(context.Background(), daos.CustomerSchema{
+			Id:        uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
+			Name:      "John Doe",
+			Email:     "john.doe@gmail.com",
+			Password:  "$2a$10$asLIHej6kxd3Fsdc76QHieBugwCGvsYJeLiZmP1K7/t1GbIbUy.pK",
+			Status:    daos.CustomerStatusActive,
+			UpdatedAt: time.Now().UTC(),
+			CreatedAt: time.Now().UTC(),
+		}))
+		utils.ThrowOnError(g.customerDAO.Create(context.Background(), daos.CustomerSchema{
+			Id:        uuid.MustParse("a06f5c45-f824-4cb1-a666-805035ae2ae1"),
+			Name:      "Richard Smith",
+			Email:     "richard.smith@gmail.com",
+			Password:  "$2a$10$1dS5NaFw0pZgGA.SvQ5awOm5jr36Z5pE2wl51mHHIQTz5fO9wwBTC",
+			Status:    daos.CustomerStatusActive,
+			UpdatedAt: time.Now().UTC(),
+			CreatedAt: time.Now().UTC(),
+		}))
+		utils.ThrowOnError(g.customerDAO.Create(context.Background(), daos.CustomerSchema{
+			Id:        uuid.MustParse("3f1c2b9e-7a4d-4e8f-9b6a-2d5c8e1f0a37"),
+			Name:      "Mary Jane",
+			Email:     "mary.jane@gmail.com",
+			Password:  "$2a$10$asLIHej6kxd3Fsdc76QHieBugwCGvsYJeLiZmP1K7/t1GbIbUy.pK",
+			Status:    daos.CustomerStatusActive,
+			UpdatedAt: time.Now().UTC(),
+			CreatedAt: time.Now().UTC(),
+		}))
+		utils.ThrowOnError(g.accountDAO.Create(context.Background(), daos.AccountSchema{
+			Id:         uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"),
+			CustomerId: uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
+			Balance:    12500,
+			UpdatedAt:  time.Now().UTC(),
+			CreatedAt:  time.Now().UTC(),
+		}))
+		utils.ThrowOnError(g.accountDAO.Create(context.Background(), daos.AccountSchema{
+			Id:         uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d"),
+			CustomerId: uuid.MustParse("a06f5c45-f824-4cb1-a666-805035ae2ae1"),
+			Balance:    3200,
+			UpdatedAt:  time.Now().UTC(),
+			CreatedAt:  time.Now().UTC(),
+		}))
+		utils.ThrowOnError(g.accountDAO.Create(context.Background(), daos.AccountSchema{
+			Id:         uuid.MustParse("9d6e4a2b-1c3f-4b7e-8a5d-6f0e2c4b8a91"),
+			CustomerId: uuid.MustParse("3f1c2b9e-7a4d-4e8f-9b6a-2d5c8e1f0a37"),
+			Balance:    7000,
+			UpdatedAt:  time.Now().UTC(),
+			CreatedAt:  time.Now().UTC(),
+		}))
+		utils.ThrowOnError(g.transactionDAO.Create(context.Background(), daos.TransactionSchema{
+			Id:                uuid.MustParse("7e7fc500-0699-4e21-895c-dc8908da9329"),
+			AccountSenderId:   uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"),
+			AccountReceiverId: uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d"),
+			IdempotencyKey:    "2108b394-b875-40cf-9ee6-1d8bd6fb1ec5",
+			Amount:            4900,
+			UpdatedAt:         time.Now().UTC(),
+			CreatedAt:         time.Now().UTC(),
+		}))
+
+		request := utils.GetOrThrow(http.NewRequest("GET", g.testEnvironment.BaseUrl()+"/v1/transactions-history", nil))
+		accessToken := testhelpers.TestGenerateAccessToken(uuid.MustParse("3f1c2b9e-7a4d-4e8f-9b6a-2d5c8e1f0a37"))
+		request.Header.Add("Authorization", "Bearer "+accessToken)
+
+		response := utils.GetOrThrow(g.testEnvironment.Client().Do(request))
+
+		body := utils.GetOrThrow(io.ReadAll(response.Body))
+		g.Equal(200, response.StatusCode)
+		g.JSONEq(`
+		{
+			"data": [],
+			"pagination": {
+				"nextCursor": null
+			}
 		}
 	`, string(body))
 	})
 }
 
+func (g *GetTransactionsHistorySuite) Test4() {
+	g.Run("given that there's more transactions than the limit, when paginating through transaction history, then returns every page newest first", func() {
+		utils.ThrowOnError(g.customerDAO.Create(context.Background(), daos.CustomerSchema{
+			Id:        uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
+			Name:      "John Doe",
+			Email:     "john.doe@gmail.com",
+			Password:  "$2a$10$asLIHej6kxd3Fsdc76QHieBugwCGvsYJeLiZmP1K7/t1GbIbUy.pK",
+			Status:    daos.CustomerStatusActive,
+			UpdatedAt: time.Now().UTC(),
+			CreatedAt: time.Now().UTC(),
+		}))
+		utils.ThrowOnError(g.customerDAO.Create(context.Background(), daos.CustomerSchema{
+			Id:        uuid.MustParse("a06f5c45-f824-4cb1-a666-805035ae2ae1"),
+			Name:      "Richard Smith",
+			Email:     "richard.smith@gmail.com",
+			Password:  "$2a$10$1dS5NaFw0pZgGA.SvQ5awOm5jr36Z5pE2wl51mHHIQTz5fO9wwBTC",
+			Status:    daos.CustomerStatusActive,
+			UpdatedAt: time.Now().UTC(),
+			CreatedAt: time.Now().UTC(),
+		}))
+		utils.ThrowOnError(g.accountDAO.Create(context.Background(), daos.AccountSchema{
+			Id:         uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"),
+			CustomerId: uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
+			Balance:    12500,
+			UpdatedAt:  time.Now().UTC(),
+			CreatedAt:  time.Now().UTC(),
+		}))
+		utils.ThrowOnError(g.accountDAO.Create(context.Background(), daos.AccountSchema{
+			Id:         uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d"),
+			CustomerId: uuid.MustParse("a06f5c45-f824-4cb1-a666-805035ae2ae1"),
+			Balance:    3200,
+			UpdatedAt:  time.Now().UTC(),
+			CreatedAt:  time.Now().UTC(),
+		}))
+		for day := 1; day <= 5; day++ {
+			utils.ThrowOnError(g.transactionDAO.Create(context.Background(), daos.TransactionSchema{
+				Id:                uuid.New(),
+				AccountSenderId:   uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"),
+				AccountReceiverId: uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d"),
+				IdempotencyKey:    uuid.New().String(),
+				Amount:            int64(day * 100),
+				UpdatedAt:         time.Date(2025, 11, day, 10, 0, 0, 0, time.UTC),
+				CreatedAt:         time.Date(2025, 11, day, 10, 0, 0, 0, time.UTC),
+			}))
+		}
+
+		amounts := []float64{}
+		url := g.testEnvironment.BaseUrl() + "/v1/transactions-history?limit=2"
+		pages := 0
+
+		for {
+			request := utils.GetOrThrow(http.NewRequest("GET", url, nil))
+			accessToken := testhelpers.TestGenerateAccessToken(uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"))
+			request.Header.Add("Authorization", "Bearer "+accessToken)
+
+			response := utils.GetOrThrow(g.testEnvironment.Client().Do(request))
+			g.Require().Equal(200, response.StatusCode)
+
+			body := utils.ParseJSONBody[map[string]any](response.Body)
+			pages++
+
+			for _, item := range body["data"].([]any) {
+				amounts = append(amounts, item.(map[string]any)["amount"].(float64))
+			}
+
+			nextCursor := body["pagination"].(map[string]any)["nextCursor"]
+			if nextCursor == nil {
+				break
+			}
+
+			url = g.testEnvironment.BaseUrl() + "/v1/transactions-history?limit=2&cursor=" + nextCursor.(string)
+		}
+
+		g.Equal(3, pages)
+		g.Equal([]float64{500, 400, 300, 200, 100}, amounts)
+	})
+}
+
+func (g *GetTransactionsHistorySuite) Test5() {
+	g.Run("given that there's transactions on several days, when getting transaction history within a date range, then returns only those in range", func() {
+		utils.ThrowOnError(g.customerDAO.Create(context.Background(), daos.CustomerSchema{
+			Id:        uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
+			Name:      "John Doe",
+			Email:     "john.doe@gmail.com",
+			Password:  "$2a$10$asLIHej6kxd3Fsdc76QHieBugwCGvsYJeLiZmP1K7/t1GbIbUy.pK",
+			Status:    daos.CustomerStatusActive,
+			UpdatedAt: time.Now().UTC(),
+			CreatedAt: time.Now().UTC(),
+		}))
+		utils.ThrowOnError(g.customerDAO.Create(context.Background(), daos.CustomerSchema{
+			Id:        uuid.MustParse("a06f5c45-f824-4cb1-a666-805035ae2ae1"),
+			Name:      "Richard Smith",
+			Email:     "richard.smith@gmail.com",
+			Password:  "$2a$10$1dS5NaFw0pZgGA.SvQ5awOm5jr36Z5pE2wl51mHHIQTz5fO9wwBTC",
+			Status:    daos.CustomerStatusActive,
+			UpdatedAt: time.Now().UTC(),
+			CreatedAt: time.Now().UTC(),
+		}))
+		utils.ThrowOnError(g.accountDAO.Create(context.Background(), daos.AccountSchema{
+			Id:         uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"),
+			CustomerId: uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
+			Balance:    12500,
+			UpdatedAt:  time.Now().UTC(),
+			CreatedAt:  time.Now().UTC(),
+		}))
+		utils.ThrowOnError(g.accountDAO.Create(context.Background(), daos.AccountSchema{
+			Id:         uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d"),
+			CustomerId: uuid.MustParse("a06f5c45-f824-4cb1-a666-805035ae2ae1"),
+			Balance:    3200,
+			UpdatedAt:  time.Now().UTC(),
+			CreatedAt:  time.Now().UTC(),
+		}))
+		for day := 1; day <= 5; day++ {
+			utils.ThrowOnError(g.transactionDAO.Create(context.Background(), daos.TransactionSchema{
+				Id:                uuid.New(),
+				AccountSenderId:   uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"),
+				AccountReceiverId: uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d"),
+				IdempotencyKey:    uuid.New().String(),
+				Amount:            int64(day * 100),
+				UpdatedAt:         time.Date(2025, 11, day, 10, 0, 0, 0, time.UTC),
+				CreatedAt:         time.Date(2025, 11, day, 10, 0, 0, 0, time.UTC),
+			}))
+		}
+
+		templates := []map[string]any{
+			{"query": "?from=2025-11-02&to=2025-11-04", "amounts": []float64{400, 300, 200}},
+			{"query": "?from=2025-11-02T10:00:00Z&to=2025-11-04T10:00:00Z", "amounts": []float64{300, 200}},
+			{"query": "?from=2025-11-04", "amounts": []float64{500, 400}},
+			{"query": "?to=2025-11-01", "amounts": []float64{100}},
+		}
+
+		for _, template := range templates {
+			request := utils.GetOrThrow(http.NewRequest("GET", g.testEnvironment.BaseUrl()+"/v1/transactions-history"+template["query"].(string), nil))
+			accessToken := testhelpers.TestGenerateAccessToken(uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"))
+			request.Header.Add("Authorization", "Bearer "+accessToken)
+
+			response := utils.GetOrThrow(g.testEnvironment.Client().Do(request))
+			g.Require().Equal(200, response.StatusCode)
+
+			body := utils.ParseJSONBody[map[string]any](response.Body)
+			amounts := []float64{}
+
+			for _, item := range body["data"].([]any) {
+				amounts = append(amounts, item.(map[string]any)["amount"].(float64))
+			}
+
+			g.Equal(template["amounts"], amounts, template["query"])
+		}
+	})
+}
+
+func (g *GetTransactionsHistorySuite) Test6() {
+	g.Run("when getting transaction history and query params are invalid, then returns 400", func() {
+		templates := []map[string]string{
+			{
+				"query": "?limit=abc&from=yesterday&to=2025/11/01",
+				"problem": `{
+					"type": "/problems/validation_failed",
+					"title": "Bad Request",
+					"status": 400,
+					"detail": "the request has one or more invalid fields",
+					"instance": "%s",
+					"code": "validation_failed",
+					"errors": [
+						{"field": "limit", "message": "limit must be integer"},
+						{"field": "from", "message": "from must follow format yyyy-mm-ddThh:mm:ssZ or yyyy-mm-dd"},
+						{"field": "to", "message": "to must follow format yyyy-mm-ddThh:mm:ssZ or yyyy-mm-dd"}
+					]
+				}`,
+			},
+			{
+				"query": "?limit=1.5",
+				"problem": `{
+					"type": "/problems/validation_failed",
+					"title": "Bad Request",
+					"status": 400,
+					"detail": "the request has one or more invalid fields",
+					"instance": "%s",
+					"code": "validation_failed",
+					"errors": [
+						{"field": "limit", "message": "limit must be integer"}
+					]
+				}`,
+			},
+			{
+				"query": "?limit=101",
+				"problem": `{
+					"type": "/problems/history_limit_out_of_range",
+					"title": "Bad Request",
+					"status": 400,
+					"detail": "limit must be between 1 and 100",
+					"instance": "%s",
+					"code": "history_limit_out_of_range"
+				}`,
+			},
+			{
+				"query": "?from=2025-11-04&to=2025-11-02",
+				"problem": `{
+					"type": "/problems/history_range_invalid",
+					"title": "Bad Request",
+					"status": 400,
+					"detail": "from must be before to",
+					"instance": "%s",
+					"code": "history_range_invalid"
+				}`,
+			},
+			{
+				"query": "?cursor=abc",
+				"problem": `{
+					"type": "/problems/history_cursor_invalid",
+					"title": "Bad Request",
+					"status": 400,
+					"detail": "cursor is invalid",
+					"instance": "%s",
+					"code": "history_cursor_invalid"
+				}`,
+			},
+		}
+
+		for _, template := range templates {
+			request := utils.GetOrThrow(http.NewRequest("GET", g.testEnvironment.BaseUrl()+"/v1/transactions-history"+template["query"], nil))
+			accessToken := testhelpers.TestGenerateAccessToken(uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"))
+			request.Header.Add("Authorization", "Bearer "+accessToken)
+
+			response := utils.GetOrThrow(g.testEnvironment.Client().Do(request))
+
+			body := utils.GetOrThrow(io.ReadAll(response.Body))
+			g.Equal(400, response.StatusCode)
+			g.Equal("application/problem+json", response.Header.Get("Content-Type"))
+			g.JSONEq(fmt.Sprintf(template["problem"], response.Header.Get("X-Request-Id")), string(body))
+		}
+	})
+}
+
 func TestGetTransactionsHistorySuite(t *testing.T) {
 	suite.Run(t, new(GetTransactionsHistorySuite))
 }
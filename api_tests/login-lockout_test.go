@@ -0,0 +1,195 @@
+package apitests_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	testhelpers "github.com/gsaaraujo/pay-bank-api/internal/test_helpers"
+	"github.com/gsaaraujo/pay-bank-api/internal/utils"
+	"github.com/stretchr/testify/suite"
+)
+
+type LoginLockoutSuite struct {
+	suite.Suite
+	customerDAO     daos.CustomerDAO
+	loginLockoutDAO daos.LoginLockoutDAO
+	testEnvironment *testhelpers.TestEnvironment
+}
+
+func (l *LoginLockoutSuite) SetupSuite() {
+	l.testEnvironment = testhelpers.NewTestEnvironment()
+	l.testEnvironment.Start()
+	l.customerDAO = daos.NewCustomerDAO(l.testEnvironment.PgxPool())
+	l.loginLockoutDAO = daos.NewLoginLockoutDAO(l.testEnvironment.PgxPool())
+}
+
+func (l *LoginLockoutSuite) SetupTest() {
+	utils.ThrowOnError(l.customerDAO.DeleteAll(context.Background()))
+	utils.ThrowOnError(l.loginLockoutDAO.DeleteAll(context.Background()))
+	utils.ThrowOnError(l.testEnvironment.RedisClient().FlushAll(context.Background()).Err())
+
+	utils.ThrowOnError(l.customerDAO.Create(context.Background(), daos.CustomerSchema{
+		Id:        uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
+		Name:      "John Doe",
+		Email:     "john.doe@gmail.com",
+		Password:  "$2a$10$asLIHej6kxd3Fsdc76QHieBugwCGvsYJeLiZmP1K7/t1GbIbUy.pK",
+		Status:    daos.CustomerStatusActive,
+		UpdatedAt: time.Now().UTC(),
+		CreatedAt: time.Now().UTC(),
+	}))
+}
+
+func (l *LoginLockoutSuite) login(email string, password string, ipAddress string) *http.Response {
+	request := utils.GetOrThrow(http.NewRequest("POST", l.testEnvironment.BaseUrl()+"/v1/login",
+		strings.NewReader(fmt.Sprintf(`{"email": "%s", "password": "%s"}`, email, password))))
+	request.Header.Add("Content-Type", "application/json")
+	request.Header.Add("X-Forwarded-For", ipAddress)
+
+	return utils.GetOrThrow(l.testEnvironment.Client().Do(request))
+}
+
+func (l *LoginLockoutSuite) failLogins(times int, email string, ipAddress string) {
+	for range times {
+		response := l.login(email, "wrong-password", ipAddress)
+		utils.ThrowOnError(response.Body.Close())
+		l.Require().Equal(409, response.StatusCode)
+	}
+}
+
+func (l *LoginLockoutSuite) Test1() {
+	l.Run("given 5 failed attempts for an email, when logging in even with the right password, then returns 429 with Retry-After", func() {
+		l.failLogins(5, "john.doe@gmail.com", "203.0.113.7")
+
+		response := l.login("john.doe@gmail.com", "123456", "198.51.100.20")
+		body := utils.GetOrThrow(io.ReadAll(response.Body))
+
+		l.Equal(429, response.StatusCode)
+		l.Equal("application/problem+json", response.Header.Get("Content-Type"))
+		l.JSONEq(fmt.Sprintf(`
+			{
+				"type": "/problems/login_locked",
+				"title": "Too Many Requests",
+				"status": 429,
+				"detail": "too many failed login attempts, try again later",
+				"instance": "%s",
+				"code": "login_locked"
+			}
+		`, response.Header.Get("X-Request-Id")), string(body))
+
+		retryAfter := utils.GetOrThrow(strconv.Atoi(response.Header.Get("Retry-After")))
+		l.InDelta(60, retryAfter, 2)
+
+		loginLockoutsSchema := utils.GetOrThrow(l.loginLockoutDAO.FindAllBySubject(context.Background(),
+			daos.LoginLockoutSubjectTypeEmail, "john.doe@gmail.com"))
+		l.Require().Len(loginLockoutsSchema, 1)
+		l.Equal(int64(5), loginLockoutsSchema[0].FailedAttempts)
+		l.WithinDuration(time.Now().UTC().Add(time.Minute), loginLockoutsSchema[0].LockedUntil, 5*time.Second)
+	})
+}
+
+func (l *LoginLockoutSuite) Test2() {
+	l.Run("given a lockout that expired, when failing again, then the next lockout doubles", func() {
+		l.failLogins(5, "John.Doe@gmail.com", "203.0.113.7")
+
+		utils.ThrowOnError(l.testEnvironment.RedisClient().Del(context.Background(),
+			"login_attempts:lockout:email:john.doe@gmail.com").Err())
+
+		l.failLogins(1, "john.doe@gmail.com", "203.0.113.7")
+
+		response := l.login("john.doe@gmail.com", "123456", "203.0.113.7")
+		utils.ThrowOnError(response.Body.Close())
+
+		l.Equal(429, response.StatusCode)
+		retryAfter := utils.GetOrThrow(strconv.Atoi(response.Header.Get("Retry-After")))
+		l.InDelta(120, retryAfter, 2)
+
+		loginLockoutsSchema := utils.GetOrThrow(l.loginLockoutDAO.FindAllBySubject(context.Background(),
+			daos.LoginLockoutSubjectTypeEmail, "john.doe@gmail.com"))
+		l.Require().Len(loginLockoutsSchema, 2)
+		l.Equal(int64(6), loginLockoutsSchema[1].FailedAttempts)
+	})
+}
+
+func (l *LoginLockoutSuite) Test3() {
+	l.Run("given 20 failed attempts from an ip address, when logging in from it, then returns 429 while other addresses can log in", func() {
+		for i := range 20 {
+			l.failLogins(1, fmt.Sprintf("unknown%d@gmail.com", i), "203.0.113.7")
+		}
+
+		response := l.login("john.doe@gmail.com", "123456", "203.0.113.7")
+		utils.ThrowOnError(response.Body.Close())
+		l.Equal(429, response.StatusCode)
+
+		response = l.login("john.doe@gmail.com", "123456", "198.51.100.20")
+		utils.ThrowOnError(response.Body.Close())
+		l.Equal(200, response.StatusCode)
+
+		loginLockoutsSchema := utils.GetOrThrow(l.loginLockoutDAO.FindAllBySubject(context.Background(),
+			daos.LoginLockoutSubjectTypeIp, "203.0.113.7"))
+		l.Require().Len(loginLockoutsSchema, 1)
+		l.Equal(int64(20), loginLockoutsSchema[0].FailedAttempts)
+	})
+}
+
+func (l *LoginLockoutSuite) Test4() {
+	l.Run("given 4 failed attempts, when logging in successfully, then the email counter starts over", func() {
+		l.failLogins(4, "john.doe@gmail.com", "203.0.113.7")
+
+		response := l.login("john.doe@gmail.com", "123456", "203.0.113.7")
+		utils.ThrowOnError(response.Body.Close())
+		l.Require().Equal(200, response.StatusCode)
+
+		l.failLogins(4, "john.doe@gmail.com", "203.0.113.7")
+
+		response = l.login("john.doe@gmail.com", "123456", "203.0.113.7")
+		utils.ThrowOnError(response.Body.Close())
+		l.Equal(200, response.StatusCode)
+
+		loginLockoutsSchema := utils.GetOrThrow(l.loginLockoutDAO.FindAllBySubject(context.Background(),
+			daos.LoginLockoutSubjectTypeEmail, "john.doe@gmail.com"))
+		l.Empty(loginLockoutsSchema)
+	})
+}
+
+func (l *LoginLockoutSuite) Test5() {
+	l.Run("when failing 20 logins for an email at once, then only 5 are checked and the others return 429", func() {
+		var waitGroup sync.WaitGroup
+		statusCodes := make(chan int, 20)
+
+		for i := range 20 {
+			waitGroup.Go(func() {
+				response := l.login("john.doe@gmail.com", "wrong-password", fmt.Sprintf("198.51.100.%d", i))
+				utils.ThrowOnError(response.Body.Close())
+				statusCodes <- response.StatusCode
+			})
+		}
+
+		waitGroup.Wait()
+		close(statusCodes)
+
+		counts := map[int]int{}
+		for statusCode := range statusCodes {
+			counts[statusCode]++
+		}
+
+		l.Equal(map[int]int{409: 5, 429: 15}, counts)
+
+		loginLockoutsSchema := utils.GetOrThrow(l.loginLockoutDAO.FindAllBySubject(context.Background(),
+			daos.LoginLockoutSubjectTypeEmail, "john.doe@gmail.com"))
+		l.Require().Len(loginLockoutsSchema, 1)
+		l.Equal(int64(5), loginLockoutsSchema[0].FailedAttempts)
+	})
+}
+
+func TestLoginLockout(t *testing.T) {
+	suite.Run(t, new(LoginLockoutSuite))
+}
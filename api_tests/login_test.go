@@ -1,6 +1,7 @@
 package apitests_test
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"strings"
@@ -19,6 +20,7 @@ import (
 type LoginSuite struct {
 	suite.Suite
 	customerDAO     daos.CustomerDAO
+	refreshTokenDAO daos.RefreshTokenDAO
 	testEnvironment *testhelpers.TestEnvironment
 }
 
@@ -26,22 +28,25 @@ func (l *LoginSuite) SetupSuite() {
 	l.testEnvironment = testhelpers.NewTestEnvironment()
 	l.testEnvironment.Start()
 	l.customerDAO = daos.NewCustomerDAO(l.testEnvironment.PgxPool())
+	l.refreshTokenDAO = daos.NewRefreshTokenDAO(l.testEnvironment.PgxPool())
 }
 
 func (l *LoginSuite) SetupTest() {
-	l.customerDAO.DeleteAll()
+	utils.ThrowOnError(l.customerDAO.DeleteAll(context.Background()))
+	utils.ThrowOnError(l.testEnvironment.RedisClient().FlushAll(context.Background()).Err())
 }
 
 func (l *LoginSuite) Test1() {
 	l.Run("given that the customer is already signed up, when logging in, then returns 200", func() {
-		l.customerDAO.Create(daos.CustomerSchema{
+		utils.ThrowOnError(l.customerDAO.Create(context.Background(), daos.CustomerSchema{
 			Id:        uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
 			Name:      "John Doe",
 			Email:     "john.doe@gmail.com",
 			Password:  "$2a$10$asLIHej6kxd3Fsdc76QHieBugwCGvsYJeLiZmP1K7/t1GbIbUy.pK",
+			Status:    daos.CustomerStatusActive,
 			UpdatedAt: time.Now().UTC(),
 			CreatedAt: time.Now().UTC(),
-		})
+		}))
 
 		response := utils.GetOrThrow(l.testEnvironment.Client().Post(l.testEnvironment.BaseUrl()+"/v1/login", "application/json", strings.NewReader(`
 			{
@@ -54,13 +59,23 @@ func (l *LoginSuite) Test1() {
 		body := utils.ParseJSONBody[map[string]map[string]any](response.Body)
 		customerId := body["data"]["customerId"].(string)
 		accessToken := body["data"]["accessToken"].(string)
+		refreshToken := body["data"]["refreshToken"].(string)
 		l.True(utils.IsValidUUID(customerId))
 		l.NotEmpty(accessToken)
+		l.NotEmpty(refreshToken)
+
+		refreshTokensSchema := utils.GetOrThrow(l.refreshTokenDAO.FindAllByCustomerId(context.Background(), uuid.MustParse(customerId)))
+		l.Require().Len(refreshTokensSchema, 1)
+		l.Require().NotEqual(refreshToken, refreshTokensSchema[0].TokenHash)
+		l.Require().Nil(refreshTokensSchema[0].ReplacedBy)
+		l.Require().Nil(refreshTokensSchema[0].RevokedAt)
+		l.Require().WithinDuration(time.Now().UTC().Add(30*24*time.Hour), refreshTokensSchema[0].ExpiresAt, 5*time.Second)
 
 		token := utils.GetOrThrow(jwt.ParseWithClaims(accessToken, &usecases.JwtAccessTokenClaims{}, func(token *jwt.Token) (any, error) {
-			return []byte("81c4a8d5b2554de4ba736e93255ba633"), nil
-		}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()})))
+			return testhelpers.TestAccessTokenVerificationKey(), nil
+		}, jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg()})))
 
+		l.Require().Equal(testhelpers.TestAccessTokenSigningKeyId, token.Header["kid"])
 		claims := token.Claims.(*usecases.JwtAccessTokenClaims)
 		l.Require().Equal("f59207c8-e837-4159-b67d-78c716510747", claims.Subject)
 		l.Require().Equal([]string{"customer"}, claims.Roles)
@@ -71,13 +86,14 @@ func (l *LoginSuite) Test1() {
 
 func (l *LoginSuite) Test2() {
 	l.Run("given that the customer is already signed up, when logging in and password is incorrect, then returns 409", func() {
-		l.customerDAO.Create(daos.CustomerSchema{
+		utils.ThrowOnError(l.customerDAO.Create(context.Background(), daos.CustomerSchema{
 			Id:        uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
 			Name:      "John Doe",
 			Email:     "john.doe@gmail.com",
 			Password:  "$2a$10$asLIHej6kxd3Fsdc76QHieBugwCGvsYJeLiZmP1K7/t1GbIbUy.pK",
+			Status:    daos.CustomerStatusActive,
 			CreatedAt: time.Now().UTC(),
-		})
+		}))
 
 		response := utils.GetOrThrow(l.testEnvironment.Client().Post(l.testEnvironment.BaseUrl()+"/v1/login", "application/json", strings.NewReader(`
 			{
@@ -89,11 +105,17 @@ func (l *LoginSuite) Test2() {
 		body := utils.GetOrThrow(io.ReadAll(response.Body))
 
 		l.Equal(409, response.StatusCode)
-		l.JSONEq(`
+		l.Equal("application/problem+json", response.Header.Get("Content-Type"))
+		l.JSONEq(fmt.Sprintf(`
 			{
-				"message": "email or password is incorrect"
+				"type": "/problems/email_or_password_incorrect",
+				"title": "Conflict",
+				"status": 409,
+				"detail": "email or password is incorrect",
+				"instance": "%s",
+				"code": "email_or_password_incorrect"
 			}
-		`, string(body))
+		`, response.Header.Get("X-Request-Id")), string(body))
 	})
 }
 
@@ -109,11 +131,17 @@ func (l *LoginSuite) Test3() {
 		body := utils.GetOrThrow(io.ReadAll(response.Body))
 
 		l.Equal(409, response.StatusCode)
-		l.JSONEq(`
+		l.Equal("application/problem+json", response.Header.Get("Content-Type"))
+		l.JSONEq(fmt.Sprintf(`
 			{
-				"message": "email or password is incorrect"
+				"type": "/problems/email_or_password_incorrect",
+				"title": "Conflict",
+				"status": 409,
+				"detail": "email or password is incorrect",
+				"instance": "%s",
+				"code": "email_or_password_incorrect"
 			}
-		`, string(body))
+		`, response.Header.Get("X-Request-Id")), string(body))
 	})
 }
 
@@ -129,11 +157,17 @@ func (l *LoginSuite) Test4() {
 		body := utils.GetOrThrow(io.ReadAll(response.Body))
 
 		l.Equal(409, response.StatusCode)
-		l.JSONEq(`
+		l.Equal("application/problem+json", response.Header.Get("Content-Type"))
+		l.JSONEq(fmt.Sprintf(`
 			{
-				"message": "email address is invalid"
+				"type": "/problems/email_address_invalid",
+				"title": "Conflict",
+				"status": 409,
+				"detail": "email address is invalid",
+				"instance": "%s",
+				"code": "email_address_invalid"
 			}
-		`, string(body))
+		`, response.Header.Get("X-Request-Id")), string(body))
 	})
 }
 
@@ -142,9 +176,9 @@ func (l *LoginSuite) Test5() {
 		templates := []map[string]string{
 			{
 				"body": `{}`,
-				"error": `[
-					"email is required",
-					"password is required"
+				"errors": `[
+					{"field": "email", "message": "email is required"},
+					{"field": "password", "message": "password is required"}
 				]`,
 			},
 			{
@@ -152,9 +186,9 @@ func (l *LoginSuite) Test5() {
 					"email": null,
 					"password": null
 				}`,
-				"error": `[
-					"email is required",
-					"password is required"
+				"errors": `[
+					{"field": "email", "message": "email is required"},
+					{"field": "password", "message": "password is required"}
 				]`,
 			},
 			{
@@ -162,9 +196,9 @@ func (l *LoginSuite) Test5() {
 					"email": "",
 					"password": ""
 				}`,
-				"error": `[
-					"email must not be empty",
-					"password must not be empty"
+				"errors": `[
+					{"field": "email", "message": "email must not be empty"},
+					{"field": "password", "message": "password must not be empty"}
 				]`,
 			},
 			{
@@ -172,9 +206,9 @@ func (l *LoginSuite) Test5() {
 					"email": " ",
 					"password": " "
 				}`,
-				"error": `[
-					"email must not be empty",
-					"password must not be empty"
+				"errors": `[
+					{"field": "email", "message": "email must not be empty"},
+					{"field": "password", "message": "password must not be empty"}
 				]`,
 			},
 			{
@@ -182,9 +216,9 @@ func (l *LoginSuite) Test5() {
 					"email": 1,
 					"password": 1
 				}`,
-				"error": `[
-					"email must be string",
-					"password must be string"
+				"errors": `[
+					{"field": "email", "message": "email must be string"},
+					{"field": "password", "message": "password must be string"}
 				]`,
 			},
 			{
@@ -192,9 +226,9 @@ func (l *LoginSuite) Test5() {
 					"email": 1.5,
 					"password": 1.5
 				}`,
-				"error": `[
-					"email must be string",
-					"password must be string"
+				"errors": `[
+					{"field": "email", "message": "email must be string"},
+					{"field": "password", "message": "password must be string"}
 				]`,
 			},
 			{
@@ -202,9 +236,9 @@ func (l *LoginSuite) Test5() {
 					"email": -1,
 					"password": -1
 				}`,
-				"error": `[
-					"email must be string",
-					"password must be string"
+				"errors": `[
+					{"field": "email", "message": "email must be string"},
+					{"field": "password", "message": "password must be string"}
 				]`,
 			},
 			{
@@ -212,9 +246,9 @@ func (l *LoginSuite) Test5() {
 					"email": true,
 					"password": true
 				}`,
-				"error": `[
-					"email must be string",
-					"password must be string"
+				"errors": `[
+					{"field": "email", "message": "email must be string"},
+					{"field": "password", "message": "password must be string"}
 				]`,
 			},
 			{
@@ -222,9 +256,9 @@ func (l *LoginSuite) Test5() {
 					"email": {},
 					"password": {}
 				}`,
-				"error": `[
-					"email must be string",
-					"password must be string"
+				"errors": `[
+					{"field": "email", "message": "email must be string"},
+					{"field": "password", "message": "password must be string"}
 				]`,
 			},
 			{
@@ -232,9 +266,9 @@ func (l *LoginSuite) Test5() {
 					"email": [],
 					"password": []
 				}`,
-				"error": `[
-					"email must be string",
-					"password must be string"
+				"errors": `[
+					{"field": "email", "message": "email must be string"},
+					{"field": "password", "message": "password must be string"}
 				]`,
 			},
 		}
@@ -246,15 +280,43 @@ func (l *LoginSuite) Test5() {
 			body := utils.GetOrThrow(io.ReadAll(response.Body))
 
 			l.Equal(400, response.StatusCode)
+			l.Equal("application/problem+json", response.Header.Get("Content-Type"))
 			l.JSONEq(fmt.Sprintf(`
 				{
-					"message": %s
+					"type": "/problems/validation_failed",
+					"title": "Bad Request",
+					"status": 400,
+					"detail": "the request has one or more invalid fields",
+					"instance": "%s",
+					"code": "validation_failed",
+					"errors": %s
 				}
-			`, template["error"]), string(body))
+			`, response.Header.Get("X-Request-Id"), template["errors"]), string(body))
 		}
 	})
 }
 
+func (l *LoginSuite) Test6() {
+	l.Run("when logging in and body is not json, then returns 415", func() {
+		response := utils.GetOrThrow(l.testEnvironment.Client().Post(l.testEnvironment.BaseUrl()+"/v1/login", "text/plain",
+			strings.NewReader("john.doe@gmail.com")))
+
+		body := utils.GetOrThrow(io.ReadAll(response.Body))
+
+		l.Equal(415, response.StatusCode)
+		l.Equal("application/problem+json", response.Header.Get("Content-Type"))
+		l.JSONEq(fmt.Sprintf(`
+			{
+				"type": "about:blank",
+				"title": "Unsupported Media Type",
+				"status": 415,
+				"instance": "%s",
+				"code": "unsupported_media_type"
+			}
+		`, response.Header.Get("X-Request-Id")), string(body))
+	})
+}
+
 func TestLogin(t *testing.T) {
 	suite.Run(t, new(LoginSuite))
 }
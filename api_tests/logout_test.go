@@ -0,0 +1,181 @@
+package apitests_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	testhelpers "github.com/gsaaraujo/pay-bank-api/internal/test_helpers"
+	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
+	"github.com/gsaaraujo/pay-bank-api/internal/utils"
+	"github.com/stretchr/testify/suite"
+)
+
+type LogoutSuite struct {
+	suite.Suite
+	customerDAO                   daos.CustomerDAO
+	revokeCustomerSessionsUsecase usecases.RevokeCustomerSessionsUsecase
+	testEnvironment               *testhelpers.TestEnvironment
+}
+
+func (l *LogoutSuite) SetupSuite() {
+	l.testEnvironment = testhelpers.NewTestEnvironment()
+	l.testEnvironment.Start()
+	l.customerDAO = daos.NewCustomerDAO(l.testEnvironment.PgxPool())
+	l.revokeCustomerSessionsUsecase = usecases.NewRevokeCustomerSessionsUsecase(l.testEnvironment.PgxPool(), l.customerDAO,
+		daos.NewRefreshTokenDAO(l.testEnvironment.PgxPool()), daos.NewRevokedAccessTokenDAO(l.testEnvironment.RedisClient()))
+}
+
+func (l *LogoutSuite) SetupTest() {
+	utils.ThrowOnError(l.customerDAO.DeleteAll(context.Background()))
+	utils.ThrowOnError(l.testEnvironment.RedisClient().FlushAll(context.Background()).Err())
+
+	response := utils.GetOrThrow(l.testEnvironment.Client().Post(l.testEnvironment.BaseUrl()+"/v1/sign-up", "application/json",
+		strings.NewReader(`{"name": "John Doe", "email": "john.doe@gmail.com", "password": "S3cret-pass"}`)))
+	utils.ThrowOnError(response.Body.Close())
+	l.Require().Equal(204, response.StatusCode)
+}
+
+func (l *LogoutSuite) login() (string, string) {
+	response := utils.GetOrThrow(l.testEnvironment.Client().Post(l.testEnvironment.BaseUrl()+"/v1/login", "application/json",
+		strings.NewReader(`{"email": "john.doe@gmail.com", "password": "S3cret-pass"}`)))
+
+	l.Require().Equal(200, response.StatusCode)
+	body := utils.ParseJSONBody[map[string]map[string]any](response.Body)
+	return body["data"]["accessToken"].(string), body["data"]["refreshToken"].(string)
+}
+
+func (l *LogoutSuite) doWithAccessToken(method string, path string, accessToken string) *http.Response {
+	request := utils.GetOrThrow(http.NewRequest(method, l.testEnvironment.BaseUrl()+path, nil))
+	request.Header.Add("Authorization", "Bearer "+accessToken)
+
+	return utils.GetOrThrow(l.testEnvironment.Client().Do(request))
+}
+
+func (l *LogoutSuite) refresh(refreshToken string) int {
+	response := utils.GetOrThrow(l.testEnvironment.Client().Post(l.testEnvironment.BaseUrl()+"/v1/token/refresh", "application/json",
+		strings.NewReader(fmt.Sprintf(`{"refreshToken": "%s"}`, refreshToken))))
+	utils.ThrowOnError(response.Body.Close())
+
+	return response.StatusCode
+}
+
+func (l *LogoutSuite) requireAccessTokenRevoked(accessToken string) {
+	response := l.doWithAccessToken("GET", "/v1/transactions-history", accessToken)
+	body := utils.GetOrThrow(io.ReadAll(response.Body))
+
+	l.Require().Equal(401, response.StatusCode)
+	l.Require().JSONEq(fmt.Sprintf(`
+		{
+			"type": "/problems/access_token_revoked",
+			"title": "Unauthorized",
+			"status": 401,
+			"detail": "access token has been revoked",
+			"instance": "%s",
+			"code": "access_token_revoked"
+		}
+	`, response.Header.Get("X-Request-Id")), string(body))
+}
+
+func (l *LogoutSuite) Test1() {
+	l.Run("given a logged in customer, when logging out, then returns 204 and revokes the access and refresh tokens", func() {
+		accessToken, refreshToken := l.login()
+
+		response := l.doWithAccessToken("POST", "/v1/logout", accessToken)
+		utils.ThrowOnError(response.Body.Close())
+
+		l.Equal(204, response.StatusCode)
+		l.requireAccessTokenRevoked(accessToken)
+		l.Equal(401, l.refresh(refreshToken))
+
+		keys := utils.GetOrThrow(l.testEnvironment.RedisClient().Keys(context.Background(), "revoked_access_tokens:*").Result())
+		l.Require().Len(keys, 1)
+		ttl := utils.GetOrThrow(l.testEnvironment.RedisClient().TTL(context.Background(), keys[0]).Result())
+		l.InDelta(30*time.Minute, ttl, float64(5*time.Second))
+	})
+}
+
+func (l *LogoutSuite) Test2() {
+	l.Run("given two sessions, when logging out of one, then the other one keeps working", func() {
+		accessToken, _ := l.login()
+		otherAccessToken, otherRefreshToken := l.login()
+
+		response := l.doWithAccessToken("POST", "/v1/logout", accessToken)
+		utils.ThrowOnError(response.Body.Close())
+		l.Require().Equal(204, response.StatusCode)
+
+		response = l.doWithAccessToken("GET", "/v1/transactions-history", otherAccessToken)
+		utils.ThrowOnError(response.Body.Close())
+		l.Equal(200, response.StatusCode)
+		l.Equal(200, l.refresh(otherRefreshToken))
+	})
+}
+
+func (l *LogoutSuite) Test3() {
+	l.Run("given two sessions, when revoking every session of the customer, then no token works until logging in again", func() {
+		accessToken, refreshToken := l.login()
+		otherAccessToken, otherRefreshToken := l.login()
+
+		customerSchema := utils.GetOrThrow(l.customerDAO.FindOneByEmail(context.Background(), "john.doe@gmail.com"))
+		utils.ThrowOnError(l.revokeCustomerSessionsUsecase.Execute(context.Background(), usecases.RevokeCustomerSessionsUsecaseInput{
+			CustomerId: customerSchema.Id,
+		}))
+
+		l.requireAccessTokenRevoked(accessToken)
+		l.requireAccessTokenRevoked(otherAccessToken)
+		l.Equal(401, l.refresh(refreshToken))
+		l.Equal(401, l.refresh(otherRefreshToken))
+
+		newAccessToken, _ := l.login()
+		response := l.doWithAccessToken("GET", "/v1/transactions-history", newAccessToken)
+		utils.ThrowOnError(response.Body.Close())
+		l.Equal(200, response.StatusCode)
+	})
+}
+
+func (l *LogoutSuite) Test4() {
+	l.Run("when logging out without an access token, then returns 401", func() {
+		response := utils.GetOrThrow(l.testEnvironment.Client().Post(l.testEnvironment.BaseUrl()+"/v1/logout", "application/json", nil))
+		body := utils.GetOrThrow(io.ReadAll(response.Body))
+
+		l.Equal(401, response.StatusCode)
+		l.JSONEq(fmt.Sprintf(`
+			{
+				"type": "about:blank",
+				"title": "Unauthorized",
+				"status": 401,
+				"instance": "%s",
+				"code": "unauthorized"
+			}
+		`, response.Header.Get("X-Request-Id")), string(body))
+	})
+}
+
+func (l *LogoutSuite) Test5() {
+	l.Run("given an access token refreshed from the session, when logging out with the first one, then both are revoked", func() {
+		accessToken, refreshToken := l.login()
+
+		response := utils.GetOrThrow(l.testEnvironment.Client().Post(l.testEnvironment.BaseUrl()+"/v1/token/refresh", "application/json",
+			strings.NewReader(fmt.Sprintf(`{"refreshToken": "%s"}`, refreshToken))))
+		l.Require().Equal(200, response.StatusCode)
+		body := utils.ParseJSONBody[map[string]map[string]any](response.Body)
+		refreshedAccessToken := body["data"]["accessToken"].(string)
+
+		response = l.doWithAccessToken("POST", "/v1/logout", accessToken)
+		utils.ThrowOnError(response.Body.Close())
+		l.Require().Equal(204, response.StatusCode)
+
+		l.requireAccessTokenRevoked(accessToken)
+		l.requireAccessTokenRevoked(refreshedAccessToken)
+		l.Equal(401, l.refresh(body["data"]["refreshToken"].(string)))
+	})
+}
+
+func TestLogout(t *testing.T) {
+	suite.Run(t, new(LogoutSuite))
+}
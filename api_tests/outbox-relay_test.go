@@ -0,0 +1,278 @@
+package apitests_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
+	testhelpers "github.com/gsaaraujo/pay-bank-api/internal/test_helpers"
+	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
+	"github.com/gsaaraujo/pay-bank-api/internal/utils"
+	"github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/suite"
+)
+
+type OutboxRelaySuite struct {
+	suite.Suite
+	customerDAO           daos.CustomerDAO
+	accountDAO            daos.AccountDAO
+	outboxEventDAO        daos.OutboxEventDAO
+	eventPublisherGateway *gateways.RabbitmqEventPublisherGateway
+	channel               *amqp091.Channel
+	queue                 amqp091.Queue
+	testEnvironment       *testhelpers.TestEnvironment
+}
+
+func (o *OutboxRelaySuite) SetupSuite() {
+	o.testEnvironment = testhelpers.NewTestEnvironment()
+	o.testEnvironment.Start()
+	o.customerDAO = daos.NewCustomerDAO(o.testEnvironment.PgxPool())
+	o.accountDAO = daos.NewAccountDAO(o.testEnvironment.PgxPool())
+	o.outboxEventDAO = daos.NewOutboxEventDAO(o.testEnvironment.PgxPool())
+	o.eventPublisherGateway = gateways.NewRabbitmqEventPublisherGateway(o.testEnvironment.RabbitmqContainerUrl(), nil)
+
+	o.channel = utils.GetOrThrow(o.testEnvironment.RabbitmqConn().Channel())
+	utils.ThrowOnError(o.channel.ExchangeDeclare(gateways.EventsExchange, "topic", true, false, false, false, nil))
+	o.queue = utils.GetOrThrow(o.channel.QueueDeclare("", false, true, true, false, nil))
+	utils.ThrowOnError(o.channel.QueueBind(o.queue.Name, "#", gateways.EventsExchange, false, nil))
+}
+
+func (o *OutboxRelaySuite) TearDownSuite() {
+	utils.ThrowOnError(o.eventPublisherGateway.Close())
+	utils.ThrowOnError(o.channel.Close())
+}
+
+func (o *OutboxRelaySuite) SetupTest() {
+	utils.ThrowOnError(o.customerDAO.DeleteAll(context.Background()))
+	utils.ThrowOnError(o.outboxEventDAO.DeleteAll(context.Background()))
+	_ = utils.GetOrThrow(o.channel.QueuePurge(o.queue.Name, false))
+	o.testEnvironment.ClearNotifications()
+}
+
+func (o *OutboxRelaySuite) signUp(name string, email string) *daos.CustomerSchema {
+	response := utils.GetOrThrow(o.testEnvironment.Client().Post(o.testEnvironment.BaseUrl()+"/v1/sign-up", "application/json",
+		strings.NewReader(`{"name": "`+name+`", "email": "`+email+`", "password": "S3cret-pass"}`)))
+	utils.ThrowOnError(response.Body.Close())
+	o.Require().Equal(204, response.StatusCode)
+
+	o.testEnvironment.VerifyEmail(email)
+
+	return utils.GetOrThrow(o.customerDAO.FindOneByEmail(context.Background(), email))
+}
+
+func (o *OutboxRelaySuite) relay() usecases.RelayOutboxEventsUsecaseOutput {
+	relayOutboxEventsUsecase := usecases.NewRelayOutboxEventsUsecase(o.testEnvironment.PgxPool(), o.outboxEventDAO, o.eventPublisherGateway)
+
+	return utils.GetOrThrow(relayOutboxEventsUsecase.Execute(context.Background(), usecases.RelayOutboxEventsUsecaseInput{
+		BatchSize: 100,
+	}))
+}
+
+func (o *OutboxRelaySuite) receive() amqp091.Delivery {
+	for range 50 {
+		delivery, ok, err := o.channel.Get(o.queue.Name, true)
+		utils.ThrowOnError(err)
+
+		if ok {
+			return delivery
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	panic("no event was published")
+}
+
+func (o *OutboxRelaySuite) Test1() {
+	o.Run("when signing up, then writes a customer signed up event that is published once relayed", func() {
+		customerSchema := o.signUp("John Doe", "john.doe@gmail.com")
+		accountSchema := utils.GetOrThrow(o.accountDAO.FindOneByCustomerId(context.Background(), customerSchema.Id))
+
+		outboxEventsSchema := utils.GetOrThrow(o.outboxEventDAO.FindAll(context.Background()))
+		o.Require().Len(outboxEventsSchema, 1)
+		o.Equal(usecases.EventTypeCustomerSignedUp, outboxEventsSchema[0].EventType)
+		o.Equal(customerSchema.Id, outboxEventsSchema[0].AggregateId)
+		o.Nil(outboxEventsSchema[0].PublishedAt)
+
+		o.Equal(1, o.relay().Published)
+
+		delivery := o.receive()
+		o.Equal(outboxEventsSchema[0].Id.String(), delivery.MessageId)
+		o.Equal(usecases.EventTypeCustomerSignedUp, delivery.RoutingKey)
+		o.Equal(usecases.EventTypeCustomerSignedUp, delivery.Type)
+		o.Equal(amqp091.Persistent, delivery.DeliveryMode)
+
+		var event usecases.CustomerSignedUpEvent
+		o.Require().NoError(json.Unmarshal(delivery.Body, &event))
+		o.Equal(customerSchema.Id, event.CustomerId)
+		o.Equal(accountSchema.Id, event.AccountId)
+		o.Equal("John Doe", event.Name)
+		o.Equal("john.doe@gmail.com", event.Email)
+
+		outboxEventsSchema = utils.GetOrThrow(o.outboxEventDAO.FindAll(context.Background()))
+		o.Require().NotNil(outboxEventsSchema[0].PublishedAt)
+
+		o.Equal(0, o.relay().Published)
+	})
+}
+
+func (o *OutboxRelaySuite) Test2() {
+	o.Run("when transferring, then writes a transfer completed event that is published once relayed", func() {
+		sender := o.signUp("John Doe", "john.doe@gmail.com")
+		receiver := o.signUp("Richard Smith", "richard.smith@gmail.com")
+		o.Equal(2, o.relay().Published)
+		o.receive()
+		o.receive()
+
+		request := utils.GetOrThrow(http.NewRequest("POST", o.testEnvironment.BaseUrl()+"/v1/transfer",
+			strings.NewReader(`{"customerReceiverId": "`+receiver.Id.String()+`", "amount": 2500}`)))
+		request.Header.Add("Content-Type", "application/json")
+		request.Header.Add("Authorization", "Bearer "+testhelpers.TestGenerateAccessToken(sender.Id))
+		request.Header.Add("Idempotency-Key", uuid.New().String())
+
+		response := utils.GetOrThrow(o.testEnvironment.Client().Do(request))
+		utils.ThrowOnError(response.Body.Close())
+		o.Require().Equal(201, response.StatusCode)
+
+		o.Equal(1, o.relay().Published)
+
+		delivery := o.receive()
+		o.Equal(usecases.EventTypeTransferCompleted, delivery.RoutingKey)
+
+		var event usecases.TransferCompletedEvent
+		o.Require().NoError(json.Unmarshal(delivery.Body, &event))
+		o.Equal(sender.Id, event.SenderCustomerId)
+		o.Equal(receiver.Id, event.ReceiverCustomerId)
+		o.Equal(int64(2500), event.Amount)
+		o.NotEqual(uuid.Nil, event.TransactionId)
+	})
+}
+
+func (o *OutboxRelaySuite) Test3() {
+	o.Run("given that the sender has not enough balance, when transferring, then writes no event", func() {
+		sender := o.signUp("John Doe", "john.doe@gmail.com")
+		receiver := o.signUp("Richard Smith", "richard.smith@gmail.com")
+		o.Equal(2, o.relay().Published)
+
+		request := utils.GetOrThrow(http.NewRequest("POST", o.testEnvironment.BaseUrl()+"/v1/transfer",
+			strings.NewReader(`{"customerReceiverId": "`+receiver.Id.String()+`", "amount": 100001}`)))
+		request.Header.Add("Content-Type", "application/json")
+		request.Header.Add("Authorization", "Bearer "+testhelpers.TestGenerateAccessToken(sender.Id))
+		request.Header.Add("Idempotency-Key", uuid.New().String())
+
+		response := utils.GetOrThrow(o.testEnvironment.Client().Do(request))
+		utils.ThrowOnError(response.Body.Close())
+		o.Require().Equal(409, response.StatusCode)
+
+		o.Equal(0, o.relay().Published)
+	})
+}
+
+func (o *OutboxRelaySuite) Test4() {
+	o.Run("given that no transfer worker has ever run, when relaying a transfer requested event, then its queue keeps it", func() {
+		sender := o.signUp("John Doe", "john.doe@gmail.com")
+		receiver := o.signUp("Richard Smith", "richard.smith@gmail.com")
+		o.Equal(2, o.relay().Published)
+
+		transferQueue := gateways.EventQueue{
+			Name:       "pay-bank.transfers-" + uuid.NewString(),
+			EventTypes: []string{usecases.EventTypeTransferRequested},
+		}
+		defer func() {
+			_ = utils.GetOrThrow(o.channel.QueueDelete(transferQueue.Name, false, false, false))
+		}()
+
+		eventPublisherGateway := gateways.NewRabbitmqEventPublisherGateway(o.testEnvironment.RabbitmqContainerUrl(), []gateways.EventQueue{transferQueue})
+		defer func() {
+			utils.ThrowOnError(eventPublisherGateway.Close())
+		}()
+
+		request := utils.GetOrThrow(http.NewRequest("POST", o.testEnvironment.BaseUrl()+"/v1/transfer",
+			strings.NewReader(`{"customerReceiverId": "`+receiver.Id.String()+`", "amount": 2500}`)))
+		request.Header.Add("Content-Type", "application/json")
+		request.Header.Add("Authorization", "Bearer "+testhelpers.TestGenerateAccessToken(sender.Id))
+		request.Header.Add("Idempotency-Key", uuid.New().String())
+		request.Header.Add("Prefer", "respond-async")
+
+		response := utils.GetOrThrow(o.testEnvironment.Client().Do(request))
+		utils.ThrowOnError(response.Body.Close())
+		o.Require().Equal(202, response.StatusCode)
+
+		relayOutboxEventsUsecase := usecases.NewRelayOutboxEventsUsecase(o.testEnvironment.PgxPool(), o.outboxEventDAO, eventPublisherGateway)
+		output := utils.GetOrThrow(relayOutboxEventsUsecase.Execute(context.Background(), usecases.RelayOutboxEventsUsecaseInput{
+			BatchSize: 100,
+		}))
+		o.Equal(1, output.Published)
+
+		delivery, ok, err := o.channel.Get(transferQueue.Name, true)
+		o.Require().NoError(err)
+		o.Require().True(ok)
+		o.Equal(usecases.EventTypeTransferRequested, delivery.RoutingKey)
+	})
+}
+
+func (o *OutboxRelaySuite) Test5() {
+	o.Run("given a handler that keeps failing, when consuming, then the event is requeued with its attempt counted", func() {
+		retriedQueue := gateways.EventQueue{
+			Name:       "pay-bank.retried-" + uuid.NewString(),
+			EventTypes: []string{usecases.EventTypeCustomerSignedUp},
+		}
+		defer func() {
+			_ = utils.GetOrThrow(o.channel.QueueDelete(retriedQueue.Name, false, false, false))
+		}()
+
+		eventPublisherGateway := gateways.NewRabbitmqEventPublisherGateway(o.testEnvironment.RabbitmqContainerUrl(), []gateways.EventQueue{retriedQueue})
+		defer func() {
+			utils.ThrowOnError(eventPublisherGateway.Close())
+		}()
+
+		event := gateways.Event{
+			Id:         uuid.New(),
+			Type:       usecases.EventTypeCustomerSignedUp,
+			Payload:    []byte(`{}`),
+			OccurredAt: time.Now().UTC().Truncate(time.Second),
+		}
+		o.Require().NoError(eventPublisherGateway.Publish(context.Background(), event))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		consumed := []gateways.Event{}
+		eventConsumerGateway := gateways.NewRabbitmqEventConsumerGateway(o.testEnvironment.RabbitmqContainerUrl(), 1)
+		err := eventConsumerGateway.Consume(ctx, retriedQueue, func(ctx context.Context, consumedEvent gateways.Event) error {
+			consumed = append(consumed, consumedEvent)
+
+			if consumedEvent.Attempt < 3 {
+				return errors.New("handling failed")
+			}
+
+			cancel()
+			return nil
+		})
+		o.Require().NoError(err)
+
+		o.Require().Len(consumed, 3)
+		for i, consumedEvent := range consumed {
+			o.Equal(i+1, consumedEvent.Attempt)
+			o.Equal(event.Id, consumedEvent.Id)
+			o.Equal(event.Type, consumedEvent.Type)
+			o.Equal(event.Payload, consumedEvent.Payload)
+			o.Equal(event.OccurredAt, consumedEvent.OccurredAt.UTC())
+		}
+
+		_, ok, err := o.channel.Get(retriedQueue.Name, true)
+		o.Require().NoError(err)
+		o.False(ok)
+	})
+}
+
+func TestOutboxRelay(t *testing.T) {
+	suite.Run(t, new(OutboxRelaySuite))
+}
@@ -0,0 +1,275 @@
+package apitests_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	testhelpers "github.com/gsaaraujo/pay-bank-api/internal/test_helpers"
+	"github.com/gsaaraujo/pay-bank-api/internal/utils"
+	"github.com/stretchr/testify/suite"
+)
+
+type PasswordSuite struct {
+	suite.Suite
+	customerDAO           daos.CustomerDAO
+	passwordResetTokenDAO daos.PasswordResetTokenDAO
+	testEnvironment       *testhelpers.TestEnvironment
+}
+
+func (p *PasswordSuite) SetupSuite() {
+	p.testEnvironment = testhelpers.NewTestEnvironment()
+	p.testEnvironment.Start()
+	p.customerDAO = daos.NewCustomerDAO(p.testEnvironment.PgxPool())
+	p.passwordResetTokenDAO = daos.NewPasswordResetTokenDAO(p.testEnvironment.PgxPool())
+}
+
+func (p *PasswordSuite) SetupTest() {
+	utils.ThrowOnError(p.customerDAO.DeleteAll(context.Background()))
+	utils.ThrowOnError(p.passwordResetTokenDAO.DeleteAll(context.Background()))
+	utils.ThrowOnError(p.testEnvironment.RedisClient().FlushAll(context.Background()).Err())
+	p.testEnvironment.ClearNotifications()
+
+	utils.ThrowOnError(p.customerDAO.Create(context.Background(), daos.CustomerSchema{
+		Id:        uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
+		Name:      "John Doe",
+		Email:     "john.doe@gmail.com",
+		Password:  "$2a$10$asLIHej6kxd3Fsdc76QHieBugwCGvsYJeLiZmP1K7/t1GbIbUy.pK",
+		Status:    daos.CustomerStatusActive,
+		UpdatedAt: time.Now().UTC(),
+		CreatedAt: time.Now().UTC(),
+	}))
+}
+
+func (p *PasswordSuite) do(method string, path string, accessToken string, body string) (*http.Response, string) {
+	request := utils.GetOrThrow(http.NewRequest(method, p.testEnvironment.BaseUrl()+path, strings.NewReader(body)))
+	request.Header.Add("Content-Type", "application/json")
+
+	if accessToken != "" {
+		request.Header.Add("Authorization", "Bearer "+accessToken)
+	}
+
+	response := utils.GetOrThrow(p.testEnvironment.Client().Do(request))
+	responseBody := utils.GetOrThrow(io.ReadAll(response.Body))
+
+	return response, string(responseBody)
+}
+
+func (p *PasswordSuite) login(password string) (*http.Response, map[string]map[string]any) {
+	response := utils.GetOrThrow(p.testEnvironment.Client().Post(p.testEnvironment.BaseUrl()+"/v1/login", "application/json",
+		strings.NewReader(fmt.Sprintf(`{"email": "john.doe@gmail.com", "password": "%s"}`, password))))
+
+	return response, utils.ParseJSONBody[map[string]map[string]any](response.Body)
+}
+
+func (p *PasswordSuite) forgotPassword() string {
+	response, _ := p.do("POST", "/v1/password/forgot", "", `{"email": "john.doe@gmail.com"}`)
+	p.Require().Equal(202, response.StatusCode)
+
+	notifications := p.testEnvironment.Notifications()
+	p.Require().NotEmpty(notifications)
+
+	token := regexp.MustCompile(`new password: (\S+)`).FindStringSubmatch(notifications[len(notifications)-1].Body)
+	p.Require().Len(token, 2)
+
+	return token[1]
+}
+
+func (p *PasswordSuite) Test1() {
+	p.Run("when asking for a reset, then sends a token that sets a new password once", func() {
+		token := p.forgotPassword()
+
+		notifications := p.testEnvironment.Notifications()
+		p.Require().Len(notifications, 1)
+		p.Equal("john.doe@gmail.com", notifications[0].To)
+		p.Equal("Reset your password", notifications[0].Subject)
+		p.Contains(notifications[0].Body, "It expires in 30 minutes.")
+
+		passwordResetTokensSchema := utils.GetOrThrow(p.passwordResetTokenDAO.FindAllByCustomerId(context.Background(),
+			uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747")))
+		p.Require().Len(passwordResetTokensSchema, 1)
+		p.NotEqual(token, passwordResetTokensSchema[0].TokenHash)
+		p.WithinDuration(time.Now().UTC().Add(30*time.Minute), passwordResetTokensSchema[0].ExpiresAt, 5*time.Second)
+
+		response, _ := p.do("POST", "/v1/password/reset", "", fmt.Sprintf(`{"token": "%s", "password": "N3w-password"}`, token))
+		p.Equal(204, response.StatusCode)
+
+		response, _ = p.login("123456")
+		p.Equal(409, response.StatusCode)
+
+		response, _ = p.login("N3w-password")
+		p.Equal(200, response.StatusCode)
+
+		response, body := p.do("POST", "/v1/password/reset", "", fmt.Sprintf(`{"token": "%s", "password": "0ther-Password"}`, token))
+		p.Equal(400, response.StatusCode)
+		p.JSONEq(fmt.Sprintf(`
+			{
+				"type": "/problems/password_reset_token_invalid",
+				"title": "Bad Request",
+				"status": 400,
+				"detail": "password reset token is invalid or expired",
+				"instance": "%s",
+				"code": "password_reset_token_invalid"
+			}
+		`, response.Header.Get("X-Request-Id")), body)
+	})
+}
+
+func (p *PasswordSuite) Test2() {
+	p.Run("given an unknown email, when asking for a reset, then returns 202 without sending anything", func() {
+		response, body := p.do("POST", "/v1/password/forgot", "", `{"email": "richard.smith@gmail.com"}`)
+
+		p.Equal(202, response.StatusCode)
+		p.Equal("", body)
+		p.Empty(p.testEnvironment.Notifications())
+	})
+}
+
+func (p *PasswordSuite) Test3() {
+	p.Run("given a reset was asked twice, when using the first token, then returns 400", func() {
+		firstToken := p.forgotPassword()
+		secondToken := p.forgotPassword()
+
+		response, _ := p.do("POST", "/v1/password/reset", "", fmt.Sprintf(`{"token": "%s", "password": "N3w-password"}`, firstToken))
+		p.Equal(400, response.StatusCode)
+
+		response, _ = p.do("POST", "/v1/password/reset", "", fmt.Sprintf(`{"token": "%s", "password": "N3w-password"}`, secondToken))
+		p.Equal(204, response.StatusCode)
+	})
+}
+
+func (p *PasswordSuite) Test4() {
+	p.Run("given an expired token or a password breaking the policy, when resetting, then the password is kept", func() {
+		token := p.forgotPassword()
+
+		response, body := p.do("POST", "/v1/password/reset", "", fmt.Sprintf(`{"token": "%s", "password": "John-2024"}`, token))
+		p.Equal(400, response.StatusCode)
+		p.JSONEq(fmt.Sprintf(`
+			{
+				"type": "/problems/validation_failed",
+				"title": "Bad Request",
+				"status": 400,
+				"detail": "the request has one or more invalid fields",
+				"instance": "%s",
+				"code": "validation_failed",
+				"errors": [
+					{"field": "password", "message": "password must not contain your name or email address"}
+				]
+			}
+		`, response.Header.Get("X-Request-Id")), body)
+
+		_, err := p.testEnvironment.PgxPool().Exec(context.Background(),
+			"UPDATE password_reset_tokens SET expires_at = $1", time.Now().UTC().Add(-time.Minute))
+		p.Require().NoError(err)
+
+		response, _ = p.do("POST", "/v1/password/reset", "", fmt.Sprintf(`{"token": "%s", "password": "N3w-password"}`, token))
+		p.Equal(400, response.StatusCode)
+
+		response, _ = p.login("123456")
+		p.Equal(200, response.StatusCode)
+	})
+}
+
+func (p *PasswordSuite) Test5() {
+	p.Run("given a signed in customer, when resetting the password, then the existing sessions are revoked", func() {
+		response, loginBody := p.login("123456")
+		p.Require().Equal(200, response.StatusCode)
+
+		response, _ = p.do("POST", "/v1/password/reset", "", fmt.Sprintf(`{"token": "%s", "password": "N3w-password"}`, p.forgotPassword()))
+		p.Require().Equal(204, response.StatusCode)
+
+		response, body := p.do("POST", "/v1/token/refresh", "", fmt.Sprintf(`{"refreshToken": "%s"}`, loginBody["data"]["refreshToken"]))
+		p.Equal(401, response.StatusCode)
+		p.Contains(body, `"code":"refresh_token_invalid"`)
+
+		response, body = p.do("POST", "/v1/logout", loginBody["data"]["accessToken"].(string), "")
+		p.Equal(401, response.StatusCode)
+		p.Contains(body, `"code":"access_token_revoked"`)
+	})
+}
+
+func (p *PasswordSuite) Test6() {
+	p.Run("when changing the password, then requires the current one and revokes the existing sessions", func() {
+		response, loginBody := p.login("123456")
+		p.Require().Equal(200, response.StatusCode)
+		accessToken := loginBody["data"]["accessToken"].(string)
+
+		response, body := p.do("PUT", "/v1/me/password", accessToken, `{"currentPassword": "wrong", "newPassword": "N3w-password"}`)
+		p.Equal(409, response.StatusCode)
+		p.JSONEq(fmt.Sprintf(`
+			{
+				"type": "/problems/current_password_incorrect",
+				"title": "Conflict",
+				"status": 409,
+				"detail": "current password is incorrect",
+				"instance": "%s",
+				"code": "current_password_incorrect"
+			}
+		`, response.Header.Get("X-Request-Id")), body)
+
+		response, body = p.do("PUT", "/v1/me/password", accessToken, `{"currentPassword": "123456", "newPassword": "Qwerty123"}`)
+		p.Equal(400, response.StatusCode)
+		p.JSONEq(fmt.Sprintf(`
+			{
+				"type": "/problems/validation_failed",
+				"title": "Bad Request",
+				"status": 400,
+				"detail": "the request has one or more invalid fields",
+				"instance": "%s",
+				"code": "validation_failed",
+				"errors": [
+					{"field": "newPassword", "message": "password has appeared in a data breach and cannot be used"}
+				]
+			}
+		`, response.Header.Get("X-Request-Id")), body)
+
+		response, _ = p.do("PUT", "/v1/me/password", accessToken, `{"currentPassword": "123456", "newPassword": "N3w-password"}`)
+		p.Equal(204, response.StatusCode)
+
+		response, body = p.do("PUT", "/v1/me/password", accessToken, `{"currentPassword": "N3w-password", "newPassword": "0ther-Password"}`)
+		p.Equal(401, response.StatusCode)
+		p.Contains(body, `"code":"access_token_revoked"`)
+
+		response, loginBody = p.login("N3w-password")
+		p.Require().Equal(200, response.StatusCode)
+
+		response, _ = p.do("PUT", "/v1/me/password", loginBody["data"]["accessToken"].(string),
+			`{"currentPassword": "N3w-password", "newPassword": "0ther-Password"}`)
+		p.Equal(204, response.StatusCode)
+	})
+}
+
+func (p *PasswordSuite) Test7() {
+	p.Run("when the request is invalid, then returns 400", func() {
+		response, body := p.do("POST", "/v1/password/reset", "", `{"token": "", "password": 1}`)
+		p.Equal(400, response.StatusCode)
+		p.JSONEq(fmt.Sprintf(`
+			{
+				"type": "/problems/validation_failed",
+				"title": "Bad Request",
+				"status": 400,
+				"detail": "the request has one or more invalid fields",
+				"instance": "%s",
+				"code": "validation_failed",
+				"errors": [
+					{"field": "token", "message": "token must not be empty"},
+					{"field": "password", "message": "password must be string"}
+				]
+			}
+		`, response.Header.Get("X-Request-Id")), body)
+
+		response, _ = p.do("PUT", "/v1/me/password", "", `{"currentPassword": "123456", "newPassword": "N3w-password"}`)
+		p.Equal(401, response.StatusCode)
+	})
+}
+
+func TestPassword(t *testing.T) {
+	suite.Run(t, new(PasswordSuite))
+}
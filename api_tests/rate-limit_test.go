@@ -0,0 +1,162 @@
+package apitests_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	testhelpers "github.com/gsaaraujo/pay-bank-api/internal/test_helpers"
+	"github.com/gsaaraujo/pay-bank-api/internal/utils"
+	"github.com/stretchr/testify/suite"
+)
+
+type RateLimitSuite struct {
+	suite.Suite
+	customerDAO     daos.CustomerDAO
+	testEnvironment *testhelpers.TestEnvironment
+}
+
+func (r *RateLimitSuite) SetupSuite() {
+	r.testEnvironment = testhelpers.NewTestEnvironment()
+	r.testEnvironment.Start()
+	r.customerDAO = daos.NewCustomerDAO(r.testEnvironment.PgxPool())
+}
+
+func (r *RateLimitSuite) SetupTest() {
+	utils.ThrowOnError(r.customerDAO.DeleteAll(context.Background()))
+	utils.ThrowOnError(r.testEnvironment.RedisClient().FlushAll(context.Background()).Err())
+}
+
+func (r *RateLimitSuite) post(path string, body string, ipAddress string, customerId *uuid.UUID) *http.Response {
+	request := utils.GetOrThrow(http.NewRequest("POST", r.testEnvironment.BaseUrl()+path, strings.NewReader(body)))
+	request.Header.Add("Content-Type", "application/json")
+	request.Header.Add("X-Forwarded-For", ipAddress)
+
+	if customerId != nil {
+		request.Header.Add("Authorization", "Bearer "+testhelpers.TestGenerateAccessToken(*customerId))
+	}
+
+	response := utils.GetOrThrow(r.testEnvironment.Client().Do(request))
+	utils.ThrowOnError(response.Body.Close())
+
+	return response
+}
+
+// exhaust makes as many requests as the policy allows, none of which is rate limited.
+func (r *RateLimitSuite) exhaust(path string, body string, ipAddress string, customerId *uuid.UUID) {
+	for i := range testhelpers.TestRateLimit {
+		response := r.post(path, body, ipAddress, customerId)
+		r.Require().NotEqual(429, response.StatusCode)
+		r.Require().Equal(strconv.Itoa(testhelpers.TestRateLimit), response.Header.Get("RateLimit-Limit"))
+		r.Require().Equal(strconv.Itoa(testhelpers.TestRateLimit-i-1), response.Header.Get("RateLimit-Remaining"))
+	}
+}
+
+func (r *RateLimitSuite) Test1() {
+	r.Run("given that an ip address made as many sign ups as allowed, when signing up, then returns 429 with the rate limit headers", func() {
+		r.exhaust("/v1/sign-up", `{}`, "203.0.113.7", nil)
+
+		request := utils.GetOrThrow(http.NewRequest("POST", r.testEnvironment.BaseUrl()+"/v1/sign-up", strings.NewReader(`{}`)))
+		request.Header.Add("Content-Type", "application/json")
+		request.Header.Add("X-Forwarded-For", "203.0.113.7")
+
+		response := utils.GetOrThrow(r.testEnvironment.Client().Do(request))
+		body := utils.GetOrThrow(io.ReadAll(response.Body))
+
+		r.Equal(429, response.StatusCode)
+		r.Equal(fmt.Sprintf("%d;w=60", testhelpers.TestRateLimit), response.Header.Get("RateLimit-Policy"))
+		r.Equal(strconv.Itoa(testhelpers.TestRateLimit), response.Header.Get("RateLimit-Limit"))
+		r.Equal("0", response.Header.Get("RateLimit-Remaining"))
+
+		reset := utils.GetOrThrow(strconv.Atoi(response.Header.Get("RateLimit-Reset")))
+		r.GreaterOrEqual(reset, 1)
+		r.LessOrEqual(reset, 60)
+		r.Equal(response.Header.Get("RateLimit-Reset"), response.Header.Get("Retry-After"))
+
+		r.JSONEq(fmt.Sprintf(`
+			{
+				"type": "/problems/rate_limit_exceeded",
+				"title": "Too Many Requests",
+				"status": 429,
+				"detail": "too many requests, try again later",
+				"instance": "%s",
+				"code": "rate_limit_exceeded"
+			}
+		`, response.Header.Get("X-Request-Id")), string(body))
+	})
+}
+
+func (r *RateLimitSuite) Test2() {
+	r.Run("given that an ip address made as many logins as allowed, when another ip address or another policy is used, then it is not limited", func() {
+		r.exhaust("/v1/login", `{}`, "203.0.113.7", nil)
+
+		response := r.post("/v1/login", `{}`, "203.0.113.7", nil)
+		r.Equal(429, response.StatusCode)
+
+		response = r.post("/v1/login", `{}`, "198.51.100.23", nil)
+		r.Equal(400, response.StatusCode)
+		r.Equal(strconv.Itoa(testhelpers.TestRateLimit-1), response.Header.Get("RateLimit-Remaining"))
+
+		response = r.post("/v1/sign-up", `{}`, "203.0.113.7", nil)
+		r.Equal(400, response.StatusCode)
+		r.Equal(strconv.Itoa(testhelpers.TestRateLimit-1), response.Header.Get("RateLimit-Remaining"))
+	})
+}
+
+func (r *RateLimitSuite) Test3() {
+	r.Run("given that a customer made as many transfers as allowed, when transferring, then returns 429 only for that customer", func() {
+		customerId := uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747")
+		anotherCustomerId := uuid.MustParse("a06f5c45-f824-4cb1-a666-805035ae2ae1")
+
+		r.exhaust("/v1/transfer", `{}`, "203.0.113.7", &customerId)
+
+		response := r.post("/v1/transfer", `{}`, "198.51.100.23", &customerId)
+		r.Equal(429, response.StatusCode)
+
+		response = r.post("/v1/transfer", `{}`, "203.0.113.7", &anotherCustomerId)
+		r.Equal(400, response.StatusCode)
+		r.Equal(strconv.Itoa(testhelpers.TestRateLimit-1), response.Header.Get("RateLimit-Remaining"))
+	})
+}
+
+func (r *RateLimitSuite) Test4() {
+	r.Run("when the access token is missing, then returns 401 without counting the request", func() {
+		response := r.post("/v1/transfer", `{}`, "203.0.113.7", nil)
+		r.Equal(401, response.StatusCode)
+		r.Equal("", response.Header.Get("RateLimit-Limit"))
+	})
+}
+
+func (r *RateLimitSuite) Test5() {
+	r.Run("given that an ip address asked for as many password resets as allowed, when asking for another, then returns 429", func() {
+		r.exhaust("/v1/password/forgot", `{"email": "john.doe@gmail.com"}`, "203.0.113.7", nil)
+
+		response := r.post("/v1/password/forgot", `{"email": "john.doe@gmail.com"}`, "203.0.113.7", nil)
+		r.Equal(429, response.StatusCode)
+
+		response = r.post("/v1/password/forgot", `{"email": "john.doe@gmail.com"}`, "198.51.100.23", nil)
+		r.Equal(202, response.StatusCode)
+	})
+}
+
+func (r *RateLimitSuite) Test6() {
+	r.Run("given that an ip address asked for as many verification emails as allowed, when asking for another, then returns 429", func() {
+		r.exhaust("/v1/verify-email/resend", `{"email": "john.doe@gmail.com"}`, "203.0.113.7", nil)
+
+		response := r.post("/v1/verify-email/resend", `{"email": "john.doe@gmail.com"}`, "203.0.113.7", nil)
+		r.Equal(429, response.StatusCode)
+
+		response = r.post("/v1/verify-email/resend", `{"email": "john.doe@gmail.com"}`, "198.51.100.23", nil)
+		r.Equal(202, response.StatusCode)
+	})
+}
+
+func TestRateLimit(t *testing.T) {
+	suite.Run(t, new(RateLimitSuite))
+}
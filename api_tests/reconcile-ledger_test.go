@@ -0,0 +1,124 @@
+package apitests_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	testhelpers "github.com/gsaaraujo/pay-bank-api/internal/test_helpers"
+	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
+	"github.com/gsaaraujo/pay-bank-api/internal/utils"
+	"github.com/stretchr/testify/suite"
+)
+
+type ReconcileLedgerSuite struct {
+	suite.Suite
+	customerDAO            daos.CustomerDAO
+	accountDAO             daos.AccountDAO
+	reconcileLedgerUsecase usecases.ReconcileLedgerUsecase
+	testEnvironment        *testhelpers.TestEnvironment
+}
+
+func (r *ReconcileLedgerSuite) SetupSuite() {
+	r.testEnvironment = testhelpers.NewTestEnvironment()
+	r.testEnvironment.Start()
+	r.customerDAO = daos.NewCustomerDAO(r.testEnvironment.PgxPool())
+	r.accountDAO = daos.NewAccountDAO(r.testEnvironment.PgxPool())
+	r.reconcileLedgerUsecase = usecases.NewReconcileLedgerUsecase(daos.NewReconciliationDAO(r.testEnvironment.PgxPool()))
+}
+
+func (r *ReconcileLedgerSuite) SetupTest() {
+	utils.ThrowOnError(r.customerDAO.DeleteAll(context.Background()))
+	r.testEnvironment.ClearNotifications()
+}
+
+func (r *ReconcileLedgerSuite) signUpAndTransfer() (*daos.AccountSchema, *daos.AccountSchema) {
+	for _, body := range []string{
+		`{"name": "John Doe", "email": "john.doe@gmail.com", "password": "S3cret-pass"}`,
+		`{"name": "Richard Smith", "email": "richard.smith@gmail.com", "password": "S3cret-pass"}`,
+	} {
+		response := utils.GetOrThrow(r.testEnvironment.Client().Post(r.testEnvironment.BaseUrl()+"/v1/sign-up", "application/json", strings.NewReader(body)))
+		utils.ThrowOnError(response.Body.Close())
+		r.Require().Equal(204, response.StatusCode)
+	}
+
+	r.testEnvironment.VerifyEmail("john.doe@gmail.com")
+	r.testEnvironment.VerifyEmail("richard.smith@gmail.com")
+
+	sender := utils.GetOrThrow(r.customerDAO.FindOneByEmail(context.Background(), "john.doe@gmail.com"))
+	receiver := utils.GetOrThrow(r.customerDAO.FindOneByEmail(context.Background(), "richard.smith@gmail.com"))
+
+	request := utils.GetOrThrow(http.NewRequest("POST", r.testEnvironment.BaseUrl()+"/v1/transfer",
+		strings.NewReader(`{"customerReceiverId": "`+receiver.Id.String()+`", "amount": 2500}`)))
+	request.Header.Add("Content-Type", "application/json")
+	request.Header.Add("Authorization", "Bearer "+testhelpers.TestGenerateAccessToken(sender.Id))
+	request.Header.Add("Idempotency-Key", uuid.New().String())
+
+	response := utils.GetOrThrow(r.testEnvironment.Client().Do(request))
+	utils.ThrowOnError(response.Body.Close())
+	r.Require().Equal(201, response.StatusCode)
+
+	senderAccount := utils.GetOrThrow(r.accountDAO.FindOneByCustomerId(context.Background(), sender.Id))
+	receiverAccount := utils.GetOrThrow(r.accountDAO.FindOneByCustomerId(context.Background(), receiver.Id))
+
+	return senderAccount, receiverAccount
+}
+
+func (r *ReconcileLedgerSuite) Test1() {
+	r.Run("given that balances match the ledger, when reconciling, then reports no mismatch", func() {
+		r.signUpAndTransfer()
+
+		output := utils.GetOrThrow(r.reconcileLedgerUsecase.Execute(context.Background(), usecases.ReconcileLedgerUsecaseInput{}))
+
+		r.Require().True(output.Balanced())
+		r.Require().Equal(2, output.AccountsChecked)
+		r.Require().Empty(output.Drifts)
+		r.Require().Empty(output.OrphanedTransactions)
+		r.Require().Equal(int64(200000), output.TotalBalances)
+		r.Require().Equal(int64(200000), output.TotalGranted)
+	})
+}
+
+func (r *ReconcileLedgerSuite) Test2() {
+	r.Run("given that a cached balance has drifted, when reconciling with fix, then reports and fixes the drift", func() {
+		senderAccount, _ := r.signUpAndTransfer()
+
+		_ = utils.GetOrThrow(r.testEnvironment.PgxPool().Exec(context.Background(), "UPDATE accounts SET balance = 99999 WHERE id = $1", senderAccount.Id))
+
+		output := utils.GetOrThrow(r.reconcileLedgerUsecase.Execute(context.Background(), usecases.ReconcileLedgerUsecaseInput{Fix: true}))
+
+		r.Require().False(output.Balanced())
+		r.Require().Equal(1, len(output.Drifts))
+		r.Require().Equal(senderAccount.Id, output.Drifts[0].AccountId)
+		r.Require().Equal(int64(99999), output.Drifts[0].CachedBalance)
+		r.Require().Equal(int64(97500), output.Drifts[0].ExpectedBalance)
+		r.Require().Equal(int64(97500), output.Drifts[0].LedgerBalance)
+		r.Require().True(output.Drifts[0].Fixed)
+		r.Require().Equal(int64(97500), utils.GetOrThrow(r.accountDAO.FindOneById(context.Background(), senderAccount.Id)).Balance)
+
+		output = utils.GetOrThrow(r.reconcileLedgerUsecase.Execute(context.Background(), usecases.ReconcileLedgerUsecaseInput{}))
+		r.Require().True(output.Balanced())
+	})
+}
+
+func (r *ReconcileLedgerSuite) Test3() {
+	r.Run("given that a transaction has no ledger entries, when reconciling, then reports it as orphaned", func() {
+		senderAccount, receiverAccount := r.signUpAndTransfer()
+
+		_ = utils.GetOrThrow(r.testEnvironment.PgxPool().Exec(context.Background(), "DELETE FROM ledger_entries WHERE account_id IN ($1, $2) AND journal_id IN (SELECT id FROM transactions)",
+			senderAccount.Id, receiverAccount.Id))
+
+		output := utils.GetOrThrow(r.reconcileLedgerUsecase.Execute(context.Background(), usecases.ReconcileLedgerUsecaseInput{}))
+
+		r.Require().False(output.Balanced())
+		r.Require().Equal(1, len(output.OrphanedTransactions))
+		r.Require().Equal(int64(0), output.OrphanedTransactions[0].LedgerEntries)
+	})
+}
+
+func TestReconcileLedger(t *testing.T) {
+	suite.Run(t, new(ReconcileLedgerSuite))
+}
@@ -0,0 +1,201 @@
+package apitests_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	testhelpers "github.com/gsaaraujo/pay-bank-api/internal/test_helpers"
+	"github.com/gsaaraujo/pay-bank-api/internal/utils"
+	"github.com/stretchr/testify/suite"
+)
+
+type RefreshTokenSuite struct {
+	suite.Suite
+	customerDAO     daos.CustomerDAO
+	refreshTokenDAO daos.RefreshTokenDAO
+	testEnvironment *testhelpers.TestEnvironment
+}
+
+func (r *RefreshTokenSuite) SetupSuite() {
+	r.testEnvironment = testhelpers.NewTestEnvironment()
+	r.testEnvironment.Start()
+	r.customerDAO = daos.NewCustomerDAO(r.testEnvironment.PgxPool())
+	r.refreshTokenDAO = daos.NewRefreshTokenDAO(r.testEnvironment.PgxPool())
+}
+
+func (r *RefreshTokenSuite) SetupTest() {
+	utils.ThrowOnError(r.customerDAO.DeleteAll(context.Background()))
+
+	utils.ThrowOnError(r.customerDAO.Create(context.Background(), daos.CustomerSchema{
+		Id:        uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
+		Name:      "John Doe",
+		Email:     "john.doe@gmail.com",
+		Password:  "$2a$10$asLIHej6kxd3Fsdc76QHieBugwCGvsYJeLiZmP1K7/t1GbIbUy.pK",
+		Status:    daos.CustomerStatusActive,
+		UpdatedAt: time.Now().UTC(),
+		CreatedAt: time.Now().UTC(),
+	}))
+}
+
+func (r *RefreshTokenSuite) login() string {
+	response := utils.GetOrThrow(r.testEnvironment.Client().Post(r.testEnvironment.BaseUrl()+"/v1/login", "application/json", strings.NewReader(`
+		{
+			"email": "john.doe@gmail.com",
+			"password": "123456"
+		}
+	`)))
+
+	r.Require().Equal(200, response.StatusCode)
+	body := utils.ParseJSONBody[map[string]map[string]any](response.Body)
+	return body["data"]["refreshToken"].(string)
+}
+
+func (r *RefreshTokenSuite) refresh(refreshToken string) *http.Response {
+	return utils.GetOrThrow(r.testEnvironment.Client().Post(r.testEnvironment.BaseUrl()+"/v1/token/refresh", "application/json",
+		strings.NewReader(fmt.Sprintf(`{"refreshToken": "%s"}`, refreshToken))))
+}
+
+func (r *RefreshTokenSuite) Test1() {
+	r.Run("given a valid refresh token, when refreshing, then returns 200 with new tokens and rotates the refresh token", func() {
+		refreshToken := r.login()
+
+		response := r.refresh(refreshToken)
+
+		r.Equal(200, response.StatusCode)
+		body := utils.ParseJSONBody[map[string]map[string]any](response.Body)
+		r.Require().Equal("f59207c8-e837-4159-b67d-78c716510747", body["data"]["customerId"])
+		r.Require().NotEmpty(body["data"]["accessToken"])
+		r.Require().NotEmpty(body["data"]["refreshToken"])
+		r.Require().NotEqual(refreshToken, body["data"]["refreshToken"])
+
+		refreshTokensSchema := utils.GetOrThrow(r.refreshTokenDAO.FindAllByCustomerId(context.Background(),
+			uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747")))
+		r.Require().Len(refreshTokensSchema, 2)
+		r.Require().Equal(refreshTokensSchema[0].FamilyId, refreshTokensSchema[1].FamilyId)
+		r.Require().Equal(&refreshTokensSchema[1].Id, refreshTokensSchema[0].ReplacedBy)
+		r.Require().Nil(refreshTokensSchema[1].ReplacedBy)
+
+		response = r.refresh(body["data"]["refreshToken"].(string))
+		utils.ThrowOnError(response.Body.Close())
+		r.Equal(200, response.StatusCode)
+	})
+}
+
+func (r *RefreshTokenSuite) Test2() {
+	r.Run("given a refresh token that was already rotated, when refreshing, then returns 401 and revokes the whole family", func() {
+		refreshToken := r.login()
+		otherSessionRefreshToken := r.login()
+
+		response := r.refresh(refreshToken)
+		r.Require().Equal(200, response.StatusCode)
+		rotated := utils.ParseJSONBody[map[string]map[string]any](response.Body)["data"]
+		rotatedRefreshToken := rotated["refreshToken"].(string)
+		rotatedAccessToken := rotated["accessToken"].(string)
+
+		response = r.refresh(refreshToken)
+		body := utils.GetOrThrow(io.ReadAll(response.Body))
+
+		r.Equal(401, response.StatusCode)
+		r.JSONEq(fmt.Sprintf(`
+			{
+				"type": "/problems/refresh_token_reused",
+				"title": "Unauthorized",
+				"status": 401,
+				"detail": "refresh token has already been used, every session issued from it was revoked",
+				"instance": "%s",
+				"code": "refresh_token_reused"
+			}
+		`, response.Header.Get("X-Request-Id")), string(body))
+
+		response = r.refresh(rotatedRefreshToken)
+		utils.ThrowOnError(response.Body.Close())
+		r.Equal(401, response.StatusCode)
+
+		request := utils.GetOrThrow(http.NewRequest("GET", r.testEnvironment.BaseUrl()+"/v1/transactions-history", nil))
+		request.Header.Add("Authorization", "Bearer "+rotatedAccessToken)
+		response = utils.GetOrThrow(r.testEnvironment.Client().Do(request))
+		body = utils.GetOrThrow(io.ReadAll(response.Body))
+		r.Equal(401, response.StatusCode)
+		r.Contains(string(body), `"code":"access_token_revoked"`)
+
+		response = r.refresh(otherSessionRefreshToken)
+		utils.ThrowOnError(response.Body.Close())
+		r.Equal(200, response.StatusCode)
+	})
+}
+
+func (r *RefreshTokenSuite) Test3() {
+	r.Run("given an unknown or expired refresh token, when refreshing, then returns 401", func() {
+		refreshToken := r.login()
+
+		_ = utils.GetOrThrow(r.testEnvironment.PgxPool().Exec(context.Background(),
+			"UPDATE refresh_tokens SET expires_at = $1", time.Now().UTC().Add(-time.Minute)))
+
+		for _, token := range []string{"unknown", refreshToken} {
+			response := r.refresh(token)
+			body := utils.GetOrThrow(io.ReadAll(response.Body))
+
+			r.Equal(401, response.StatusCode)
+			r.JSONEq(fmt.Sprintf(`
+				{
+					"type": "/problems/refresh_token_invalid",
+					"title": "Unauthorized",
+					"status": 401,
+					"detail": "refresh token is invalid or expired",
+					"instance": "%s",
+					"code": "refresh_token_invalid"
+				}
+			`, response.Header.Get("X-Request-Id")), string(body))
+		}
+	})
+}
+
+func (r *RefreshTokenSuite) Test4() {
+	r.Run("when refreshing and body is invalid, then returns 400", func() {
+		templates := []map[string]string{
+			{
+				"body":   `{}`,
+				"errors": `[{"field": "refreshToken", "message": "refreshToken is required"}]`,
+			},
+			{
+				"body":   `{"refreshToken": " "}`,
+				"errors": `[{"field": "refreshToken", "message": "refreshToken must not be empty"}]`,
+			},
+			{
+				"body":   `{"refreshToken": 1}`,
+				"errors": `[{"field": "refreshToken", "message": "refreshToken must be string"}]`,
+			},
+		}
+
+		for _, template := range templates {
+			response := utils.GetOrThrow(r.testEnvironment.Client().Post(r.testEnvironment.BaseUrl()+"/v1/token/refresh", "application/json",
+				strings.NewReader(template["body"])))
+
+			body := utils.GetOrThrow(io.ReadAll(response.Body))
+
+			r.Equal(400, response.StatusCode)
+			r.JSONEq(fmt.Sprintf(`
+				{
+					"type": "/problems/validation_failed",
+					"title": "Bad Request",
+					"status": 400,
+					"detail": "the request has one or more invalid fields",
+					"instance": "%s",
+					"code": "validation_failed",
+					"errors": %s
+				}
+			`, response.Header.Get("X-Request-Id"), template["errors"]), string(body))
+		}
+	})
+}
+
+func TestRefreshToken(t *testing.T) {
+	suite.Run(t, new(RefreshTokenSuite))
+}
@@ -1,6 +1,7 @@
 package apitests_test
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"strings"
@@ -19,6 +20,7 @@ type SignUpSuite struct {
 	suite.Suite
 	accountDAO      daos.AccountDAO
 	customerDAO     daos.CustomerDAO
+	ledgerDAO       daos.LedgerDAO
 	testEnvironment *testhelpers.TestEnvironment
 }
 
@@ -28,19 +30,21 @@ func (r *SignUpSuite) SetupSuite() {
 
 	r.accountDAO = daos.NewAccountDAO(r.testEnvironment.PgxPool())
 	r.customerDAO = daos.NewCustomerDAO(r.testEnvironment.PgxPool())
+	r.ledgerDAO = daos.NewLedgerDAO(r.testEnvironment.PgxPool())
 }
 
 func (r *SignUpSuite) SetupTest() {
-	r.customerDAO.DeleteAll()
+	utils.ThrowOnError(r.customerDAO.DeleteAll(context.Background()))
+	r.testEnvironment.ClearNotifications()
 }
 
 func (r *SignUpSuite) Test1() {
-	r.Run(`given that the customer is not already signed up, when signing up, then returns 204 and a new customer is created`, func() {
+	r.Run(`given that the customer is not already signed up, when signing up, then returns 204 and a customer pending verification is created`, func() {
 		response := utils.GetOrThrow(r.testEnvironment.Client().Post(r.testEnvironment.BaseUrl()+"/v1/sign-up", "application/json", strings.NewReader(`
 			{
 				"name": "John Doe",
 				"email": "john.doe@gmail.com",
-				"password": "123456"
+				"password": "S3cret-pass"
 			}
 		`)))
 
@@ -48,49 +52,64 @@ func (r *SignUpSuite) Test1() {
 		r.Equal(204, response.StatusCode)
 		r.Equal("", string(body))
 
-		customerSchema := r.customerDAO.FindOneByEmail("john.doe@gmail.com")
+		customerSchema := utils.GetOrThrow(r.customerDAO.FindOneByEmail(context.Background(), "john.doe@gmail.com"))
 		r.Require().NotNil(customerSchema)
 		r.Require().True(utils.IsValidUUID(customerSchema.Id.String()))
 		r.Require().Equal("John Doe", customerSchema.Name)
 		r.Require().Equal("john.doe@gmail.com", customerSchema.Email)
-		utils.ThrowOnError(bcrypt.CompareHashAndPassword([]byte(customerSchema.Password), []byte("123456")))
+		r.Require().Equal(daos.CustomerStatusPendingVerification, customerSchema.Status)
+		utils.ThrowOnError(bcrypt.CompareHashAndPassword([]byte(customerSchema.Password), []byte("S3cret-pass")))
 		r.Require().WithinDuration(time.Now().UTC(), customerSchema.UpdatedAt, 5*time.Second)
 		r.Require().WithinDuration(time.Now().UTC(), customerSchema.CreatedAt, 5*time.Second)
 
-		accountSchema := r.accountDAO.FindOneByCustomerId(customerSchema.Id)
+		accountSchema := utils.GetOrThrow(r.accountDAO.FindOneByCustomerId(context.Background(), customerSchema.Id))
 		r.Require().NotNil(accountSchema)
 		r.Require().True(utils.IsValidUUID(accountSchema.Id.String()))
-		r.Require().Equal(int64(100000), accountSchema.Balance)
+		r.Require().Equal(int64(0), accountSchema.Balance)
 		r.Require().WithinDuration(time.Now().UTC(), accountSchema.UpdatedAt, 5*time.Second)
 		r.Require().WithinDuration(time.Now().UTC(), accountSchema.CreatedAt, 5*time.Second)
+		r.Require().Empty(utils.GetOrThrow(r.ledgerDAO.FindAllByAccountId(context.Background(), accountSchema.Id)))
+
+		notifications := r.testEnvironment.Notifications()
+		r.Require().Len(notifications, 1)
+		r.Require().Equal("john.doe@gmail.com", notifications[0].To)
+		r.Require().Equal("Verify your email address", notifications[0].Subject)
+		r.Require().Contains(notifications[0].Body, "It expires in 24 hours.")
 	})
 }
 
 func (r *SignUpSuite) Test2() {
 	r.Run("given that the email has already been taken by someone, when signing up, then returns 409", func() {
-		r.customerDAO.Create(daos.CustomerSchema{
+		utils.ThrowOnError(r.customerDAO.Create(context.Background(), daos.CustomerSchema{
 			Id:        uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
 			Name:      "John Doe",
 			Email:     "john.doe@gmail.com",
 			Password:  "$2a$10$asLIHej6kxd3Fsdc76QHieBugwCGvsYJeLiZmP1K7/t1GbIbUy.pK",
+			Status:    daos.CustomerStatusActive,
 			CreatedAt: time.Now().UTC(),
-		})
+		}))
 
 		response := utils.GetOrThrow(r.testEnvironment.Client().Post(r.testEnvironment.BaseUrl()+"/v1/sign-up", "application/json", strings.NewReader(`
 			{
 				"name": "John Doe Smith",
 				"email": "john.doe@gmail.com",
-				"password": "123456"
+				"password": "S3cret-pass"
 			}
 		`)))
 
 		body := utils.GetOrThrow(io.ReadAll(response.Body))
 		r.Equal(409, response.StatusCode)
-		r.JSONEq(`
+		r.Equal("application/problem+json", response.Header.Get("Content-Type"))
+		r.JSONEq(fmt.Sprintf(`
 			{
-				"message": "this email address has already been taken by someone"
+				"type": "/problems/email_address_already_taken",
+				"title": "Conflict",
+				"status": 409,
+				"detail": "this email address has already been taken by someone",
+				"instance": "%s",
+				"code": "email_address_already_taken"
 			}
-		`, string(body))
+		`, response.Header.Get("X-Request-Id")), string(body))
 	})
 }
 
@@ -100,17 +119,23 @@ func (r *SignUpSuite) Test3() {
 			{
 				"name": "J",
 				"email": "john.doe@gmail.com",
-				"password": "123456"
+				"password": "S3cret-pass"
 			}
 		`)))
 
 		body := utils.GetOrThrow(io.ReadAll(response.Body))
 		r.Equal(409, response.StatusCode)
-		r.JSONEq(`
+		r.Equal("application/problem+json", response.Header.Get("Content-Type"))
+		r.JSONEq(fmt.Sprintf(`
 			{
-				"message": "name must be at least 2 characters"
+				"type": "/problems/name_too_short",
+				"title": "Conflict",
+				"status": 409,
+				"detail": "name must be at least 2 characters",
+				"instance": "%s",
+				"code": "name_too_short"
 			}
-		`, string(body))
+		`, response.Header.Get("X-Request-Id")), string(body))
 	})
 }
 
@@ -120,37 +145,86 @@ func (r *SignUpSuite) Test4() {
 			{
 				"name": "John Doe",
 				"email": "john",
-				"password": "123456"
+				"password": "S3cret-pass"
 			}
 		`)))
 
 		body := utils.GetOrThrow(io.ReadAll(response.Body))
 		r.Equal(409, response.StatusCode)
-		r.JSONEq(`
+		r.Equal("application/problem+json", response.Header.Get("Content-Type"))
+		r.JSONEq(fmt.Sprintf(`
 			{
-				"message": "email address is invalid"
+				"type": "/problems/email_address_invalid",
+				"title": "Conflict",
+				"status": 409,
+				"detail": "email address is invalid",
+				"instance": "%s",
+				"code": "email_address_invalid"
 			}
-		`, string(body))
+		`, response.Header.Get("X-Request-Id")), string(body))
 	})
 }
 
 func (r *SignUpSuite) Test5() {
-	r.Run("when signing up and password is less than 6 characters, then returns 409", func() {
-		response := utils.GetOrThrow(r.testEnvironment.Client().Post(r.testEnvironment.BaseUrl()+"/v1/sign-up", "application/json", strings.NewReader(`
+	r.Run("when signing up and the password breaks the policy, then returns 400 with one error per rule broken", func() {
+		templates := []map[string]string{
 			{
-				"name": "John Doe",
-				"email": "john.doe@gmail.com",
-				"password": "123"
-			}
-		`)))
-
-		body := utils.GetOrThrow(io.ReadAll(response.Body))
-		r.Equal(409, response.StatusCode)
-		r.JSONEq(`
+				"password": "123",
+				"errors": `[
+					{"field": "password", "message": "password must be at least 8 characters"},
+					{"field": "password", "message": "password must contain an uppercase letter"},
+					{"field": "password", "message": "password must contain a lowercase letter"}
+				]`,
+			},
 			{
-				"message": "password must be at least 6 characters"
-			}
-		`, string(body))
+				"password": "abcdefgh",
+				"errors": `[
+					{"field": "password", "message": "password must contain an uppercase letter"},
+					{"field": "password", "message": "password must contain a digit"}
+				]`,
+			},
+			{
+				"password": "Johnny-2024",
+				"errors": `[
+					{"field": "password", "message": "password must not contain your name or email address"}
+				]`,
+			},
+			{
+				"password": "my-DOE-1234",
+				"errors": `[
+					{"field": "password", "message": "password must not contain your name or email address"}
+				]`,
+			},
+			{
+				"password": "Password1",
+				"errors": `[
+					{"field": "password", "message": "password has appeared in a data breach and cannot be used"}
+				]`,
+			},
+		}
+
+		for _, template := range templates {
+			response := utils.GetOrThrow(r.testEnvironment.Client().Post(r.testEnvironment.BaseUrl()+"/v1/sign-up", "application/json",
+				strings.NewReader(fmt.Sprintf(`{"name": "John Doe", "email": "john.doe@gmail.com", "password": "%s"}`, template["password"]))))
+
+			body := utils.GetOrThrow(io.ReadAll(response.Body))
+			r.Equal(400, response.StatusCode)
+			r.Equal("application/problem+json", response.Header.Get("Content-Type"))
+			r.JSONEq(fmt.Sprintf(`
+				{
+					"type": "/problems/validation_failed",
+					"title": "Bad Request",
+					"status": 400,
+					"detail": "the request has one or more invalid fields",
+					"instance": "%s",
+					"code": "validation_failed",
+					"errors": %s
+				}
+			`, response.Header.Get("X-Request-Id"), template["errors"]), string(body))
+		}
+
+		customerSchema := utils.GetOrThrow(r.customerDAO.FindOneByEmail(context.Background(), "john.doe@gmail.com"))
+		r.Nil(customerSchema)
 	})
 }
 
@@ -159,10 +233,10 @@ func (r *SignUpSuite) Test6() {
 		templates := []map[string]string{
 			{
 				"body": `{}`,
-				"error": `[
-					"name is required",
-					"email is required",
-					"password is required"
+				"errors": `[
+					{"field": "name", "message": "name is required"},
+					{"field": "email", "message": "email is required"},
+					{"field": "password", "message": "password is required"}
 				]`,
 			},
 			{
@@ -171,10 +245,10 @@ func (r *SignUpSuite) Test6() {
 					"email": null,
 					"password": null
 				}`,
-				"error": `[
-					"name is required",
-					"email is required",
-					"password is required"
+				"errors": `[
+					{"field": "name", "message": "name is required"},
+					{"field": "email", "message": "email is required"},
+					{"field": "password", "message": "password is required"}
 				]`,
 			},
 			{
@@ -183,10 +257,10 @@ func (r *SignUpSuite) Test6() {
 					"email": "",
 					"password": ""
 				}`,
-				"error": `[
-					"name must not be empty",
-					"email must not be empty",
-					"password must not be empty"
+				"errors": `[
+					{"field": "name", "message": "name must not be empty"},
+					{"field": "email", "message": "email must not be empty"},
+					{"field": "password", "message": "password must not be empty"}
 				]`,
 			},
 			{
@@ -195,10 +269,10 @@ func (r *SignUpSuite) Test6() {
 					"email": " ",
 					"password": " "
 				}`,
-				"error": `[
-					"name must not be empty",
-					"email must not be empty",
-					"password must not be empty"
+				"errors": `[
+					{"field": "name", "message": "name must not be empty"},
+					{"field": "email", "message": "email must not be empty"},
+					{"field": "password", "message": "password must not be empty"}
 				]`,
 			},
 			{
@@ -207,10 +281,10 @@ func (r *SignUpSuite) Test6() {
 					"email": 1,
 					"password": 1
 				}`,
-				"error": `[
-					"name must be string",
-					"email must be string",
-					"password must be string"
+				"errors": `[
+					{"field": "name", "message": "name must be string"},
+					{"field": "email", "message": "email must be string"},
+					{"field": "password", "message": "password must be string"}
 				]`,
 			},
 			{
@@ -219,10 +293,10 @@ func (r *SignUpSuite) Test6() {
 					"email": 1.5,
 					"password": 1.5
 				}`,
-				"error": `[
-					"name must be string",
-					"email must be string",
-					"password must be string"
+				"errors": `[
+					{"field": "name", "message": "name must be string"},
+					{"field": "email", "message": "email must be string"},
+					{"field": "password", "message": "password must be string"}
 				]`,
 			},
 			{
@@ -231,10 +305,10 @@ func (r *SignUpSuite) Test6() {
 					"email": -1,
 					"password": -1
 				}`,
-				"error": `[
-					"name must be string",
-					"email must be string",
-					"password must be string"
+				"errors": `[
+					{"field": "name", "message": "name must be string"},
+					{"field": "email", "message": "email must be string"},
+					{"field": "password", "message": "password must be string"}
 				]`,
 			},
 			{
@@ -243,10 +317,10 @@ func (r *SignUpSuite) Test6() {
 					"email": true,
 					"password": true
 				}`,
-				"error": `[
-					"name must be string",
-					"email must be string",
-					"password must be string"
+				"errors": `[
+					{"field": "name", "message": "name must be string"},
+					{"field": "email", "message": "email must be string"},
+					{"field": "password", "message": "password must be string"}
 				]`,
 			},
 			{
@@ -255,10 +329,10 @@ func (r *SignUpSuite) Test6() {
 					"email": {},
 					"password": {}
 				}`,
-				"error": `[
-					"name must be string",
-					"email must be string",
-					"password must be string"
+				"errors": `[
+					{"field": "name", "message": "name must be string"},
+					{"field": "email", "message": "email must be string"},
+					{"field": "password", "message": "password must be string"}
 				]`,
 			},
 			{
@@ -267,10 +341,10 @@ func (r *SignUpSuite) Test6() {
 					"email": [],
 					"password": []
 				}`,
-				"error": `[
-					"name must be string",
-					"email must be string",
-					"password must be string"
+				"errors": `[
+					{"field": "name", "message": "name must be string"},
+					{"field": "email", "message": "email must be string"},
+					{"field": "password", "message": "password must be string"}
 				]`,
 			},
 		}
@@ -281,15 +355,54 @@ func (r *SignUpSuite) Test6() {
 
 			body := utils.GetOrThrow(io.ReadAll(response.Body))
 			r.Equal(400, response.StatusCode)
+			r.Equal("application/problem+json", response.Header.Get("Content-Type"))
 			r.JSONEq(fmt.Sprintf(`
 				{
-					"message": %s
+					"type": "/problems/validation_failed",
+					"title": "Bad Request",
+					"status": 400,
+					"detail": "the request has one or more invalid fields",
+					"instance": "%s",
+					"code": "validation_failed",
+					"errors": %s
 				}
-			`, template["error"]), string(body))
+			`, response.Header.Get("X-Request-Id"), template["errors"]), string(body))
 		}
 	})
 }
 
+func (r *SignUpSuite) Test7() {
+	r.Run("given that the notifier fails, when signing up, then returns 204 and the verification email can be sent again", func() {
+		r.testEnvironment.BreakNotifier()
+
+		response := utils.GetOrThrow(r.testEnvironment.Client().Post(r.testEnvironment.BaseUrl()+"/v1/sign-up", "application/json", strings.NewReader(`
+			{
+				"name": "John Doe",
+				"email": "john.doe@gmail.com",
+				"password": "S3cret-pass"
+			}
+		`)))
+		utils.ThrowOnError(response.Body.Close())
+		r.Equal(204, response.StatusCode)
+
+		r.testEnvironment.ClearNotifications()
+
+		customerSchema := utils.GetOrThrow(r.customerDAO.FindOneByEmail(context.Background(), "john.doe@gmail.com"))
+		r.Require().NotNil(customerSchema)
+		r.Require().Equal(daos.CustomerStatusPendingVerification, customerSchema.Status)
+
+		response = utils.GetOrThrow(r.testEnvironment.Client().Post(r.testEnvironment.BaseUrl()+"/v1/verify-email/resend", "application/json",
+			strings.NewReader(`{"email": "john.doe@gmail.com"}`)))
+		utils.ThrowOnError(response.Body.Close())
+		r.Equal(202, response.StatusCode)
+
+		notifications := r.testEnvironment.Notifications()
+		r.Require().Len(notifications, 1)
+		r.Equal("john.doe@gmail.com", notifications[0].To)
+		r.Equal("Verify your email address", notifications[0].Subject)
+	})
+}
+
 func TestSignUp(t *testing.T) {
 	suite.Run(t, new(SignUpSuite))
 }
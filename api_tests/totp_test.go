@@ -0,0 +1,501 @@
+package apitests_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	testhelpers "github.com/gsaaraujo/pay-bank-api/internal/test_helpers"
+	"github.com/gsaaraujo/pay-bank-api/internal/utils"
+	"github.com/stretchr/testify/suite"
+)
+
+type TotpSuite struct {
+	suite.Suite
+	customerDAO         daos.CustomerDAO
+	accountDAO          daos.AccountDAO
+	customerTotpDAO     daos.CustomerTotpDAO
+	totpRecoveryCodeDAO daos.TotpRecoveryCodeDAO
+	loginLockoutDAO     daos.LoginLockoutDAO
+	testEnvironment     *testhelpers.TestEnvironment
+}
+
+func (t *TotpSuite) SetupSuite() {
+	t.testEnvironment = testhelpers.NewTestEnvironment()
+	t.testEnvironment.Start()
+	t.customerDAO = daos.NewCustomerDAO(t.testEnvironment.PgxPool())
+	t.accountDAO = daos.NewAccountDAO(t.testEnvironment.PgxPool())
+	t.customerTotpDAO = daos.NewCustomerTotpDAO(t.testEnvironment.PgxPool())
+	t.totpRecoveryCodeDAO = daos.NewTotpRecoveryCodeDAO(t.testEnvironment.PgxPool())
+	t.loginLockoutDAO = daos.NewLoginLockoutDAO(t.testEnvironment.PgxPool())
+}
+
+func (t *TotpSuite) SetupTest() {
+	utils.ThrowOnError(t.customerDAO.DeleteAll(context.Background()))
+	utils.ThrowOnError(t.accountDAO.DeleteAll(context.Background()))
+	utils.ThrowOnError(t.customerTotpDAO.DeleteAll(context.Background()))
+	utils.ThrowOnError(t.totpRecoveryCodeDAO.DeleteAll(context.Background()))
+	utils.ThrowOnError(t.loginLockoutDAO.DeleteAll(context.Background()))
+	utils.ThrowOnError(t.testEnvironment.RedisClient().FlushAll(context.Background()).Err())
+
+	for _, customerSchema := range []daos.CustomerSchema{
+		{
+			Id:       uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
+			Name:     "John Doe",
+			Email:    "john.doe@gmail.com",
+			Password: "$2a$10$asLIHej6kxd3Fsdc76QHieBugwCGvsYJeLiZmP1K7/t1GbIbUy.pK",
+			Status:   daos.CustomerStatusActive,
+		},
+		{
+			Id:       uuid.MustParse("a06f5c45-f824-4cb1-a666-805035ae2ae1"),
+			Name:     "Richard Smith",
+			Email:    "richard.smith@gmail.com",
+			Password: "$2a$10$asLIHej6kxd3Fsdc76QHieBugwCGvsYJeLiZmP1K7/t1GbIbUy.pK",
+			Status:   daos.CustomerStatusActive,
+		},
+	} {
+		customerSchema.CreatedAt = time.Now().UTC()
+		customerSchema.UpdatedAt = time.Now().UTC()
+		utils.ThrowOnError(t.customerDAO.Create(context.Background(), customerSchema))
+	}
+
+	utils.ThrowOnError(t.accountDAO.Create(context.Background(), daos.AccountSchema{
+		Id:         uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"),
+		CustomerId: uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
+		Balance:    500000,
+		UpdatedAt:  time.Now().UTC(),
+		CreatedAt:  time.Now().UTC(),
+	}))
+	utils.ThrowOnError(t.accountDAO.Create(context.Background(), daos.AccountSchema{
+		Id:         uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d"),
+		CustomerId: uuid.MustParse("a06f5c45-f824-4cb1-a666-805035ae2ae1"),
+		Balance:    0,
+		UpdatedAt:  time.Now().UTC(),
+		CreatedAt:  time.Now().UTC(),
+	}))
+}
+
+func (t *TotpSuite) do(method string, path string, accessToken string, body string) (*http.Response, string) {
+	request := utils.GetOrThrow(http.NewRequest(method, t.testEnvironment.BaseUrl()+path, strings.NewReader(body)))
+	request.Header.Add("Content-Type", "application/json")
+
+	if accessToken != "" {
+		request.Header.Add("Authorization", "Bearer "+accessToken)
+	}
+
+	response := utils.GetOrThrow(t.testEnvironment.Client().Do(request))
+	responseBody := utils.GetOrThrow(io.ReadAll(response.Body))
+
+	return response, string(responseBody)
+}
+
+// enable enrolls and confirms an authenticator for John Doe, returning its secret and the
+// recovery codes. The confirmation spends the code of the previous step, so tests can still use
+// the current one.
+func (t *TotpSuite) enable() (string, []string) {
+	accessToken := testhelpers.TestGenerateAccessToken(uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"))
+
+	response, body := t.do("POST", "/v1/me/totp", accessToken, "")
+	t.Require().Equal(200, response.StatusCode)
+	secret := parseTotpData[string](body)["secret"]
+
+	code := utils.GetOrThrow(utils.TotpCode(secret, utils.TotpStep(time.Now())-1))
+	response, body = t.do("POST", "/v1/me/totp/confirm", accessToken, fmt.Sprintf(`{"code": "%s"}`, code))
+	t.Require().Equal(200, response.StatusCode)
+	recoveryCodes := parseTotpData[[]string](body)["recoveryCodes"]
+
+	return secret, recoveryCodes
+}
+
+func (t *TotpSuite) loginChallenge() string {
+	response, body := t.do("POST", "/v1/login", "", `{"email": "john.doe@gmail.com", "password": "123456"}`)
+	t.Require().Equal(200, response.StatusCode)
+
+	data := parseTotpData[any](body)
+	t.Require().Equal(true, data["totpRequired"])
+	t.Require().NotContains(data, "accessToken")
+
+	return data["challengeToken"].(string)
+}
+
+func parseTotpData[T any](body string) map[string]T {
+	var parsed struct {
+		Data map[string]T `json:"data"`
+	}
+
+	utils.ThrowOnError(json.Unmarshal([]byte(body), &parsed))
+	return parsed.Data
+}
+
+func (t *TotpSuite) Test1() {
+	t.Run("when enrolling and confirming an authenticator, then returns the secret and 10 recovery codes", func() {
+		accessToken := testhelpers.TestGenerateAccessToken(uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"))
+
+		response, body := t.do("POST", "/v1/me/totp", accessToken, "")
+		t.Require().Equal(200, response.StatusCode)
+
+		data := parseTotpData[string](body)
+		t.Len(data["secret"], 32)
+		t.Equal(fmt.Sprintf("otpauth://totp/PayBank:john.doe@gmail.com?algorithm=SHA1&digits=6&issuer=PayBank&period=30&secret=%s",
+			data["secret"]), data["otpauthUri"])
+
+		customerTotpSchema := utils.GetOrThrow(t.customerTotpDAO.FindOneByCustomerId(context.Background(),
+			uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747")))
+		t.Require().NotNil(customerTotpSchema)
+		t.Nil(customerTotpSchema.ConfirmedAt)
+
+		code := utils.GetOrThrow(utils.TotpCode(data["secret"], utils.TotpStep(time.Now())))
+		response, body = t.do("POST", "/v1/me/totp/confirm", accessToken, fmt.Sprintf(`{"code": "%s"}`, code))
+		t.Require().Equal(200, response.StatusCode)
+
+		recoveryCodes := parseTotpData[[]string](body)["recoveryCodes"]
+		t.Len(recoveryCodes, 10)
+		for _, recoveryCode := range recoveryCodes {
+			t.Regexp(`^[a-z2-7]{5}-[a-z2-7]{5}$`, recoveryCode)
+		}
+
+		customerTotpSchema = utils.GetOrThrow(t.customerTotpDAO.FindOneByCustomerId(context.Background(),
+			uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747")))
+		t.Require().NotNil(customerTotpSchema.ConfirmedAt)
+		t.WithinDuration(time.Now().UTC(), *customerTotpSchema.ConfirmedAt, 5*time.Second)
+
+		totpRecoveryCodesSchema := utils.GetOrThrow(t.totpRecoveryCodeDAO.FindAllByCustomerId(context.Background(),
+			uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747")))
+		t.Len(totpRecoveryCodesSchema, 10)
+		t.Len(totpRecoveryCodesSchema[0].CodeHash, 64)
+
+		response, body = t.do("POST", "/v1/me/totp", accessToken, "")
+		t.Equal(409, response.StatusCode)
+		t.Contains(body, `"code":"totp_already_enabled"`)
+	})
+}
+
+func (t *TotpSuite) Test2() {
+	t.Run("when confirming without enrolling or with a wrong code, then returns 409", func() {
+		accessToken := testhelpers.TestGenerateAccessToken(uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"))
+
+		response, body := t.do("POST", "/v1/me/totp/confirm", accessToken, `{"code": "123456"}`)
+		t.Equal(409, response.StatusCode)
+		t.JSONEq(fmt.Sprintf(`
+			{
+				"type": "/problems/totp_not_enrolled",
+				"title": "Conflict",
+				"status": 409,
+				"detail": "two-factor authentication enrollment has not been started",
+				"instance": "%s",
+				"code": "totp_not_enrolled"
+			}
+		`, response.Header.Get("X-Request-Id")), body)
+
+		response, _ = t.do("POST", "/v1/me/totp", accessToken, "")
+		t.Require().Equal(200, response.StatusCode)
+
+		response, body = t.do("POST", "/v1/me/totp/confirm", accessToken, `{"code": "abcdef"}`)
+		t.Equal(409, response.StatusCode)
+		t.JSONEq(fmt.Sprintf(`
+			{
+				"type": "/problems/totp_code_invalid",
+				"title": "Conflict",
+				"status": 409,
+				"detail": "two-factor authentication code is invalid or has already been used",
+				"instance": "%s",
+				"code": "totp_code_invalid"
+			}
+		`, response.Header.Get("X-Request-Id")), body)
+
+		response, body = t.do("POST", "/v1/me/totp/confirm", accessToken, `{"code": ""}`)
+		t.Equal(400, response.StatusCode)
+		t.Contains(body, `{"field":"code","message":"code must not be empty"}`)
+	})
+}
+
+func (t *TotpSuite) Test3() {
+	t.Run("given 2FA is enabled, when logging in, then returns a challenge that a code exchanges for the tokens once", func() {
+		secret, _ := t.enable()
+
+		challengeToken := t.loginChallenge()
+		code := utils.GetOrThrow(utils.TotpCode(secret, utils.TotpStep(time.Now())))
+
+		response, body := t.do("POST", "/v1/login/totp", "", fmt.Sprintf(`{"challengeToken": "%s", "code": "%s"}`, challengeToken, code))
+		t.Require().Equal(200, response.StatusCode)
+
+		data := parseTotpData[any](body)
+		t.Equal("f59207c8-e837-4159-b67d-78c716510747", data["customerId"])
+		t.NotEmpty(data["accessToken"])
+		t.NotEmpty(data["refreshToken"])
+
+		response, body = t.do("POST", "/v1/login/totp", "", fmt.Sprintf(`{"challengeToken": "%s", "code": "%s"}`, challengeToken, code))
+		t.Equal(401, response.StatusCode)
+		t.Contains(body, `"code":"login_challenge_invalid"`)
+
+		response, body = t.do("POST", "/v1/login/totp", "", fmt.Sprintf(`{"challengeToken": "%s", "code": "%s"}`, t.loginChallenge(), code))
+		t.Equal(409, response.StatusCode)
+		t.Contains(body, `"code":"totp_code_invalid"`)
+	})
+}
+
+func (t *TotpSuite) Test4() {
+	t.Run("given 2FA is enabled, when logging in with a recovery code, then it can only be used once", func() {
+		_, recoveryCodes := t.enable()
+
+		response, _ := t.do("POST", "/v1/login/totp", "",
+			fmt.Sprintf(`{"challengeToken": "%s", "code": "%s"}`, t.loginChallenge(), strings.ToUpper(recoveryCodes[3])))
+		t.Require().Equal(200, response.StatusCode)
+
+		response, body := t.do("POST", "/v1/login/totp", "",
+			fmt.Sprintf(`{"challengeToken": "%s", "code": "%s"}`, t.loginChallenge(), recoveryCodes[3]))
+		t.Equal(409, response.StatusCode)
+		t.Contains(body, `"code":"totp_code_invalid"`)
+
+		totpRecoveryCodesSchema := utils.GetOrThrow(t.totpRecoveryCodeDAO.FindAllByCustomerId(context.Background(),
+			uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747")))
+		usedRecoveryCodes := 0
+		for _, totpRecoveryCodeSchema := range totpRecoveryCodesSchema {
+			if totpRecoveryCodeSchema.UsedAt != nil {
+				usedRecoveryCodes++
+			}
+		}
+		t.Equal(1, usedRecoveryCodes)
+	})
+}
+
+func (t *TotpSuite) Test5() {
+	t.Run("given a challenge, when 5 wrong codes were tried, then the challenge is spent", func() {
+		secret, _ := t.enable()
+		challengeToken := t.loginChallenge()
+
+		for range 5 {
+			response, _ := t.do("POST", "/v1/login/totp", "", fmt.Sprintf(`{"challengeToken": "%s", "code": "000000"}`, challengeToken))
+			t.Require().Equal(409, response.StatusCode)
+		}
+
+		code := utils.GetOrThrow(utils.TotpCode(secret, utils.TotpStep(time.Now())))
+		response, body := t.do("POST", "/v1/login/totp", "", fmt.Sprintf(`{"challengeToken": "%s", "code": "%s"}`, challengeToken, code))
+		t.Equal(401, response.StatusCode)
+		t.JSONEq(fmt.Sprintf(`
+			{
+				"type": "/problems/login_challenge_invalid",
+				"title": "Unauthorized",
+				"status": 401,
+				"detail": "login challenge is invalid or expired",
+				"instance": "%s",
+				"code": "login_challenge_invalid"
+			}
+		`, response.Header.Get("X-Request-Id")), body)
+
+		t.Empty(utils.GetOrThrow(t.testEnvironment.RedisClient().Keys(context.Background(), "login_challenges:*").Result()))
+	})
+}
+
+func (t *TotpSuite) Test6() {
+	t.Run("given 2FA is enabled, when disabling it with a code, then returns 204 and logins return the tokens again", func() {
+		secret, _ := t.enable()
+		accessToken := testhelpers.TestGenerateAccessToken(uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"))
+
+		response, body := t.do("DELETE", "/v1/me/totp", accessToken, `{"code": "000000"}`)
+		t.Equal(409, response.StatusCode)
+		t.Contains(body, `"code":"totp_code_invalid"`)
+
+		code := utils.GetOrThrow(utils.TotpCode(secret, utils.TotpStep(time.Now())))
+		response, _ = t.do("DELETE", "/v1/me/totp", accessToken, fmt.Sprintf(`{"code": "%s"}`, code))
+		t.Equal(204, response.StatusCode)
+
+		customerTotpSchema := utils.GetOrThrow(t.customerTotpDAO.FindOneByCustomerId(context.Background(),
+			uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747")))
+		t.Nil(customerTotpSchema)
+
+		totpRecoveryCodesSchema := utils.GetOrThrow(t.totpRecoveryCodeDAO.FindAllByCustomerId(context.Background(),
+			uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747")))
+		t.Empty(totpRecoveryCodesSchema)
+
+		response, body = t.do("POST", "/v1/login", "", `{"email": "john.doe@gmail.com", "password": "123456"}`)
+		t.Equal(200, response.StatusCode)
+		t.Contains(body, `"accessToken"`)
+
+		response, body = t.do("DELETE", "/v1/me/totp", accessToken, fmt.Sprintf(`{"code": "%s"}`, code))
+		t.Equal(409, response.StatusCode)
+		t.Contains(body, `"code":"totp_not_enabled"`)
+	})
+}
+
+func (t *TotpSuite) Test7() {
+	t.Run("when transferring over the threshold, then requires 2FA and a fresh code", func() {
+		accessToken := testhelpers.TestGenerateAccessToken(uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"))
+		transfer := func(idempotencyKey string, body string) (*http.Response, string) {
+			request := utils.GetOrThrow(http.NewRequest("POST", t.testEnvironment.BaseUrl()+"/v1/transfer", strings.NewReader(body)))
+			request.Header.Add("Content-Type", "application/json")
+			request.Header.Add("Authorization", "Bearer "+accessToken)
+			request.Header.Add("Idempotency-Key", idempotencyKey)
+
+			response := utils.GetOrThrow(t.testEnvironment.Client().Do(request))
+			return response, string(utils.GetOrThrow(io.ReadAll(response.Body)))
+		}
+
+		response, _ := transfer("5d2b7a1e-3c4f-4e8a-9b6d-0f1e2d3c4b5a",
+			`{"customerReceiverId": "a06f5c45-f824-4cb1-a666-805035ae2ae1", "amount": 100000}`)
+		t.Require().Equal(201, response.StatusCode)
+
+		response, body := transfer("6e3c8b2f-4d5a-4f9b-8c7e-1a2b3c4d5e6f",
+			`{"customerReceiverId": "a06f5c45-f824-4cb1-a666-805035ae2ae1", "amount": 100001}`)
+		t.Equal(403, response.StatusCode)
+		t.JSONEq(fmt.Sprintf(`
+			{
+				"type": "/problems/transfer_requires_totp",
+				"title": "Forbidden",
+				"status": 403,
+				"detail": "two-factor authentication must be enabled to transfer this amount",
+				"instance": "%s",
+				"code": "transfer_requires_totp"
+			}
+		`, response.Header.Get("X-Request-Id")), body)
+
+		secret, _ := t.enable()
+
+		response, body = transfer("6e3c8b2f-4d5a-4f9b-8c7e-1a2b3c4d5e6f",
+			`{"customerReceiverId": "a06f5c45-f824-4cb1-a666-805035ae2ae1", "amount": 100001}`)
+		t.Equal(403, response.StatusCode)
+		t.Contains(body, `"code":"totp_code_required"`)
+
+		code := utils.GetOrThrow(utils.TotpCode(secret, utils.TotpStep(time.Now())))
+		transferBody := fmt.Sprintf(`{"customerReceiverId": "a06f5c45-f824-4cb1-a666-805035ae2ae1", "amount": 100001, "totpCode": "%s"}`, code)
+
+		response, _ = transfer("6e3c8b2f-4d5a-4f9b-8c7e-1a2b3c4d5e6f", transferBody)
+		t.Equal(204, response.StatusCode)
+
+		response, _ = transfer("6e3c8b2f-4d5a-4f9b-8c7e-1a2b3c4d5e6f", transferBody)
+		t.Equal(204, response.StatusCode)
+
+		response, body = transfer("7f4d9c3a-5e6b-4a0c-9d8f-2b3c4d5e6f7a", transferBody)
+		t.Equal(409, response.StatusCode)
+		t.Contains(body, `"code":"totp_code_invalid"`)
+
+		accountSchema := utils.GetOrThrow(t.accountDAO.FindOneById(context.Background(), uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d")))
+		t.Equal(int64(200001), accountSchema.Balance)
+	})
+}
+
+func (t *TotpSuite) Test8() {
+	t.Run("given 10 wrong codes across challenges, when logging in or disabling with the right code, then returns 429 until the lockout ends", func() {
+		secret, _ := t.enable()
+		accessToken := testhelpers.TestGenerateAccessToken(uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"))
+
+		for range 2 {
+			challengeToken := t.loginChallenge()
+
+			for range 5 {
+				response, _ := t.do("POST", "/v1/login/totp", "", fmt.Sprintf(`{"challengeToken": "%s", "code": "000000"}`, challengeToken))
+				t.Require().Equal(409, response.StatusCode)
+			}
+		}
+
+		code := utils.GetOrThrow(utils.TotpCode(secret, utils.TotpStep(time.Now())))
+		response, body := t.do("POST", "/v1/login/totp", "", fmt.Sprintf(`{"challengeToken": "%s", "code": "%s"}`, t.loginChallenge(), code))
+		t.Equal(429, response.StatusCode)
+		t.InDelta(60, utils.GetOrThrow(strconv.Atoi(response.Header.Get("Retry-After"))), 2)
+		t.JSONEq(fmt.Sprintf(`
+			{
+				"type": "/problems/totp_locked",
+				"title": "Too Many Requests",
+				"status": 429,
+				"detail": "too many invalid two-factor authentication codes, try again later",
+				"instance": "%s",
+				"code": "totp_locked"
+			}
+		`, response.Header.Get("X-Request-Id")), body)
+
+		response, _ = t.do("DELETE", "/v1/me/totp", accessToken, fmt.Sprintf(`{"code": "%s"}`, code))
+		t.Equal(429, response.StatusCode)
+
+		loginLockoutsSchema := utils.GetOrThrow(t.loginLockoutDAO.FindAllBySubject(context.Background(),
+			daos.LoginLockoutSubjectTypeTotp, "f59207c8-e837-4159-b67d-78c716510747"))
+		t.Require().Len(loginLockoutsSchema, 1)
+		t.Equal(int64(10), loginLockoutsSchema[0].FailedAttempts)
+
+		utils.ThrowOnError(t.testEnvironment.RedisClient().Del(context.Background(),
+			"login_attempts:lockout:totp:f59207c8-e837-4159-b67d-78c716510747").Err())
+
+		response, _ = t.do("DELETE", "/v1/me/totp", accessToken, fmt.Sprintf(`{"code": "%s"}`, code))
+		t.Equal(204, response.StatusCode)
+	})
+}
+
+func (t *TotpSuite) Test9() {
+	t.Run("when disabling with 10 wrong codes, then the customer is locked out of disabling it", func() {
+		secret, _ := t.enable()
+		accessToken := testhelpers.TestGenerateAccessToken(uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"))
+
+		for range 10 {
+			response, _ := t.do("DELETE", "/v1/me/totp", accessToken, `{"code": "000000"}`)
+			t.Require().Equal(409, response.StatusCode)
+		}
+
+		code := utils.GetOrThrow(utils.TotpCode(secret, utils.TotpStep(time.Now())))
+		response, body := t.do("DELETE", "/v1/me/totp", accessToken, fmt.Sprintf(`{"code": "%s"}`, code))
+		t.Equal(429, response.StatusCode)
+		t.Contains(body, `"code":"totp_locked"`)
+
+		customerTotpSchema := utils.GetOrThrow(t.customerTotpDAO.FindOneByCustomerId(context.Background(),
+			uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747")))
+		t.NotNil(customerTotpSchema)
+	})
+}
+
+func (t *TotpSuite) Test10() {
+	t.Run("when transferring over the threshold with 10 wrong codes, then the customer is locked out of transferring and logging in", func() {
+		secret, _ := t.enable()
+		accessToken := testhelpers.TestGenerateAccessToken(uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"))
+		transfer := func(prefer string, code string) (*http.Response, string) {
+			body := fmt.Sprintf(`{"customerReceiverId": "a06f5c45-f824-4cb1-a666-805035ae2ae1", "amount": 100001, "totpCode": "%s"}`, code)
+			request := utils.GetOrThrow(http.NewRequest("POST", t.testEnvironment.BaseUrl()+"/v1/transfer", strings.NewReader(body)))
+			request.Header.Add("Content-Type", "application/json")
+			request.Header.Add("Authorization", "Bearer "+accessToken)
+			request.Header.Add("Idempotency-Key", "5d2b7a1e-3c4f-4e8a-9b6d-0f1e2d3c4b5a")
+
+			if prefer != "" {
+				request.Header.Add("Prefer", prefer)
+			}
+
+			response := utils.GetOrThrow(t.testEnvironment.Client().Do(request))
+			return response, string(utils.GetOrThrow(io.ReadAll(response.Body)))
+		}
+
+		for i := range 10 {
+			prefer := ""
+			if i%2 == 1 {
+				prefer = "respond-async"
+			}
+
+			response, body := transfer(prefer, "000000")
+			t.Require().Equal(409, response.StatusCode)
+			t.Require().Contains(body, `"code":"totp_code_invalid"`)
+		}
+
+		code := utils.GetOrThrow(utils.TotpCode(secret, utils.TotpStep(time.Now())))
+		response, body := transfer("", code)
+		t.Equal(429, response.StatusCode)
+		t.Contains(body, `"code":"totp_locked"`)
+
+		response, _ = t.do("POST", "/v1/login/totp", "", fmt.Sprintf(`{"challengeToken": "%s", "code": "%s"}`, t.loginChallenge(), code))
+		t.Equal(429, response.StatusCode)
+
+		loginLockoutsSchema := utils.GetOrThrow(t.loginLockoutDAO.FindAllBySubject(context.Background(),
+			daos.LoginLockoutSubjectTypeTotp, "f59207c8-e837-4159-b67d-78c716510747"))
+		t.Require().Len(loginLockoutsSchema, 1)
+		t.Equal(int64(10), loginLockoutsSchema[0].FailedAttempts)
+
+		accountSchema := utils.GetOrThrow(t.accountDAO.FindOneById(context.Background(), uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d")))
+		t.Equal(int64(0), accountSchema.Balance)
+	})
+}
+
+func TestTotp(t *testing.T) {
+	suite.Run(t, new(TotpSuite))
+}
@@ -0,0 +1,230 @@
+package apitests_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	testhelpers "github.com/gsaaraujo/pay-bank-api/internal/test_helpers"
+	"github.com/gsaaraujo/pay-bank-api/internal/utils"
+	"github.com/stretchr/testify/suite"
+)
+
+type TransactionsSuite struct {
+	suite.Suite
+	customerDAO     daos.CustomerDAO
+	accountDAO      daos.AccountDAO
+	transactionDAO  daos.TransactionDAO
+	testEnvironment *testhelpers.TestEnvironment
+}
+
+func (tr *TransactionsSuite) SetupSuite() {
+	tr.testEnvironment = testhelpers.NewTestEnvironment()
+	tr.testEnvironment.Start()
+	tr.customerDAO = daos.NewCustomerDAO(tr.testEnvironment.PgxPool())
+	tr.accountDAO = daos.NewAccountDAO(tr.testEnvironment.PgxPool())
+	tr.transactionDAO = daos.NewTransactionDAO(tr.testEnvironment.PgxPool())
+}
+
+func (tr *TransactionsSuite) SetupTest() {
+	utils.ThrowOnError(tr.customerDAO.DeleteAll(context.Background()))
+	utils.ThrowOnError(tr.transactionDAO.DeleteAll(context.Background()))
+
+	tr.createCustomer(uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"), uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"),
+		"John Doe", "john.doe@gmail.com")
+	tr.createCustomer(uuid.MustParse("a06f5c45-f824-4cb1-a666-805035ae2ae1"), uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d"),
+		"Richard Smith", "richard.smith@gmail.com")
+	tr.createCustomer(uuid.MustParse("0b8d6a4e-5c3f-4e2a-9f1b-7d6c5b4a3e2f"), uuid.MustParse("7e6d5c4b-3a2f-4e1d-8c9b-0a1f2e3d4c5b"),
+		"Anna Lee", "anna.lee@gmail.com")
+}
+
+func (tr *TransactionsSuite) createCustomer(customerId uuid.UUID, accountId uuid.UUID, name string, email string) {
+	utils.ThrowOnError(tr.customerDAO.Create(context.Background(), daos.CustomerSchema{
+		Id:        customerId,
+		Name:      name,
+		Email:     email,
+		Password:  "$2a$10$asLIHej6kxd3Fsdc76QHieBugwCGvsYJeLiZmP1K7/t1GbIbUy.pK",
+		Status:    daos.CustomerStatusActive,
+		UpdatedAt: time.Now().UTC(),
+		CreatedAt: time.Now().UTC(),
+	}))
+	utils.ThrowOnError(tr.accountDAO.Create(context.Background(), daos.AccountSchema{
+		Id:         accountId,
+		CustomerId: customerId,
+		Balance:    12500,
+		UpdatedAt:  time.Now().UTC(),
+		CreatedAt:  time.Now().UTC(),
+	}))
+}
+
+func (tr *TransactionsSuite) transfer() uuid.UUID {
+	request := utils.GetOrThrow(http.NewRequest("POST", tr.testEnvironment.BaseUrl()+"/v1/transfer",
+		strings.NewReader(`{"customerReceiverId": "a06f5c45-f824-4cb1-a666-805035ae2ae1", "amount": 2550}`)))
+	request.Header.Add("Content-Type", "application/json")
+	request.Header.Add("Authorization", "Bearer "+testhelpers.TestGenerateAccessToken(uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747")))
+	request.Header.Add("Idempotency-Key", uuid.New().String())
+
+	response := utils.GetOrThrow(tr.testEnvironment.Client().Do(request))
+	tr.Require().Equal(201, response.StatusCode)
+
+	var created struct {
+		Data struct {
+			TransactionId uuid.UUID `json:"transactionId"`
+		} `json:"data"`
+	}
+	utils.ThrowOnError(json.NewDecoder(response.Body).Decode(&created))
+	utils.ThrowOnError(response.Body.Close())
+
+	return created.Data.TransactionId
+}
+
+func (tr *TransactionsSuite) get(customerId uuid.UUID, path string, accept string) (*http.Response, string) {
+	request := utils.GetOrThrow(http.NewRequest("GET", tr.testEnvironment.BaseUrl()+path, nil))
+	request.Header.Add("Authorization", "Bearer "+testhelpers.TestGenerateAccessToken(customerId))
+
+	if accept != "" {
+		request.Header.Add("Accept", accept)
+	}
+
+	response := utils.GetOrThrow(tr.testEnvironment.Client().Do(request))
+	body := utils.GetOrThrow(io.ReadAll(response.Body))
+
+	return response, string(body)
+}
+
+func (tr *TransactionsSuite) Test1() {
+	tr.Run("when the sender or the receiver gets a transaction, then returns it with their direction", func() {
+		transactionId := tr.transfer()
+		transactionSchema := utils.GetOrThrow(tr.transactionDAO.FindOneHistoryById(context.Background(), transactionId))
+		tr.Require().NotNil(transactionSchema)
+
+		for customerId, direction := range map[string]string{
+			"f59207c8-e837-4159-b67d-78c716510747": "sent",
+			"a06f5c45-f824-4cb1-a666-805035ae2ae1": "received",
+		} {
+			response, body := tr.get(uuid.MustParse(customerId), "/v1/transactions/"+transactionId.String(), "")
+			tr.Equal(200, response.StatusCode)
+			tr.JSONEq(fmt.Sprintf(`
+				{
+					"data": {
+						"id": "%s",
+						"direction": "%s",
+						"customerSender": {"id": "f59207c8-e837-4159-b67d-78c716510747", "name": "John Doe"},
+						"customerReceiver": {"id": "a06f5c45-f824-4cb1-a666-805035ae2ae1", "name": "Richard Smith"},
+						"accountSender": {"id": "2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"},
+						"accountReceiver": {"id": "c7333b68-6f2a-46db-89c8-fd833fd3546d"},
+						"amount": 2550,
+						"createdAt": "%s"
+					}
+				}
+			`, transactionId, direction, transactionSchema.CreatedAt.UTC().Format(time.RFC3339)), body)
+		}
+	})
+}
+
+func (tr *TransactionsSuite) Test2() {
+	tr.Run("when someone else gets a transaction or its receipt, then returns 404", func() {
+		transactionId := tr.transfer()
+
+		for _, path := range []string{"/v1/transactions/" + transactionId.String(), "/v1/transactions/" + transactionId.String() + "/receipt",
+			"/v1/transactions/" + uuid.NewString()} {
+			response, body := tr.get(uuid.MustParse("0b8d6a4e-5c3f-4e2a-9f1b-7d6c5b4a3e2f"), path, "")
+			tr.Equal(404, response.StatusCode)
+			tr.JSONEq(fmt.Sprintf(`
+				{
+					"type": "/problems/transaction_not_found",
+					"title": "Not Found",
+					"status": 404,
+					"detail": "transaction not found",
+					"instance": "%s",
+					"code": "transaction_not_found"
+				}
+			`, response.Header.Get("X-Request-Id")), body)
+		}
+
+		response, _ := tr.get(uuid.MustParse("0b8d6a4e-5c3f-4e2a-9f1b-7d6c5b4a3e2f"), "/v1/transactions/not-an-id", "")
+		tr.Equal(400, response.StatusCode)
+	})
+}
+
+func (tr *TransactionsSuite) Test3() {
+	tr.Run("when getting the receipt as JSON, then returns the parties, the amount and an authentication hash over them", func() {
+		transactionId := tr.transfer()
+		transactionSchema := utils.GetOrThrow(tr.transactionDAO.FindOneHistoryById(context.Background(), transactionId))
+		createdAt := transactionSchema.CreatedAt.UTC().Truncate(time.Second)
+
+		message := fmt.Sprintf("v1|%s|f59207c8-e837-4159-b67d-78c716510747|2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d|"+
+			"a06f5c45-f824-4cb1-a666-805035ae2ae1|c7333b68-6f2a-46db-89c8-fd833fd3546d|2550|%d", transactionId, createdAt.Unix())
+		mac := hmac.New(sha256.New, []byte(testhelpers.TestReceiptSigningKey))
+		mac.Write([]byte(message))
+		authenticationHash := "v1," + hex.EncodeToString(mac.Sum(nil))
+
+		response, body := tr.get(uuid.MustParse("a06f5c45-f824-4cb1-a666-805035ae2ae1"), "/v1/transactions/"+transactionId.String()+"/receipt", "")
+		tr.Equal(200, response.StatusCode)
+		tr.JSONEq(fmt.Sprintf(`
+			{
+				"data": {
+					"transactionId": "%s",
+					"sender": {
+						"customerId": "f59207c8-e837-4159-b67d-78c716510747",
+						"name": "John Doe",
+						"accountId": "2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"
+					},
+					"receiver": {
+						"customerId": "a06f5c45-f824-4cb1-a666-805035ae2ae1",
+						"name": "Richard Smith",
+						"accountId": "c7333b68-6f2a-46db-89c8-fd833fd3546d"
+					},
+					"amount": 2550,
+					"createdAt": "%s",
+					"authenticationHash": "%s"
+				}
+			}
+		`, transactionId, createdAt.Format(time.RFC3339), authenticationHash), body)
+
+		_, senderBody := tr.get(uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"), "/v1/transactions/"+transactionId.String()+"/receipt", "")
+		tr.JSONEq(body, senderBody)
+	})
+}
+
+func (tr *TransactionsSuite) Test4() {
+	tr.Run("given Accept application/pdf, when getting the receipt, then returns it as a PDF document", func() {
+		transactionId := tr.transfer()
+
+		_, jsonBody := tr.get(uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"), "/v1/transactions/"+transactionId.String()+"/receipt", "")
+
+		var receipt struct {
+			Data struct {
+				AuthenticationHash string `json:"authenticationHash"`
+			} `json:"data"`
+		}
+		utils.ThrowOnError(json.Unmarshal([]byte(jsonBody), &receipt))
+
+		response, body := tr.get(uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"), "/v1/transactions/"+transactionId.String()+"/receipt",
+			"application/pdf, application/json;q=0.5")
+		tr.Equal(200, response.StatusCode)
+		tr.Equal("application/pdf", response.Header.Get("Content-Type"))
+		tr.Equal(fmt.Sprintf(`attachment; filename="receipt-%s.pdf"`, transactionId), response.Header.Get("Content-Disposition"))
+		tr.True(strings.HasPrefix(body, "%PDF-1.4\n"))
+		tr.True(strings.HasSuffix(body, "%%EOF\n"))
+		tr.Contains(body, "(Transaction: "+transactionId.String()+")")
+		tr.Contains(body, "(Amount: 25.50)")
+		tr.Contains(body, "(From: John Doe)")
+		tr.Contains(body, "(To: Richard Smith)")
+		tr.Contains(body, "("+receipt.Data.AuthenticationHash+")")
+	})
+}
+
+func TestTransactions(t *testing.T) {
+	suite.Run(t, new(TransactionsSuite))
+}
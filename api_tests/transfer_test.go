@@ -1,6 +1,7 @@
 package apitests_test
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -21,6 +22,7 @@ type TransferSuite struct {
 	customerDAO     daos.CustomerDAO
 	accountDAO      daos.AccountDAO
 	transactionDAO  daos.TransactionDAO
+	ledgerDAO       daos.LedgerDAO
 	testEnvironment *testhelpers.TestEnvironment
 }
 
@@ -30,46 +32,50 @@ func (tr *TransferSuite) SetupSuite() {
 	tr.customerDAO = daos.NewCustomerDAO(tr.testEnvironment.PgxPool())
 	tr.accountDAO = daos.NewAccountDAO(tr.testEnvironment.PgxPool())
 	tr.transactionDAO = daos.NewTransactionDAO(tr.testEnvironment.PgxPool())
+	tr.ledgerDAO = daos.NewLedgerDAO(tr.testEnvironment.PgxPool())
 }
 
 func (tr *TransferSuite) SetupTest() {
-	tr.customerDAO.DeleteAll()
-	tr.accountDAO.DeleteAll()
-	tr.transactionDAO.DeleteAll()
+	utils.ThrowOnError(tr.customerDAO.DeleteAll(context.Background()))
+	utils.ThrowOnError(tr.accountDAO.DeleteAll(context.Background()))
+	utils.ThrowOnError(tr.transactionDAO.DeleteAll(context.Background()))
+	utils.ThrowOnError(tr.testEnvironment.RedisClient().FlushAll(context.Background()).Err())
 }
 
 func (tr *TransferSuite) Test1() {
-	tr.Run("when transferring, then returns 204 and credits the receiver and debits the sender", func() {
-		tr.customerDAO.Create(daos.CustomerSchema{
+	tr.Run("when transferring, then returns 201 and credits the receiver and debits the sender", func() {
+		utils.ThrowOnError(tr.customerDAO.Create(context.Background(), daos.CustomerSchema{
 			Id:        uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
 			Name:      "John Doe",
 			Email:     "john.doe@gmail.com",
 			Password:  "$2a$10$asLIHej6kxd3Fsdc76QHieBugwCGvsYJeLiZmP1K7/t1GbIbUy.pK",
+			Status:    daos.CustomerStatusActive,
 			UpdatedAt: time.Now().UTC(),
 			CreatedAt: time.Now().UTC(),
-		})
-		tr.customerDAO.Create(daos.CustomerSchema{
+		}))
+		utils.ThrowOnError(tr.customerDAO.Create(context.Background(), daos.CustomerSchema{
 			Id:        uuid.MustParse("a06f5c45-f824-4cb1-a666-805035ae2ae1"),
 			Name:      "Richard Smith",
 			Email:     "richard.smith@gmail.com",
 			Password:  "$2a$10$1dS5NaFw0pZgGA.SvQ5awOm5jr36Z5pE2wl51mHHIQTz5fO9wwBTC",
+			Status:    daos.CustomerStatusActive,
 			UpdatedAt: time.Now().UTC(),
 			CreatedAt: time.Now().UTC(),
-		})
-		tr.accountDAO.Create(daos.AccountSchema{
+		}))
+		utils.ThrowOnError(tr.accountDAO.Create(context.Background(), daos.AccountSchema{
 			Id:         uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"),
 			CustomerId: uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
 			Balance:    12500,
 			UpdatedAt:  time.Now().UTC(),
 			CreatedAt:  time.Now().UTC(),
-		})
-		tr.accountDAO.Create(daos.AccountSchema{
+		}))
+		utils.ThrowOnError(tr.accountDAO.Create(context.Background(), daos.AccountSchema{
 			Id:         uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d"),
 			CustomerId: uuid.MustParse("a06f5c45-f824-4cb1-a666-805035ae2ae1"),
 			Balance:    3200,
 			UpdatedAt:  time.Now().UTC(),
 			CreatedAt:  time.Now().UTC(),
-		})
+		}))
 
 		request := utils.GetOrThrow(http.NewRequest("POST", tr.testEnvironment.BaseUrl()+"/v1/transfer", strings.NewReader(`
 			{
@@ -85,10 +91,9 @@ func (tr *TransferSuite) Test1() {
 		response := utils.GetOrThrow(tr.testEnvironment.Client().Do(request))
 
 		body := utils.GetOrThrow(io.ReadAll(response.Body))
-		tr.Equal(204, response.StatusCode)
-		tr.Equal("", string(body))
+		tr.Equal(201, response.StatusCode)
 
-		accountSender := tr.accountDAO.FindOneById(uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"))
+		accountSender := utils.GetOrThrow(tr.accountDAO.FindOneById(context.Background(), uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d")))
 		tr.Require().NotNil(accountSender)
 		tr.Require().True(utils.IsValidUUID(accountSender.Id.String()))
 		tr.Require().Equal("f59207c8-e837-4159-b67d-78c716510747", accountSender.CustomerId.String())
@@ -96,7 +101,7 @@ func (tr *TransferSuite) Test1() {
 		tr.Require().WithinDuration(time.Now().UTC(), accountSender.UpdatedAt, 5*time.Second)
 		tr.Require().WithinDuration(time.Now().UTC(), accountSender.CreatedAt, 5*time.Second)
 
-		accountReceiver := tr.accountDAO.FindOneById(uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d"))
+		accountReceiver := utils.GetOrThrow(tr.accountDAO.FindOneById(context.Background(), uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d")))
 		tr.Require().NotNil(accountReceiver)
 		tr.Require().True(utils.IsValidUUID(accountReceiver.Id.String()))
 		tr.Require().Equal("a06f5c45-f824-4cb1-a666-805035ae2ae1", accountReceiver.CustomerId.String())
@@ -104,8 +109,8 @@ func (tr *TransferSuite) Test1() {
 		tr.Require().WithinDuration(time.Now().UTC(), accountReceiver.UpdatedAt, 5*time.Second)
 		tr.Require().WithinDuration(time.Now().UTC(), accountReceiver.CreatedAt, 5*time.Second)
 
-		transactionSchema := tr.transactionDAO.FindOneByAccountSenderIdAndAccountReceiverId(uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"),
-			uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d"))
+		transactionSchema := utils.GetOrThrow(tr.transactionDAO.FindOneByAccountSenderIdAndAccountReceiverId(context.Background(), uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"),
+			uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d")))
 		tr.Require().NotNil(transactionSchema)
 		tr.Require().True(utils.IsValidUUID(transactionSchema.Id.String()))
 		tr.Require().Equal("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d", transactionSchema.AccountSenderId.String())
@@ -114,41 +119,63 @@ func (tr *TransferSuite) Test1() {
 		tr.Require().Equal(int64(2500), transactionSchema.Amount)
 		tr.Require().WithinDuration(time.Now().UTC(), transactionSchema.UpdatedAt, 5*time.Second)
 		tr.Require().WithinDuration(time.Now().UTC(), transactionSchema.CreatedAt, 5*time.Second)
+
+		tr.Equal("/v1/transactions/"+transactionSchema.Id.String(), response.Header.Get("Location"))
+		tr.JSONEq(fmt.Sprintf(`
+			{
+				"data": {
+					"id": "%s",
+					"status": "completed",
+					"transactionId": "%s"
+				}
+			}
+		`, transactionSchema.Id, transactionSchema.Id), string(body))
+
+		ledgerEntriesSchema := utils.GetOrThrow(tr.ledgerDAO.FindAllByJournalId(context.Background(), transactionSchema.Id))
+		tr.Require().Equal(2, len(ledgerEntriesSchema))
+		tr.Require().Equal("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d", ledgerEntriesSchema[0].AccountId.String())
+		tr.Require().Equal(daos.LedgerDirectionDebit, ledgerEntriesSchema[0].Direction)
+		tr.Require().Equal(int64(2500), ledgerEntriesSchema[0].Amount)
+		tr.Require().Equal("c7333b68-6f2a-46db-89c8-fd833fd3546d", ledgerEntriesSchema[1].AccountId.String())
+		tr.Require().Equal(daos.LedgerDirectionCredit, ledgerEntriesSchema[1].Direction)
+		tr.Require().Equal(int64(2500), ledgerEntriesSchema[1].Amount)
 	})
 }
 
 func (tr *TransferSuite) Test2() {
-	tr.Run("when transferring and there's concurrency, then returns 204 and credits the receiver and debits the sender", func() {
-		tr.customerDAO.Create(daos.CustomerSchema{
+	tr.Run("when transferring and there's concurrency, then returns 201 and credits the receiver and debits the sender", func() {
+		utils.ThrowOnError(tr.customerDAO.Create(context.Background(), daos.CustomerSchema{
 			Id:        uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
 			Name:      "John Doe",
 			Email:     "john.doe@gmail.com",
 			Password:  "$2a$10$asLIHej6kxd3Fsdc76QHieBugwCGvsYJeLiZmP1K7/t1GbIbUy.pK",
+			Status:    daos.CustomerStatusActive,
 			UpdatedAt: time.Now().UTC(),
 			CreatedAt: time.Now().UTC(),
-		})
-		tr.customerDAO.Create(daos.CustomerSchema{
+		}))
+		utils.ThrowOnError(tr.customerDAO.Create(context.Background(), daos.CustomerSchema{
 			Id:        uuid.MustParse("a06f5c45-f824-4cb1-a666-805035ae2ae1"),
 			Name:      "Richard Smith",
 			Email:     "richard.smith@gmail.com",
 			Password:  "$2a$10$1dS5NaFw0pZgGA.SvQ5awOm5jr36Z5pE2wl51mHHIQTz5fO9wwBTC",
+			Status:    daos.CustomerStatusActive,
 			UpdatedAt: time.Now().UTC(),
 			CreatedAt: time.Now().UTC(),
-		})
-		tr.accountDAO.Create(daos.AccountSchema{
+		}))
+		utils.ThrowOnError(tr.accountDAO.Create(context.Background(), daos.AccountSchema{
 			Id:         uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"),
 			CustomerId: uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
 			Balance:    10,
 			UpdatedAt:  time.Now().UTC(),
 			CreatedAt:  time.Now().UTC(),
-		})
-		tr.accountDAO.Create(daos.AccountSchema{
+		}))
+		utils.ThrowOnError(tr.accountDAO.Create(context.Background(), daos.AccountSchema{
 			Id:         uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d"),
 			CustomerId: uuid.MustParse("a06f5c45-f824-4cb1-a666-805035ae2ae1"),
 			Balance:    0,
 			UpdatedAt:  time.Now().UTC(),
 			CreatedAt:  time.Now().UTC(),
-		})
+		}))
 
 		var wg sync.WaitGroup
 
@@ -168,14 +195,14 @@ func (tr *TransferSuite) Test2() {
 				response := utils.GetOrThrow(tr.testEnvironment.Client().Do(request))
 
 				body := utils.GetOrThrow(io.ReadAll(response.Body))
-				tr.Equal(204, response.StatusCode)
-				tr.Equal("", string(body))
+				tr.Equal(201, response.StatusCode)
+				tr.Contains(string(body), `"status":"completed"`)
 			})
 		}
 
 		wg.Wait()
 
-		accountSender := tr.accountDAO.FindOneById(uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"))
+		accountSender := utils.GetOrThrow(tr.accountDAO.FindOneById(context.Background(), uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d")))
 		tr.Require().NotNil(accountSender)
 		tr.Require().True(utils.IsValidUUID(accountSender.Id.String()))
 		tr.Require().Equal("f59207c8-e837-4159-b67d-78c716510747", accountSender.CustomerId.String())
@@ -183,7 +210,7 @@ func (tr *TransferSuite) Test2() {
 		tr.Require().WithinDuration(time.Now().UTC(), accountSender.UpdatedAt, 5*time.Second)
 		tr.Require().WithinDuration(time.Now().UTC(), accountSender.CreatedAt, 5*time.Second)
 
-		accountReceiver := tr.accountDAO.FindOneById(uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d"))
+		accountReceiver := utils.GetOrThrow(tr.accountDAO.FindOneById(context.Background(), uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d")))
 		tr.Require().NotNil(accountReceiver)
 		tr.Require().True(utils.IsValidUUID(accountReceiver.Id.String()))
 		tr.Require().Equal("a06f5c45-f824-4cb1-a666-805035ae2ae1", accountReceiver.CustomerId.String())
@@ -191,44 +218,46 @@ func (tr *TransferSuite) Test2() {
 		tr.Require().WithinDuration(time.Now().UTC(), accountReceiver.UpdatedAt, 5*time.Second)
 		tr.Require().WithinDuration(time.Now().UTC(), accountReceiver.CreatedAt, 5*time.Second)
 
-		transactionSchema := tr.transactionDAO.FindAllByAccountSenderIdAndAccountReceiverId(uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"),
-			uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d"))
+		transactionSchema := utils.GetOrThrow(tr.transactionDAO.FindAllByAccountSenderIdAndAccountReceiverId(context.Background(), uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"),
+			uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d")))
 		tr.Require().Equal(10, len(transactionSchema))
 	})
 }
 
 func (tr *TransferSuite) Test3() {
-	tr.Run("when transferring more than once with the same idempotency key, then returns 204 and credits the receiver and debits the sender only once", func() {
-		tr.customerDAO.Create(daos.CustomerSchema{
+	tr.Run("when transferring more than once with the same idempotency key, then returns 201 and credits the receiver and debits the sender only once", func() {
+		utils.ThrowOnError(tr.customerDAO.Create(context.Background(), daos.CustomerSchema{
 			Id:        uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
 			Name:      "John Doe",
 			Email:     "john.doe@gmail.com",
 			Password:  "$2a$10$asLIHej6kxd3Fsdc76QHieBugwCGvsYJeLiZmP1K7/t1GbIbUy.pK",
+			Status:    daos.CustomerStatusActive,
 			UpdatedAt: time.Now().UTC(),
 			CreatedAt: time.Now().UTC(),
-		})
-		tr.customerDAO.Create(daos.CustomerSchema{
+		}))
+		utils.ThrowOnError(tr.customerDAO.Create(context.Background(), daos.CustomerSchema{
 			Id:        uuid.MustParse("a06f5c45-f824-4cb1-a666-805035ae2ae1"),
 			Name:      "Richard Smith",
 			Email:     "richard.smith@gmail.com",
 			Password:  "$2a$10$1dS5NaFw0pZgGA.SvQ5awOm5jr36Z5pE2wl51mHHIQTz5fO9wwBTC",
+			Status:    daos.CustomerStatusActive,
 			UpdatedAt: time.Now().UTC(),
 			CreatedAt: time.Now().UTC(),
-		})
-		tr.accountDAO.Create(daos.AccountSchema{
+		}))
+		utils.ThrowOnError(tr.accountDAO.Create(context.Background(), daos.AccountSchema{
 			Id:         uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"),
 			CustomerId: uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
 			Balance:    12500,
 			UpdatedAt:  time.Now().UTC(),
 			CreatedAt:  time.Now().UTC(),
-		})
-		tr.accountDAO.Create(daos.AccountSchema{
+		}))
+		utils.ThrowOnError(tr.accountDAO.Create(context.Background(), daos.AccountSchema{
 			Id:         uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d"),
 			CustomerId: uuid.MustParse("a06f5c45-f824-4cb1-a666-805035ae2ae1"),
 			Balance:    3200,
 			UpdatedAt:  time.Now().UTC(),
 			CreatedAt:  time.Now().UTC(),
-		})
+		}))
 
 		for range 4 {
 			request := utils.GetOrThrow(http.NewRequest("POST", tr.testEnvironment.BaseUrl()+"/v1/transfer", strings.NewReader(`
@@ -245,11 +274,11 @@ func (tr *TransferSuite) Test3() {
 			response := utils.GetOrThrow(tr.testEnvironment.Client().Do(request))
 
 			body := utils.GetOrThrow(io.ReadAll(response.Body))
-			tr.Equal(204, response.StatusCode)
-			tr.Equal("", string(body))
+			tr.Equal(201, response.StatusCode)
+			tr.Contains(string(body), `"status":"completed"`)
 		}
 
-		accountSender := tr.accountDAO.FindOneById(uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"))
+		accountSender := utils.GetOrThrow(tr.accountDAO.FindOneById(context.Background(), uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d")))
 		tr.Require().NotNil(accountSender)
 		tr.Require().True(utils.IsValidUUID(accountSender.Id.String()))
 		tr.Require().Equal("f59207c8-e837-4159-b67d-78c716510747", accountSender.CustomerId.String())
@@ -257,7 +286,7 @@ func (tr *TransferSuite) Test3() {
 		tr.Require().WithinDuration(time.Now().UTC(), accountSender.UpdatedAt, 5*time.Second)
 		tr.Require().WithinDuration(time.Now().UTC(), accountSender.CreatedAt, 5*time.Second)
 
-		accountReceiver := tr.accountDAO.FindOneById(uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d"))
+		accountReceiver := utils.GetOrThrow(tr.accountDAO.FindOneById(context.Background(), uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d")))
 		tr.Require().NotNil(accountReceiver)
 		tr.Require().True(utils.IsValidUUID(accountReceiver.Id.String()))
 		tr.Require().Equal("a06f5c45-f824-4cb1-a666-805035ae2ae1", accountReceiver.CustomerId.String())
@@ -265,8 +294,8 @@ func (tr *TransferSuite) Test3() {
 		tr.Require().WithinDuration(time.Now().UTC(), accountReceiver.UpdatedAt, 5*time.Second)
 		tr.Require().WithinDuration(time.Now().UTC(), accountReceiver.CreatedAt, 5*time.Second)
 
-		transactionSchema := tr.transactionDAO.FindAllByAccountSenderIdAndAccountReceiverId(uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"),
-			uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d"))
+		transactionSchema := utils.GetOrThrow(tr.transactionDAO.FindAllByAccountSenderIdAndAccountReceiverId(context.Background(), uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"),
+			uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d")))
 		tr.Require().Equal(1, len(transactionSchema))
 		tr.Require().True(utils.IsValidUUID(transactionSchema[0].Id.String()))
 		tr.Require().Equal("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d", transactionSchema[0].AccountSenderId.String())
@@ -295,11 +324,17 @@ func (tr *TransferSuite) Test4() {
 
 		body := utils.GetOrThrow(io.ReadAll(response.Body))
 		tr.Equal(409, response.StatusCode)
-		tr.JSONEq(`
+		tr.Equal("application/problem+json", response.Header.Get("Content-Type"))
+		tr.JSONEq(fmt.Sprintf(`
 			{
-				"message": "you cannot transfer to yourself"
+				"type": "/problems/transfer_to_yourself",
+				"title": "Conflict",
+				"status": 409,
+				"detail": "you cannot transfer to yourself",
+				"instance": "%s",
+				"code": "transfer_to_yourself"
 			}
-		`, string(body))
+		`, response.Header.Get("X-Request-Id")), string(body))
 	})
 }
 
@@ -320,11 +355,17 @@ func (tr *TransferSuite) Test5() {
 
 		body := utils.GetOrThrow(io.ReadAll(response.Body))
 		tr.Equal(409, response.StatusCode)
-		tr.JSONEq(`
+		tr.Equal("application/problem+json", response.Header.Get("Content-Type"))
+		tr.JSONEq(fmt.Sprintf(`
 			{
-				"message": "the amount to be transferred cannot be zero"
+				"type": "/problems/transfer_amount_zero",
+				"title": "Conflict",
+				"status": 409,
+				"detail": "the amount to be transferred cannot be zero",
+				"instance": "%s",
+				"code": "transfer_amount_zero"
 			}
-		`, string(body))
+		`, response.Header.Get("X-Request-Id")), string(body))
 	})
 }
 
@@ -344,11 +385,17 @@ func (tr *TransferSuite) Test6() {
 
 		body := utils.GetOrThrow(io.ReadAll(response.Body))
 		tr.Equal(400, response.StatusCode)
-		tr.JSONEq(`
+		tr.Equal("application/problem+json", response.Header.Get("Content-Type"))
+		tr.JSONEq(fmt.Sprintf(`
 			{
-				"message": "idempotency-key header is required"
+				"type": "/problems/idempotency_key_required",
+				"title": "Bad Request",
+				"status": 400,
+				"detail": "idempotency-key header is required",
+				"instance": "%s",
+				"code": "idempotency_key_required"
 			}
-		`, string(body))
+		`, response.Header.Get("X-Request-Id")), string(body))
 	})
 }
 
@@ -369,46 +416,54 @@ func (tr *TransferSuite) Test7() {
 
 		body := utils.GetOrThrow(io.ReadAll(response.Body))
 		tr.Equal(400, response.StatusCode)
-		tr.JSONEq(`
+		tr.Equal("application/problem+json", response.Header.Get("Content-Type"))
+		tr.JSONEq(fmt.Sprintf(`
 			{
-				"message": "idempotency-key header must be uuidv4"
+				"type": "/problems/idempotency_key_invalid",
+				"title": "Bad Request",
+				"status": 400,
+				"detail": "idempotency-key header must be uuidv4",
+				"instance": "%s",
+				"code": "idempotency_key_invalid"
 			}
-		`, string(body))
+		`, response.Header.Get("X-Request-Id")), string(body))
 	})
 }
 
 func (tr *TransferSuite) Test8() {
 	tr.Run("given that the sender has not enough balance, when transferring an amount higher than it's balance, then returns 409", func() {
-		tr.customerDAO.Create(daos.CustomerSchema{
+		utils.ThrowOnError(tr.customerDAO.Create(context.Background(), daos.CustomerSchema{
 			Id:        uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
 			Name:      "John Doe",
 			Email:     "john.doe@gmail.com",
 			Password:  "$2a$10$asLIHej6kxd3Fsdc76QHieBugwCGvsYJeLiZmP1K7/t1GbIbUy.pK",
+			Status:    daos.CustomerStatusActive,
 			UpdatedAt: time.Now().UTC(),
 			CreatedAt: time.Now().UTC(),
-		})
-		tr.customerDAO.Create(daos.CustomerSchema{
+		}))
+		utils.ThrowOnError(tr.customerDAO.Create(context.Background(), daos.CustomerSchema{
 			Id:        uuid.MustParse("a06f5c45-f824-4cb1-a666-805035ae2ae1"),
 			Name:      "Richard Smith",
 			Email:     "richard.smith@gmail.com",
 			Password:  "$2a$10$1dS5NaFw0pZgGA.SvQ5awOm5jr36Z5pE2wl51mHHIQTz5fO9wwBTC",
+			Status:    daos.CustomerStatusActive,
 			UpdatedAt: time.Now().UTC(),
 			CreatedAt: time.Now().UTC(),
-		})
-		tr.accountDAO.Create(daos.AccountSchema{
+		}))
+		utils.ThrowOnError(tr.accountDAO.Create(context.Background(), daos.AccountSchema{
 			Id:         uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"),
 			CustomerId: uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
 			Balance:    12500,
 			UpdatedAt:  time.Now().UTC(),
 			CreatedAt:  time.Now().UTC(),
-		})
-		tr.accountDAO.Create(daos.AccountSchema{
+		}))
+		utils.ThrowOnError(tr.accountDAO.Create(context.Background(), daos.AccountSchema{
 			Id:         uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d"),
 			CustomerId: uuid.MustParse("a06f5c45-f824-4cb1-a666-805035ae2ae1"),
 			Balance:    3200,
 			UpdatedAt:  time.Now().UTC(),
 			CreatedAt:  time.Now().UTC(),
-		})
+		}))
 
 		request := utils.GetOrThrow(http.NewRequest("POST", tr.testEnvironment.BaseUrl()+"/v1/transfer", strings.NewReader(`
 			{
@@ -425,11 +480,17 @@ func (tr *TransferSuite) Test8() {
 
 		body := utils.GetOrThrow(io.ReadAll(response.Body))
 		tr.Equal(409, response.StatusCode)
-		tr.JSONEq(`
+		tr.Equal("application/problem+json", response.Header.Get("Content-Type"))
+		tr.JSONEq(fmt.Sprintf(`
 			{
-				"message": "the sender does not have enough balance to make the transfer"
+				"type": "/problems/insufficient_balance",
+				"title": "Conflict",
+				"status": 409,
+				"detail": "the sender does not have enough balance to make the transfer",
+				"instance": "%s",
+				"code": "insufficient_balance"
 			}
-		`, string(body))
+		`, response.Header.Get("X-Request-Id")), string(body))
 	})
 }
 
@@ -438,9 +499,9 @@ func (tr *TransferSuite) Test9() {
 		templates := []map[string]string{
 			{
 				"body": `{}`,
-				"error": `[
-					"customerReceiverId is required",
-					"amount is required"
+				"errors": `[
+					{"field": "customerReceiverId", "message": "customerReceiverId is required"},
+					{"field": "amount", "message": "amount is required"}
 				]`,
 			},
 			{
@@ -448,9 +509,9 @@ func (tr *TransferSuite) Test9() {
 					"customerReceiverId": null,
 					"amount": null
 				}`,
-				"error": `[
-					"customerReceiverId is required",
-					"amount is required"
+				"errors": `[
+					{"field": "customerReceiverId", "message": "customerReceiverId is required"},
+					{"field": "amount", "message": "amount is required"}
 				]`,
 			},
 			{
@@ -458,9 +519,9 @@ func (tr *TransferSuite) Test9() {
 					"customerReceiverId": "",
 					"amount": ""
 				}`,
-				"error": `[
-					"customerReceiverId must be uuidv4",
-					"amount must be integer"
+				"errors": `[
+					{"field": "customerReceiverId", "message": "customerReceiverId must be uuidv4"},
+					{"field": "amount", "message": "amount must be integer"}
 				]`,
 			},
 			{
@@ -468,9 +529,9 @@ func (tr *TransferSuite) Test9() {
 					"customerReceiverId": " ",
 					"amount": " "
 				}`,
-				"error": `[
-					"customerReceiverId must be uuidv4",
-					"amount must be integer"
+				"errors": `[
+					{"field": "customerReceiverId", "message": "customerReceiverId must be uuidv4"},
+					{"field": "amount", "message": "amount must be integer"}
 				]`,
 			},
 			{
@@ -478,8 +539,8 @@ func (tr *TransferSuite) Test9() {
 					"customerReceiverId": 1,
 					"amount": 1
 				}`,
-				"error": `[
-					"customerReceiverId must be uuidv4"
+				"errors": `[
+					{"field": "customerReceiverId", "message": "customerReceiverId must be uuidv4"}
 				]`,
 			},
 			{
@@ -487,9 +548,9 @@ func (tr *TransferSuite) Test9() {
 					"customerReceiverId": 1.5,
 					"amount": 1.5
 				}`,
-				"error": `[
-					"customerReceiverId must be uuidv4",
-					"amount must be integer"
+				"errors": `[
+					{"field": "customerReceiverId", "message": "customerReceiverId must be uuidv4"},
+					{"field": "amount", "message": "amount must be integer"}
 				]`,
 			},
 			{
@@ -497,9 +558,9 @@ func (tr *TransferSuite) Test9() {
 					"customerReceiverId": -1,
 					"amount": -1
 				}`,
-				"error": `[
-					"customerReceiverId must be uuidv4",
-					"amount must be positive"
+				"errors": `[
+					{"field": "customerReceiverId", "message": "customerReceiverId must be uuidv4"},
+					{"field": "amount", "message": "amount must be positive"}
 				]`,
 			},
 			{
@@ -507,9 +568,9 @@ func (tr *TransferSuite) Test9() {
 					"customerReceiverId": true,
 					"amount": false
 				}`,
-				"error": `[
-					"customerReceiverId must be uuidv4",
-					"amount must be integer"
+				"errors": `[
+					{"field": "customerReceiverId", "message": "customerReceiverId must be uuidv4"},
+					{"field": "amount", "message": "amount must be integer"}
 				]`,
 			},
 			{
@@ -517,9 +578,9 @@ func (tr *TransferSuite) Test9() {
 					"customerReceiverId": {},
 					"amount": {}
 				}`,
-				"error": `[
-					"customerReceiverId must be uuidv4",
-					"amount must be integer"
+				"errors": `[
+					{"field": "customerReceiverId", "message": "customerReceiverId must be uuidv4"},
+					{"field": "amount", "message": "amount must be integer"}
 				]`,
 			},
 			{
@@ -527,9 +588,9 @@ func (tr *TransferSuite) Test9() {
 					"customerReceiverId": [],
 					"amount": []
 				}`,
-				"error": `[
-					"customerReceiverId must be uuidv4",
-					"amount must be integer"
+				"errors": `[
+					{"field": "customerReceiverId", "message": "customerReceiverId must be uuidv4"},
+					{"field": "amount", "message": "amount must be integer"}
 				]`,
 			},
 		}
@@ -545,12 +606,371 @@ func (tr *TransferSuite) Test9() {
 
 			body := utils.GetOrThrow(io.ReadAll(response.Body))
 			tr.Equal(400, response.StatusCode)
+			tr.Equal("application/problem+json", response.Header.Get("Content-Type"))
 			tr.JSONEq(fmt.Sprintf(`
 				{
-					"message": %s
+					"type": "/problems/validation_failed",
+					"title": "Bad Request",
+					"status": 400,
+					"detail": "the request has one or more invalid fields",
+					"instance": "%s",
+					"code": "validation_failed",
+					"errors": %s
 				}
-			`, template["error"]), string(body))
+			`, response.Header.Get("X-Request-Id"), template["errors"]), string(body))
+		}
+	})
+}
+
+func (tr *TransferSuite) Test10() {
+	tr.Run("given that the sender has not enough balance for every concurrent transfer, when transferring in parallel, then never overdraws the sender", func() {
+		utils.ThrowOnError(tr.customerDAO.Create(context.Background(), daos.CustomerSchema{
+			Id:        uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
+			Name:      "John Doe",
+			Email:     "john.doe@gmail.com",
+			Password:  "$2a$10$asLIHej6kxd3Fsdc76QHieBugwCGvsYJeLiZmP1K7/t1GbIbUy.pK",
+			Status:    daos.CustomerStatusActive,
+			UpdatedAt: time.Now().UTC(),
+			CreatedAt: time.Now().UTC(),
+		}))
+		utils.ThrowOnError(tr.customerDAO.Create(context.Background(), daos.CustomerSchema{
+			Id:        uuid.MustParse("a06f5c45-f824-4cb1-a666-805035ae2ae1"),
+			Name:      "Richard Smith",
+			Email:     "richard.smith@gmail.com",
+			Password:  "$2a$10$1dS5NaFw0pZgGA.SvQ5awOm5jr36Z5pE2wl51mHHIQTz5fO9wwBTC",
+			Status:    daos.CustomerStatusActive,
+			UpdatedAt: time.Now().UTC(),
+			CreatedAt: time.Now().UTC(),
+		}))
+		utils.ThrowOnError(tr.accountDAO.Create(context.Background(), daos.AccountSchema{
+			Id:         uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"),
+			CustomerId: uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
+			Balance:    5,
+			UpdatedAt:  time.Now().UTC(),
+			CreatedAt:  time.Now().UTC(),
+		}))
+		utils.ThrowOnError(tr.accountDAO.Create(context.Background(), daos.AccountSchema{
+			Id:         uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d"),
+			CustomerId: uuid.MustParse("a06f5c45-f824-4cb1-a666-805035ae2ae1"),
+			Balance:    0,
+			UpdatedAt:  time.Now().UTC(),
+			CreatedAt:  time.Now().UTC(),
+		}))
+
+		var wg sync.WaitGroup
+		var mutex sync.Mutex
+		statusCodes := map[int]int{}
+
+		for range 20 {
+			wg.Go(func() {
+				request := utils.GetOrThrow(http.NewRequest("POST", tr.testEnvironment.BaseUrl()+"/v1/transfer", strings.NewReader(`
+					{
+						"customerReceiverId": "a06f5c45-f824-4cb1-a666-805035ae2ae1",
+						"amount": 1
+					}
+				`)))
+				accessToken := testhelpers.TestGenerateAccessToken(uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"))
+				request.Header.Add("Content-Type", "application/json")
+				request.Header.Add("Idempotency-Key", uuid.New().String())
+				request.Header.Add("Authorization", "Bearer "+accessToken)
+
+				response := utils.GetOrThrow(tr.testEnvironment.Client().Do(request))
+				utils.ThrowOnError(response.Body.Close())
+
+				mutex.Lock()
+				statusCodes[response.StatusCode]++
+				mutex.Unlock()
+			})
+		}
+
+		wg.Wait()
+
+		tr.Require().Equal(map[int]int{201: 5, 409: 15}, statusCodes)
+
+		accountSender := utils.GetOrThrow(tr.accountDAO.FindOneById(context.Background(), uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d")))
+		tr.Require().NotNil(accountSender)
+		tr.Require().Equal(int64(0), accountSender.Balance)
+
+		accountReceiver := utils.GetOrThrow(tr.accountDAO.FindOneById(context.Background(), uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d")))
+		tr.Require().NotNil(accountReceiver)
+		tr.Require().Equal(int64(5), accountReceiver.Balance)
+
+		transactionSchema := utils.GetOrThrow(tr.transactionDAO.FindAllByAccountSenderIdAndAccountReceiverId(context.Background(), uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"),
+			uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d")))
+		tr.Require().Equal(5, len(transactionSchema))
+	})
+}
+
+func (tr *TransferSuite) Test11() {
+	tr.Run("when two customers transfer to each other in parallel, then returns 201 for every transfer without deadlocking", func() {
+		utils.ThrowOnError(tr.customerDAO.Create(context.Background(), daos.CustomerSchema{
+			Id:        uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
+			Name:      "John Doe",
+			Email:     "john.doe@gmail.com",
+			Password:  "$2a$10$asLIHej6kxd3Fsdc76QHieBugwCGvsYJeLiZmP1K7/t1GbIbUy.pK",
+			Status:    daos.CustomerStatusActive,
+			UpdatedAt: time.Now().UTC(),
+			CreatedAt: time.Now().UTC(),
+		}))
+		utils.ThrowOnError(tr.customerDAO.Create(context.Background(), daos.CustomerSchema{
+			Id:        uuid.MustParse("a06f5c45-f824-4cb1-a666-805035ae2ae1"),
+			Name:      "Richard Smith",
+			Email:     "richard.smith@gmail.com",
+			Password:  "$2a$10$1dS5NaFw0pZgGA.SvQ5awOm5jr36Z5pE2wl51mHHIQTz5fO9wwBTC",
+			Status:    daos.CustomerStatusActive,
+			UpdatedAt: time.Now().UTC(),
+			CreatedAt: time.Now().UTC(),
+		}))
+		utils.ThrowOnError(tr.accountDAO.Create(context.Background(), daos.AccountSchema{
+			Id:         uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"),
+			CustomerId: uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
+			Balance:    1000,
+			UpdatedAt:  time.Now().UTC(),
+			CreatedAt:  time.Now().UTC(),
+		}))
+		utils.ThrowOnError(tr.accountDAO.Create(context.Background(), daos.AccountSchema{
+			Id:         uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d"),
+			CustomerId: uuid.MustParse("a06f5c45-f824-4cb1-a666-805035ae2ae1"),
+			Balance:    1000,
+			UpdatedAt:  time.Now().UTC(),
+			CreatedAt:  time.Now().UTC(),
+		}))
+
+		var wg sync.WaitGroup
+		customers := [][]string{
+			{"f59207c8-e837-4159-b67d-78c716510747", "a06f5c45-f824-4cb1-a666-805035ae2ae1"},
+			{"a06f5c45-f824-4cb1-a666-805035ae2ae1", "f59207c8-e837-4159-b67d-78c716510747"},
+		}
+
+		for i := range 20 {
+			wg.Go(func() {
+				sender, receiver := customers[i%2][0], customers[i%2][1]
+
+				request := utils.GetOrThrow(http.NewRequest("POST", tr.testEnvironment.BaseUrl()+"/v1/transfer", strings.NewReader(fmt.Sprintf(`
+					{
+						"customerReceiverId": "%s",
+						"amount": 10
+					}
+				`, receiver))))
+				accessToken := testhelpers.TestGenerateAccessToken(uuid.MustParse(sender))
+				request.Header.Add("Content-Type", "application/json")
+				request.Header.Add("Idempotency-Key", uuid.New().String())
+				request.Header.Add("Authorization", "Bearer "+accessToken)
+
+				response := utils.GetOrThrow(tr.testEnvironment.Client().Do(request))
+				utils.ThrowOnError(response.Body.Close())
+				tr.Equal(201, response.StatusCode)
+			})
+		}
+
+		wg.Wait()
+
+		accountSender := utils.GetOrThrow(tr.accountDAO.FindOneById(context.Background(), uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d")))
+		tr.Require().NotNil(accountSender)
+		tr.Require().Equal(int64(1000), accountSender.Balance)
+
+		accountReceiver := utils.GetOrThrow(tr.accountDAO.FindOneById(context.Background(), uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d")))
+		tr.Require().NotNil(accountReceiver)
+		tr.Require().Equal(int64(1000), accountReceiver.Balance)
+	})
+}
+
+func (tr *TransferSuite) Test12() {
+	tr.Run("when retrying a transfer with the same idempotency key and a different body, then returns 422 and does not transfer again", func() {
+		utils.ThrowOnError(tr.customerDAO.Create(context.Background(), daos.CustomerSchema{
+			Id:        uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
+			Name:      "John Doe",
+			Email:     "john.doe@gmail.com",
+			Password:  "$2a$10$asLIHej6kxd3Fsdc76QHieBugwCGvsYJeLiZmP1K7/t1GbIbUy.pK",
+			Status:    daos.CustomerStatusActive,
+			UpdatedAt: time.Now().UTC(),
+			CreatedAt: time.Now().UTC(),
+		}))
+		utils.ThrowOnError(tr.customerDAO.Create(context.Background(), daos.CustomerSchema{
+			Id:        uuid.MustParse("a06f5c45-f824-4cb1-a666-805035ae2ae1"),
+			Name:      "Richard Smith",
+			Email:     "richard.smith@gmail.com",
+			Password:  "$2a$10$1dS5NaFw0pZgGA.SvQ5awOm5jr36Z5pE2wl51mHHIQTz5fO9wwBTC",
+			Status:    daos.CustomerStatusActive,
+			UpdatedAt: time.Now().UTC(),
+			CreatedAt: time.Now().UTC(),
+		}))
+		utils.ThrowOnError(tr.accountDAO.Create(context.Background(), daos.AccountSchema{
+			Id:         uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"),
+			CustomerId: uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
+			Balance:    12500,
+			UpdatedAt:  time.Now().UTC(),
+			CreatedAt:  time.Now().UTC(),
+		}))
+		utils.ThrowOnError(tr.accountDAO.Create(context.Background(), daos.AccountSchema{
+			Id:         uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d"),
+			CustomerId: uuid.MustParse("a06f5c45-f824-4cb1-a666-805035ae2ae1"),
+			Balance:    3200,
+			UpdatedAt:  time.Now().UTC(),
+			CreatedAt:  time.Now().UTC(),
+		}))
+
+		bodies := []string{
+			`{"customerReceiverId": "a06f5c45-f824-4cb1-a666-805035ae2ae1", "amount": 2500}`,
+			`{"customerReceiverId": "a06f5c45-f824-4cb1-a666-805035ae2ae1", "amount": 3000}`,
+		}
+		statusCodes := []int{}
+
+		for _, body := range bodies {
+			request := utils.GetOrThrow(http.NewRequest("POST", tr.testEnvironment.BaseUrl()+"/v1/transfer", strings.NewReader(body)))
+			accessToken := testhelpers.TestGenerateAccessToken(uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"))
+			request.Header.Add("Content-Type", "application/json")
+			request.Header.Add("Idempotency-Key", "2108b394-b875-40cf-9ee6-1d8bd6fb1ec5")
+			request.Header.Add("Authorization", "Bearer "+accessToken)
+
+			response := utils.GetOrThrow(tr.testEnvironment.Client().Do(request))
+			utils.ThrowOnError(response.Body.Close())
+			statusCodes = append(statusCodes, response.StatusCode)
 		}
+
+		tr.Require().Equal([]int{201, 422}, statusCodes)
+
+		accountSender := utils.GetOrThrow(tr.accountDAO.FindOneById(context.Background(), uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d")))
+		tr.Require().NotNil(accountSender)
+		tr.Require().Equal(int64(10000), accountSender.Balance)
+	})
+}
+
+func (tr *TransferSuite) Test13() {
+	tr.Run("when retrying a transfer concurrently with the same idempotency key, then returns 201 for every retry and transfers only once", func() {
+		utils.ThrowOnError(tr.customerDAO.Create(context.Background(), daos.CustomerSchema{
+			Id:        uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
+			Name:      "John Doe",
+			Email:     "john.doe@gmail.com",
+			Password:  "$2a$10$asLIHej6kxd3Fsdc76QHieBugwCGvsYJeLiZmP1K7/t1GbIbUy.pK",
+			Status:    daos.CustomerStatusActive,
+			UpdatedAt: time.Now().UTC(),
+			CreatedAt: time.Now().UTC(),
+		}))
+		utils.ThrowOnError(tr.customerDAO.Create(context.Background(), daos.CustomerSchema{
+			Id:        uuid.MustParse("a06f5c45-f824-4cb1-a666-805035ae2ae1"),
+			Name:      "Richard Smith",
+			Email:     "richard.smith@gmail.com",
+			Password:  "$2a$10$1dS5NaFw0pZgGA.SvQ5awOm5jr36Z5pE2wl51mHHIQTz5fO9wwBTC",
+			Status:    daos.CustomerStatusActive,
+			UpdatedAt: time.Now().UTC(),
+			CreatedAt: time.Now().UTC(),
+		}))
+		utils.ThrowOnError(tr.accountDAO.Create(context.Background(), daos.AccountSchema{
+			Id:         uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"),
+			CustomerId: uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
+			Balance:    12500,
+			UpdatedAt:  time.Now().UTC(),
+			CreatedAt:  time.Now().UTC(),
+		}))
+		utils.ThrowOnError(tr.accountDAO.Create(context.Background(), daos.AccountSchema{
+			Id:         uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d"),
+			CustomerId: uuid.MustParse("a06f5c45-f824-4cb1-a666-805035ae2ae1"),
+			Balance:    3200,
+			UpdatedAt:  time.Now().UTC(),
+			CreatedAt:  time.Now().UTC(),
+		}))
+
+		var wg sync.WaitGroup
+
+		for range 10 {
+			wg.Go(func() {
+				request := utils.GetOrThrow(http.NewRequest("POST", tr.testEnvironment.BaseUrl()+"/v1/transfer", strings.NewReader(`
+					{
+						"customerReceiverId": "a06f5c45-f824-4cb1-a666-805035ae2ae1",
+						"amount": 2500
+					}
+				`)))
+				accessToken := testhelpers.TestGenerateAccessToken(uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"))
+				request.Header.Add("Content-Type", "application/json")
+				request.Header.Add("Idempotency-Key", "2108b394-b875-40cf-9ee6-1d8bd6fb1ec5")
+				request.Header.Add("Authorization", "Bearer "+accessToken)
+
+				response := utils.GetOrThrow(tr.testEnvironment.Client().Do(request))
+				utils.ThrowOnError(response.Body.Close())
+				tr.Equal(201, response.StatusCode)
+			})
+		}
+
+		wg.Wait()
+
+		accountSender := utils.GetOrThrow(tr.accountDAO.FindOneById(context.Background(), uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d")))
+		tr.Require().NotNil(accountSender)
+		tr.Require().Equal(int64(10000), accountSender.Balance)
+
+		accountReceiver := utils.GetOrThrow(tr.accountDAO.FindOneById(context.Background(), uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d")))
+		tr.Require().NotNil(accountReceiver)
+		tr.Require().Equal(int64(5700), accountReceiver.Balance)
+
+		transactionSchema := utils.GetOrThrow(tr.transactionDAO.FindAllByAccountSenderIdAndAccountReceiverId(context.Background(), uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"),
+			uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d")))
+		tr.Require().Equal(1, len(transactionSchema))
+	})
+}
+
+func (tr *TransferSuite) Test14() {
+	tr.Run("given that the sender has not verified the email address, when transferring, then returns 403 and does not transfer", func() {
+		utils.ThrowOnError(tr.customerDAO.Create(context.Background(), daos.CustomerSchema{
+			Id:        uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
+			Name:      "John Doe",
+			Email:     "john.doe@gmail.com",
+			Password:  "$2a$10$asLIHej6kxd3Fsdc76QHieBugwCGvsYJeLiZmP1K7/t1GbIbUy.pK",
+			Status:    daos.CustomerStatusPendingVerification,
+			UpdatedAt: time.Now().UTC(),
+			CreatedAt: time.Now().UTC(),
+		}))
+		utils.ThrowOnError(tr.customerDAO.Create(context.Background(), daos.CustomerSchema{
+			Id:        uuid.MustParse("a06f5c45-f824-4cb1-a666-805035ae2ae1"),
+			Name:      "Richard Smith",
+			Email:     "richard.smith@gmail.com",
+			Password:  "$2a$10$1dS5NaFw0pZgGA.SvQ5awOm5jr36Z5pE2wl51mHHIQTz5fO9wwBTC",
+			Status:    daos.CustomerStatusActive,
+			UpdatedAt: time.Now().UTC(),
+			CreatedAt: time.Now().UTC(),
+		}))
+		utils.ThrowOnError(tr.accountDAO.Create(context.Background(), daos.AccountSchema{
+			Id:         uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d"),
+			CustomerId: uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"),
+			Balance:    12500,
+			UpdatedAt:  time.Now().UTC(),
+			CreatedAt:  time.Now().UTC(),
+		}))
+		utils.ThrowOnError(tr.accountDAO.Create(context.Background(), daos.AccountSchema{
+			Id:         uuid.MustParse("c7333b68-6f2a-46db-89c8-fd833fd3546d"),
+			CustomerId: uuid.MustParse("a06f5c45-f824-4cb1-a666-805035ae2ae1"),
+			Balance:    3200,
+			UpdatedAt:  time.Now().UTC(),
+			CreatedAt:  time.Now().UTC(),
+		}))
+
+		request := utils.GetOrThrow(http.NewRequest("POST", tr.testEnvironment.BaseUrl()+"/v1/transfer", strings.NewReader(`
+			{
+				"customerReceiverId": "a06f5c45-f824-4cb1-a666-805035ae2ae1",
+				"amount": 2500
+			}
+		`)))
+		accessToken := testhelpers.TestGenerateAccessToken(uuid.MustParse("f59207c8-e837-4159-b67d-78c716510747"))
+		request.Header.Add("Content-Type", "application/json")
+		request.Header.Add("Authorization", "Bearer "+accessToken)
+		request.Header.Add("Idempotency-Key", "2108b394-b875-40cf-9ee6-1d8bd6fb1ec5")
+
+		response := utils.GetOrThrow(tr.testEnvironment.Client().Do(request))
+
+		body := utils.GetOrThrow(io.ReadAll(response.Body))
+		tr.Equal(403, response.StatusCode)
+		tr.JSONEq(fmt.Sprintf(`
+			{
+				"type": "/problems/email_not_verified",
+				"title": "Forbidden",
+				"status": 403,
+				"detail": "email address must be verified before transferring",
+				"instance": "%s",
+				"code": "email_not_verified"
+			}
+		`, response.Header.Get("X-Request-Id")), string(body))
+
+		accountSender := utils.GetOrThrow(tr.accountDAO.FindOneById(context.Background(), uuid.MustParse("2a351ae8-cd0b-41c0-b28b-570f8dd5fb4d")))
+		tr.Require().Equal(int64(12500), accountSender.Balance)
 	})
 }
 
@@ -0,0 +1,167 @@
+package apitests_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	testhelpers "github.com/gsaaraujo/pay-bank-api/internal/test_helpers"
+	"github.com/gsaaraujo/pay-bank-api/internal/utils"
+	"github.com/stretchr/testify/suite"
+)
+
+type VerifyEmailSuite struct {
+	suite.Suite
+	customerDAO     daos.CustomerDAO
+	accountDAO      daos.AccountDAO
+	ledgerDAO       daos.LedgerDAO
+	testEnvironment *testhelpers.TestEnvironment
+}
+
+func (v *VerifyEmailSuite) SetupSuite() {
+	v.testEnvironment = testhelpers.NewTestEnvironment()
+	v.testEnvironment.Start()
+	v.customerDAO = daos.NewCustomerDAO(v.testEnvironment.PgxPool())
+	v.accountDAO = daos.NewAccountDAO(v.testEnvironment.PgxPool())
+	v.ledgerDAO = daos.NewLedgerDAO(v.testEnvironment.PgxPool())
+}
+
+func (v *VerifyEmailSuite) SetupTest() {
+	utils.ThrowOnError(v.customerDAO.DeleteAll(context.Background()))
+	v.testEnvironment.ClearNotifications()
+
+	response := utils.GetOrThrow(v.testEnvironment.Client().Post(v.testEnvironment.BaseUrl()+"/v1/sign-up", "application/json",
+		strings.NewReader(`{"name": "John Doe", "email": "john.doe@gmail.com", "password": "S3cret-pass"}`)))
+	utils.ThrowOnError(response.Body.Close())
+	v.Require().Equal(204, response.StatusCode)
+}
+
+func (v *VerifyEmailSuite) verifyEmail(token string) (*http.Response, string) {
+	response := utils.GetOrThrow(v.testEnvironment.Client().Get(v.testEnvironment.BaseUrl() + "/v1/verify-email?token=" + url.QueryEscape(token)))
+	body := utils.GetOrThrow(io.ReadAll(response.Body))
+
+	return response, string(body)
+}
+
+func (v *VerifyEmailSuite) resendEmailVerification(email string) {
+	response := utils.GetOrThrow(v.testEnvironment.Client().Post(v.testEnvironment.BaseUrl()+"/v1/verify-email/resend", "application/json",
+		strings.NewReader(fmt.Sprintf(`{"email": "%s"}`, email))))
+	body := utils.GetOrThrow(io.ReadAll(response.Body))
+
+	v.Require().Equal(202, response.StatusCode)
+	v.Require().Equal("", string(body))
+}
+
+func (v *VerifyEmailSuite) Test1() {
+	v.Run("when verifying the email, then activates the customer and grants the sign up bonus once", func() {
+		token := v.testEnvironment.EmailVerificationToken("john.doe@gmail.com")
+
+		response, body := v.verifyEmail(token)
+		v.Equal(204, response.StatusCode)
+		v.Equal("", body)
+
+		customerSchema := utils.GetOrThrow(v.customerDAO.FindOneByEmail(context.Background(), "john.doe@gmail.com"))
+		v.Require().Equal(daos.CustomerStatusActive, customerSchema.Status)
+
+		accountSchema := utils.GetOrThrow(v.accountDAO.FindOneByCustomerId(context.Background(), customerSchema.Id))
+		v.Require().Equal(int64(100000), accountSchema.Balance)
+
+		ledgerEntriesSchema := utils.GetOrThrow(v.ledgerDAO.FindAllByAccountId(context.Background(), accountSchema.Id))
+		v.Require().Equal(1, len(ledgerEntriesSchema))
+		v.Require().Equal(daos.LedgerDirectionCredit, ledgerEntriesSchema[0].Direction)
+		v.Require().Equal(int64(100000), ledgerEntriesSchema[0].Amount)
+
+		journalEntriesSchema := utils.GetOrThrow(v.ledgerDAO.FindAllByJournalId(context.Background(), ledgerEntriesSchema[0].JournalId))
+		v.Require().Equal(2, len(journalEntriesSchema))
+		v.Require().Nil(journalEntriesSchema[0].AccountId)
+		v.Require().Equal(daos.LedgerSystemAccountSignUpBonus, *journalEntriesSchema[0].SystemAccount)
+		v.Require().Equal(daos.LedgerDirectionDebit, journalEntriesSchema[0].Direction)
+
+		response, body = v.verifyEmail(token)
+		v.Equal(400, response.StatusCode)
+		v.JSONEq(fmt.Sprintf(`
+			{
+				"type": "/problems/email_verification_token_invalid",
+				"title": "Bad Request",
+				"status": 400,
+				"detail": "email verification token is invalid or expired",
+				"instance": "%s",
+				"code": "email_verification_token_invalid"
+			}
+		`, response.Header.Get("X-Request-Id")), body)
+
+		accountSchema = utils.GetOrThrow(v.accountDAO.FindOneByCustomerId(context.Background(), customerSchema.Id))
+		v.Require().Equal(int64(100000), accountSchema.Balance)
+	})
+}
+
+func (v *VerifyEmailSuite) Test2() {
+	v.Run("when resending the verification, then only the latest token verifies the email", func() {
+		firstToken := v.testEnvironment.EmailVerificationToken("john.doe@gmail.com")
+
+		v.resendEmailVerification("john.doe@gmail.com")
+
+		notifications := v.testEnvironment.Notifications()
+		v.Require().Len(notifications, 2)
+		v.Equal("Verify your email address", notifications[1].Subject)
+
+		secondToken := v.testEnvironment.EmailVerificationToken("john.doe@gmail.com")
+		v.NotEqual(firstToken, secondToken)
+
+		response, _ := v.verifyEmail(firstToken)
+		v.Equal(400, response.StatusCode)
+
+		response, _ = v.verifyEmail(secondToken)
+		v.Equal(204, response.StatusCode)
+
+		v.resendEmailVerification("john.doe@gmail.com")
+		v.resendEmailVerification("richard.smith@gmail.com")
+		v.Len(v.testEnvironment.Notifications(), 2)
+	})
+}
+
+func (v *VerifyEmailSuite) Test3() {
+	v.Run("given an expired token, when verifying the email, then returns 400 and the customer stays pending", func() {
+		_, err := v.testEnvironment.PgxPool().Exec(context.Background(),
+			"UPDATE email_verification_tokens SET expires_at = $1", time.Now().UTC().Add(-time.Minute))
+		v.Require().NoError(err)
+
+		response, _ := v.verifyEmail(v.testEnvironment.EmailVerificationToken("john.doe@gmail.com"))
+		v.Equal(400, response.StatusCode)
+
+		customerSchema := utils.GetOrThrow(v.customerDAO.FindOneByEmail(context.Background(), "john.doe@gmail.com"))
+		v.Require().Equal(daos.CustomerStatusPendingVerification, customerSchema.Status)
+	})
+}
+
+func (v *VerifyEmailSuite) Test4() {
+	v.Run("when the token is missing, then returns 400", func() {
+		response := utils.GetOrThrow(v.testEnvironment.Client().Get(v.testEnvironment.BaseUrl() + "/v1/verify-email"))
+		body := utils.GetOrThrow(io.ReadAll(response.Body))
+
+		v.Equal(400, response.StatusCode)
+		v.JSONEq(fmt.Sprintf(`
+			{
+				"type": "/problems/validation_failed",
+				"title": "Bad Request",
+				"status": 400,
+				"detail": "the request has one or more invalid fields",
+				"instance": "%s",
+				"code": "validation_failed",
+				"errors": [
+					{"field": "token", "message": "token is required"}
+				]
+			}
+		`, response.Header.Get("X-Request-Id")), string(body))
+	})
+}
+
+func TestVerifyEmail(t *testing.T) {
+	suite.Run(t, new(VerifyEmailSuite))
+}
@@ -0,0 +1,11 @@
+package main
+
+import (
+	"os"
+
+	"github.com/gsaaraujo/pay-bank-api/internal"
+)
+
+func main() {
+	os.Exit(internal.NewReconcileCommand().Run(os.Args[1:]))
+}
@@ -2,10 +2,10 @@ package daos
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/gsaaraujo/pay-bank-api/internal/utils"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -26,48 +26,109 @@ func NewAccountDAO(pgxPool *pgxpool.Pool) AccountDAO {
 	return AccountDAO{pgxPool}
 }
 
-func (p *AccountDAO) Create(accountSchema AccountSchema) {
-	_ = utils.GetOrThrow(p.pgxPool.Exec(context.Background(),
-		"INSERT INTO accounts (id, customer_id, balance, created_at, updated_at) VALUES ($1, $2, $3, $4, $5)",
-		accountSchema.Id, accountSchema.CustomerId, accountSchema.Balance, accountSchema.UpdatedAt, accountSchema.CreatedAt))
+// Create posts a positive balance as an opening balance journal, the way the ledger backfill
+// does, so the ledger explains it and recomputing the balance from the ledger keeps it.
+func (p *AccountDAO) Create(ctx context.Context, accountSchema AccountSchema) error {
+	return pgx.BeginFunc(ctx, p.pgxPool, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx,
+			"INSERT INTO accounts (id, customer_id, balance, created_at, updated_at) VALUES ($1, $2, $3, $4, $5)",
+			accountSchema.Id, accountSchema.CustomerId, accountSchema.Balance, accountSchema.CreatedAt, accountSchema.UpdatedAt)
+		if err != nil || accountSchema.Balance <= 0 {
+			return err
+		}
+
+		ledgerDAO := NewLedgerDAO(p.pgxPool)
+		journalId := uuid.New()
+		systemAccount := LedgerSystemAccountOpeningBalance
+
+		return ledgerDAO.CreateAll(ctx, tx, []LedgerEntrySchema{
+			{
+				Id:            uuid.New(),
+				JournalId:     journalId,
+				SystemAccount: &systemAccount,
+				Direction:     LedgerDirectionDebit,
+				Amount:        accountSchema.Balance,
+				CreatedAt:     accountSchema.CreatedAt,
+			},
+			{
+				Id:        uuid.New(),
+				JournalId: journalId,
+				AccountId: &accountSchema.Id,
+				Direction: LedgerDirectionCredit,
+				Amount:    accountSchema.Balance,
+				CreatedAt: accountSchema.CreatedAt,
+			},
+		})
+	})
 }
 
-func (c *AccountDAO) FindOneById(id uuid.UUID) *AccountSchema {
+func (c *AccountDAO) FindOneById(ctx context.Context, id uuid.UUID) (*AccountSchema, error) {
 	var accountSchema AccountSchema
 
-	err := c.pgxPool.QueryRow(context.Background(),
+	err := c.pgxPool.QueryRow(ctx,
 		"SELECT id, customer_id, balance, created_at, updated_at FROM accounts WHERE id = $1", id).
 		Scan(&accountSchema.Id, &accountSchema.CustomerId, &accountSchema.Balance, &accountSchema.CreatedAt, &accountSchema.UpdatedAt)
 
-	if err != nil && err == pgx.ErrNoRows {
-		return nil
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
 	}
 
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
-	return &accountSchema
+	return &accountSchema, nil
 }
 
-func (c *AccountDAO) FindOneByCustomerId(customerId uuid.UUID) *AccountSchema {
+func (c *AccountDAO) FindOneByCustomerId(ctx context.Context, customerId uuid.UUID) (*AccountSchema, error) {
 	var accountSchema AccountSchema
 
-	err := c.pgxPool.QueryRow(context.Background(),
+	err := c.pgxPool.QueryRow(ctx,
 		"SELECT id, customer_id, balance, created_at, updated_at FROM accounts WHERE customer_id = $1", customerId).
 		Scan(&accountSchema.Id, &accountSchema.CustomerId, &accountSchema.Balance, &accountSchema.CreatedAt, &accountSchema.UpdatedAt)
 
-	if err != nil && err == pgx.ErrNoRows {
-		return nil
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
 	}
 
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
-	return &accountSchema
+	return &accountSchema, nil
 }
 
-func (c *AccountDAO) DeleteAll() {
-	_ = utils.GetOrThrow(c.pgxPool.Exec(context.Background(), "TRUNCATE TABLE accounts CASCADE"))
+// FindAllByIdsForUpdate locks the accounts ordered by id so that concurrent
+// transactions touching the same accounts always acquire the locks in the same order.
+func (c *AccountDAO) FindAllByIdsForUpdate(ctx context.Context, tx pgx.Tx, ids []uuid.UUID) ([]AccountSchema, error) {
+	rows, err := tx.Query(ctx,
+		"SELECT id, customer_id, balance, created_at, updated_at FROM accounts WHERE id = ANY($1) ORDER BY id FOR UPDATE", ids)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (AccountSchema, error) {
+		var item AccountSchema
+		err := row.Scan(&item.Id, &item.CustomerId, &item.Balance, &item.CreatedAt, &item.UpdatedAt)
+		return item, err
+	})
+}
+
+// UpdateBalancesFromLedger sets the cached balance of the accounts to the sum of their ledger
+// entries, so it is written from the entries posted in tx rather than alongside them.
+func (c *AccountDAO) UpdateBalancesFromLedger(ctx context.Context, tx pgx.Tx, ids []uuid.UUID, updatedAt time.Time) error {
+	_, err := tx.Exec(ctx,
+		`UPDATE accounts SET updated_at = $2, balance = COALESCE((
+			SELECT SUM(CASE WHEN le.direction = 'credit' THEN le.amount ELSE -le.amount END)
+			FROM ledger_entries le
+			WHERE le.account_id = accounts.id
+		), 0)
+		WHERE id = ANY($1)`, ids, updatedAt)
+
+	return err
+}
+
+func (c *AccountDAO) DeleteAll(ctx context.Context) error {
+	_, err := c.pgxPool.Exec(ctx, "TRUNCATE TABLE accounts CASCADE")
+	return err
 }
@@ -2,19 +2,27 @@ package daos
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/gsaaraujo/pay-bank-api/internal/utils"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// A customer signs up pending verification and becomes active once the email address is
+// verified.
+const (
+	CustomerStatusPendingVerification = "pending_verification"
+	CustomerStatusActive              = "active"
+)
+
 type CustomerSchema struct {
 	Id        uuid.UUID
 	Name      string
 	Email     string
 	Password  string
+	Status    string
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -27,48 +35,71 @@ func NewCustomerDAO(pgxPool *pgxpool.Pool) CustomerDAO {
 	return CustomerDAO{pgxPool}
 }
 
-func (p *CustomerDAO) Create(customerSchema CustomerSchema) {
-	_ = utils.GetOrThrow(p.pgxPool.Exec(context.Background(),
-		"INSERT INTO customers (id, name, email, password, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6)",
-		customerSchema.Id, customerSchema.Name, customerSchema.Email, customerSchema.Password, customerSchema.CreatedAt, customerSchema.UpdatedAt))
+func (p *CustomerDAO) Create(ctx context.Context, customerSchema CustomerSchema) error {
+	_, err := p.pgxPool.Exec(ctx,
+		"INSERT INTO customers (id, name, email, password, status, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7)",
+		customerSchema.Id, customerSchema.Name, customerSchema.Email, customerSchema.Password, customerSchema.Status, customerSchema.CreatedAt,
+		customerSchema.UpdatedAt)
+
+	return err
 }
 
-func (c *CustomerDAO) FindOneById(id uuid.UUID) *CustomerSchema {
+func (c *CustomerDAO) FindOneById(ctx context.Context, id uuid.UUID) (*CustomerSchema, error) {
 	var customerSchema CustomerSchema
 
-	err := c.pgxPool.QueryRow(context.Background(),
-		"SELECT id, name, email, password, created_at, updated_at FROM customers WHERE id = $1", id).
-		Scan(&customerSchema.Id, &customerSchema.Name, &customerSchema.Email, &customerSchema.Password, &customerSchema.CreatedAt, &customerSchema.UpdatedAt)
+	err := c.pgxPool.QueryRow(ctx,
+		"SELECT id, name, email, password, status, created_at, updated_at FROM customers WHERE id = $1", id).
+		Scan(&customerSchema.Id, &customerSchema.Name, &customerSchema.Email, &customerSchema.Password, &customerSchema.Status, &customerSchema.CreatedAt,
+			&customerSchema.UpdatedAt)
 
-	if err != nil && err == pgx.ErrNoRows {
-		return nil
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
 	}
 
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
-	return &customerSchema
+	return &customerSchema, nil
 }
 
-func (c *CustomerDAO) FindOneByEmail(email string) *CustomerSchema {
+func (c *CustomerDAO) FindOneByEmail(ctx context.Context, email string) (*CustomerSchema, error) {
 	var customerSchema CustomerSchema
 
-	err := c.pgxPool.QueryRow(context.Background(),
-		"SELECT id, name, email, password, created_at, updated_at FROM customers WHERE email = $1", email).
-		Scan(&customerSchema.Id, &customerSchema.Name, &customerSchema.Email, &customerSchema.Password, &customerSchema.CreatedAt, &customerSchema.UpdatedAt)
+	err := c.pgxPool.QueryRow(ctx,
+		"SELECT id, name, email, password, status, created_at, updated_at FROM customers WHERE email = $1", email).
+		Scan(&customerSchema.Id, &customerSchema.Name, &customerSchema.Email, &customerSchema.Password, &customerSchema.Status, &customerSchema.CreatedAt,
+			&customerSchema.UpdatedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
 
-	if err != nil && err == pgx.ErrNoRows {
-		return nil
+	if err != nil {
+		return nil, err
 	}
 
+	return &customerSchema, nil
+}
+
+func (c *CustomerDAO) UpdatePassword(ctx context.Context, tx pgx.Tx, id uuid.UUID, password string, updatedAt time.Time) error {
+	_, err := tx.Exec(ctx, "UPDATE customers SET password = $1, updated_at = $2 WHERE id = $3", password, updatedAt, id)
+	return err
+}
+
+// Activate marks a customer pending verification as active, returning false when the customer
+// was already active.
+func (c *CustomerDAO) Activate(ctx context.Context, tx pgx.Tx, id uuid.UUID, updatedAt time.Time) (bool, error) {
+	commandTag, err := tx.Exec(ctx, "UPDATE customers SET status = $1, updated_at = $2 WHERE id = $3 AND status = $4",
+		CustomerStatusActive, updatedAt, id, CustomerStatusPendingVerification)
 	if err != nil {
-		panic(err)
+		return false, err
 	}
 
-	return &customerSchema
+	return commandTag.RowsAffected() == 1, nil
 }
 
-func (c *CustomerDAO) DeleteAll() {
-	_ = utils.GetOrThrow(c.pgxPool.Exec(context.Background(), "TRUNCATE TABLE customers CASCADE"))
+func (c *CustomerDAO) DeleteAll(ctx context.Context) error {
+	_, err := c.pgxPool.Exec(ctx, "TRUNCATE TABLE customers CASCADE")
+	return err
 }
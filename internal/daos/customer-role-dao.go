@@ -0,0 +1,57 @@
+package daos
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CustomerRoleSchema struct {
+	CustomerId uuid.UUID
+	Role       string
+	CreatedAt  time.Time
+}
+
+type CustomerRoleDAO struct {
+	pgxPool *pgxpool.Pool
+}
+
+func NewCustomerRoleDAO(pgxPool *pgxpool.Pool) CustomerRoleDAO {
+	return CustomerRoleDAO{pgxPool}
+}
+
+// Create assigns the role, assigning a role the customer already has is a no-op.
+func (c *CustomerRoleDAO) Create(ctx context.Context, customerRoleSchema CustomerRoleSchema) error {
+	_, err := c.pgxPool.Exec(ctx,
+		"INSERT INTO customer_roles (customer_id, role, created_at) VALUES ($1, $2, $3) ON CONFLICT (customer_id, role) DO NOTHING",
+		customerRoleSchema.CustomerId, customerRoleSchema.Role, customerRoleSchema.CreatedAt)
+
+	return err
+}
+
+func (c *CustomerRoleDAO) FindAllByCustomerId(ctx context.Context, customerId uuid.UUID) ([]CustomerRoleSchema, error) {
+	rows, err := c.pgxPool.Query(ctx,
+		"SELECT customer_id, role, created_at FROM customer_roles WHERE customer_id = $1 ORDER BY role", customerId)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (CustomerRoleSchema, error) {
+		var item CustomerRoleSchema
+		err := row.Scan(&item.CustomerId, &item.Role, &item.CreatedAt)
+		return item, err
+	})
+}
+
+func (c *CustomerRoleDAO) DeleteOneByCustomerIdAndRole(ctx context.Context, customerId uuid.UUID, role string) error {
+	_, err := c.pgxPool.Exec(ctx, "DELETE FROM customer_roles WHERE customer_id = $1 AND role = $2", customerId, role)
+	return err
+}
+
+func (c *CustomerRoleDAO) DeleteAll(ctx context.Context) error {
+	_, err := c.pgxPool.Exec(ctx, "TRUNCATE TABLE customer_roles CASCADE")
+	return err
+}
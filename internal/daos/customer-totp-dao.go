@@ -0,0 +1,109 @@
+package daos
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CustomerTotpSchema is the TOTP authenticator of a customer. It only protects the account
+// once ConfirmedAt is set. LastUsedStep is the time step of the last accepted code, codes of
+// that step or earlier are rejected.
+type CustomerTotpSchema struct {
+	CustomerId   uuid.UUID
+	Secret       string
+	LastUsedStep int64
+	ConfirmedAt  *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type CustomerTotpDAO struct {
+	pgxPool *pgxpool.Pool
+}
+
+func NewCustomerTotpDAO(pgxPool *pgxpool.Pool) CustomerTotpDAO {
+	return CustomerTotpDAO{pgxPool}
+}
+
+// CreateOrReplacePending stores a new enrollment, replacing one that was never confirmed. A
+// confirmed authenticator is left untouched.
+func (c *CustomerTotpDAO) CreateOrReplacePending(ctx context.Context, customerTotpSchema CustomerTotpSchema) error {
+	_, err := c.pgxPool.Exec(ctx,
+		`INSERT INTO customer_totp (customer_id, secret, last_used_step, confirmed_at, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (customer_id) DO UPDATE SET secret = EXCLUDED.secret, last_used_step = EXCLUDED.last_used_step,
+		created_at = EXCLUDED.created_at, updated_at = EXCLUDED.updated_at
+		WHERE customer_totp.confirmed_at IS NULL`,
+		customerTotpSchema.CustomerId, customerTotpSchema.Secret, customerTotpSchema.LastUsedStep, customerTotpSchema.ConfirmedAt,
+		customerTotpSchema.CreatedAt, customerTotpSchema.UpdatedAt)
+
+	return err
+}
+
+func (c *CustomerTotpDAO) FindOneByCustomerId(ctx context.Context, customerId uuid.UUID) (*CustomerTotpSchema, error) {
+	var customerTotpSchema CustomerTotpSchema
+
+	err := c.pgxPool.QueryRow(ctx,
+		"SELECT customer_id, secret, last_used_step, confirmed_at, created_at, updated_at FROM customer_totp WHERE customer_id = $1", customerId).
+		Scan(&customerTotpSchema.CustomerId, &customerTotpSchema.Secret, &customerTotpSchema.LastUsedStep, &customerTotpSchema.ConfirmedAt,
+			&customerTotpSchema.CreatedAt, &customerTotpSchema.UpdatedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &customerTotpSchema, nil
+}
+
+// FindOneByCustomerIdForUpdate locks the authenticator so that two requests with the same code
+// are serialized and only the first one is accepted.
+func (c *CustomerTotpDAO) FindOneByCustomerIdForUpdate(ctx context.Context, tx pgx.Tx, customerId uuid.UUID) (*CustomerTotpSchema, error) {
+	var customerTotpSchema CustomerTotpSchema
+
+	err := tx.QueryRow(ctx,
+		"SELECT customer_id, secret, last_used_step, confirmed_at, created_at, updated_at FROM customer_totp WHERE customer_id = $1 FOR UPDATE", customerId).
+		Scan(&customerTotpSchema.CustomerId, &customerTotpSchema.Secret, &customerTotpSchema.LastUsedStep, &customerTotpSchema.ConfirmedAt,
+			&customerTotpSchema.CreatedAt, &customerTotpSchema.UpdatedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &customerTotpSchema, nil
+}
+
+func (c *CustomerTotpDAO) UpdateLastUsedStep(ctx context.Context, tx pgx.Tx, customerId uuid.UUID, lastUsedStep int64, updatedAt time.Time) error {
+	_, err := tx.Exec(ctx, "UPDATE customer_totp SET last_used_step = $1, updated_at = $2 WHERE customer_id = $3",
+		lastUsedStep, updatedAt, customerId)
+
+	return err
+}
+
+func (c *CustomerTotpDAO) Confirm(ctx context.Context, tx pgx.Tx, customerId uuid.UUID, lastUsedStep int64, confirmedAt time.Time) error {
+	_, err := tx.Exec(ctx, "UPDATE customer_totp SET last_used_step = $1, confirmed_at = $2, updated_at = $2 WHERE customer_id = $3",
+		lastUsedStep, confirmedAt, customerId)
+
+	return err
+}
+
+func (c *CustomerTotpDAO) DeleteOneByCustomerId(ctx context.Context, tx pgx.Tx, customerId uuid.UUID) error {
+	_, err := tx.Exec(ctx, "DELETE FROM customer_totp WHERE customer_id = $1", customerId)
+	return err
+}
+
+func (c *CustomerTotpDAO) DeleteAll(ctx context.Context) error {
+	_, err := c.pgxPool.Exec(ctx, "TRUNCATE TABLE customer_totp CASCADE")
+	return err
+}
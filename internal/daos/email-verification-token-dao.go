@@ -0,0 +1,89 @@
+package daos
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// EmailVerificationTokenSchema stores only the SHA-256 of the token, which is sent to the
+// email address being verified.
+type EmailVerificationTokenSchema struct {
+	Id         uuid.UUID
+	CustomerId uuid.UUID
+	TokenHash  string
+	ExpiresAt  time.Time
+	UsedAt     *time.Time
+	CreatedAt  time.Time
+}
+
+type EmailVerificationTokenDAO struct {
+	pgxPool *pgxpool.Pool
+}
+
+func NewEmailVerificationTokenDAO(pgxPool *pgxpool.Pool) EmailVerificationTokenDAO {
+	return EmailVerificationTokenDAO{pgxPool}
+}
+
+func (p *EmailVerificationTokenDAO) Create(ctx context.Context, tx pgx.Tx, emailVerificationTokenSchema EmailVerificationTokenSchema) error {
+	_, err := tx.Exec(ctx,
+		"INSERT INTO email_verification_tokens (id, customer_id, token_hash, expires_at, used_at, created_at) VALUES ($1, $2, $3, $4, $5, $6)",
+		emailVerificationTokenSchema.Id, emailVerificationTokenSchema.CustomerId, emailVerificationTokenSchema.TokenHash, emailVerificationTokenSchema.ExpiresAt,
+		emailVerificationTokenSchema.UsedAt, emailVerificationTokenSchema.CreatedAt)
+
+	return err
+}
+
+// FindOneByTokenHashForUpdate locks the token so that it can only be used once even when
+// submitted twice concurrently.
+func (p *EmailVerificationTokenDAO) FindOneByTokenHashForUpdate(ctx context.Context, tx pgx.Tx, tokenHash string) (*EmailVerificationTokenSchema, error) {
+	var emailVerificationTokenSchema EmailVerificationTokenSchema
+
+	err := tx.QueryRow(ctx,
+		"SELECT id, customer_id, token_hash, expires_at, used_at, created_at FROM email_verification_tokens WHERE token_hash = $1 FOR UPDATE", tokenHash).
+		Scan(&emailVerificationTokenSchema.Id, &emailVerificationTokenSchema.CustomerId, &emailVerificationTokenSchema.TokenHash, &emailVerificationTokenSchema.ExpiresAt,
+			&emailVerificationTokenSchema.UsedAt, &emailVerificationTokenSchema.CreatedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &emailVerificationTokenSchema, nil
+}
+
+// MarkAllAsUsedByCustomerId spends every pending token of the customer, so only the latest
+// one sent, or none once the email address was verified, can be used.
+func (p *EmailVerificationTokenDAO) MarkAllAsUsedByCustomerId(ctx context.Context, tx pgx.Tx, customerId uuid.UUID, usedAt time.Time) error {
+	_, err := tx.Exec(ctx,
+		"UPDATE email_verification_tokens SET used_at = $1 WHERE customer_id = $2 AND used_at IS NULL", usedAt, customerId)
+
+	return err
+}
+
+func (p *EmailVerificationTokenDAO) FindAllByCustomerId(ctx context.Context, customerId uuid.UUID) ([]EmailVerificationTokenSchema, error) {
+	rows, err := p.pgxPool.Query(ctx,
+		"SELECT id, customer_id, token_hash, expires_at, used_at, created_at FROM email_verification_tokens WHERE customer_id = $1 ORDER BY created_at, id",
+		customerId)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (EmailVerificationTokenSchema, error) {
+		var item EmailVerificationTokenSchema
+		err := row.Scan(&item.Id, &item.CustomerId, &item.TokenHash, &item.ExpiresAt, &item.UsedAt, &item.CreatedAt)
+		return item, err
+	})
+}
+
+func (p *EmailVerificationTokenDAO) DeleteAll(ctx context.Context) error {
+	_, err := p.pgxPool.Exec(ctx, "TRUNCATE TABLE email_verification_tokens CASCADE")
+	return err
+}
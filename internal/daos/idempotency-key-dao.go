@@ -0,0 +1,88 @@
+package daos
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// The outcome of the request that claimed an idempotency key: the transfer was either made
+// right away or accepted to be processed later.
+const (
+	IdempotencyKeyOutcomeCompleted = "completed"
+	IdempotencyKeyOutcomeAccepted  = "accepted"
+)
+
+type IdempotencyKeySchema struct {
+	CustomerId         uuid.UUID
+	IdempotencyKey     string
+	RequestFingerprint string
+	TransferId         *uuid.UUID
+	Outcome            *string
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+type IdempotencyKeyDAO struct {
+	pgxPool *pgxpool.Pool
+}
+
+func NewIdempotencyKeyDAO(pgxPool *pgxpool.Pool) IdempotencyKeyDAO {
+	return IdempotencyKeyDAO{pgxPool}
+}
+
+// CreateIfNotExists claims the key for the customer. When another transaction holds an
+// uncommitted claim for the same key, the insert waits for it to finish, so a false return
+// always means the key was claimed by a committed transaction.
+func (i *IdempotencyKeyDAO) CreateIfNotExists(ctx context.Context, tx pgx.Tx, idempotencyKeySchema IdempotencyKeySchema) (bool, error) {
+	commandTag, err := tx.Exec(ctx,
+		`INSERT INTO idempotency_keys (customer_id, idempotency_key, request_fingerprint, created_at, updated_at) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (customer_id, idempotency_key) DO NOTHING`,
+		idempotencyKeySchema.CustomerId, idempotencyKeySchema.IdempotencyKey, idempotencyKeySchema.RequestFingerprint,
+		idempotencyKeySchema.CreatedAt, idempotencyKeySchema.UpdatedAt)
+	if err != nil {
+		return false, err
+	}
+
+	return commandTag.RowsAffected() == 1, nil
+}
+
+func (i *IdempotencyKeyDAO) FindOneByCustomerIdAndIdempotencyKey(ctx context.Context, tx pgx.Tx, customerId uuid.UUID,
+	idempotencyKey string) (*IdempotencyKeySchema, error) {
+	var idempotencyKeySchema IdempotencyKeySchema
+
+	err := tx.QueryRow(ctx,
+		`SELECT customer_id, idempotency_key, request_fingerprint, transfer_id, outcome, created_at, updated_at
+		FROM idempotency_keys WHERE customer_id = $1 AND idempotency_key = $2`, customerId, idempotencyKey).
+		Scan(&idempotencyKeySchema.CustomerId, &idempotencyKeySchema.IdempotencyKey, &idempotencyKeySchema.RequestFingerprint, &idempotencyKeySchema.TransferId,
+			&idempotencyKeySchema.Outcome, &idempotencyKeySchema.CreatedAt, &idempotencyKeySchema.UpdatedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &idempotencyKeySchema, nil
+}
+
+func (i *IdempotencyKeyDAO) UpdateResult(ctx context.Context, tx pgx.Tx, idempotencyKeySchema IdempotencyKeySchema) error {
+	_, err := tx.Exec(ctx,
+		`UPDATE idempotency_keys SET transfer_id = $1, outcome = $2, updated_at = $3
+		WHERE customer_id = $4 AND idempotency_key = $5`,
+		idempotencyKeySchema.TransferId, idempotencyKeySchema.Outcome, idempotencyKeySchema.UpdatedAt,
+		idempotencyKeySchema.CustomerId, idempotencyKeySchema.IdempotencyKey)
+
+	return err
+}
+
+func (i *IdempotencyKeyDAO) DeleteAll(ctx context.Context) error {
+	_, err := i.pgxPool.Exec(ctx, "TRUNCATE TABLE idempotency_keys CASCADE")
+	return err
+}
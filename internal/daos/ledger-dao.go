@@ -0,0 +1,86 @@
+package daos
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	LedgerDirectionDebit  = "debit"
+	LedgerDirectionCredit = "credit"
+
+	LedgerSystemAccountSignUpBonus    = "sign_up_bonus"
+	LedgerSystemAccountOpeningBalance = "opening_balance"
+)
+
+// LedgerEntrySchema is one side of a journal. Customer accounts are liabilities of the bank,
+// so a credit increases their balance and a debit decreases it. Entries that do not belong to
+// a customer account are posted against a SystemAccount instead.
+type LedgerEntrySchema struct {
+	Id            uuid.UUID
+	JournalId     uuid.UUID
+	AccountId     *uuid.UUID
+	SystemAccount *string
+	Direction     string
+	Amount        int64
+	CreatedAt     time.Time
+}
+
+type LedgerDAO struct {
+	pgxPool *pgxpool.Pool
+}
+
+func NewLedgerDAO(pgxPool *pgxpool.Pool) LedgerDAO {
+	return LedgerDAO{pgxPool}
+}
+
+func (l *LedgerDAO) CreateAll(ctx context.Context, tx pgx.Tx, ledgerEntriesSchema []LedgerEntrySchema) error {
+	for _, ledgerEntrySchema := range ledgerEntriesSchema {
+		_, err := tx.Exec(ctx,
+			"INSERT INTO ledger_entries (id, journal_id, account_id, system_account, direction, amount, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7)",
+			ledgerEntrySchema.Id, ledgerEntrySchema.JournalId, ledgerEntrySchema.AccountId, ledgerEntrySchema.SystemAccount, ledgerEntrySchema.Direction,
+			ledgerEntrySchema.Amount, ledgerEntrySchema.CreatedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *LedgerDAO) FindAllByAccountId(ctx context.Context, accountId uuid.UUID) ([]LedgerEntrySchema, error) {
+	rows, err := l.pgxPool.Query(ctx,
+		`SELECT id, journal_id, account_id, system_account, direction, amount, created_at FROM ledger_entries
+		WHERE account_id = $1 ORDER BY created_at, id`, accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, scanLedgerEntry)
+}
+
+func (l *LedgerDAO) FindAllByJournalId(ctx context.Context, journalId uuid.UUID) ([]LedgerEntrySchema, error) {
+	rows, err := l.pgxPool.Query(ctx,
+		`SELECT id, journal_id, account_id, system_account, direction, amount, created_at FROM ledger_entries
+		WHERE journal_id = $1 ORDER BY direction DESC, id`, journalId)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, scanLedgerEntry)
+}
+
+func (l *LedgerDAO) DeleteAll(ctx context.Context) error {
+	_, err := l.pgxPool.Exec(ctx, "TRUNCATE TABLE ledger_entries CASCADE")
+	return err
+}
+
+func scanLedgerEntry(row pgx.CollectableRow) (LedgerEntrySchema, error) {
+	var item LedgerEntrySchema
+	err := row.Scan(&item.Id, &item.JournalId, &item.AccountId, &item.SystemAccount, &item.Direction, &item.Amount, &item.CreatedAt)
+	return item, err
+}
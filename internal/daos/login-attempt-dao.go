@@ -0,0 +1,165 @@
+package daos
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// LoginLockoutPolicy locks a subject out for LockoutBase once it reaches its maximum number of
+// failures, doubling the lockout with every further failure up to LockoutMax. Failures are
+// forgotten after FailuresWindow without any.
+type LoginLockoutPolicy struct {
+	FailuresWindow time.Duration
+	LockoutBase    time.Duration
+	LockoutMax     time.Duration
+}
+
+// LoginAttemptLimit is how many failures Subject may have before it is locked out.
+type LoginAttemptLimit struct {
+	Subject     string
+	MaxFailures int64
+}
+
+// LoginAttemptReservation is an attempt counted as failed before it is checked, so concurrent
+// attempts cannot get past the limit. Lockout is how long the subject was locked out for by it,
+// zero when it was not.
+type LoginAttemptReservation struct {
+	Subject  string
+	Failures int64
+	Lockout  time.Duration
+}
+
+// reserveLoginAttemptScript takes the failure counters of every subject as the first half of
+// KEYS and their lockouts as the second half. Nothing is counted when any of them is locked out,
+// in which case only the longest remaining lockout is returned.
+var reserveLoginAttemptScript = redis.NewScript(`
+local window = tonumber(ARGV[1])
+local lockoutBase = tonumber(ARGV[2])
+local lockoutMax = tonumber(ARGV[3])
+local subjects = #KEYS / 2
+
+local remaining = 0
+for i = 1, subjects do
+	remaining = math.max(remaining, redis.call("PTTL", KEYS[subjects + i]))
+end
+
+if remaining > 0 then
+	return {remaining}
+end
+
+local result = {0}
+for i = 1, subjects do
+	local maxFailures = tonumber(ARGV[3 + i])
+	local failures = redis.call("INCR", KEYS[i])
+	redis.call("PEXPIRE", KEYS[i], window)
+
+	local lockout = 0
+	if failures >= maxFailures then
+		lockout = lockoutBase
+		local doublings = failures - maxFailures
+		while doublings > 0 and lockout < lockoutMax do
+			lockout = math.min(lockout * 2, lockoutMax)
+			doublings = doublings - 1
+		end
+
+		redis.call("SET", KEYS[subjects + i], 1, "PX", lockout)
+	end
+
+	table.insert(result, failures)
+	table.insert(result, lockout)
+end
+
+return result
+`)
+
+// refundLoginAttemptScript gives back a reserved attempt, unless its counter already expired,
+// and lifts the lockout the attempt caused, if any.
+var refundLoginAttemptScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 1 then
+	redis.call("DECR", KEYS[1])
+end
+
+if ARGV[1] == "1" then
+	redis.call("DEL", KEYS[2])
+end
+
+return 0
+`)
+
+// LoginAttemptDAO keeps the failed login counters and the active lockouts in Redis. A subject
+// is what is being throttled, such as "email:john.doe@gmail.com" or "ip:203.0.113.7".
+type LoginAttemptDAO struct {
+	redisClient *redis.Client
+}
+
+func NewLoginAttemptDAO(redisClient *redis.Client) LoginAttemptDAO {
+	return LoginAttemptDAO{redisClient}
+}
+
+// ReserveAttempt counts an attempt as failed for every subject at once, locking out the ones
+// reaching their limit, and returns the reservations in the order of limits. When a subject is
+// already locked out nothing is counted and only the longest remaining lockout is returned.
+func (l *LoginAttemptDAO) ReserveAttempt(ctx context.Context, policy LoginLockoutPolicy,
+	limits ...LoginAttemptLimit) ([]LoginAttemptReservation, time.Duration, error) {
+	keys := make([]string, 2*len(limits))
+	args := []any{policy.FailuresWindow.Milliseconds(), policy.LockoutBase.Milliseconds(), policy.LockoutMax.Milliseconds()}
+
+	for i, limit := range limits {
+		keys[i] = loginFailuresKey(limit.Subject)
+		keys[len(limits)+i] = loginLockoutKey(limit.Subject)
+		args = append(args, limit.MaxFailures)
+	}
+
+	result, err := reserveLoginAttemptScript.Run(ctx, l.redisClient, keys, args...).Int64Slice()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(result) == 1 {
+		return nil, time.Duration(result[0]) * time.Millisecond, nil
+	}
+
+	reservations := make([]LoginAttemptReservation, len(limits))
+
+	for i, limit := range limits {
+		reservations[i] = LoginAttemptReservation{
+			Subject:  limit.Subject,
+			Failures: result[1+2*i],
+			Lockout:  time.Duration(result[2+2*i]) * time.Millisecond,
+		}
+	}
+
+	return reservations, 0, nil
+}
+
+// RefundAttempt gives back the reserved attempts of an attempt that turned out to succeed.
+func (l *LoginAttemptDAO) RefundAttempt(ctx context.Context, reservations ...LoginAttemptReservation) error {
+	for _, reservation := range reservations {
+		lockedOut := "0"
+		if reservation.Lockout > 0 {
+			lockedOut = "1"
+		}
+
+		err := refundLoginAttemptScript.Run(ctx, l.redisClient,
+			[]string{loginFailuresKey(reservation.Subject), loginLockoutKey(reservation.Subject)}, lockedOut).Err()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *LoginAttemptDAO) DeleteFailures(ctx context.Context, subject string) error {
+	return l.redisClient.Del(ctx, loginFailuresKey(subject)).Err()
+}
+
+func loginFailuresKey(subject string) string {
+	return "login_attempts:failures:" + subject
+}
+
+func loginLockoutKey(subject string) string {
+	return "login_attempts:lockout:" + subject
+}
@@ -0,0 +1,74 @@
+package daos
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// incrementLoginChallengeAttemptsScript only counts attempts of a challenge that still exists,
+// since HINCRBY would otherwise create it again, without any expiry.
+var incrementLoginChallengeAttemptsScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return 0
+end
+
+return redis.call("HINCRBY", KEYS[1], "attempts", 1)
+`)
+
+// LoginChallengeDAO keeps the pending two-factor logins in Redis, by the SHA-256 of the
+// challenge token handed to the client once the password was checked.
+type LoginChallengeDAO struct {
+	redisClient *redis.Client
+}
+
+func NewLoginChallengeDAO(redisClient *redis.Client) LoginChallengeDAO {
+	return LoginChallengeDAO{redisClient}
+}
+
+func (l *LoginChallengeDAO) Create(ctx context.Context, challengeHash string, customerId uuid.UUID, ttl time.Duration) error {
+	_, err := l.redisClient.TxPipelined(ctx, func(pipeliner redis.Pipeliner) error {
+		pipeliner.HSet(ctx, loginChallengeKey(challengeHash), "customer_id", customerId.String(), "attempts", 0)
+		pipeliner.Expire(ctx, loginChallengeKey(challengeHash), ttl)
+		return nil
+	})
+
+	return err
+}
+
+// FindCustomerId returns the customer the challenge was issued to, nil when it does not exist
+// or has expired.
+func (l *LoginChallengeDAO) FindCustomerId(ctx context.Context, challengeHash string) (*uuid.UUID, error) {
+	value, err := l.redisClient.HGet(ctx, loginChallengeKey(challengeHash), "customer_id").Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	customerId, err := uuid.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &customerId, nil
+}
+
+// IncrementAttempts counts one more code submitted for the challenge and returns the total,
+// zero when the challenge does not exist anymore.
+func (l *LoginChallengeDAO) IncrementAttempts(ctx context.Context, challengeHash string) (int64, error) {
+	return incrementLoginChallengeAttemptsScript.Run(ctx, l.redisClient, []string{loginChallengeKey(challengeHash)}).Int64()
+}
+
+func (l *LoginChallengeDAO) Delete(ctx context.Context, challengeHash string) error {
+	return l.redisClient.Del(ctx, loginChallengeKey(challengeHash)).Err()
+}
+
+func loginChallengeKey(challengeHash string) string {
+	return "login_challenges:" + challengeHash
+}
@@ -0,0 +1,67 @@
+package daos
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	LoginLockoutSubjectTypeEmail = "email"
+	LoginLockoutSubjectTypeIp    = "ip"
+	// LoginLockoutSubjectTypeTotp counts the wrong two-factor codes of the customer whose id is
+	// the subject.
+	LoginLockoutSubjectTypeTotp = "totp"
+)
+
+// LoginLockoutSchema is the audit trail of lockouts. The lockout itself lives in Redis; these
+// rows are only kept to investigate attacks afterwards.
+type LoginLockoutSchema struct {
+	Id             uuid.UUID
+	SubjectType    string
+	Subject        string
+	FailedAttempts int64
+	LockedUntil    time.Time
+	CreatedAt      time.Time
+}
+
+type LoginLockoutDAO struct {
+	pgxPool *pgxpool.Pool
+}
+
+func NewLoginLockoutDAO(pgxPool *pgxpool.Pool) LoginLockoutDAO {
+	return LoginLockoutDAO{pgxPool}
+}
+
+func (l *LoginLockoutDAO) Create(ctx context.Context, loginLockoutSchema LoginLockoutSchema) error {
+	_, err := l.pgxPool.Exec(ctx,
+		`INSERT INTO login_lockouts (id, subject_type, subject, failed_attempts, locked_until, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		loginLockoutSchema.Id, loginLockoutSchema.SubjectType, loginLockoutSchema.Subject, loginLockoutSchema.FailedAttempts,
+		loginLockoutSchema.LockedUntil, loginLockoutSchema.CreatedAt)
+
+	return err
+}
+
+func (l *LoginLockoutDAO) FindAllBySubject(ctx context.Context, subjectType string, subject string) ([]LoginLockoutSchema, error) {
+	rows, err := l.pgxPool.Query(ctx,
+		`SELECT id, subject_type, subject, failed_attempts, locked_until, created_at
+		FROM login_lockouts WHERE subject_type = $1 AND subject = $2 ORDER BY created_at, id`, subjectType, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (LoginLockoutSchema, error) {
+		var item LoginLockoutSchema
+		err := row.Scan(&item.Id, &item.SubjectType, &item.Subject, &item.FailedAttempts, &item.LockedUntil, &item.CreatedAt)
+		return item, err
+	})
+}
+
+func (l *LoginLockoutDAO) DeleteAll(ctx context.Context) error {
+	_, err := l.pgxPool.Exec(ctx, "TRUNCATE TABLE login_lockouts")
+	return err
+}
@@ -0,0 +1,79 @@
+package daos
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// OutboxEventSchema is a domain event waiting to be published, or already published when
+// PublishedAt is set. Payload is the JSON document sent as the message body.
+type OutboxEventSchema struct {
+	Id          uuid.UUID
+	EventType   string
+	AggregateId uuid.UUID
+	Payload     []byte
+	CreatedAt   time.Time
+	PublishedAt *time.Time
+}
+
+type OutboxEventDAO struct {
+	pgxPool *pgxpool.Pool
+}
+
+func NewOutboxEventDAO(pgxPool *pgxpool.Pool) OutboxEventDAO {
+	return OutboxEventDAO{pgxPool}
+}
+
+// Create must be called in the transaction of the change the event describes, so the event is
+// stored if and only if the change is committed.
+func (o *OutboxEventDAO) Create(ctx context.Context, tx pgx.Tx, outboxEventSchema OutboxEventSchema) error {
+	_, err := tx.Exec(ctx,
+		"INSERT INTO outbox_events (id, event_type, aggregate_id, payload, created_at, published_at) VALUES ($1, $2, $3, $4, $5, $6)",
+		outboxEventSchema.Id, outboxEventSchema.EventType, outboxEventSchema.AggregateId, outboxEventSchema.Payload, outboxEventSchema.CreatedAt,
+		outboxEventSchema.PublishedAt)
+
+	return err
+}
+
+// FindAllUnpublishedForUpdate returns the oldest unpublished events, skipping the ones locked by
+// another relay so several can run at once without publishing the same batch.
+func (o *OutboxEventDAO) FindAllUnpublishedForUpdate(ctx context.Context, tx pgx.Tx, limit int) ([]OutboxEventSchema, error) {
+	rows, err := tx.Query(ctx,
+		`SELECT id, event_type, aggregate_id, payload, created_at, published_at FROM outbox_events
+		WHERE published_at IS NULL ORDER BY created_at, id LIMIT $1 FOR UPDATE SKIP LOCKED`, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, scanOutboxEvent)
+}
+
+func (o *OutboxEventDAO) MarkAllAsPublished(ctx context.Context, tx pgx.Tx, ids []uuid.UUID, publishedAt time.Time) error {
+	_, err := tx.Exec(ctx, "UPDATE outbox_events SET published_at = $1 WHERE id = ANY($2)", publishedAt, ids)
+	return err
+}
+
+func (o *OutboxEventDAO) FindAll(ctx context.Context) ([]OutboxEventSchema, error) {
+	rows, err := o.pgxPool.Query(ctx,
+		"SELECT id, event_type, aggregate_id, payload, created_at, published_at FROM outbox_events ORDER BY created_at, id")
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, scanOutboxEvent)
+}
+
+func (o *OutboxEventDAO) DeleteAll(ctx context.Context) error {
+	_, err := o.pgxPool.Exec(ctx, "TRUNCATE TABLE outbox_events")
+	return err
+}
+
+func scanOutboxEvent(row pgx.CollectableRow) (OutboxEventSchema, error) {
+	var item OutboxEventSchema
+	err := row.Scan(&item.Id, &item.EventType, &item.AggregateId, &item.Payload, &item.CreatedAt, &item.PublishedAt)
+	return item, err
+}
@@ -0,0 +1,88 @@
+package daos
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PasswordResetTokenSchema stores only the SHA-256 of the token, which is sent to the customer.
+type PasswordResetTokenSchema struct {
+	Id         uuid.UUID
+	CustomerId uuid.UUID
+	TokenHash  string
+	ExpiresAt  time.Time
+	UsedAt     *time.Time
+	CreatedAt  time.Time
+}
+
+type PasswordResetTokenDAO struct {
+	pgxPool *pgxpool.Pool
+}
+
+func NewPasswordResetTokenDAO(pgxPool *pgxpool.Pool) PasswordResetTokenDAO {
+	return PasswordResetTokenDAO{pgxPool}
+}
+
+func (p *PasswordResetTokenDAO) Create(ctx context.Context, tx pgx.Tx, passwordResetTokenSchema PasswordResetTokenSchema) error {
+	_, err := tx.Exec(ctx,
+		"INSERT INTO password_reset_tokens (id, customer_id, token_hash, expires_at, used_at, created_at) VALUES ($1, $2, $3, $4, $5, $6)",
+		passwordResetTokenSchema.Id, passwordResetTokenSchema.CustomerId, passwordResetTokenSchema.TokenHash, passwordResetTokenSchema.ExpiresAt,
+		passwordResetTokenSchema.UsedAt, passwordResetTokenSchema.CreatedAt)
+
+	return err
+}
+
+// FindOneByTokenHashForUpdate locks the token so that it can only be used once even when
+// submitted twice concurrently.
+func (p *PasswordResetTokenDAO) FindOneByTokenHashForUpdate(ctx context.Context, tx pgx.Tx, tokenHash string) (*PasswordResetTokenSchema, error) {
+	var passwordResetTokenSchema PasswordResetTokenSchema
+
+	err := tx.QueryRow(ctx,
+		"SELECT id, customer_id, token_hash, expires_at, used_at, created_at FROM password_reset_tokens WHERE token_hash = $1 FOR UPDATE", tokenHash).
+		Scan(&passwordResetTokenSchema.Id, &passwordResetTokenSchema.CustomerId, &passwordResetTokenSchema.TokenHash, &passwordResetTokenSchema.ExpiresAt,
+			&passwordResetTokenSchema.UsedAt, &passwordResetTokenSchema.CreatedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &passwordResetTokenSchema, nil
+}
+
+// MarkAllAsUsedByCustomerId spends every pending token of the customer, so only the latest
+// one requested, or none once the password was reset, can be used.
+func (p *PasswordResetTokenDAO) MarkAllAsUsedByCustomerId(ctx context.Context, tx pgx.Tx, customerId uuid.UUID, usedAt time.Time) error {
+	_, err := tx.Exec(ctx,
+		"UPDATE password_reset_tokens SET used_at = $1 WHERE customer_id = $2 AND used_at IS NULL", usedAt, customerId)
+
+	return err
+}
+
+func (p *PasswordResetTokenDAO) FindAllByCustomerId(ctx context.Context, customerId uuid.UUID) ([]PasswordResetTokenSchema, error) {
+	rows, err := p.pgxPool.Query(ctx,
+		"SELECT id, customer_id, token_hash, expires_at, used_at, created_at FROM password_reset_tokens WHERE customer_id = $1 ORDER BY created_at, id",
+		customerId)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (PasswordResetTokenSchema, error) {
+		var item PasswordResetTokenSchema
+		err := row.Scan(&item.Id, &item.CustomerId, &item.TokenHash, &item.ExpiresAt, &item.UsedAt, &item.CreatedAt)
+		return item, err
+	})
+}
+
+func (p *PasswordResetTokenDAO) DeleteAll(ctx context.Context) error {
+	_, err := p.pgxPool.Exec(ctx, "TRUNCATE TABLE password_reset_tokens CASCADE")
+	return err
+}
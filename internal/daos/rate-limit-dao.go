@@ -0,0 +1,78 @@
+package daos
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// RateLimitHit is the state of a rate limit right after a request was counted against it.
+// Reset is how long until the oldest request in the window expires and frees a slot.
+type RateLimitHit struct {
+	Allowed   bool
+	Remaining int
+	Reset     time.Duration
+}
+
+// rateLimitScript keeps the timestamps of the requests inside the window in a sorted set, so
+// the window slides with every request instead of resetting at fixed boundaries. Rejected
+// requests are not recorded, otherwise a client retrying in a loop would never get through.
+var rateLimitScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now - window)
+
+local count = redis.call("ZCARD", KEYS[1])
+local allowed = 0
+
+if count < limit then
+	redis.call("ZADD", KEYS[1], now, ARGV[4])
+	count = count + 1
+	allowed = 1
+end
+
+redis.call("PEXPIRE", KEYS[1], window)
+
+local reset = window
+local oldest = redis.call("ZRANGE", KEYS[1], 0, 0, "WITHSCORES")
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window - now
+end
+
+return {allowed, limit - count, reset}
+`)
+
+// RateLimitDAO counts requests in Redis. A subject is what is being throttled, such as
+// "ip:203.0.113.7" or "customer:f59207c8-e837-4159-b67d-78c716510747".
+type RateLimitDAO struct {
+	redisClient *redis.Client
+}
+
+func NewRateLimitDAO(redisClient *redis.Client) RateLimitDAO {
+	return RateLimitDAO{redisClient}
+}
+
+// Hit counts one request of subject against policy when fewer than limit requests were counted
+// in the window ending at now.
+func (r *RateLimitDAO) Hit(ctx context.Context, policy string, subject string, limit int, window time.Duration,
+	now time.Time) (RateLimitHit, error) {
+	result, err := rateLimitScript.Run(ctx, r.redisClient, []string{rateLimitKey(policy, subject)},
+		now.UnixMilli(), window.Milliseconds(), limit, uuid.NewString()).Int64Slice()
+	if err != nil {
+		return RateLimitHit{}, err
+	}
+
+	return RateLimitHit{
+		Allowed:   result[0] == 1,
+		Remaining: int(result[1]),
+		Reset:     time.Duration(result[2]) * time.Millisecond,
+	}, nil
+}
+
+func rateLimitKey(policy string, subject string) string {
+	return "rate_limit:" + policy + ":" + subject
+}
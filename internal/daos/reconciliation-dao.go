@@ -0,0 +1,125 @@
+package daos
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AccountReconciliationSchema struct {
+	AccountId     uuid.UUID
+	CustomerId    uuid.UUID
+	CachedBalance int64
+	InitialGrant  int64
+	Inbound       int64
+	Outbound      int64
+	LedgerBalance int64
+}
+
+type OrphanedTransactionSchema struct {
+	TransactionId uuid.UUID
+	LedgerEntries int64
+}
+
+type MoneySupplySchema struct {
+	TotalBalances int64
+	TotalGranted  int64
+}
+
+type ReconciliationDAO struct {
+	pgxPool *pgxpool.Pool
+}
+
+func NewReconciliationDAO(pgxPool *pgxpool.Pool) ReconciliationDAO {
+	return ReconciliationDAO{pgxPool}
+}
+
+// FindAllAccounts returns, for every account, the figures needed to recompute its balance.
+// The initial grant is whatever the account was credited by journals posted against a system
+// account, such as the sign-up bonus or the opening balance of the ledger backfill.
+func (r *ReconciliationDAO) FindAllAccounts(ctx context.Context) ([]AccountReconciliationSchema, error) {
+	rows, err := r.pgxPool.Query(ctx, `
+		SELECT
+			a.id,
+			a.customer_id,
+			a.balance,
+			COALESCE((
+				SELECT SUM(le.amount)
+				FROM ledger_entries le
+				WHERE le.account_id = a.id
+					AND le.direction = 'credit'
+					AND EXISTS (SELECT 1 FROM ledger_entries s WHERE s.journal_id = le.journal_id AND s.system_account IS NOT NULL)
+			), 0),
+			COALESCE((SELECT SUM(t.amount) FROM transactions t WHERE t.account_receiver_id = a.id), 0),
+			COALESCE((SELECT SUM(t.amount) FROM transactions t WHERE t.account_sender_id = a.id), 0),
+			COALESCE((
+				SELECT SUM(CASE WHEN le.direction = 'credit' THEN le.amount ELSE -le.amount END)
+				FROM ledger_entries le
+				WHERE le.account_id = a.id
+			), 0)
+		FROM accounts a
+		ORDER BY a.id`)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (AccountReconciliationSchema, error) {
+		var item AccountReconciliationSchema
+		err := row.Scan(&item.AccountId, &item.CustomerId, &item.CachedBalance, &item.InitialGrant, &item.Inbound, &item.Outbound, &item.LedgerBalance)
+		return item, err
+	})
+}
+
+// FindAllOrphanedTransactions returns the transactions whose ledger journal is missing or does
+// not consist of exactly one debit of the sender and one credit of the receiver for the amount.
+func (r *ReconciliationDAO) FindAllOrphanedTransactions(ctx context.Context) ([]OrphanedTransactionSchema, error) {
+	rows, err := r.pgxPool.Query(ctx, `
+		SELECT t.id, COUNT(le.id)
+		FROM transactions t
+		LEFT JOIN ledger_entries le
+			ON le.journal_id = t.id
+		GROUP BY t.id
+		HAVING COUNT(le.id) <> 2
+			OR COUNT(le.id) FILTER (WHERE le.direction = 'debit' AND le.account_id = t.account_sender_id AND le.amount = t.amount) <> 1
+			OR COUNT(le.id) FILTER (WHERE le.direction = 'credit' AND le.account_id = t.account_receiver_id AND le.amount = t.amount) <> 1
+		ORDER BY t.id`)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (OrphanedTransactionSchema, error) {
+		var item OrphanedTransactionSchema
+		err := row.Scan(&item.TransactionId, &item.LedgerEntries)
+		return item, err
+	})
+}
+
+func (r *ReconciliationDAO) FindMoneySupply(ctx context.Context) (MoneySupplySchema, error) {
+	var moneySupplySchema MoneySupplySchema
+
+	err := r.pgxPool.QueryRow(ctx, `
+		SELECT
+			COALESCE((SELECT SUM(balance) FROM accounts), 0),
+			COALESCE((
+				SELECT SUM(CASE WHEN direction = 'debit' THEN amount ELSE -amount END)
+				FROM ledger_entries
+				WHERE system_account IS NOT NULL
+			), 0)`).
+		Scan(&moneySupplySchema.TotalBalances, &moneySupplySchema.TotalGranted)
+
+	return moneySupplySchema, err
+}
+
+// UpdateBalance only overwrites the cached balance when it still holds the drifted value,
+// so a transfer that committed in the meantime is never clobbered.
+func (r *ReconciliationDAO) UpdateBalance(ctx context.Context, accountId uuid.UUID, driftedBalance int64, balance int64) (bool, error) {
+	commandTag, err := r.pgxPool.Exec(ctx,
+		"UPDATE accounts SET balance = $1 WHERE id = $2 AND balance = $3", balance, accountId, driftedBalance)
+	if err != nil {
+		return false, err
+	}
+
+	return commandTag.RowsAffected() == 1, nil
+}
@@ -0,0 +1,112 @@
+package daos
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RefreshTokenSchema stores only the SHA-256 of the token. Every token issued by rotating
+// another one shares its FamilyId, which is what gets revoked when a token is reused.
+type RefreshTokenSchema struct {
+	Id         uuid.UUID
+	FamilyId   uuid.UUID
+	CustomerId uuid.UUID
+	TokenHash  string
+	ReplacedBy *uuid.UUID
+	ExpiresAt  time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+type RefreshTokenDAO struct {
+	pgxPool *pgxpool.Pool
+}
+
+func NewRefreshTokenDAO(pgxPool *pgxpool.Pool) RefreshTokenDAO {
+	return RefreshTokenDAO{pgxPool}
+}
+
+func (r *RefreshTokenDAO) Create(ctx context.Context, refreshTokenSchema RefreshTokenSchema) error {
+	_, err := r.pgxPool.Exec(ctx,
+		`INSERT INTO refresh_tokens (id, family_id, customer_id, token_hash, replaced_by, expires_at, revoked_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		refreshTokenSchema.Id, refreshTokenSchema.FamilyId, refreshTokenSchema.CustomerId, refreshTokenSchema.TokenHash,
+		refreshTokenSchema.ReplacedBy, refreshTokenSchema.ExpiresAt, refreshTokenSchema.RevokedAt, refreshTokenSchema.CreatedAt)
+
+	return err
+}
+
+// FindOneByTokenHashForUpdate locks the token so that two concurrent refreshes with the same
+// token are serialized and the second one is seen as a reuse.
+func (r *RefreshTokenDAO) FindOneByTokenHashForUpdate(ctx context.Context, tx pgx.Tx, tokenHash string) (*RefreshTokenSchema, error) {
+	var refreshTokenSchema RefreshTokenSchema
+
+	err := tx.QueryRow(ctx,
+		`SELECT id, family_id, customer_id, token_hash, replaced_by, expires_at, revoked_at, created_at
+		FROM refresh_tokens WHERE token_hash = $1 FOR UPDATE`, tokenHash).
+		Scan(&refreshTokenSchema.Id, &refreshTokenSchema.FamilyId, &refreshTokenSchema.CustomerId, &refreshTokenSchema.TokenHash,
+			&refreshTokenSchema.ReplacedBy, &refreshTokenSchema.ExpiresAt, &refreshTokenSchema.RevokedAt, &refreshTokenSchema.CreatedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &refreshTokenSchema, nil
+}
+
+// Rotate stores the token that replaces the current one and links both.
+func (r *RefreshTokenDAO) Rotate(ctx context.Context, tx pgx.Tx, currentId uuid.UUID, next RefreshTokenSchema) error {
+	_, err := tx.Exec(ctx,
+		`INSERT INTO refresh_tokens (id, family_id, customer_id, token_hash, replaced_by, expires_at, revoked_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		next.Id, next.FamilyId, next.CustomerId, next.TokenHash, next.ReplacedBy, next.ExpiresAt, next.RevokedAt, next.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, "UPDATE refresh_tokens SET replaced_by = $1 WHERE id = $2", next.Id, currentId)
+	return err
+}
+
+func (r *RefreshTokenDAO) RevokeFamily(ctx context.Context, tx pgx.Tx, familyId uuid.UUID, revokedAt time.Time) error {
+	_, err := tx.Exec(ctx,
+		"UPDATE refresh_tokens SET revoked_at = $1 WHERE family_id = $2 AND revoked_at IS NULL", revokedAt, familyId)
+
+	return err
+}
+
+func (r *RefreshTokenDAO) RevokeAllByCustomerId(ctx context.Context, tx pgx.Tx, customerId uuid.UUID, revokedAt time.Time) error {
+	_, err := tx.Exec(ctx,
+		"UPDATE refresh_tokens SET revoked_at = $1 WHERE customer_id = $2 AND revoked_at IS NULL", revokedAt, customerId)
+
+	return err
+}
+
+func (r *RefreshTokenDAO) FindAllByCustomerId(ctx context.Context, customerId uuid.UUID) ([]RefreshTokenSchema, error) {
+	rows, err := r.pgxPool.Query(ctx,
+		`SELECT id, family_id, customer_id, token_hash, replaced_by, expires_at, revoked_at, created_at
+		FROM refresh_tokens WHERE customer_id = $1 ORDER BY created_at, id`, customerId)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (RefreshTokenSchema, error) {
+		var item RefreshTokenSchema
+		err := row.Scan(&item.Id, &item.FamilyId, &item.CustomerId, &item.TokenHash, &item.ReplacedBy, &item.ExpiresAt, &item.RevokedAt, &item.CreatedAt)
+		return item, err
+	})
+}
+
+func (r *RefreshTokenDAO) DeleteAll(ctx context.Context) error {
+	_, err := r.pgxPool.Exec(ctx, "TRUNCATE TABLE refresh_tokens CASCADE")
+	return err
+}
@@ -0,0 +1,68 @@
+package daos
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// RevokedAccessTokenDAO keeps the denylist of access tokens in Redis. Entries only need to
+// live until the tokens they refer to expire on their own, so every key carries that TTL.
+type RevokedAccessTokenDAO struct {
+	redisClient *redis.Client
+}
+
+func NewRevokedAccessTokenDAO(redisClient *redis.Client) RevokedAccessTokenDAO {
+	return RevokedAccessTokenDAO{redisClient}
+}
+
+// CreateForSession revokes every token of the session, the ones issued with its sid later on
+// included. ttl must be at least the access token lifetime, after which the refresh tokens of
+// the session, revoked along with it, could not have issued any.
+func (r *RevokedAccessTokenDAO) CreateForSession(ctx context.Context, sessionId uuid.UUID, ttl time.Duration) error {
+	return r.redisClient.Set(ctx, revokedSessionAccessTokensKey(sessionId), 1, ttl).Err()
+}
+
+// CreateForCustomer revokes every token of the customer issued at or before revokedAt, kept
+// with nanosecond precision. ttl must be at least the access token lifetime, after which those
+// tokens are expired anyway.
+func (r *RevokedAccessTokenDAO) CreateForCustomer(ctx context.Context, customerId uuid.UUID, revokedAt time.Time, ttl time.Duration) error {
+	return r.redisClient.Set(ctx, revokedCustomerAccessTokensKey(customerId), revokedAt.UnixNano(), ttl).Err()
+}
+
+// IsRevoked checks both the session of the token and the revocations of its customer in one
+// round trip. issuedAt must be precise enough to order the token against revocations made in
+// the same second, see usecases.JwtAccessTokenClaims.IssuedAtExact.
+func (r *RevokedAccessTokenDAO) IsRevoked(ctx context.Context, sessionId uuid.UUID, customerId uuid.UUID,
+	issuedAt time.Time) (bool, error) {
+	values, err := r.redisClient.MGet(ctx, revokedSessionAccessTokensKey(sessionId), revokedCustomerAccessTokensKey(customerId)).Result()
+	if err != nil {
+		return false, err
+	}
+
+	if values[0] != nil {
+		return true, nil
+	}
+
+	if values[1] == nil {
+		return false, nil
+	}
+
+	revokedAt, err := strconv.ParseInt(values[1].(string), 10, 64)
+	if err != nil {
+		return false, err
+	}
+
+	return issuedAt.UnixNano() <= revokedAt, nil
+}
+
+func revokedSessionAccessTokensKey(sessionId uuid.UUID) string {
+	return "revoked_access_tokens:session:" + sessionId.String()
+}
+
+func revokedCustomerAccessTokensKey(customerId uuid.UUID) string {
+	return "revoked_access_tokens:customer:" + customerId.String()
+}
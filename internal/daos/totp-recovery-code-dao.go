@@ -0,0 +1,79 @@
+package daos
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TotpRecoveryCodeSchema stores only the SHA-256 of the code, which is shown to the customer
+// once when the authenticator is confirmed.
+type TotpRecoveryCodeSchema struct {
+	Id         uuid.UUID
+	CustomerId uuid.UUID
+	CodeHash   string
+	UsedAt     *time.Time
+	CreatedAt  time.Time
+}
+
+type TotpRecoveryCodeDAO struct {
+	pgxPool *pgxpool.Pool
+}
+
+func NewTotpRecoveryCodeDAO(pgxPool *pgxpool.Pool) TotpRecoveryCodeDAO {
+	return TotpRecoveryCodeDAO{pgxPool}
+}
+
+func (t *TotpRecoveryCodeDAO) CreateAll(ctx context.Context, tx pgx.Tx, totpRecoveryCodesSchema []TotpRecoveryCodeSchema) error {
+	for _, totpRecoveryCodeSchema := range totpRecoveryCodesSchema {
+		_, err := tx.Exec(ctx,
+			"INSERT INTO totp_recovery_codes (id, customer_id, code_hash, used_at, created_at) VALUES ($1, $2, $3, $4, $5)",
+			totpRecoveryCodeSchema.Id, totpRecoveryCodeSchema.CustomerId, totpRecoveryCodeSchema.CodeHash, totpRecoveryCodeSchema.UsedAt,
+			totpRecoveryCodeSchema.CreatedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MarkOneAsUsed spends the code and reports whether it was still unused.
+func (t *TotpRecoveryCodeDAO) MarkOneAsUsed(ctx context.Context, tx pgx.Tx, customerId uuid.UUID, codeHash string, usedAt time.Time) (bool, error) {
+	commandTag, err := tx.Exec(ctx,
+		"UPDATE totp_recovery_codes SET used_at = $1 WHERE customer_id = $2 AND code_hash = $3 AND used_at IS NULL",
+		usedAt, customerId, codeHash)
+	if err != nil {
+		return false, err
+	}
+
+	return commandTag.RowsAffected() == 1, nil
+}
+
+func (t *TotpRecoveryCodeDAO) FindAllByCustomerId(ctx context.Context, customerId uuid.UUID) ([]TotpRecoveryCodeSchema, error) {
+	rows, err := t.pgxPool.Query(ctx,
+		"SELECT id, customer_id, code_hash, used_at, created_at FROM totp_recovery_codes WHERE customer_id = $1 ORDER BY created_at, id",
+		customerId)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (TotpRecoveryCodeSchema, error) {
+		var item TotpRecoveryCodeSchema
+		err := row.Scan(&item.Id, &item.CustomerId, &item.CodeHash, &item.UsedAt, &item.CreatedAt)
+		return item, err
+	})
+}
+
+func (t *TotpRecoveryCodeDAO) DeleteAllByCustomerId(ctx context.Context, tx pgx.Tx, customerId uuid.UUID) error {
+	_, err := tx.Exec(ctx, "DELETE FROM totp_recovery_codes WHERE customer_id = $1", customerId)
+	return err
+}
+
+func (t *TotpRecoveryCodeDAO) DeleteAll(ctx context.Context) error {
+	_, err := t.pgxPool.Exec(ctx, "TRUNCATE TABLE totp_recovery_codes CASCADE")
+	return err
+}
@@ -2,10 +2,10 @@ package daos
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/gsaaraujo/pay-bank-api/internal/utils"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -20,6 +20,26 @@ type TransactionSchema struct {
 	UpdatedAt         time.Time
 }
 
+type TransactionHistorySchema struct {
+	Id                   uuid.UUID
+	CustomerSenderId     uuid.UUID
+	CustomerSenderName   string
+	CustomerReceiverId   uuid.UUID
+	CustomerReceiverName string
+	AccountSenderId      uuid.UUID
+	AccountReceiverId    uuid.UUID
+	Amount               int64
+	CreatedAt            time.Time
+}
+
+type TransactionHistoryFilter struct {
+	From            *time.Time
+	To              *time.Time
+	BeforeCreatedAt *time.Time
+	BeforeId        *uuid.UUID
+	Limit           int
+}
+
 type TransactionDAO struct {
 	pgxPool *pgxpool.Pool
 }
@@ -28,68 +48,150 @@ func NewTransactionDAO(pgxPool *pgxpool.Pool) TransactionDAO {
 	return TransactionDAO{pgxPool}
 }
 
-func (t *TransactionDAO) Create(transactionSchema TransactionSchema) {
-	_ = utils.GetOrThrow(t.pgxPool.Exec(context.Background(),
+func (t *TransactionDAO) Create(ctx context.Context, transactionSchema TransactionSchema) error {
+	_, err := t.pgxPool.Exec(ctx,
 		"INSERT INTO transactions (id, account_sender_id, account_receiver_id, idempotency_key, amount, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7)",
 		transactionSchema.Id, transactionSchema.AccountSenderId, transactionSchema.AccountReceiverId, transactionSchema.IdempotencyKey, transactionSchema.Amount,
-		transactionSchema.UpdatedAt, transactionSchema.CreatedAt))
+		transactionSchema.CreatedAt, transactionSchema.UpdatedAt)
+
+	return err
 }
 
-func (c *TransactionDAO) FindAllByAccountSenderIdAndAccountReceiverId(accountSenderId uuid.UUID, accountReceiverId uuid.UUID) []TransactionSchema {
-	rows := utils.GetOrThrow(c.pgxPool.Query(context.Background(),
+func (c *TransactionDAO) FindAllByAccountSenderIdAndAccountReceiverId(ctx context.Context, accountSenderId uuid.UUID,
+	accountReceiverId uuid.UUID) ([]TransactionSchema, error) {
+	rows, err := c.pgxPool.Query(ctx,
 		`SELECT id, account_sender_id, account_receiver_id, idempotency_key, amount, created_at, updated_at FROM transactions 
-	WHERE account_sender_id = $1 AND account_receiver_id = $2`, accountSenderId, accountReceiverId))
-
-	transactionsSchema := []TransactionSchema{}
-
-	for rows.Next() {
-		var item TransactionSchema
-		utils.ThrowOnError(rows.Scan(&item.Id, &item.AccountSenderId, &item.AccountReceiverId, &item.IdempotencyKey, &item.Amount, &item.UpdatedAt, &item.CreatedAt))
-		transactionsSchema = append(transactionsSchema, item)
+	WHERE account_sender_id = $1 AND account_receiver_id = $2`, accountSenderId, accountReceiverId)
+	if err != nil {
+		return nil, err
 	}
 
-	return transactionsSchema
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (TransactionSchema, error) {
+		var item TransactionSchema
+		err := row.Scan(&item.Id, &item.AccountSenderId, &item.AccountReceiverId, &item.IdempotencyKey, &item.Amount, &item.CreatedAt, &item.UpdatedAt)
+		return item, err
+	})
 }
 
-func (c *TransactionDAO) FindOneByIdempotencyKey(idempotencyKey uuid.UUID) *TransactionSchema {
+func (c *TransactionDAO) FindOneByIdempotencyKey(ctx context.Context, idempotencyKey uuid.UUID) (*TransactionSchema, error) {
 	var transactionSchema TransactionSchema
 
-	err := c.pgxPool.QueryRow(context.Background(),
+	err := c.pgxPool.QueryRow(ctx,
 		`SELECT id, account_sender_id, account_receiver_id, idempotency_key, amount, created_at, updated_at FROM transactions WHERE idempotency_key = $1`, idempotencyKey).
 		Scan(&transactionSchema.Id, &transactionSchema.AccountSenderId, &transactionSchema.AccountReceiverId, &transactionSchema.IdempotencyKey, &transactionSchema.Amount,
-			&transactionSchema.UpdatedAt, &transactionSchema.CreatedAt)
+			&transactionSchema.CreatedAt, &transactionSchema.UpdatedAt)
 
-	if err != nil && err == pgx.ErrNoRows {
-		return nil
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
 	}
 
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
-	return &transactionSchema
+	return &transactionSchema, nil
 }
 
-func (c *TransactionDAO) FindOneByAccountSenderIdAndAccountReceiverId(accountSenderId uuid.UUID, accountReceiverId uuid.UUID) *TransactionSchema {
+func (c *TransactionDAO) FindOneByAccountSenderIdAndAccountReceiverId(ctx context.Context, accountSenderId uuid.UUID,
+	accountReceiverId uuid.UUID) (*TransactionSchema, error) {
 	var transactionSchema TransactionSchema
 
-	err := c.pgxPool.QueryRow(context.Background(),
+	err := c.pgxPool.QueryRow(ctx,
 		`SELECT id, account_sender_id, account_receiver_id, idempotency_key, amount, created_at, updated_at FROM transactions 
 		WHERE account_sender_id = $1 AND account_receiver_id = $2`, accountSenderId, accountReceiverId).
 		Scan(&transactionSchema.Id, &transactionSchema.AccountSenderId, &transactionSchema.AccountReceiverId, &transactionSchema.IdempotencyKey, &transactionSchema.Amount,
-			&transactionSchema.UpdatedAt, &transactionSchema.CreatedAt)
+			&transactionSchema.CreatedAt, &transactionSchema.UpdatedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &transactionSchema, nil
+}
+
+func (c *TransactionDAO) FindAllHistoryByAccountId(ctx context.Context, accountId uuid.UUID,
+	filter TransactionHistoryFilter) ([]TransactionHistorySchema, error) {
+	rows, err := c.pgxPool.Query(ctx, `
+		SELECT
+			t.id,
+			cs.id,
+			cs.name,
+			cr.id,
+			cr.name,
+			t.account_sender_id,
+			t.account_receiver_id,
+			t.amount,
+			t.created_at
+		FROM transactions t
+		JOIN accounts asnd
+			ON t.account_sender_id = asnd.id
+		JOIN customers cs
+			ON asnd.customer_id = cs.id
+		JOIN accounts arec
+			ON t.account_receiver_id = arec.id
+		JOIN customers cr
+			ON arec.customer_id = cr.id
+		WHERE (t.account_sender_id = $1 OR t.account_receiver_id = $1)
+			AND ($2::timestamptz IS NULL OR t.created_at >= $2)
+			AND ($3::timestamptz IS NULL OR t.created_at < $3)
+			AND ($4::timestamptz IS NULL OR (t.created_at, t.id) < ($4, $5::uuid))
+		ORDER BY t.created_at DESC, t.id DESC
+		LIMIT $6`, accountId, filter.From, filter.To, filter.BeforeCreatedAt, filter.BeforeId, filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (TransactionHistorySchema, error) {
+		var item TransactionHistorySchema
+		err := row.Scan(&item.Id, &item.CustomerSenderId, &item.CustomerSenderName, &item.CustomerReceiverId, &item.CustomerReceiverName,
+			&item.AccountSenderId, &item.AccountReceiverId, &item.Amount, &item.CreatedAt)
+		return item, err
+	})
+}
 
-	if err != nil && err == pgx.ErrNoRows {
-		return nil
+func (c *TransactionDAO) FindOneHistoryById(ctx context.Context, id uuid.UUID) (*TransactionHistorySchema, error) {
+	var item TransactionHistorySchema
+
+	err := c.pgxPool.QueryRow(ctx, `
+		SELECT
+			t.id,
+			cs.id,
+			cs.name,
+			cr.id,
+			cr.name,
+			t.account_sender_id,
+			t.account_receiver_id,
+			t.amount,
+			t.created_at
+		FROM transactions t
+		JOIN accounts asnd
+			ON t.account_sender_id = asnd.id
+		JOIN customers cs
+			ON asnd.customer_id = cs.id
+		JOIN accounts arec
+			ON t.account_receiver_id = arec.id
+		JOIN customers cr
+			ON arec.customer_id = cr.id
+		WHERE t.id = $1`, id).
+		Scan(&item.Id, &item.CustomerSenderId, &item.CustomerSenderName, &item.CustomerReceiverId, &item.CustomerReceiverName,
+			&item.AccountSenderId, &item.AccountReceiverId, &item.Amount, &item.CreatedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
 	}
 
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
-	return &transactionSchema
+	return &item, nil
 }
 
-func (c *TransactionDAO) DeleteAll() {
-	_ = utils.GetOrThrow(c.pgxPool.Exec(context.Background(), "TRUNCATE TABLE transactions CASCADE"))
+func (c *TransactionDAO) DeleteAll(ctx context.Context) error {
+	_, err := c.pgxPool.Exec(ctx, "TRUNCATE TABLE transactions CASCADE")
+	return err
 }
@@ -0,0 +1,121 @@
+package daos
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	TransferStatusPending   = "pending"
+	TransferStatusCompleted = "completed"
+	TransferStatusFailed    = "failed"
+)
+
+// TransferSchema is a transfer requested by a customer. FailureCode and FailureReason are only
+// set once it failed, with the code and message of the domain error that made it fail.
+type TransferSchema struct {
+	Id                 uuid.UUID
+	SenderCustomerId   uuid.UUID
+	ReceiverCustomerId uuid.UUID
+	IdempotencyKey     string
+	Amount             int64
+	Status             string
+	FailureCode        *string
+	FailureReason      *string
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+type TransferDAO struct {
+	pgxPool *pgxpool.Pool
+}
+
+func NewTransferDAO(pgxPool *pgxpool.Pool) TransferDAO {
+	return TransferDAO{pgxPool}
+}
+
+func (t *TransferDAO) Create(ctx context.Context, tx pgx.Tx, transferSchema TransferSchema) error {
+	_, err := tx.Exec(ctx,
+		`INSERT INTO transfers (id, sender_customer_id, receiver_customer_id, idempotency_key, amount, status, failure_code, failure_reason, created_at,
+		updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		transferSchema.Id, transferSchema.SenderCustomerId, transferSchema.ReceiverCustomerId, transferSchema.IdempotencyKey, transferSchema.Amount,
+		transferSchema.Status, transferSchema.FailureCode, transferSchema.FailureReason, transferSchema.CreatedAt, transferSchema.UpdatedAt)
+
+	return err
+}
+
+func (t *TransferDAO) FindOneById(ctx context.Context, id uuid.UUID) (*TransferSchema, error) {
+	rows, err := t.pgxPool.Query(ctx,
+		`SELECT id, sender_customer_id, receiver_customer_id, idempotency_key, amount, status, failure_code, failure_reason, created_at, updated_at
+		FROM transfers WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectOneTransfer(rows)
+}
+
+// FindOneByIdForUpdate locks the transfer so that it is processed only once even when its
+// message is delivered twice concurrently.
+func (t *TransferDAO) FindOneByIdForUpdate(ctx context.Context, tx pgx.Tx, id uuid.UUID) (*TransferSchema, error) {
+	rows, err := tx.Query(ctx,
+		`SELECT id, sender_customer_id, receiver_customer_id, idempotency_key, amount, status, failure_code, failure_reason, created_at, updated_at
+		FROM transfers WHERE id = $1 FOR UPDATE`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectOneTransfer(rows)
+}
+
+// FindAllPendingForUpdate returns the transfers still pending that were last updated before
+// updatedBefore, skipping the ones being processed or requeued concurrently.
+func (t *TransferDAO) FindAllPendingForUpdate(ctx context.Context, tx pgx.Tx, updatedBefore time.Time, limit int) ([]TransferSchema, error) {
+	rows, err := tx.Query(ctx,
+		`SELECT id, sender_customer_id, receiver_customer_id, idempotency_key, amount, status, failure_code, failure_reason, created_at, updated_at
+		FROM transfers WHERE status = $1 AND updated_at < $2 ORDER BY updated_at, id LIMIT $3 FOR UPDATE SKIP LOCKED`,
+		TransferStatusPending, updatedBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, scanTransfer)
+}
+
+func (t *TransferDAO) UpdateStatus(ctx context.Context, tx pgx.Tx, transferSchema TransferSchema) error {
+	_, err := tx.Exec(ctx, "UPDATE transfers SET status = $1, failure_code = $2, failure_reason = $3, updated_at = $4 WHERE id = $5",
+		transferSchema.Status, transferSchema.FailureCode, transferSchema.FailureReason, transferSchema.UpdatedAt, transferSchema.Id)
+
+	return err
+}
+
+func (t *TransferDAO) DeleteAll(ctx context.Context) error {
+	_, err := t.pgxPool.Exec(ctx, "TRUNCATE TABLE transfers CASCADE")
+	return err
+}
+
+func collectOneTransfer(rows pgx.Rows) (*TransferSchema, error) {
+	transferSchema, err := pgx.CollectOneRow(rows, scanTransfer)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &transferSchema, nil
+}
+
+func scanTransfer(row pgx.CollectableRow) (TransferSchema, error) {
+	var item TransferSchema
+	err := row.Scan(&item.Id, &item.SenderCustomerId, &item.ReceiverCustomerId, &item.IdempotencyKey, &item.Amount, &item.Status,
+		&item.FailureCode, &item.FailureReason, &item.CreatedAt, &item.UpdatedAt)
+	return item, err
+}
@@ -0,0 +1,83 @@
+package daos
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// WebhookSchema is an URL a customer registered to be notified of its events. Secret signs
+// every delivery, so the receiver can tell they come from us.
+type WebhookSchema struct {
+	Id         uuid.UUID
+	CustomerId uuid.UUID
+	Url        string
+	Secret     string
+	CreatedAt  time.Time
+}
+
+type WebhookDAO struct {
+	pgxPool *pgxpool.Pool
+}
+
+func NewWebhookDAO(pgxPool *pgxpool.Pool) WebhookDAO {
+	return WebhookDAO{pgxPool}
+}
+
+func (w *WebhookDAO) Create(ctx context.Context, webhookSchema WebhookSchema) error {
+	_, err := w.pgxPool.Exec(ctx,
+		"INSERT INTO webhooks (id, customer_id, url, secret, created_at) VALUES ($1, $2, $3, $4, $5)",
+		webhookSchema.Id, webhookSchema.CustomerId, webhookSchema.Url, webhookSchema.Secret, webhookSchema.CreatedAt)
+
+	return err
+}
+
+func (w *WebhookDAO) FindOneById(ctx context.Context, id uuid.UUID) (*WebhookSchema, error) {
+	var webhookSchema WebhookSchema
+
+	err := w.pgxPool.QueryRow(ctx, "SELECT id, customer_id, url, secret, created_at FROM webhooks WHERE id = $1", id).
+		Scan(&webhookSchema.Id, &webhookSchema.CustomerId, &webhookSchema.Url, &webhookSchema.Secret, &webhookSchema.CreatedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhookSchema, nil
+}
+
+func (w *WebhookDAO) FindAllByCustomerId(ctx context.Context, customerId uuid.UUID) ([]WebhookSchema, error) {
+	rows, err := w.pgxPool.Query(ctx,
+		"SELECT id, customer_id, url, secret, created_at FROM webhooks WHERE customer_id = $1 ORDER BY created_at, id", customerId)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (WebhookSchema, error) {
+		var item WebhookSchema
+		err := row.Scan(&item.Id, &item.CustomerId, &item.Url, &item.Secret, &item.CreatedAt)
+		return item, err
+	})
+}
+
+// Delete also deletes the deliveries of the webhook, pending ones included.
+func (w *WebhookDAO) Delete(ctx context.Context, id uuid.UUID) (bool, error) {
+	commandTag, err := w.pgxPool.Exec(ctx, "DELETE FROM webhooks WHERE id = $1", id)
+	if err != nil {
+		return false, err
+	}
+
+	return commandTag.RowsAffected() == 1, nil
+}
+
+func (w *WebhookDAO) DeleteAll(ctx context.Context) error {
+	_, err := w.pgxPool.Exec(ctx, "TRUNCATE TABLE webhooks CASCADE")
+	return err
+}
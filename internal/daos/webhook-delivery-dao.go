@@ -0,0 +1,116 @@
+package daos
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	WebhookDeliveryStatusPending      = "pending"
+	WebhookDeliveryStatusDelivered    = "delivered"
+	WebhookDeliveryStatusDeadLettered = "dead_lettered"
+)
+
+// WebhookDeliverySchema is an event to be sent to a webhook and the outcome of its last
+// attempt. NextAttemptAt is only set while the delivery is pending.
+type WebhookDeliverySchema struct {
+	Id                 uuid.UUID
+	WebhookId          uuid.UUID
+	EventId            uuid.UUID
+	EventType          string
+	Payload            []byte
+	Status             string
+	Attempts           int
+	NextAttemptAt      *time.Time
+	LastAttemptedAt    *time.Time
+	LastResponseStatus *int
+	LastError          *string
+	CreatedAt          time.Time
+	DeliveredAt        *time.Time
+}
+
+type WebhookDeliveryDAO struct {
+	pgxPool *pgxpool.Pool
+}
+
+func NewWebhookDeliveryDAO(pgxPool *pgxpool.Pool) WebhookDeliveryDAO {
+	return WebhookDeliveryDAO{pgxPool}
+}
+
+// CreateAllByCustomerId schedules the event for every webhook of the customer, to be attempted
+// right away. It must be called in the transaction of the change the event describes.
+func (w *WebhookDeliveryDAO) CreateAllByCustomerId(ctx context.Context, tx pgx.Tx, customerId uuid.UUID, eventId uuid.UUID, eventType string,
+	payload []byte, createdAt time.Time) error {
+	_, err := tx.Exec(ctx,
+		`INSERT INTO webhook_deliveries (id, webhook_id, event_id, event_type, payload, status, attempts, next_attempt_at, created_at)
+		SELECT gen_random_uuid(), id, $2, $3, $4, $5, 0, $6, $6 FROM webhooks WHERE customer_id = $1
+		ON CONFLICT (webhook_id, event_id) DO NOTHING`,
+		customerId, eventId, eventType, payload, WebhookDeliveryStatusPending, createdAt)
+
+	return err
+}
+
+// FindOneDueForUpdate returns the pending delivery that has been due the longest, skipping the
+// ones locked by another worker so several can deliver at once.
+func (w *WebhookDeliveryDAO) FindOneDueForUpdate(ctx context.Context, tx pgx.Tx, now time.Time) (*WebhookDeliverySchema, error) {
+	rows, err := tx.Query(ctx,
+		`SELECT id, webhook_id, event_id, event_type, payload, status, attempts, next_attempt_at, last_attempted_at, last_response_status,
+		last_error, created_at, delivered_at FROM webhook_deliveries
+		WHERE status = $1 AND next_attempt_at <= $2 ORDER BY next_attempt_at, id LIMIT 1 FOR UPDATE SKIP LOCKED`,
+		WebhookDeliveryStatusPending, now)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookDeliverySchema, err := pgx.CollectOneRow(rows, scanWebhookDelivery)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhookDeliverySchema, nil
+}
+
+// UpdateAttempt records the outcome of an attempt.
+func (w *WebhookDeliveryDAO) UpdateAttempt(ctx context.Context, tx pgx.Tx, webhookDeliverySchema WebhookDeliverySchema) error {
+	_, err := tx.Exec(ctx,
+		`UPDATE webhook_deliveries SET status = $1, attempts = $2, next_attempt_at = $3, last_attempted_at = $4, last_response_status = $5,
+		last_error = $6, delivered_at = $7 WHERE id = $8`,
+		webhookDeliverySchema.Status, webhookDeliverySchema.Attempts, webhookDeliverySchema.NextAttemptAt, webhookDeliverySchema.LastAttemptedAt,
+		webhookDeliverySchema.LastResponseStatus, webhookDeliverySchema.LastError, webhookDeliverySchema.DeliveredAt, webhookDeliverySchema.Id)
+
+	return err
+}
+
+// FindAllByWebhookId returns the latest deliveries of the webhook, newest first.
+func (w *WebhookDeliveryDAO) FindAllByWebhookId(ctx context.Context, webhookId uuid.UUID, limit int) ([]WebhookDeliverySchema, error) {
+	rows, err := w.pgxPool.Query(ctx,
+		`SELECT id, webhook_id, event_id, event_type, payload, status, attempts, next_attempt_at, last_attempted_at, last_response_status,
+		last_error, created_at, delivered_at FROM webhook_deliveries WHERE webhook_id = $1 ORDER BY created_at DESC, id DESC LIMIT $2`,
+		webhookId, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, scanWebhookDelivery)
+}
+
+func (w *WebhookDeliveryDAO) DeleteAll(ctx context.Context) error {
+	_, err := w.pgxPool.Exec(ctx, "TRUNCATE TABLE webhook_deliveries")
+	return err
+}
+
+func scanWebhookDelivery(row pgx.CollectableRow) (WebhookDeliverySchema, error) {
+	var item WebhookDeliverySchema
+	err := row.Scan(&item.Id, &item.WebhookId, &item.EventId, &item.EventType, &item.Payload, &item.Status, &item.Attempts, &item.NextAttemptAt,
+		&item.LastAttemptedAt, &item.LastResponseStatus, &item.LastError, &item.CreatedAt, &item.DeliveredAt)
+	return item, err
+}
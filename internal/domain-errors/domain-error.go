@@ -0,0 +1,16 @@
+package domainerrors
+
+// DomainError is an expected business failure. Code is stable and meant for clients to branch
+// on, while Message is human readable and may be reworded at any time.
+type DomainError struct {
+	Code    string
+	Message string
+}
+
+func New(code string, message string) *DomainError {
+	return &DomainError{code, message}
+}
+
+func (d *DomainError) Error() string {
+	return d.Message
+}
@@ -0,0 +1,57 @@
+package domainerrors
+
+var (
+	ErrNameTooShort              = New("name_too_short", "name must be at least 2 characters")
+	ErrEmailAddressInvalid       = New("email_address_invalid", "email address is invalid")
+	ErrEmailAddressAlreadyTaken  = New("email_address_already_taken", "this email address has already been taken by someone")
+	ErrEmailOrPasswordIncorrect  = New("email_or_password_incorrect", "email or password is incorrect")
+	ErrLoginLocked               = New("login_locked", "too many failed login attempts, try again later")
+	ErrRefreshTokenInvalid       = New("refresh_token_invalid", "refresh token is invalid or expired")
+	ErrRefreshTokenReused        = New("refresh_token_reused", "refresh token has already been used, every session issued from it was revoked")
+	ErrAccessTokenRevoked        = New("access_token_revoked", "access token has been revoked")
+	ErrCustomerNotFound          = New("customer_not_found", "customer not found")
+	ErrLoginChallengeInvalid     = New("login_challenge_invalid", "login challenge is invalid or expired")
+	ErrPasswordResetTokenInvalid = New("password_reset_token_invalid", "password reset token is invalid or expired")
+	ErrCurrentPasswordIncorrect  = New("current_password_incorrect", "current password is incorrect")
+	ErrRateLimitExceeded         = New("rate_limit_exceeded", "too many requests, try again later")
+
+	ErrEmailVerificationTokenInvalid = New("email_verification_token_invalid", "email verification token is invalid or expired")
+	ErrEmailNotVerified              = New("email_not_verified", "email address must be verified before transferring")
+
+	ErrPasswordMissingUppercase     = New("password_missing_uppercase", "password must contain an uppercase letter")
+	ErrPasswordMissingLowercase     = New("password_missing_lowercase", "password must contain a lowercase letter")
+	ErrPasswordMissingDigit         = New("password_missing_digit", "password must contain a digit")
+	ErrPasswordMissingSymbol        = New("password_missing_symbol", "password must contain a symbol")
+	ErrPasswordContainsPersonalInfo = New("password_contains_personal_info", "password must not contain your name or email address")
+	ErrPasswordBreached             = New("password_breached", "password has appeared in a data breach and cannot be used")
+
+	ErrTotpAlreadyEnabled = New("totp_already_enabled", "two-factor authentication is already enabled")
+	ErrTotpNotEnrolled    = New("totp_not_enrolled", "two-factor authentication enrollment has not been started")
+	ErrTotpNotEnabled     = New("totp_not_enabled", "two-factor authentication is not enabled")
+	ErrTotpCodeRequired   = New("totp_code_required", "a two-factor authentication code is required")
+	ErrTotpCodeInvalid    = New("totp_code_invalid", "two-factor authentication code is invalid or has already been used")
+	ErrTotpLocked         = New("totp_locked", "too many invalid two-factor authentication codes, try again later")
+
+	ErrTransferToYourself     = New("transfer_to_yourself", "you cannot transfer to yourself")
+	ErrTransferAmountZero     = New("transfer_amount_zero", "the amount to be transferred cannot be zero")
+	ErrInsufficientBalance    = New("insufficient_balance", "the sender does not have enough balance to make the transfer")
+	ErrTransferRequiresTotp   = New("transfer_requires_totp", "two-factor authentication must be enabled to transfer this amount")
+	ErrIdempotencyKeyRequired = New("idempotency_key_required", "idempotency-key header is required")
+	ErrIdempotencyKeyInvalid  = New("idempotency_key_invalid", "idempotency-key header must be uuidv4")
+	ErrIdempotencyKeyReused   = New("idempotency_key_reused", "the idempotency key has already been used with a different request")
+	ErrTransferNotFound       = New("transfer_not_found", "transfer not found")
+
+	// ErrTransferProcessingFailed is only recorded on transfers given up on by the transfer
+	// worker, never returned to clients.
+	ErrTransferProcessingFailed = New("transfer_processing_failed", "the transfer could not be processed, no money was moved")
+
+	ErrWebhookUrlInvalid   = New("webhook_url_invalid", "webhook url must be an absolute https url")
+	ErrWebhookLimitReached = New("webhook_limit_reached", "you cannot register more than 10 webhooks")
+	ErrWebhookNotFound     = New("webhook_not_found", "webhook not found")
+
+	ErrTransactionNotFound = New("transaction_not_found", "transaction not found")
+
+	ErrHistoryLimitOutOfRange = New("history_limit_out_of_range", "limit must be between 1 and 100")
+	ErrHistoryRangeInvalid    = New("history_range_invalid", "from must be before to")
+	ErrHistoryCursorInvalid   = New("history_cursor_invalid", "cursor is invalid")
+)
@@ -0,0 +1,24 @@
+package domainerrors
+
+import "fmt"
+
+// PasswordPolicyError lists every rule a new password breaks, so the customer can fix them all
+// at once. Field names the input the password came from.
+type PasswordPolicyError struct {
+	Field      string
+	Violations []*DomainError
+}
+
+func NewPasswordPolicyError(field string, violations []*DomainError) *PasswordPolicyError {
+	return &PasswordPolicyError{field, violations}
+}
+
+// NewPasswordTooShort is the violation of the minimum length, which is configurable and so
+// cannot be a fixed error.
+func NewPasswordTooShort(minLength int) *DomainError {
+	return New("password_too_short", fmt.Sprintf("password must be at least %d characters", minLength))
+}
+
+func (p *PasswordPolicyError) Error() string {
+	return "password does not meet the password policy"
+}
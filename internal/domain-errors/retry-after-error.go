@@ -0,0 +1,22 @@
+package domainerrors
+
+import "time"
+
+// RetryAfterError is a domain error that only holds for a while, such as a lockout. RetryAfter
+// tells the client how long to wait before trying again.
+type RetryAfterError struct {
+	Err        *DomainError
+	RetryAfter time.Duration
+}
+
+func NewRetryAfterError(err *DomainError, retryAfter time.Duration) *RetryAfterError {
+	return &RetryAfterError{err, retryAfter}
+}
+
+func (r *RetryAfterError) Error() string {
+	return r.Err.Error()
+}
+
+func (r *RetryAfterError) Unwrap() error {
+	return r.Err
+}
@@ -0,0 +1,102 @@
+package gateways
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// AccessTokenSigningKey is the Ed25519 key access tokens are currently signed with. Id goes in
+// the kid header so verifiers know which public key to check the signature against.
+type AccessTokenSigningKey struct {
+	Id         string
+	PrivateKey ed25519.PrivateKey
+}
+
+// AccessTokenKeysGateway reads the access token keys from the configuration:
+//
+//   - ACCESS_TOKEN_SIGNING_KEY_ID and ACCESS_TOKEN_SIGNING_PRIVATE_KEY, the base64 encoded 32
+//     byte seed, are the key new tokens are signed with.
+//   - ACCESS_TOKEN_VERIFICATION_KEYS, optional, lists other public keys still accepted as
+//     "kid:base64,kid:base64".
+//
+// To rotate, publish the next key in ACCESS_TOKEN_VERIFICATION_KEYS first, then make it the
+// signing key and keep the previous one listed until the tokens it signed have expired.
+type AccessTokenKeysGateway struct {
+	configGateway ConfigGateway
+}
+
+func NewAccessTokenKeysGateway(configGateway ConfigGateway) AccessTokenKeysGateway {
+	return AccessTokenKeysGateway{configGateway}
+}
+
+func (a *AccessTokenKeysGateway) SigningKey(ctx context.Context) (AccessTokenSigningKey, error) {
+	keyId, err := a.configGateway.GetString(ctx, "ACCESS_TOKEN_SIGNING_KEY_ID")
+	if err != nil {
+		return AccessTokenSigningKey{}, err
+	}
+
+	encodedSeed, err := a.configGateway.GetString(ctx, "ACCESS_TOKEN_SIGNING_PRIVATE_KEY")
+	if err != nil {
+		return AccessTokenSigningKey{}, err
+	}
+
+	seed, err := base64.StdEncoding.DecodeString(encodedSeed)
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return AccessTokenSigningKey{}, fmt.Errorf("ACCESS_TOKEN_SIGNING_PRIVATE_KEY must be a base64 encoded %d byte Ed25519 seed", ed25519.SeedSize)
+	}
+
+	return AccessTokenSigningKey{
+		Id:         keyId,
+		PrivateKey: ed25519.NewKeyFromSeed(seed),
+	}, nil
+}
+
+// VerificationKeys returns every public key tokens may be signed with, by kid, the current
+// signing key included.
+func (a *AccessTokenKeysGateway) VerificationKeys(ctx context.Context) (map[string]ed25519.PublicKey, error) {
+	signingKey, err := a.SigningKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	verificationKeys := map[string]ed25519.PublicKey{
+		signingKey.Id: signingKey.PrivateKey.Public().(ed25519.PublicKey),
+	}
+
+	encodedKeys, err := a.configGateway.GetString(ctx, "ACCESS_TOKEN_VERIFICATION_KEYS")
+	if errors.Is(err, ErrConfigKeyNotFound) {
+		return verificationKeys, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(encodedKeys) == "" {
+		return verificationKeys, nil
+	}
+
+	for _, encodedKey := range strings.Split(encodedKeys, ",") {
+		keyId, encodedPublicKey, ok := strings.Cut(strings.TrimSpace(encodedKey), ":")
+		if !ok || keyId == "" {
+			return nil, fmt.Errorf("ACCESS_TOKEN_VERIFICATION_KEYS must be a list of kid:base64 entries, got %q", encodedKey)
+		}
+
+		publicKey, err := base64.StdEncoding.DecodeString(encodedPublicKey)
+		if err != nil || len(publicKey) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("ACCESS_TOKEN_VERIFICATION_KEYS key %s must be a base64 encoded %d byte Ed25519 public key", keyId, ed25519.PublicKeySize)
+		}
+
+		if _, exists := verificationKeys[keyId]; exists {
+			continue
+		}
+
+		verificationKeys[keyId] = publicKey
+	}
+
+	return verificationKeys, nil
+}
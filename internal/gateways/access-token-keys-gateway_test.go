@@ -0,0 +1,82 @@
+package gateways_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"testing"
+
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
+	"github.com/stretchr/testify/suite"
+)
+
+type AccessTokenKeysGatewaySuite struct {
+	suite.Suite
+	accessTokenKeysGateway gateways.AccessTokenKeysGateway
+}
+
+func (a *AccessTokenKeysGatewaySuite) SetupTest() {
+	a.accessTokenKeysGateway = gateways.NewAccessTokenKeysGateway(gateways.NewEnvConfigGateway())
+}
+
+func (a *AccessTokenKeysGatewaySuite) Test1() {
+	a.Run("given a signing key, when getting it, then returns the key derived from the seed", func() {
+		a.T().Setenv("ACCESS_TOKEN_SIGNING_KEY_ID", "key-2")
+		a.T().Setenv("ACCESS_TOKEN_SIGNING_PRIVATE_KEY", "j1SDbI5Y7qVB8ZU/1CBEWz+KRr8LRW4SyonkVEj85LA=")
+
+		signingKey, err := a.accessTokenKeysGateway.SigningKey(context.Background())
+		a.Require().NoError(err)
+		a.Require().Equal("key-2", signingKey.Id)
+		a.Require().Equal("dfePGW/39XRonwbtbdxpzqsxQ7c0FIsh4+vacl8XPFY=",
+			base64.StdEncoding.EncodeToString(signingKey.PrivateKey.Public().(ed25519.PublicKey)))
+	})
+}
+
+func (a *AccessTokenKeysGatewaySuite) Test2() {
+	a.Run("given previous verification keys, when getting the verification keys, then returns them with the signing key", func() {
+		a.T().Setenv("ACCESS_TOKEN_SIGNING_KEY_ID", "key-2")
+		a.T().Setenv("ACCESS_TOKEN_SIGNING_PRIVATE_KEY", "j1SDbI5Y7qVB8ZU/1CBEWz+KRr8LRW4SyonkVEj85LA=")
+		a.T().Setenv("ACCESS_TOKEN_VERIFICATION_KEYS", "key-1:V2a6t2pfc4ceM6PiiSPwcG5PFt9UFtCeabzboB1lyCE=, key-2:dfePGW/39XRonwbtbdxpzqsxQ7c0FIsh4+vacl8XPFY=")
+
+		verificationKeys, err := a.accessTokenKeysGateway.VerificationKeys(context.Background())
+		a.Require().NoError(err)
+		a.Require().Len(verificationKeys, 2)
+		a.Require().Equal("V2a6t2pfc4ceM6PiiSPwcG5PFt9UFtCeabzboB1lyCE=", base64.StdEncoding.EncodeToString(verificationKeys["key-1"]))
+		a.Require().Equal("dfePGW/39XRonwbtbdxpzqsxQ7c0FIsh4+vacl8XPFY=", base64.StdEncoding.EncodeToString(verificationKeys["key-2"]))
+	})
+}
+
+func (a *AccessTokenKeysGatewaySuite) Test3() {
+	a.Run("given no previous verification keys, when getting the verification keys, then returns only the signing key", func() {
+		a.T().Setenv("ACCESS_TOKEN_SIGNING_KEY_ID", "key-2")
+		a.T().Setenv("ACCESS_TOKEN_SIGNING_PRIVATE_KEY", "j1SDbI5Y7qVB8ZU/1CBEWz+KRr8LRW4SyonkVEj85LA=")
+
+		verificationKeys, err := a.accessTokenKeysGateway.VerificationKeys(context.Background())
+		a.Require().NoError(err)
+		a.Require().Len(verificationKeys, 1)
+		a.Require().Contains(verificationKeys, "key-2")
+	})
+}
+
+func (a *AccessTokenKeysGatewaySuite) Test4() {
+	a.Run("given malformed keys, when getting the keys, then returns error", func() {
+		a.T().Setenv("ACCESS_TOKEN_SIGNING_KEY_ID", "key-2")
+		a.T().Setenv("ACCESS_TOKEN_SIGNING_PRIVATE_KEY", "abc")
+
+		_, err := a.accessTokenKeysGateway.SigningKey(context.Background())
+		a.Require().ErrorContains(err, "ACCESS_TOKEN_SIGNING_PRIVATE_KEY must be a base64 encoded 32 byte Ed25519 seed")
+
+		a.T().Setenv("ACCESS_TOKEN_SIGNING_PRIVATE_KEY", "j1SDbI5Y7qVB8ZU/1CBEWz+KRr8LRW4SyonkVEj85LA=")
+
+		for _, verificationKeys := range []string{"key-1", ":V2a6t2pfc4ceM6PiiSPwcG5PFt9UFtCeabzboB1lyCE=", "key-1:abc"} {
+			a.T().Setenv("ACCESS_TOKEN_VERIFICATION_KEYS", verificationKeys)
+
+			_, err := a.accessTokenKeysGateway.VerificationKeys(context.Background())
+			a.Require().ErrorContains(err, "ACCESS_TOKEN_VERIFICATION_KEYS")
+		}
+	})
+}
+
+func TestAccessTokenKeysGateway(t *testing.T) {
+	suite.Run(t, new(AccessTokenKeysGatewaySuite))
+}
@@ -0,0 +1,42 @@
+package gateways
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// AsyncNotifierGateway sends notifications in the background, so a request takes as long whether
+// or not it sends one. Endpoints that notify only registered emails use it to not reveal which
+// emails are registered through their response times. Failures are logged, not returned.
+type AsyncNotifierGateway struct {
+	notifierGateway NotifierGateway
+	logger          *slog.Logger
+	timeout         time.Duration
+	waitGroup       sync.WaitGroup
+}
+
+func NewAsyncNotifierGateway(notifierGateway NotifierGateway, logger *slog.Logger, timeout time.Duration) *AsyncNotifierGateway {
+	return &AsyncNotifierGateway{notifierGateway: notifierGateway, logger: logger, timeout: timeout}
+}
+
+// Send returns at once. The notification is sent with a context that outlives ctx, since the
+// request that asked for it is usually answered first, bounded by the gateway timeout.
+func (a *AsyncNotifierGateway) Send(ctx context.Context, notification Notification) error {
+	a.waitGroup.Go(func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), a.timeout)
+		defer cancel()
+
+		if err := a.notifierGateway.Send(ctx, notification); err != nil {
+			a.logger.LogAttrs(ctx, slog.LevelError, "notification failed", slog.String("error", err.Error()))
+		}
+	})
+
+	return nil
+}
+
+// Wait blocks until every notification sent so far is delivered or has failed.
+func (a *AsyncNotifierGateway) Wait() {
+	a.waitGroup.Wait()
+}
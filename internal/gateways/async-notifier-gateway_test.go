@@ -0,0 +1,64 @@
+package gateways_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
+	"github.com/stretchr/testify/suite"
+)
+
+type failingNotifierGateway struct{}
+
+func (f failingNotifierGateway) Send(ctx context.Context, notification gateways.Notification) error {
+	return errors.New("connection refused")
+}
+
+type AsyncNotifierGatewaySuite struct {
+	suite.Suite
+}
+
+func (a *AsyncNotifierGatewaySuite) Test1() {
+	a.Run("when sending a notification, then delivers it after the request context is cancelled", func() {
+		path := filepath.Join(a.T().TempDir(), "notifications.jsonl")
+		asyncNotifierGateway := gateways.NewAsyncNotifierGateway(gateways.NewFileNotifierGateway(path),
+			slog.New(slog.NewTextHandler(io.Discard, nil)), time.Second)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		a.Require().NoError(asyncNotifierGateway.Send(ctx, gateways.Notification{
+			To:      "john.doe@gmail.com",
+			Subject: "Subject 1",
+			Body:    "Body 1",
+		}))
+		cancel()
+		asyncNotifierGateway.Wait()
+
+		content, err := os.ReadFile(path)
+		a.Require().NoError(err)
+		a.Require().Equal(`{"to":"john.doe@gmail.com","subject":"Subject 1","body":"Body 1"}`+"\n", string(content))
+	})
+}
+
+func (a *AsyncNotifierGatewaySuite) Test2() {
+	a.Run("given a notifier that fails, when sending a notification, then returns no error", func() {
+		asyncNotifierGateway := gateways.NewAsyncNotifierGateway(failingNotifierGateway{},
+			slog.New(slog.NewTextHandler(io.Discard, nil)), time.Second)
+
+		a.Require().NoError(asyncNotifierGateway.Send(context.Background(), gateways.Notification{
+			To:      "john.doe@gmail.com",
+			Subject: "Subject 1",
+			Body:    "Body 1",
+		}))
+		asyncNotifierGateway.Wait()
+	})
+}
+
+func TestAsyncNotifierGateway(t *testing.T) {
+	suite.Run(t, new(AsyncNotifierGatewaySuite))
+}
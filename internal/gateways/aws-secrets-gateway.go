@@ -3,40 +3,143 @@ package gateways
 import (
 	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"os"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
-	"github.com/gsaaraujo/pay-bank-api/internal/utils"
 )
 
+// AwsSecretsGateway reads the application secret, a single JSON document stored in Secrets
+// Manager, and keeps it in memory for ttl. Once the ttl expires the next read fetches it again,
+// and StartRotation refreshes it in the background so reads never have to wait for AWS.
+// When a refresh fails the last known values keep being served.
 type AwsSecretsGateway struct {
 	secretsClient *secretsmanager.Client
+	ttl           time.Duration
+
+	mutex     sync.RWMutex
+	secret    map[string]any
+	fetchedAt time.Time
 }
 
-func NewAwsSecretsGateway(secretsClient *secretsmanager.Client) AwsSecretsGateway {
-	return AwsSecretsGateway{
+func NewAwsSecretsGateway(secretsClient *secretsmanager.Client, ttl time.Duration) *AwsSecretsGateway {
+	return &AwsSecretsGateway{
 		secretsClient: secretsClient,
+		ttl:           ttl,
+	}
+}
+
+func (a *AwsSecretsGateway) Get(ctx context.Context, key string) (any, error) {
+	secret, err := a.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	value, exists := secret[key]
+	if !exists {
+		return nil, configNotFound(key)
+	}
+
+	return value, nil
+}
+
+func (a *AwsSecretsGateway) GetString(ctx context.Context, key string) (string, error) {
+	value, err := a.Get(ctx, key)
+	if err != nil {
+		return "", err
+	}
+
+	return configString(key, value)
+}
+
+func (a *AwsSecretsGateway) GetInt(ctx context.Context, key string) (int, error) {
+	value, err := a.Get(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+
+	return configInt(key, value)
+}
+
+func (a *AwsSecretsGateway) GetBool(ctx context.Context, key string) (bool, error) {
+	value, err := a.Get(ctx, key)
+	if err != nil {
+		return false, err
 	}
+
+	return configBool(key, value)
 }
 
-func (a *AwsSecretsGateway) Get(key string) any {
-	if _, ok := os.LookupEnv("AWS_SECRET_MANAGER_NAME"); !ok {
-		panic("AWS_SECRET_MANAGER_NAME environment variable not found")
+// Refresh fetches the secret from Secrets Manager and replaces the cached values.
+func (a *AwsSecretsGateway) Refresh(ctx context.Context) error {
+	secretName, ok := os.LookupEnv("AWS_SECRET_MANAGER_NAME")
+	if !ok {
+		return errors.New("AWS_SECRET_MANAGER_NAME environment variable not found")
 	}
 
-	secretValue := utils.GetOrThrow(a.secretsClient.GetSecretValue(context.TODO(), &secretsmanager.GetSecretValueInput{
-		SecretId: aws.String(os.Getenv("AWS_SECRET_MANAGER_NAME")),
-	}))
+	secretValue, err := a.secretsClient.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(secretName),
+	})
+	if err != nil {
+		return err
+	}
 
 	var secret map[string]any
-	utils.ThrowOnError(json.Unmarshal([]byte(*secretValue.SecretString), &secret))
+	if err := json.Unmarshal([]byte(*secretValue.SecretString), &secret); err != nil {
+		return err
+	}
 
-	value, exists := secret[key]
-	if !exists {
-		panic(fmt.Sprintf("%s secret not found", key))
+	a.mutex.Lock()
+	a.secret = secret
+	a.fetchedAt = time.Now()
+	a.mutex.Unlock()
+
+	return nil
+}
+
+// StartRotation refreshes the secret every interval until ctx is done, so rotated values are
+// picked up without a restart. Failed refreshes are reported to onError and retried on the
+// next tick.
+func (a *AwsSecretsGateway) StartRotation(ctx context.Context, interval time.Duration, onError func(err error)) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := a.Refresh(ctx); err != nil && ctx.Err() == nil {
+					onError(err)
+				}
+			}
+		}
+	}()
+}
+
+func (a *AwsSecretsGateway) load(ctx context.Context) (map[string]any, error) {
+	a.mutex.RLock()
+	secret, fetchedAt := a.secret, a.fetchedAt
+	a.mutex.RUnlock()
+
+	if secret != nil && time.Since(fetchedAt) < a.ttl {
+		return secret, nil
 	}
 
-	return value
+	if err := a.Refresh(ctx); err != nil {
+		if secret != nil {
+			return secret, nil
+		}
+
+		return nil, err
+	}
+
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	return a.secret, nil
 }
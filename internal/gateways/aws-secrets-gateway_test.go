@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -16,8 +17,7 @@ import (
 
 type AwsSecretsGatewaySuite struct {
 	suite.Suite
-	secretsClient     *secretsmanager.Client
-	awsSecretsGateway gateways.AwsSecretsGateway
+	secretsClient *secretsmanager.Client
 }
 
 func (a *AwsSecretsGatewaySuite) SetupSuite() {
@@ -31,8 +31,6 @@ func (a *AwsSecretsGatewaySuite) SetupSuite() {
 
 	awsConfig := utils.GetOrThrow(config.LoadDefaultConfig(context.TODO()))
 	a.secretsClient = secretsmanager.NewFromConfig(awsConfig)
-
-	a.awsSecretsGateway = gateways.NewAwsSecretsGateway(a.secretsClient)
 }
 
 func (a *AwsSecretsGatewaySuite) SetupTest() {
@@ -41,46 +39,111 @@ func (a *AwsSecretsGatewaySuite) SetupTest() {
 		ForceDeleteWithoutRecovery: aws.Bool(true),
 	})
 	a.Require().NoError(err)
+
+	utils.GetOrThrow(a.secretsClient.CreateSecret(context.TODO(), &secretsmanager.CreateSecretInput{
+		Name: aws.String("secret-us-east-1-local-app"),
+		SecretString: aws.String(`
+			{
+				"ANY_INT": 1,
+				"ANY_STRING": "abc",
+				"ANY_BOOLEAN": true
+			}
+		`),
+	}))
+}
+
+func (a *AwsSecretsGatewaySuite) putSecret(secretString string) {
+	utils.GetOrThrow(a.secretsClient.PutSecretValue(context.TODO(), &secretsmanager.PutSecretValueInput{
+		SecretId:     aws.String("secret-us-east-1-local-app"),
+		SecretString: aws.String(secretString),
+	}))
 }
 
 func (a *AwsSecretsGatewaySuite) Test1() {
 	a.Run("given that the secrets were added, when getting, then returns secret", func() {
-		utils.GetOrThrow(a.secretsClient.CreateSecret(context.TODO(), &secretsmanager.CreateSecretInput{
-			Name: aws.String("secret-us-east-1-local-app"),
-			SecretString: aws.String(`
-				{
-					"ANY_INT": 1,
-					"ANY_STRING": "abc",
-					"ANY_BOOLEAN": true
-				}
-			`),
-		}))
-
-		anyInt := a.awsSecretsGateway.Get("ANY_INT")
-		a.Require().Equal(float64(1), anyInt)
-
-		anyString := a.awsSecretsGateway.Get("ANY_STRING")
+		awsSecretsGateway := gateways.NewAwsSecretsGateway(a.secretsClient, time.Hour)
+
+		anyInt, err := awsSecretsGateway.GetInt(context.Background(), "ANY_INT")
+		a.Require().NoError(err)
+		a.Require().Equal(1, anyInt)
+
+		anyString, err := awsSecretsGateway.GetString(context.Background(), "ANY_STRING")
+		a.Require().NoError(err)
 		a.Require().Equal("abc", anyString)
 
-		anyBoolean := a.awsSecretsGateway.Get("ANY_BOOLEAN")
+		anyBoolean, err := awsSecretsGateway.GetBool(context.Background(), "ANY_BOOLEAN")
+		a.Require().NoError(err)
 		a.Require().Equal(true, anyBoolean)
 	})
 }
 
 func (a *AwsSecretsGatewaySuite) Test2() {
-	a.Run("given that the secrets were not added, when getting, then panics", func() {
-		utils.GetOrThrow(a.secretsClient.CreateSecret(context.TODO(), &secretsmanager.CreateSecretInput{
-			Name: aws.String("secret-us-east-1-local-app"),
-			SecretString: aws.String(`
-				{
-					"ANY_INT": 1,
-					"ANY_STRING": "abc",
-					"ANY_BOOLEAN": true
-				}
-			`),
-		}))
-
-		a.PanicsWithValue("POSTGRES_URL secret not found", func() { a.awsSecretsGateway.Get("POSTGRES_URL") })
+	a.Run("given that the secrets were not added, when getting, then returns error", func() {
+		awsSecretsGateway := gateways.NewAwsSecretsGateway(a.secretsClient, time.Hour)
+
+		_, err := awsSecretsGateway.GetString(context.Background(), "POSTGRES_URL")
+		a.Require().ErrorIs(err, gateways.ErrConfigKeyNotFound)
+		a.Require().EqualError(err, "POSTGRES_URL: configuration key not found")
+	})
+}
+
+func (a *AwsSecretsGatewaySuite) Test3() {
+	a.Run("given that the secret has another type, when getting, then returns error", func() {
+		awsSecretsGateway := gateways.NewAwsSecretsGateway(a.secretsClient, time.Hour)
+
+		_, err := awsSecretsGateway.GetString(context.Background(), "ANY_INT")
+		a.Require().ErrorIs(err, gateways.ErrConfigKeyWrongType)
+
+		_, err = awsSecretsGateway.GetInt(context.Background(), "ANY_STRING")
+		a.Require().ErrorIs(err, gateways.ErrConfigKeyWrongType)
+
+		_, err = awsSecretsGateway.GetBool(context.Background(), "ANY_STRING")
+		a.Require().ErrorIs(err, gateways.ErrConfigKeyWrongType)
+	})
+}
+
+func (a *AwsSecretsGatewaySuite) Test4() {
+	a.Run("given that the secret was cached, when it changes, then keeps the cached value until the ttl expires", func() {
+		awsSecretsGateway := gateways.NewAwsSecretsGateway(a.secretsClient, 500*time.Millisecond)
+
+		anyString, err := awsSecretsGateway.GetString(context.Background(), "ANY_STRING")
+		a.Require().NoError(err)
+		a.Require().Equal("abc", anyString)
+
+		a.putSecret(`{"ANY_STRING": "def"}`)
+
+		anyString, err = awsSecretsGateway.GetString(context.Background(), "ANY_STRING")
+		a.Require().NoError(err)
+		a.Require().Equal("abc", anyString)
+
+		time.Sleep(time.Second)
+
+		anyString, err = awsSecretsGateway.GetString(context.Background(), "ANY_STRING")
+		a.Require().NoError(err)
+		a.Require().Equal("def", anyString)
+	})
+}
+
+func (a *AwsSecretsGatewaySuite) Test5() {
+	a.Run("given that rotation was started, when the secret changes, then picks up the new value", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		awsSecretsGateway := gateways.NewAwsSecretsGateway(a.secretsClient, time.Hour)
+		awsSecretsGateway.StartRotation(ctx, 200*time.Millisecond, func(err error) {
+			a.Fail(err.Error())
+		})
+
+		anyString, err := awsSecretsGateway.GetString(context.Background(), "ANY_STRING")
+		a.Require().NoError(err)
+		a.Require().Equal("abc", anyString)
+
+		a.putSecret(`{"ANY_STRING": "def"}`)
+
+		a.Require().Eventually(func() bool {
+			anyString, err := awsSecretsGateway.GetString(context.Background(), "ANY_STRING")
+			return err == nil && anyString == "def"
+		}, 5*time.Second, 100*time.Millisecond)
 	})
 }
 
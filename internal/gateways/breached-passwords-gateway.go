@@ -0,0 +1,76 @@
+package gateways
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// BreachedPasswordsGateway answers k-anonymity range queries over known breached passwords:
+// given the first 5 hex characters of a password's SHA-1, it returns the remaining 35 of every
+// breached hash starting with them, so the full hash never has to leave the caller.
+type BreachedPasswordsGateway interface {
+	FindHashSuffixes(ctx context.Context, hashPrefix string) ([]string, error)
+}
+
+// NewBreachedPasswordsGateway builds a gateway over the list at the BREACHED_PASSWORDS_FILE
+// configuration key. Without the key no password is considered breached.
+func NewBreachedPasswordsGateway(ctx context.Context, configGateway ConfigGateway) (BreachedPasswordsGateway, error) {
+	path, err := configGateway.GetString(ctx, "BREACHED_PASSWORDS_FILE")
+	if errors.Is(err, ErrConfigKeyNotFound) {
+		return &FileBreachedPasswordsGateway{map[string][]string{}}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFileBreachedPasswordsGateway(path)
+}
+
+// FileBreachedPasswordsGateway loads a local list in the Have I Been Pwned format, one
+// uppercase SHA-1 per line optionally followed by ":count", and keeps it in memory by prefix.
+type FileBreachedPasswordsGateway struct {
+	suffixesByPrefix map[string][]string
+}
+
+func NewFileBreachedPasswordsGateway(path string) (*FileBreachedPasswordsGateway, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	suffixesByPrefix := map[string][]string{}
+	scanner := bufio.NewScanner(file)
+
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		hash, _, _ := strings.Cut(line, ":")
+		if _, err := hex.DecodeString(hash); err != nil || len(hash) != 40 {
+			return nil, fmt.Errorf("%s:%d: expected a SHA-1 hash in hexadecimal", path, lineNumber)
+		}
+
+		hash = strings.ToUpper(hash)
+		suffixesByPrefix[hash[:5]] = append(suffixesByPrefix[hash[:5]], hash[5:])
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return &FileBreachedPasswordsGateway{suffixesByPrefix}, nil
+}
+
+func (f *FileBreachedPasswordsGateway) FindHashSuffixes(ctx context.Context, hashPrefix string) ([]string, error) {
+	return f.suffixesByPrefix[strings.ToUpper(hashPrefix)], nil
+}
@@ -0,0 +1,62 @@
+package gateways_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
+	"github.com/stretchr/testify/suite"
+)
+
+type BreachedPasswordsGatewaySuite struct {
+	suite.Suite
+}
+
+func (b *BreachedPasswordsGatewaySuite) Test1() {
+	b.Run("given a list file, when finding by prefix, then returns the suffixes of the hashes starting with it", func() {
+		path := filepath.Join(b.T().TempDir(), "breached-passwords.txt")
+		b.Require().NoError(os.WriteFile(path, []byte(
+			"7C4A8D09CA3762AF61E59520943DC26494F8941B:37359195\n"+
+				"7c4a8aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa\n"+
+				"\n"+
+				"5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8:9545824\n"), 0o600))
+
+		breachedPasswordsGateway, err := gateways.NewFileBreachedPasswordsGateway(path)
+		b.Require().NoError(err)
+
+		hashSuffixes, err := breachedPasswordsGateway.FindHashSuffixes(context.Background(), "7c4a8")
+		b.Require().NoError(err)
+		b.Require().Equal([]string{"D09CA3762AF61E59520943DC26494F8941B", "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"}, hashSuffixes)
+
+		hashSuffixes, err = breachedPasswordsGateway.FindHashSuffixes(context.Background(), "00000")
+		b.Require().NoError(err)
+		b.Require().Empty(hashSuffixes)
+	})
+}
+
+func (b *BreachedPasswordsGatewaySuite) Test2() {
+	b.Run("given a malformed line, when loading the list, then returns error", func() {
+		path := filepath.Join(b.T().TempDir(), "breached-passwords.txt")
+		b.Require().NoError(os.WriteFile(path, []byte("7C4A8D09CA3762AF61E59520943DC26494F8941B\npassword\n"), 0o600))
+
+		_, err := gateways.NewFileBreachedPasswordsGateway(path)
+		b.Require().ErrorContains(err, "breached-passwords.txt:2: expected a SHA-1 hash in hexadecimal")
+	})
+}
+
+func (b *BreachedPasswordsGatewaySuite) Test3() {
+	b.Run("given that no list was configured, when finding by prefix, then returns nothing", func() {
+		breachedPasswordsGateway, err := gateways.NewBreachedPasswordsGateway(context.Background(), gateways.NewEnvConfigGateway())
+		b.Require().NoError(err)
+
+		hashSuffixes, err := breachedPasswordsGateway.FindHashSuffixes(context.Background(), "7C4A8")
+		b.Require().NoError(err)
+		b.Require().Empty(hashSuffixes)
+	})
+}
+
+func TestBreachedPasswordsGateway(t *testing.T) {
+	suite.Run(t, new(BreachedPasswordsGatewaySuite))
+}
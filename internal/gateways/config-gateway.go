@@ -0,0 +1,133 @@
+package gateways
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+var (
+	ErrConfigKeyNotFound  = errors.New("configuration key not found")
+	ErrConfigKeyWrongType = errors.New("configuration key has the wrong type")
+)
+
+// ConfigGateway gives access to the application configuration without tying callers to where
+// it is stored.
+type ConfigGateway interface {
+	GetString(ctx context.Context, key string) (string, error)
+	GetInt(ctx context.Context, key string) (int, error)
+	GetBool(ctx context.Context, key string) (bool, error)
+}
+
+// MissingConfigKeysError lists every required key that is absent, so a misconfigured
+// deployment can be fixed in one go instead of one restart per key.
+type MissingConfigKeysError struct {
+	Keys []string
+}
+
+func (m *MissingConfigKeysError) Error() string {
+	return fmt.Sprintf("missing configuration keys: %s", strings.Join(m.Keys, ", "))
+}
+
+// NewConfigGateway builds the gateway selected by the CONFIG_PROVIDER environment variable:
+// "aws" (the default) reads the Secrets Manager secret named by AWS_SECRET_MANAGER_NAME, "env"
+// reads environment variables and "file" reads the JSON or YAML file at CONFIG_FILE.
+func NewConfigGateway(ctx context.Context, ttl time.Duration) (ConfigGateway, error) {
+	provider, ok := os.LookupEnv("CONFIG_PROVIDER")
+	if !ok {
+		provider = "aws"
+	}
+
+	switch provider {
+	case "aws":
+		defaultConfig, err := config.LoadDefaultConfig(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewAwsSecretsGateway(secretsmanager.NewFromConfig(defaultConfig), ttl), nil
+	case "env":
+		return NewEnvConfigGateway(), nil
+	case "file":
+		path, ok := os.LookupEnv("CONFIG_FILE")
+		if !ok {
+			return nil, errors.New("CONFIG_FILE environment variable not found")
+		}
+
+		return NewFileConfigGateway(path)
+	default:
+		return nil, fmt.Errorf("unknown CONFIG_PROVIDER %q, expected aws, env or file", provider)
+	}
+}
+
+// ValidateConfig checks that every key is present, reporting all missing keys at once.
+func ValidateConfig(ctx context.Context, configGateway ConfigGateway, keys []string) error {
+	missingKeys := []string{}
+
+	for _, key := range keys {
+		_, err := configGateway.GetString(ctx, key)
+
+		if errors.Is(err, ErrConfigKeyNotFound) {
+			missingKeys = append(missingKeys, key)
+			continue
+		}
+
+		if err != nil && !errors.Is(err, ErrConfigKeyWrongType) {
+			return err
+		}
+	}
+
+	if len(missingKeys) > 0 {
+		return &MissingConfigKeysError{missingKeys}
+	}
+
+	return nil
+}
+
+func configNotFound(key string) error {
+	return fmt.Errorf("%s: %w", key, ErrConfigKeyNotFound)
+}
+
+func configWrongType(key string, expected string) error {
+	return fmt.Errorf("%s must be %s: %w", key, expected, ErrConfigKeyWrongType)
+}
+
+// The helpers below convert values decoded from JSON or YAML. JSON numbers decode as float64
+// while YAML integers decode as int, so both are accepted as integers.
+
+func configString(key string, value any) (string, error) {
+	stringValue, ok := value.(string)
+	if !ok {
+		return "", configWrongType(key, "string")
+	}
+
+	return stringValue, nil
+}
+
+func configInt(key string, value any) (int, error) {
+	switch number := value.(type) {
+	case int:
+		return number, nil
+	case float64:
+		if number == float64(int(number)) {
+			return int(number), nil
+		}
+	}
+
+	return 0, configWrongType(key, "integer")
+}
+
+func configBool(key string, value any) (bool, error) {
+	boolValue, ok := value.(bool)
+	if !ok {
+		return false, configWrongType(key, "boolean")
+	}
+
+	return boolValue, nil
+}
@@ -0,0 +1,60 @@
+package gateways_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
+	"github.com/stretchr/testify/suite"
+)
+
+type ConfigGatewaySuite struct {
+	suite.Suite
+}
+
+func (c *ConfigGatewaySuite) Test1() {
+	c.Run("given that CONFIG_PROVIDER is env, when building, then returns the env gateway", func() {
+		c.T().Setenv("CONFIG_PROVIDER", "env")
+
+		configGateway, err := gateways.NewConfigGateway(context.Background(), time.Minute)
+		c.Require().NoError(err)
+		c.Require().IsType(gateways.EnvConfigGateway{}, configGateway)
+	})
+}
+
+func (c *ConfigGatewaySuite) Test2() {
+	c.Run("given that CONFIG_PROVIDER is unknown, when building, then returns error", func() {
+		c.T().Setenv("CONFIG_PROVIDER", "vault")
+
+		_, err := gateways.NewConfigGateway(context.Background(), time.Minute)
+		c.Require().EqualError(err, `unknown CONFIG_PROVIDER "vault", expected aws, env or file`)
+	})
+}
+
+func (c *ConfigGatewaySuite) Test3() {
+	c.Run("given that some required keys are missing, when validating, then reports all of them", func() {
+		c.T().Setenv("POSTGRES_URL", "postgres://localhost:5432/postgres")
+
+		err := gateways.ValidateConfig(context.Background(), gateways.NewEnvConfigGateway(),
+			[]string{"ANY_MISSING_KEY", "POSTGRES_URL", "ANOTHER_MISSING_KEY"})
+
+		var missingConfigKeysError *gateways.MissingConfigKeysError
+		c.Require().ErrorAs(err, &missingConfigKeysError)
+		c.Require().Equal([]string{"ANY_MISSING_KEY", "ANOTHER_MISSING_KEY"}, missingConfigKeysError.Keys)
+		c.Require().EqualError(err, "missing configuration keys: ANY_MISSING_KEY, ANOTHER_MISSING_KEY")
+	})
+}
+
+func (c *ConfigGatewaySuite) Test4() {
+	c.Run("given that every required key is present, when validating, then returns no error", func() {
+		c.T().Setenv("POSTGRES_URL", "postgres://localhost:5432/postgres")
+
+		err := gateways.ValidateConfig(context.Background(), gateways.NewEnvConfigGateway(), []string{"POSTGRES_URL"})
+		c.Require().NoError(err)
+	})
+}
+
+func TestConfigGateway(t *testing.T) {
+	suite.Run(t, new(ConfigGatewaySuite))
+}
@@ -0,0 +1,52 @@
+package gateways
+
+import (
+	"context"
+	"os"
+	"strconv"
+)
+
+// EnvConfigGateway reads the configuration from environment variables, which is the simplest
+// way to run the API locally without AWS.
+type EnvConfigGateway struct{}
+
+func NewEnvConfigGateway() EnvConfigGateway {
+	return EnvConfigGateway{}
+}
+
+func (e EnvConfigGateway) GetString(ctx context.Context, key string) (string, error) {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return "", configNotFound(key)
+	}
+
+	return value, nil
+}
+
+func (e EnvConfigGateway) GetInt(ctx context.Context, key string) (int, error) {
+	value, err := e.GetString(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+
+	intValue, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, configWrongType(key, "integer")
+	}
+
+	return intValue, nil
+}
+
+func (e EnvConfigGateway) GetBool(ctx context.Context, key string) (bool, error) {
+	value, err := e.GetString(ctx, key)
+	if err != nil {
+		return false, err
+	}
+
+	boolValue, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, configWrongType(key, "boolean")
+	}
+
+	return boolValue, nil
+}
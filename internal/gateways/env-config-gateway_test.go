@@ -0,0 +1,61 @@
+package gateways_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
+	"github.com/stretchr/testify/suite"
+)
+
+type EnvConfigGatewaySuite struct {
+	suite.Suite
+	envConfigGateway gateways.EnvConfigGateway
+}
+
+func (e *EnvConfigGatewaySuite) SetupTest() {
+	e.envConfigGateway = gateways.NewEnvConfigGateway()
+}
+
+func (e *EnvConfigGatewaySuite) Test1() {
+	e.Run("given that the variables were set, when getting, then returns the typed value", func() {
+		e.T().Setenv("ANY_INT", "1")
+		e.T().Setenv("ANY_STRING", "abc")
+		e.T().Setenv("ANY_BOOLEAN", "true")
+
+		anyInt, err := e.envConfigGateway.GetInt(context.Background(), "ANY_INT")
+		e.Require().NoError(err)
+		e.Require().Equal(1, anyInt)
+
+		anyString, err := e.envConfigGateway.GetString(context.Background(), "ANY_STRING")
+		e.Require().NoError(err)
+		e.Require().Equal("abc", anyString)
+
+		anyBoolean, err := e.envConfigGateway.GetBool(context.Background(), "ANY_BOOLEAN")
+		e.Require().NoError(err)
+		e.Require().Equal(true, anyBoolean)
+	})
+}
+
+func (e *EnvConfigGatewaySuite) Test2() {
+	e.Run("given that the variable was not set, when getting, then returns error", func() {
+		_, err := e.envConfigGateway.GetString(context.Background(), "ANY_MISSING_KEY")
+		e.Require().ErrorIs(err, gateways.ErrConfigKeyNotFound)
+	})
+}
+
+func (e *EnvConfigGatewaySuite) Test3() {
+	e.Run("given that the variable cannot be parsed, when getting, then returns error", func() {
+		e.T().Setenv("ANY_STRING", "abc")
+
+		_, err := e.envConfigGateway.GetInt(context.Background(), "ANY_STRING")
+		e.Require().ErrorIs(err, gateways.ErrConfigKeyWrongType)
+
+		_, err = e.envConfigGateway.GetBool(context.Background(), "ANY_STRING")
+		e.Require().ErrorIs(err, gateways.ErrConfigKeyWrongType)
+	})
+}
+
+func TestEnvConfigGateway(t *testing.T) {
+	suite.Run(t, new(EnvConfigGatewaySuite))
+}
@@ -0,0 +1,69 @@
+package gateways
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileConfigGateway reads the configuration from a flat JSON or YAML document, chosen by the
+// file extension. The file is read once when the gateway is built.
+type FileConfigGateway struct {
+	values map[string]any
+}
+
+func NewFileConfigGateway(path string) (FileConfigGateway, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return FileConfigGateway{}, err
+	}
+
+	values := map[string]any{}
+
+	switch strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".") {
+	case "json":
+		err = json.Unmarshal(data, &values)
+	case "yaml", "yml":
+		err = yaml.Unmarshal(data, &values)
+	default:
+		return FileConfigGateway{}, fmt.Errorf("config file %s must be .json, .yaml or .yml", path)
+	}
+
+	if err != nil {
+		return FileConfigGateway{}, fmt.Errorf("config file %s is invalid: %w", path, err)
+	}
+
+	return FileConfigGateway{values}, nil
+}
+
+func (f FileConfigGateway) GetString(ctx context.Context, key string) (string, error) {
+	value, ok := f.values[key]
+	if !ok {
+		return "", configNotFound(key)
+	}
+
+	return configString(key, value)
+}
+
+func (f FileConfigGateway) GetInt(ctx context.Context, key string) (int, error) {
+	value, ok := f.values[key]
+	if !ok {
+		return 0, configNotFound(key)
+	}
+
+	return configInt(key, value)
+}
+
+func (f FileConfigGateway) GetBool(ctx context.Context, key string) (bool, error) {
+	value, ok := f.values[key]
+	if !ok {
+		return false, configNotFound(key)
+	}
+
+	return configBool(key, value)
+}
@@ -0,0 +1,98 @@
+package gateways_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
+	"github.com/stretchr/testify/suite"
+)
+
+type FileConfigGatewaySuite struct {
+	suite.Suite
+}
+
+func (f *FileConfigGatewaySuite) writeConfigFile(name string, content string) string {
+	path := filepath.Join(f.T().TempDir(), name)
+	f.Require().NoError(os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func (f *FileConfigGatewaySuite) Test1() {
+	f.Run("given a json file, when getting, then returns the typed value", func() {
+		path := f.writeConfigFile("config.json", `
+			{
+				"ANY_INT": 1,
+				"ANY_STRING": "abc",
+				"ANY_BOOLEAN": true
+			}
+		`)
+
+		fileConfigGateway, err := gateways.NewFileConfigGateway(path)
+		f.Require().NoError(err)
+
+		anyInt, err := fileConfigGateway.GetInt(context.Background(), "ANY_INT")
+		f.Require().NoError(err)
+		f.Require().Equal(1, anyInt)
+
+		anyString, err := fileConfigGateway.GetString(context.Background(), "ANY_STRING")
+		f.Require().NoError(err)
+		f.Require().Equal("abc", anyString)
+
+		anyBoolean, err := fileConfigGateway.GetBool(context.Background(), "ANY_BOOLEAN")
+		f.Require().NoError(err)
+		f.Require().Equal(true, anyBoolean)
+	})
+}
+
+func (f *FileConfigGatewaySuite) Test2() {
+	f.Run("given a yaml file, when getting, then returns the typed value", func() {
+		path := f.writeConfigFile("config.yaml", "ANY_INT: 1\nANY_STRING: abc\nANY_BOOLEAN: true\n")
+
+		fileConfigGateway, err := gateways.NewFileConfigGateway(path)
+		f.Require().NoError(err)
+
+		anyInt, err := fileConfigGateway.GetInt(context.Background(), "ANY_INT")
+		f.Require().NoError(err)
+		f.Require().Equal(1, anyInt)
+
+		anyString, err := fileConfigGateway.GetString(context.Background(), "ANY_STRING")
+		f.Require().NoError(err)
+		f.Require().Equal("abc", anyString)
+
+		anyBoolean, err := fileConfigGateway.GetBool(context.Background(), "ANY_BOOLEAN")
+		f.Require().NoError(err)
+		f.Require().Equal(true, anyBoolean)
+	})
+}
+
+func (f *FileConfigGatewaySuite) Test3() {
+	f.Run("given a file without the key or with another type, when getting, then returns error", func() {
+		path := f.writeConfigFile("config.json", `{"ANY_INT": 1.5}`)
+
+		fileConfigGateway, err := gateways.NewFileConfigGateway(path)
+		f.Require().NoError(err)
+
+		_, err = fileConfigGateway.GetString(context.Background(), "ANY_MISSING_KEY")
+		f.Require().ErrorIs(err, gateways.ErrConfigKeyNotFound)
+
+		_, err = fileConfigGateway.GetInt(context.Background(), "ANY_INT")
+		f.Require().ErrorIs(err, gateways.ErrConfigKeyWrongType)
+	})
+}
+
+func (f *FileConfigGatewaySuite) Test4() {
+	f.Run("given a file with an unsupported extension or invalid content, when building, then returns error", func() {
+		_, err := gateways.NewFileConfigGateway(f.writeConfigFile("config.toml", `ANY_INT = 1`))
+		f.Require().ErrorContains(err, "must be .json, .yaml or .yml")
+
+		_, err = gateways.NewFileConfigGateway(f.writeConfigFile("config.json", `{`))
+		f.Require().ErrorContains(err, "is invalid")
+	})
+}
+
+func TestFileConfigGateway(t *testing.T) {
+	suite.Run(t, new(FileConfigGatewaySuite))
+}
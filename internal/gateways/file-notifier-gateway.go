@@ -0,0 +1,40 @@
+package gateways
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// FileNotifierGateway appends every notification to a file as one JSON document per line.
+type FileNotifierGateway struct {
+	path  string
+	mutex sync.Mutex
+}
+
+func NewFileNotifierGateway(path string) *FileNotifierGateway {
+	return &FileNotifierGateway{path: path}
+}
+
+func (f *FileNotifierGateway) Send(ctx context.Context, notification Notification) error {
+	line, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,58 @@
+package gateways_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
+	"github.com/stretchr/testify/suite"
+)
+
+type FileNotifierGatewaySuite struct {
+	suite.Suite
+}
+
+func (f *FileNotifierGatewaySuite) Test1() {
+	f.Run("when sending notifications, then appends one json line per notification", func() {
+		path := filepath.Join(f.T().TempDir(), "notifications.jsonl")
+		fileNotifierGateway := gateways.NewFileNotifierGateway(path)
+
+		f.Require().NoError(fileNotifierGateway.Send(context.Background(), gateways.Notification{
+			To:      "john.doe@gmail.com",
+			Subject: "Subject 1",
+			Body:    "Body 1",
+		}))
+		f.Require().NoError(fileNotifierGateway.Send(context.Background(), gateways.Notification{
+			To:      "richard.smith@gmail.com",
+			Subject: "Subject 2",
+			Body:    "Body\n2",
+		}))
+
+		content, err := os.ReadFile(path)
+		f.Require().NoError(err)
+		f.Require().Equal(`{"to":"john.doe@gmail.com","subject":"Subject 1","body":"Body 1"}`+"\n"+
+			`{"to":"richard.smith@gmail.com","subject":"Subject 2","body":"Body\n2"}`+"\n", string(content))
+	})
+}
+
+func (f *FileNotifierGatewaySuite) Test2() {
+	f.Run("given the provider configuration, when building the notifier, then returns the matching gateway", func() {
+		f.T().Setenv("NOTIFIER_PROVIDER", "file")
+		f.T().Setenv("NOTIFIER_FILE", filepath.Join(f.T().TempDir(), "notifications.jsonl"))
+
+		notifierGateway, err := gateways.NewNotifierGateway(context.Background(), gateways.NewEnvConfigGateway(), nil)
+		f.Require().NoError(err)
+		f.Require().IsType(&gateways.FileNotifierGateway{}, notifierGateway)
+
+		f.T().Setenv("NOTIFIER_PROVIDER", "pigeon")
+
+		_, err = gateways.NewNotifierGateway(context.Background(), gateways.NewEnvConfigGateway(), nil)
+		f.Require().ErrorContains(err, `unknown NOTIFIER_PROVIDER "pigeon", expected smtp, log or file`)
+	})
+}
+
+func TestFileNotifierGateway(t *testing.T) {
+	suite.Run(t, new(FileNotifierGatewaySuite))
+}
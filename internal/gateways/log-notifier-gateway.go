@@ -0,0 +1,26 @@
+package gateways
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LogNotifierGateway writes notifications to the logger instead of delivering them. Their
+// bodies may carry secrets such as reset tokens, so it must not be used in production.
+type LogNotifierGateway struct {
+	logger *slog.Logger
+}
+
+func NewLogNotifierGateway(logger *slog.Logger) *LogNotifierGateway {
+	return &LogNotifierGateway{logger}
+}
+
+func (l *LogNotifierGateway) Send(ctx context.Context, notification Notification) error {
+	l.logger.LogAttrs(ctx, slog.LevelInfo, "notification",
+		slog.String("to", notification.To),
+		slog.String("subject", notification.Subject),
+		slog.String("body", notification.Body),
+	)
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package gateways
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// Notification is a message for a customer, addressed by email.
+type Notification struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// NotifierGateway delivers notifications to customers without tying callers to the channel.
+type NotifierGateway interface {
+	Send(ctx context.Context, notification Notification) error
+}
+
+// NewNotifierGateway builds the notifier selected by the NOTIFIER_PROVIDER configuration key:
+// "smtp" emails notifications through the server at SMTP_HOST and SMTP_PORT, from SMTP_FROM,
+// authenticating with SMTP_USERNAME and SMTP_PASSWORD when set. "log" (the default) writes
+// them to the logger and "file" appends them to the file at NOTIFIER_FILE, both stand-ins for
+// local development and tests.
+func NewNotifierGateway(ctx context.Context, configGateway ConfigGateway, logger *slog.Logger) (NotifierGateway, error) {
+	provider, err := configGateway.GetString(ctx, "NOTIFIER_PROVIDER")
+	if errors.Is(err, ErrConfigKeyNotFound) {
+		provider = "log"
+	} else if err != nil {
+		return nil, err
+	}
+
+	switch provider {
+	case "log":
+		return NewLogNotifierGateway(logger), nil
+	case "file":
+		path, err := configGateway.GetString(ctx, "NOTIFIER_FILE")
+		if err != nil {
+			return nil, err
+		}
+
+		return NewFileNotifierGateway(path), nil
+	case "smtp":
+		return newSmtpNotifierGateway(ctx, configGateway)
+	default:
+		return nil, fmt.Errorf("unknown NOTIFIER_PROVIDER %q, expected smtp, log or file", provider)
+	}
+}
+
+func newSmtpNotifierGateway(ctx context.Context, configGateway ConfigGateway) (*SmtpNotifierGateway, error) {
+	host, err := configGateway.GetString(ctx, "SMTP_HOST")
+	if err != nil {
+		return nil, err
+	}
+
+	port, err := configGateway.GetInt(ctx, "SMTP_PORT")
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := configGateway.GetString(ctx, "SMTP_FROM")
+	if err != nil {
+		return nil, err
+	}
+
+	username, err := configGateway.GetString(ctx, "SMTP_USERNAME")
+	if err != nil && !errors.Is(err, ErrConfigKeyNotFound) {
+		return nil, err
+	}
+
+	password, err := configGateway.GetString(ctx, "SMTP_PASSWORD")
+	if err != nil && !errors.Is(err, ErrConfigKeyNotFound) {
+		return nil, err
+	}
+
+	return NewSmtpNotifierGateway(host, port, from, username, password), nil
+}
@@ -0,0 +1,83 @@
+package gateways
+
+import (
+	"context"
+	"errors"
+)
+
+// PasswordPolicy holds the rules new passwords must follow.
+type PasswordPolicy struct {
+	MinLength          int
+	RequireUppercase   bool
+	RequireLowercase   bool
+	RequireDigit       bool
+	RequireSymbol      bool
+	RejectPersonalInfo bool
+}
+
+// PasswordPolicyGateway reads the password policy from the configuration. Every key is
+// optional and falls back to its default:
+//
+//   - PASSWORD_MIN_LENGTH, 8.
+//   - PASSWORD_REQUIRE_UPPERCASE, PASSWORD_REQUIRE_LOWERCASE and PASSWORD_REQUIRE_DIGIT, true.
+//   - PASSWORD_REQUIRE_SYMBOL, false.
+//   - PASSWORD_REJECT_PERSONAL_INFO, true, rejects passwords containing the name or email.
+type PasswordPolicyGateway struct {
+	configGateway ConfigGateway
+}
+
+func NewPasswordPolicyGateway(configGateway ConfigGateway) PasswordPolicyGateway {
+	return PasswordPolicyGateway{configGateway}
+}
+
+func (p *PasswordPolicyGateway) Policy(ctx context.Context) (PasswordPolicy, error) {
+	minLength, err := p.configGateway.GetInt(ctx, "PASSWORD_MIN_LENGTH")
+	if errors.Is(err, ErrConfigKeyNotFound) {
+		minLength = 8
+	} else if err != nil {
+		return PasswordPolicy{}, err
+	}
+
+	requireUppercase, err := p.getBool(ctx, "PASSWORD_REQUIRE_UPPERCASE", true)
+	if err != nil {
+		return PasswordPolicy{}, err
+	}
+
+	requireLowercase, err := p.getBool(ctx, "PASSWORD_REQUIRE_LOWERCASE", true)
+	if err != nil {
+		return PasswordPolicy{}, err
+	}
+
+	requireDigit, err := p.getBool(ctx, "PASSWORD_REQUIRE_DIGIT", true)
+	if err != nil {
+		return PasswordPolicy{}, err
+	}
+
+	requireSymbol, err := p.getBool(ctx, "PASSWORD_REQUIRE_SYMBOL", false)
+	if err != nil {
+		return PasswordPolicy{}, err
+	}
+
+	rejectPersonalInfo, err := p.getBool(ctx, "PASSWORD_REJECT_PERSONAL_INFO", true)
+	if err != nil {
+		return PasswordPolicy{}, err
+	}
+
+	return PasswordPolicy{
+		MinLength:          minLength,
+		RequireUppercase:   requireUppercase,
+		RequireLowercase:   requireLowercase,
+		RequireDigit:       requireDigit,
+		RequireSymbol:      requireSymbol,
+		RejectPersonalInfo: rejectPersonalInfo,
+	}, nil
+}
+
+func (p *PasswordPolicyGateway) getBool(ctx context.Context, key string, fallback bool) (bool, error) {
+	value, err := p.configGateway.GetBool(ctx, key)
+	if errors.Is(err, ErrConfigKeyNotFound) {
+		return fallback, nil
+	}
+
+	return value, err
+}
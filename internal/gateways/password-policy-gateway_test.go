@@ -0,0 +1,68 @@
+package gateways_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
+	"github.com/stretchr/testify/suite"
+)
+
+type PasswordPolicyGatewaySuite struct {
+	suite.Suite
+	passwordPolicyGateway gateways.PasswordPolicyGateway
+}
+
+func (p *PasswordPolicyGatewaySuite) SetupTest() {
+	p.passwordPolicyGateway = gateways.NewPasswordPolicyGateway(gateways.NewEnvConfigGateway())
+}
+
+func (p *PasswordPolicyGatewaySuite) Test1() {
+	p.Run("given that no key was set, when getting the policy, then returns the defaults", func() {
+		passwordPolicy, err := p.passwordPolicyGateway.Policy(context.Background())
+		p.Require().NoError(err)
+		p.Require().Equal(gateways.PasswordPolicy{
+			MinLength:          8,
+			RequireUppercase:   true,
+			RequireLowercase:   true,
+			RequireDigit:       true,
+			RequireSymbol:      false,
+			RejectPersonalInfo: true,
+		}, passwordPolicy)
+	})
+}
+
+func (p *PasswordPolicyGatewaySuite) Test2() {
+	p.Run("given that the keys were set, when getting the policy, then returns them", func() {
+		p.T().Setenv("PASSWORD_MIN_LENGTH", "12")
+		p.T().Setenv("PASSWORD_REQUIRE_UPPERCASE", "false")
+		p.T().Setenv("PASSWORD_REQUIRE_LOWERCASE", "false")
+		p.T().Setenv("PASSWORD_REQUIRE_DIGIT", "false")
+		p.T().Setenv("PASSWORD_REQUIRE_SYMBOL", "true")
+		p.T().Setenv("PASSWORD_REJECT_PERSONAL_INFO", "false")
+
+		passwordPolicy, err := p.passwordPolicyGateway.Policy(context.Background())
+		p.Require().NoError(err)
+		p.Require().Equal(gateways.PasswordPolicy{
+			MinLength:          12,
+			RequireUppercase:   false,
+			RequireLowercase:   false,
+			RequireDigit:       false,
+			RequireSymbol:      true,
+			RejectPersonalInfo: false,
+		}, passwordPolicy)
+	})
+}
+
+func (p *PasswordPolicyGatewaySuite) Test3() {
+	p.Run("given that a key has the wrong type, when getting the policy, then returns error", func() {
+		p.T().Setenv("PASSWORD_REQUIRE_SYMBOL", "sometimes")
+
+		_, err := p.passwordPolicyGateway.Policy(context.Background())
+		p.Require().ErrorIs(err, gateways.ErrConfigKeyWrongType)
+	})
+}
+
+func TestPasswordPolicyGateway(t *testing.T) {
+	suite.Run(t, new(PasswordPolicyGatewaySuite))
+}
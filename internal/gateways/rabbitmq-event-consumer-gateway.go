@@ -0,0 +1,161 @@
+package gateways
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// consumerRequeueDelay keeps a message that cannot be handled, say while the database is down,
+// from being redelivered in a tight loop.
+const consumerRequeueDelay = time.Second
+
+// eventAttemptHeader counts the attempts to handle a message requeued by the consumer, which
+// RabbitMQ does not keep for classic queues.
+const eventAttemptHeader = "x-attempt"
+
+// EventHandler handles a consumed event. The event is acknowledged when it returns nil and
+// requeued otherwise, at the back of the queue with Event.Attempt incremented, so handlers can
+// give up on events that keep failing. Errors that retrying cannot fix must not be returned.
+type EventHandler func(ctx context.Context, event Event) error
+
+// RabbitmqEventConsumerGateway consumes events published to EventsExchange from a durable
+// queue, which makes it a work queue shared by every consumer of the same name.
+type RabbitmqEventConsumerGateway struct {
+	url      string
+	prefetch int
+}
+
+func NewRabbitmqEventConsumerGateway(url string, prefetch int) *RabbitmqEventConsumerGateway {
+	return &RabbitmqEventConsumerGateway{url, prefetch}
+}
+
+// Consume declares the queue and hands its messages to handler, one at a time, until ctx is
+// cancelled, when it returns nil, or the connection is lost.
+func (r *RabbitmqEventConsumerGateway) Consume(ctx context.Context, queue EventQueue, handler EventHandler) error {
+	conn, err := amqp091.Dial(r.url)
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	channel, err := conn.Channel()
+	if err != nil {
+		return err
+	}
+
+	defer channel.Close()
+
+	if err := channel.Qos(r.prefetch, 0, false); err != nil {
+		return err
+	}
+
+	if err := channel.Confirm(false); err != nil {
+		return err
+	}
+
+	if err := channel.ExchangeDeclare(EventsExchange, "topic", true, false, false, false, nil); err != nil {
+		return err
+	}
+
+	if err := declareEventQueue(channel, queue); err != nil {
+		return err
+	}
+
+	deliveries, err := channel.ConsumeWithContext(ctx, queue.Name, "", false, false, false, false, nil)
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case delivery, ok := <-deliveries:
+			if !ok {
+				if ctx.Err() != nil {
+					return nil
+				}
+
+				return errors.New("consumer channel was closed")
+			}
+
+			if err := r.handle(ctx, channel, queue, delivery, handler); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (r *RabbitmqEventConsumerGateway) handle(ctx context.Context, channel *amqp091.Channel, queue EventQueue, delivery amqp091.Delivery,
+	handler EventHandler) error {
+	eventId, _ := uuid.Parse(delivery.MessageId)
+
+	// Requeued messages are published straight to the queue, so only Type still tells the event
+	// type apart; the routing key is kept as a fallback for messages published without it.
+	eventType := delivery.Type
+	if eventType == "" {
+		eventType = delivery.RoutingKey
+	}
+
+	attempt := deliveryAttempt(delivery)
+
+	err := handler(ctx, Event{
+		Id:         eventId,
+		Type:       eventType,
+		Payload:    delivery.Body,
+		OccurredAt: delivery.Timestamp,
+		Attempt:    attempt,
+	})
+	if err == nil {
+		return delivery.Ack(false)
+	}
+
+	select {
+	case <-ctx.Done():
+		return delivery.Nack(false, true)
+	case <-time.After(consumerRequeueDelay):
+	}
+
+	// The copy is confirmed before the original is acknowledged, so a failure in between can
+	// only deliver the event twice, which handlers must cope with anyway.
+	confirmation, err := channel.PublishWithDeferredConfirmWithContext(ctx, "", queue.Name, false, false, amqp091.Publishing{
+		MessageId:    delivery.MessageId,
+		Type:         eventType,
+		ContentType:  delivery.ContentType,
+		DeliveryMode: amqp091.Persistent,
+		Timestamp:    delivery.Timestamp,
+		Headers:      amqp091.Table{eventAttemptHeader: int32(attempt + 1)},
+		Body:         delivery.Body,
+	})
+	if err != nil {
+		return err
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !acked {
+		return fmt.Errorf("broker did not confirm requeued event %s", delivery.MessageId)
+	}
+
+	return delivery.Ack(false)
+}
+
+func deliveryAttempt(delivery amqp091.Delivery) int {
+	switch attempt := delivery.Headers[eventAttemptHeader].(type) {
+	case int32:
+		return int(attempt)
+	case int64:
+		return int(attempt)
+	default:
+		return 1
+	}
+}
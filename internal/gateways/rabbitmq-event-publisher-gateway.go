@@ -0,0 +1,179 @@
+package gateways
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// EventsExchange is the durable topic exchange domain events are published to, with the event
+// type as routing key.
+const EventsExchange = "pay-bank.events"
+
+// EventQueue is a durable queue bound to EventsExchange for the given event types. Publishers
+// declare it as well as consumers: the exchange drops what no queue is bound to, so an event
+// published before its consumer ever started would otherwise be lost.
+type EventQueue struct {
+	Name       string
+	EventTypes []string
+}
+
+// Event is a domain event as published. Id is stable across retries, so consumers can drop the
+// duplicates at-least-once delivery implies.
+type Event struct {
+	Id         uuid.UUID
+	Type       string
+	Payload    []byte
+	OccurredAt time.Time
+	// Attempt is only set on consumed events: 1 on the first delivery, and one more every time
+	// a handler failed to handle the event.
+	Attempt int
+}
+
+// EventPublisherGateway publishes domain events to a message broker.
+type EventPublisherGateway interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// RabbitmqEventPublisherGateway publishes to EventsExchange on a channel in confirm mode:
+// Publish only returns once the broker has taken responsibility for the message. The
+// connection is opened on first use and reopened after any failure, declaring queues each time.
+type RabbitmqEventPublisherGateway struct {
+	url     string
+	queues  []EventQueue
+	conn    *amqp091.Connection
+	channel *amqp091.Channel
+	mutex   sync.Mutex
+}
+
+func NewRabbitmqEventPublisherGateway(url string, queues []EventQueue) *RabbitmqEventPublisherGateway {
+	return &RabbitmqEventPublisherGateway{url: url, queues: queues}
+}
+
+func (r *RabbitmqEventPublisherGateway) Publish(ctx context.Context, event Event) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.publish(ctx, event); err != nil {
+		r.closeChannel()
+		return err
+	}
+
+	return nil
+}
+
+func (r *RabbitmqEventPublisherGateway) publish(ctx context.Context, event Event) error {
+	channel, err := r.openChannel()
+	if err != nil {
+		return err
+	}
+
+	confirmation, err := channel.PublishWithDeferredConfirmWithContext(ctx, EventsExchange, event.Type, false, false, amqp091.Publishing{
+		MessageId:    event.Id.String(),
+		Type:         event.Type,
+		ContentType:  "application/json",
+		DeliveryMode: amqp091.Persistent,
+		Timestamp:    event.OccurredAt,
+		Body:         event.Payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !acked {
+		return fmt.Errorf("broker did not confirm event %s", event.Id)
+	}
+
+	return nil
+}
+
+func (r *RabbitmqEventPublisherGateway) openChannel() (*amqp091.Channel, error) {
+	if r.channel != nil && !r.channel.IsClosed() {
+		return r.channel, nil
+	}
+
+	if r.conn == nil || r.conn.IsClosed() {
+		conn, err := amqp091.Dial(r.url)
+		if err != nil {
+			return nil, err
+		}
+
+		r.conn = conn
+	}
+
+	channel, err := r.conn.Channel()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := channel.Confirm(false); err != nil {
+		_ = channel.Close()
+		return nil, err
+	}
+
+	if err := channel.ExchangeDeclare(EventsExchange, "topic", true, false, false, false, nil); err != nil {
+		_ = channel.Close()
+		return nil, err
+	}
+
+	for _, queue := range r.queues {
+		if err := declareEventQueue(channel, queue); err != nil {
+			_ = channel.Close()
+			return nil, err
+		}
+	}
+
+	r.channel = channel
+
+	return channel, nil
+}
+
+func declareEventQueue(channel *amqp091.Channel, queue EventQueue) error {
+	if _, err := channel.QueueDeclare(queue.Name, true, false, false, false, nil); err != nil {
+		return err
+	}
+
+	for _, eventType := range queue.EventTypes {
+		if err := channel.QueueBind(queue.Name, eventType, EventsExchange, false, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *RabbitmqEventPublisherGateway) closeChannel() {
+	if r.channel != nil {
+		_ = r.channel.Close()
+		r.channel = nil
+	}
+}
+
+// Close releases the connection to the broker.
+func (r *RabbitmqEventPublisherGateway) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.closeChannel()
+
+	if r.conn == nil || r.conn.IsClosed() {
+		return nil
+	}
+
+	err := r.conn.Close()
+	if errors.Is(err, amqp091.ErrClosed) {
+		return nil
+	}
+
+	return err
+}
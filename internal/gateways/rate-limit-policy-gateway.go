@@ -0,0 +1,66 @@
+package gateways
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// RateLimitPolicy allows Limit requests per subject in any Window. A Limit of 0 turns the
+// policy off.
+type RateLimitPolicy struct {
+	Name   string
+	Limit  int
+	Window time.Duration
+}
+
+// The policies below are the defaults, used when their keys are not configured.
+var (
+	RateLimitPolicyLogin                   = RateLimitPolicy{"login", 20, time.Minute}
+	RateLimitPolicyLoginTotp               = RateLimitPolicy{"login_totp", 10, time.Minute}
+	RateLimitPolicyDisableTotp             = RateLimitPolicy{"disable_totp", 5, time.Hour}
+	RateLimitPolicySignUp                  = RateLimitPolicy{"sign_up", 10, time.Hour}
+	RateLimitPolicyForgotPassword          = RateLimitPolicy{"forgot_password", 10, time.Hour}
+	RateLimitPolicyResendEmailVerification = RateLimitPolicy{"resend_email_verification", 10, time.Hour}
+	RateLimitPolicyTransfer                = RateLimitPolicy{"transfer", 60, time.Minute}
+)
+
+// RateLimitPolicyGateway reads rate limit policies from the configuration, so they can be tuned
+// without a deploy. Both keys of a policy are optional, RATE_LIMIT_<NAME>_LIMIT and
+// RATE_LIMIT_<NAME>_WINDOW_SECONDS, such as RATE_LIMIT_SIGN_UP_LIMIT.
+type RateLimitPolicyGateway struct {
+	configGateway ConfigGateway
+}
+
+func NewRateLimitPolicyGateway(configGateway ConfigGateway) RateLimitPolicyGateway {
+	return RateLimitPolicyGateway{configGateway}
+}
+
+func (r *RateLimitPolicyGateway) Policy(ctx context.Context, fallback RateLimitPolicy) (RateLimitPolicy, error) {
+	prefix := "RATE_LIMIT_" + strings.ToUpper(fallback.Name)
+
+	limit, err := r.configGateway.GetInt(ctx, prefix+"_LIMIT")
+	if errors.Is(err, ErrConfigKeyNotFound) {
+		limit = fallback.Limit
+	} else if err != nil {
+		return RateLimitPolicy{}, err
+	}
+
+	windowSeconds, err := r.configGateway.GetInt(ctx, prefix+"_WINDOW_SECONDS")
+	if errors.Is(err, ErrConfigKeyNotFound) {
+		windowSeconds = int(fallback.Window.Seconds())
+	} else if err != nil {
+		return RateLimitPolicy{}, err
+	}
+
+	if limit < 0 {
+		return RateLimitPolicy{}, configWrongType(prefix+"_LIMIT", "zero or positive")
+	}
+
+	if windowSeconds <= 0 {
+		return RateLimitPolicy{}, configWrongType(prefix+"_WINDOW_SECONDS", "positive")
+	}
+
+	return RateLimitPolicy{fallback.Name, limit, time.Duration(windowSeconds) * time.Second}, nil
+}
@@ -0,0 +1,73 @@
+package gateways_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
+	"github.com/stretchr/testify/suite"
+)
+
+type RateLimitPolicyGatewaySuite struct {
+	suite.Suite
+	rateLimitPolicyGateway gateways.RateLimitPolicyGateway
+}
+
+func (r *RateLimitPolicyGatewaySuite) SetupTest() {
+	r.rateLimitPolicyGateway = gateways.NewRateLimitPolicyGateway(gateways.NewEnvConfigGateway())
+}
+
+func (r *RateLimitPolicyGatewaySuite) Test1() {
+	r.Run("given that no key was set, when getting a policy, then returns the default", func() {
+		rateLimitPolicy, err := r.rateLimitPolicyGateway.Policy(context.Background(), gateways.RateLimitPolicySignUp)
+		r.Require().NoError(err)
+		r.Require().Equal(gateways.RateLimitPolicySignUp, rateLimitPolicy)
+	})
+}
+
+func (r *RateLimitPolicyGatewaySuite) Test2() {
+	r.Run("given that the keys were set, when getting a policy, then returns them", func() {
+		r.T().Setenv("RATE_LIMIT_SIGN_UP_LIMIT", "3")
+		r.T().Setenv("RATE_LIMIT_SIGN_UP_WINDOW_SECONDS", "30")
+
+		rateLimitPolicy, err := r.rateLimitPolicyGateway.Policy(context.Background(), gateways.RateLimitPolicySignUp)
+		r.Require().NoError(err)
+		r.Require().Equal(gateways.RateLimitPolicy{
+			Name:   "sign_up",
+			Limit:  3,
+			Window: 30 * time.Second,
+		}, rateLimitPolicy)
+
+		rateLimitPolicy, err = r.rateLimitPolicyGateway.Policy(context.Background(), gateways.RateLimitPolicyLogin)
+		r.Require().NoError(err)
+		r.Require().Equal(gateways.RateLimitPolicyLogin, rateLimitPolicy)
+	})
+}
+
+func (r *RateLimitPolicyGatewaySuite) Test3() {
+	r.Run("given that a key is invalid, when getting a policy, then returns error", func() {
+		templates := []map[string]string{
+			{"RATE_LIMIT_TRANSFER_LIMIT": "many"},
+			{"RATE_LIMIT_TRANSFER_LIMIT": "-1"},
+			{"RATE_LIMIT_TRANSFER_WINDOW_SECONDS": "0"},
+		}
+
+		for _, template := range templates {
+			for key, value := range template {
+				r.T().Setenv(key, value)
+			}
+
+			_, err := r.rateLimitPolicyGateway.Policy(context.Background(), gateways.RateLimitPolicyTransfer)
+			r.Require().ErrorIs(err, gateways.ErrConfigKeyWrongType)
+
+			for key := range template {
+				r.T().Setenv(key, "1")
+			}
+		}
+	})
+}
+
+func TestRateLimitPolicyGateway(t *testing.T) {
+	suite.Run(t, new(RateLimitPolicyGatewaySuite))
+}
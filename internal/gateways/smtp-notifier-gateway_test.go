@@ -0,0 +1,112 @@
+package gateways_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
+	testhelpers "github.com/gsaaraujo/pay-bank-api/internal/test_helpers"
+	"github.com/gsaaraujo/pay-bank-api/internal/utils"
+	"github.com/stretchr/testify/suite"
+)
+
+type mailpitAddress struct {
+	Address string
+}
+
+type mailpitMessage struct {
+	ID      string
+	From    mailpitAddress
+	To      []mailpitAddress
+	Subject string
+	Text    string
+}
+
+type SmtpNotifierGatewaySuite struct {
+	suite.Suite
+	mailpitContainer testhelpers.MailpitContainer
+}
+
+func (s *SmtpNotifierGatewaySuite) SetupSuite() {
+	s.mailpitContainer = testhelpers.NewMailpitContainer()
+}
+
+func (s *SmtpNotifierGatewaySuite) SetupTest() {
+	request := utils.GetOrThrow(http.NewRequest("DELETE", s.mailpitContainer.ApiUrl()+"/api/v1/messages", nil))
+	response := utils.GetOrThrow(http.DefaultClient.Do(request))
+	utils.ThrowOnError(response.Body.Close())
+}
+
+func (s *SmtpNotifierGatewaySuite) messages() []mailpitMessage {
+	response := utils.GetOrThrow(http.Get(s.mailpitContainer.ApiUrl() + "/api/v1/messages"))
+	summaries := utils.ParseJSONBody[struct{ Messages []mailpitMessage }](response.Body).Messages
+
+	messages := []mailpitMessage{}
+
+	for _, summary := range summaries {
+		response := utils.GetOrThrow(http.Get(s.mailpitContainer.ApiUrl() + "/api/v1/message/" + summary.ID))
+		messages = append(messages, utils.ParseJSONBody[mailpitMessage](response.Body))
+	}
+
+	return messages
+}
+
+func (s *SmtpNotifierGatewaySuite) Test1() {
+	s.Run("when sending a notification, then the smtp server receives it as a plain text email", func() {
+		smtpNotifierGateway := gateways.NewSmtpNotifierGateway(s.mailpitContainer.SmtpHost(), s.mailpitContainer.SmtpPort(),
+			"no-reply@paybank.com", "", "")
+
+		s.Require().NoError(smtpNotifierGateway.Send(context.Background(), gateways.Notification{
+			To:      "john.doe@gmail.com",
+			Subject: "Verify your email address",
+			Body:    "Use this token: abc\n\nThanks.",
+		}))
+
+		messages := s.messages()
+		s.Require().Len(messages, 1)
+		s.Equal("no-reply@paybank.com", messages[0].From.Address)
+		s.Require().Len(messages[0].To, 1)
+		s.Equal("john.doe@gmail.com", messages[0].To[0].Address)
+		s.Equal("Verify your email address", messages[0].Subject)
+		s.Equal("Use this token: abc\n\nThanks.", strings.TrimSpace(strings.ReplaceAll(messages[0].Text, "\r\n", "\n")))
+	})
+}
+
+func (s *SmtpNotifierGatewaySuite) Test2() {
+	s.Run("given the smtp provider configuration, when building the notifier, then sends through the smtp server", func() {
+		s.T().Setenv("NOTIFIER_PROVIDER", "smtp")
+		s.T().Setenv("SMTP_HOST", s.mailpitContainer.SmtpHost())
+		s.T().Setenv("SMTP_PORT", fmt.Sprint(s.mailpitContainer.SmtpPort()))
+		s.T().Setenv("SMTP_FROM", "no-reply@paybank.com")
+
+		notifierGateway, err := gateways.NewNotifierGateway(context.Background(), gateways.NewEnvConfigGateway(), nil)
+		s.Require().NoError(err)
+		s.Require().IsType(&gateways.SmtpNotifierGateway{}, notifierGateway)
+
+		s.Require().NoError(notifierGateway.Send(context.Background(), gateways.Notification{
+			To:      "richard.smith@gmail.com",
+			Subject: "Olá",
+			Body:    "Body",
+		}))
+
+		messages := s.messages()
+		s.Require().Len(messages, 1)
+		s.Equal("Olá", messages[0].Subject)
+	})
+}
+
+func (s *SmtpNotifierGatewaySuite) Test3() {
+	s.Run("given that the smtp server is unreachable, when sending a notification, then returns error", func() {
+		smtpNotifierGateway := gateways.NewSmtpNotifierGateway("127.0.0.1", 1, "no-reply@paybank.com", "", "")
+
+		err := smtpNotifierGateway.Send(context.Background(), gateways.Notification{To: "john.doe@gmail.com", Subject: "Subject", Body: "Body"})
+		s.Require().Error(err)
+	})
+}
+
+func TestSmtpNotifierGateway(t *testing.T) {
+	suite.Run(t, new(SmtpNotifierGatewaySuite))
+}
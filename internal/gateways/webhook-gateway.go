@@ -0,0 +1,169 @@
+package gateways
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookSecretPrefix starts every webhook secret, followed by the key in base64.
+const WebhookSecretPrefix = "whsec_"
+
+// Send returns these instead of the underlying error, which is shown to customers and would
+// otherwise tell them about the network the deliveries are made from.
+var (
+	ErrWebhookAddressNotAllowed = errors.New("webhook address is not allowed")
+	ErrWebhookUnreachable       = errors.New("webhook could not be reached")
+)
+
+// nonPublicPrefixes are the ranges, besides the loopback, private, link-local, multicast and
+// unspecified ones netip knows about, that are not reachable on the internet.
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// webhookResponseMaxBytes is as much of a response as is read, only so the connection can be
+// reused.
+const webhookResponseMaxBytes = 64 * 1024
+
+// WebhookRequest is an attempt to deliver an event to a webhook. MessageId is the same on every
+// attempt, so receivers can drop the ones they already processed.
+type WebhookRequest struct {
+	Url       string
+	Secret    string
+	MessageId uuid.UUID
+	Timestamp time.Time
+	Payload   []byte
+}
+
+// WebhookGateway delivers events to the URLs customers registered. A response that is not 2xx
+// is returned as its status code, not as an error.
+type WebhookGateway interface {
+	Send(ctx context.Context, request WebhookRequest) (int, error)
+}
+
+// HttpWebhookGateway posts events signed as described by the Standard Webhooks specification:
+// the Webhook-Signature header holds "v1," followed by the HMAC-SHA256, in base64, of
+// "<Webhook-Id>.<Webhook-Timestamp>.<body>". Redirects are not followed, and unless
+// allowPrivateNetworks is set, which is meant for local environments, connections to addresses
+// that are not public are refused once the host is resolved, so a URL cannot reach the internal
+// network, whatever its host resolves to.
+type HttpWebhookGateway struct {
+	client *http.Client
+}
+
+func NewHttpWebhookGateway(timeout time.Duration, allowPrivateNetworks bool) *HttpWebhookGateway {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network string, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+
+			if !allowPrivateNetworks && !IsPublicAddress(addrPort.Addr()) {
+				return ErrWebhookAddressNotAllowed
+			}
+
+			return nil
+		},
+	}
+
+	return &HttpWebhookGateway{
+		client: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: timeout,
+				MaxIdleConnsPerHost: 2,
+				IdleConnTimeout:     90 * time.Second,
+			},
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+func (h *HttpWebhookGateway) Send(ctx context.Context, request WebhookRequest) (int, error) {
+	signature, err := SignWebhook(request.Secret, request.MessageId.String(), request.Timestamp, request.Payload)
+	if err != nil {
+		return 0, err
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, request.Url, bytes.NewReader(request.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	httpRequest.Header.Set("Content-Type", "application/json")
+	httpRequest.Header.Set("User-Agent", "pay-bank-webhooks/1.0")
+	httpRequest.Header.Set("Webhook-Id", request.MessageId.String())
+	httpRequest.Header.Set("Webhook-Timestamp", strconv.FormatInt(request.Timestamp.Unix(), 10))
+	httpRequest.Header.Set("Webhook-Signature", signature)
+
+	response, err := h.client.Do(httpRequest)
+	if errors.Is(err, ErrWebhookAddressNotAllowed) {
+		return 0, ErrWebhookAddressNotAllowed
+	}
+
+	if err != nil {
+		return 0, ErrWebhookUnreachable
+	}
+
+	defer response.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, webhookResponseMaxBytes))
+
+	return response.StatusCode, nil
+}
+
+// IsPublicAddress reports whether addr can be reached on the internet, which webhooks must be.
+func IsPublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	if !addr.IsValid() || addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() || addr.IsUnspecified() {
+		return false
+	}
+
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// SignWebhook returns the Webhook-Signature header of a delivery.
+func SignWebhook(secret string, messageId string, timestamp time.Time, payload []byte) (string, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(secret, WebhookSecretPrefix))
+	if err != nil || !strings.HasPrefix(secret, WebhookSecretPrefix) {
+		return "", errors.New("webhook secret must be " + WebhookSecretPrefix + " followed by base64")
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(messageId + "." + strconv.FormatInt(timestamp.Unix(), 10) + "."))
+	mac.Write(payload)
+
+	return "v1," + base64.StdEncoding.EncodeToString(mac.Sum(nil)), nil
+}
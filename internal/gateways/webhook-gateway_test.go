@@ -0,0 +1,141 @@
+package gateways_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
+	"github.com/stretchr/testify/suite"
+)
+
+type WebhookGatewaySuite struct {
+	suite.Suite
+}
+
+func (w *WebhookGatewaySuite) Test1() {
+	w.Run("when signing, then matches the standard webhooks reference signature", func() {
+		signature, err := gateways.SignWebhook("whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw", "msg_p5jXN8AQM9LWM0D4loKWxJek",
+			time.Unix(1614265330, 0), []byte(`{"test": 2432232314}`))
+		w.Require().NoError(err)
+		w.Require().Equal("v1,g0hM9SsE+OTPJTGt/tmIKtSyZlE3uFJELVlNIOLJ1OE=", signature)
+
+		_, err = gateways.SignWebhook("MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw", "msg_p5jXN8AQM9LWM0D4loKWxJek", time.Unix(1614265330, 0), nil)
+		w.Require().ErrorContains(err, "webhook secret must be whsec_ followed by base64")
+	})
+}
+
+func (w *WebhookGatewaySuite) Test2() {
+	w.Run("when sending, then posts the signed payload and returns the response status", func() {
+		var request *http.Request
+		var body []byte
+
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, r *http.Request) {
+			request = r
+			body, _ = io.ReadAll(r.Body)
+			writer.WriteHeader(202)
+		}))
+		defer server.Close()
+
+		messageId := uuid.New()
+		timestamp := time.Unix(1760000000, 0)
+
+		statusCode, err := gateways.NewHttpWebhookGateway(time.Second, true).Send(context.Background(), gateways.WebhookRequest{
+			Url:       server.URL + "/hooks",
+			Secret:    "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw",
+			MessageId: messageId,
+			Timestamp: timestamp,
+			Payload:   []byte(`{"type":"transfer.completed"}`),
+		})
+		w.Require().NoError(err)
+		w.Require().Equal(202, statusCode)
+
+		signature, err := gateways.SignWebhook("whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw", messageId.String(), timestamp, body)
+		w.Require().NoError(err)
+
+		w.Equal("POST", request.Method)
+		w.Equal("/hooks", request.URL.Path)
+		w.Equal(`{"type":"transfer.completed"}`, string(body))
+		w.Equal("application/json", request.Header.Get("Content-Type"))
+		w.Equal(messageId.String(), request.Header.Get("Webhook-Id"))
+		w.Equal("1760000000", request.Header.Get("Webhook-Timestamp"))
+		w.Equal(signature, request.Header.Get("Webhook-Signature"))
+	})
+}
+
+func (w *WebhookGatewaySuite) Test3() {
+	w.Run("when the webhook redirects, then returns the redirect status without following it", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, r *http.Request) {
+			http.Redirect(writer, r, "/elsewhere", http.StatusFound)
+		}))
+		defer server.Close()
+
+		statusCode, err := gateways.NewHttpWebhookGateway(time.Second, true).Send(context.Background(), gateways.WebhookRequest{
+			Url:       server.URL,
+			Secret:    "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw",
+			MessageId: uuid.New(),
+			Timestamp: time.Now(),
+			Payload:   []byte(`{}`),
+		})
+		w.Require().NoError(err)
+		w.Require().Equal(302, statusCode)
+	})
+}
+
+func (w *WebhookGatewaySuite) Test4() {
+	w.Run("given that private networks are not allowed, when sending to a local webhook, then refuses to connect", func() {
+		called := false
+
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+		defer server.Close()
+
+		statusCode, err := gateways.NewHttpWebhookGateway(time.Second, false).Send(context.Background(), gateways.WebhookRequest{
+			Url:       strings.Replace(server.URL, "127.0.0.1", "localhost", 1),
+			Secret:    "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw",
+			MessageId: uuid.New(),
+			Timestamp: time.Now(),
+			Payload:   []byte(`{}`),
+		})
+		w.Require().ErrorIs(err, gateways.ErrWebhookAddressNotAllowed)
+		w.Require().Equal(0, statusCode)
+		w.False(called)
+	})
+}
+
+func (w *WebhookGatewaySuite) Test5() {
+	w.Run("when the webhook is unreachable, then returns a generic error", func() {
+		_, err := gateways.NewHttpWebhookGateway(time.Second, true).Send(context.Background(), gateways.WebhookRequest{
+			Url:       "http://127.0.0.1:1/hooks",
+			Secret:    "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw",
+			MessageId: uuid.New(),
+			Timestamp: time.Now(),
+			Payload:   []byte(`{}`),
+		})
+		w.Require().Equal(gateways.ErrWebhookUnreachable, err)
+	})
+}
+
+func (w *WebhookGatewaySuite) Test6() {
+	w.Run("when checking whether an address is public, then rejects the ones not reachable on the internet", func() {
+		for _, address := range []string{"127.0.0.1", "10.0.0.1", "172.16.0.1", "192.168.1.1", "169.254.169.254", "100.64.0.1", "0.0.0.0",
+			"224.0.0.1", "::1", "::", "fe80::1", "fc00::1", "::ffff:127.0.0.1", "::ffff:169.254.169.254"} {
+			w.False(gateways.IsPublicAddress(netip.MustParseAddr(address)), address)
+		}
+
+		for _, address := range []string{"8.8.8.8", "1.1.1.1", "2606:4700:4700::1111"} {
+			w.True(gateways.IsPublicAddress(netip.MustParseAddr(address)), address)
+		}
+	})
+}
+
+func TestWebhookGateway(t *testing.T) {
+	suite.Run(t, new(WebhookGatewaySuite))
+}
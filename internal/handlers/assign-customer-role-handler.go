@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
+	webhttp "github.com/gsaaraujo/pay-bank-api/internal/web-http"
+	"github.com/labstack/echo/v4"
+)
+
+type AssignCustomerRoleHandlerInput struct {
+	CustomerId any `validate:"required,uuid4"`
+	Role       any `validate:"required,oneof=support admin auditor"`
+}
+
+type AssignCustomerRoleHandler struct {
+	jsonBodyValidator         webhttp.JSONBodyValidator
+	assignCustomerRoleUsecase usecases.AssignCustomerRoleUsecase
+}
+
+func NewAssignCustomerRoleHandler(jsonBodyValidator webhttp.JSONBodyValidator,
+	assignCustomerRoleUsecase usecases.AssignCustomerRoleUsecase) AssignCustomerRoleHandler {
+	return AssignCustomerRoleHandler{jsonBodyValidator, assignCustomerRoleUsecase}
+}
+
+func (a *AssignCustomerRoleHandler) Handle(c echo.Context) error {
+	input := AssignCustomerRoleHandlerInput{
+		CustomerId: c.Param("customerId"),
+		Role:       c.Param("role"),
+	}
+
+	if fieldErrors := a.jsonBodyValidator.Validate(input); len(fieldErrors) > 0 {
+		return webhttp.NewValidationError(fieldErrors)
+	}
+
+	err := a.assignCustomerRoleUsecase.Execute(c.Request().Context(), usecases.AssignCustomerRoleUsecaseInput{
+		CustomerId: uuid.MustParse(input.CustomerId.(string)),
+		Role:       input.Role.(string),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return c.NoContent(204)
+}
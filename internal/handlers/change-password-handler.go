@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
+	webhttp "github.com/gsaaraujo/pay-bank-api/internal/web-http"
+	"github.com/labstack/echo/v4"
+)
+
+type ChangePasswordHandlerInput struct {
+	CurrentPassword any `validate:"required,string,notEmpty"`
+	NewPassword     any `validate:"required,string,notEmpty"`
+}
+
+type ChangePasswordHandler struct {
+	jsonBodyValidator     webhttp.JSONBodyValidator
+	changePasswordUsecase usecases.ChangePasswordUsecase
+}
+
+func NewChangePasswordHandler(jsonBodyValidator webhttp.JSONBodyValidator,
+	changePasswordUsecase usecases.ChangePasswordUsecase) ChangePasswordHandler {
+	return ChangePasswordHandler{jsonBodyValidator, changePasswordUsecase}
+}
+
+func (ch *ChangePasswordHandler) Handle(c echo.Context) error {
+	var input ChangePasswordHandlerInput
+
+	if err := c.Bind(&input); err != nil {
+		return echo.ErrUnsupportedMediaType
+	}
+
+	if fieldErrors := ch.jsonBodyValidator.Validate(input); len(fieldErrors) > 0 {
+		return webhttp.NewValidationError(fieldErrors)
+	}
+
+	token := c.Get("customer").(*jwt.Token)
+	claims := token.Claims.(*usecases.JwtAccessTokenClaims)
+
+	err := ch.changePasswordUsecase.Execute(c.Request().Context(), usecases.ChangePasswordUsecaseInput{
+		CustomerId:      uuid.MustParse(claims.Subject),
+		CurrentPassword: input.CurrentPassword.(string),
+		NewPassword:     input.NewPassword.(string),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return c.NoContent(204)
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
+	webhttp "github.com/gsaaraujo/pay-bank-api/internal/web-http"
+	"github.com/labstack/echo/v4"
+)
+
+type ConfirmTotpHandlerInput struct {
+	Code any `validate:"required,string,notEmpty"`
+}
+
+type ConfirmTotpHandler struct {
+	jsonBodyValidator  webhttp.JSONBodyValidator
+	confirmTotpUsecase usecases.ConfirmTotpUsecase
+}
+
+func NewConfirmTotpHandler(jsonBodyValidator webhttp.JSONBodyValidator, confirmTotpUsecase usecases.ConfirmTotpUsecase) ConfirmTotpHandler {
+	return ConfirmTotpHandler{jsonBodyValidator, confirmTotpUsecase}
+}
+
+func (co *ConfirmTotpHandler) Handle(c echo.Context) error {
+	var input ConfirmTotpHandlerInput
+
+	if err := c.Bind(&input); err != nil {
+		return echo.ErrUnsupportedMediaType
+	}
+
+	if fieldErrors := co.jsonBodyValidator.Validate(input); len(fieldErrors) > 0 {
+		return webhttp.NewValidationError(fieldErrors)
+	}
+
+	token := c.Get("customer").(*jwt.Token)
+	claims := token.Claims.(*usecases.JwtAccessTokenClaims)
+
+	output, err := co.confirmTotpUsecase.Execute(c.Request().Context(), usecases.ConfirmTotpUsecaseInput{
+		CustomerId: uuid.MustParse(claims.Subject),
+		Code:       input.Code.(string),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, map[string]any{
+		"data": map[string]any{
+			"recoveryCodes": output.RecoveryCodes,
+		},
+	})
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
+	webhttp "github.com/gsaaraujo/pay-bank-api/internal/web-http"
+	"github.com/labstack/echo/v4"
+)
+
+type CreateWebhookHandlerInput struct {
+	Url any `validate:"required,string,notEmpty"`
+}
+
+type CreateWebhookHandler struct {
+	jsonBodyValidator    webhttp.JSONBodyValidator
+	createWebhookUsecase usecases.CreateWebhookUsecase
+}
+
+func NewCreateWebhookHandler(jsonBodyValidator webhttp.JSONBodyValidator, createWebhookUsecase usecases.CreateWebhookUsecase) CreateWebhookHandler {
+	return CreateWebhookHandler{jsonBodyValidator, createWebhookUsecase}
+}
+
+func (cr *CreateWebhookHandler) Handle(c echo.Context) error {
+	var input CreateWebhookHandlerInput
+
+	if err := c.Bind(&input); err != nil {
+		return echo.ErrUnsupportedMediaType
+	}
+
+	if fieldErrors := cr.jsonBodyValidator.Validate(input); len(fieldErrors) > 0 {
+		return webhttp.NewValidationError(fieldErrors)
+	}
+
+	token := c.Get("customer").(*jwt.Token)
+	claims := token.Claims.(*usecases.JwtAccessTokenClaims)
+
+	output, err := cr.createWebhookUsecase.Execute(c.Request().Context(), usecases.CreateWebhookUsecaseInput{
+		CustomerId: uuid.MustParse(claims.Subject),
+		Url:        input.Url.(string),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(201, map[string]any{
+		"data": map[string]any{
+			"id":        output.Webhook.Id,
+			"url":       output.Webhook.Url,
+			"secret":    output.Secret,
+			"createdAt": output.Webhook.CreatedAt.UTC().Format(time.RFC3339),
+		},
+	})
+}
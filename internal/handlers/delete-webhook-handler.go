@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
+	webhttp "github.com/gsaaraujo/pay-bank-api/internal/web-http"
+	"github.com/labstack/echo/v4"
+)
+
+type DeleteWebhookHandlerInput struct {
+	WebhookId any `validate:"required,uuid4"`
+}
+
+type DeleteWebhookHandler struct {
+	jsonBodyValidator    webhttp.JSONBodyValidator
+	deleteWebhookUsecase usecases.DeleteWebhookUsecase
+}
+
+func NewDeleteWebhookHandler(jsonBodyValidator webhttp.JSONBodyValidator, deleteWebhookUsecase usecases.DeleteWebhookUsecase) DeleteWebhookHandler {
+	return DeleteWebhookHandler{jsonBodyValidator, deleteWebhookUsecase}
+}
+
+func (d *DeleteWebhookHandler) Handle(c echo.Context) error {
+	input := DeleteWebhookHandlerInput{
+		WebhookId: c.Param("webhookId"),
+	}
+
+	if fieldErrors := d.jsonBodyValidator.Validate(input); len(fieldErrors) > 0 {
+		return webhttp.NewValidationError(fieldErrors)
+	}
+
+	token := c.Get("customer").(*jwt.Token)
+	claims := token.Claims.(*usecases.JwtAccessTokenClaims)
+
+	err := d.deleteWebhookUsecase.Execute(c.Request().Context(), usecases.DeleteWebhookUsecaseInput{
+		CustomerId: uuid.MustParse(claims.Subject),
+		WebhookId:  uuid.MustParse(input.WebhookId.(string)),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return c.NoContent(204)
+}
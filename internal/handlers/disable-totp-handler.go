@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
+	webhttp "github.com/gsaaraujo/pay-bank-api/internal/web-http"
+	"github.com/labstack/echo/v4"
+)
+
+type DisableTotpHandlerInput struct {
+	Code any `validate:"required,string,notEmpty"`
+}
+
+type DisableTotpHandler struct {
+	jsonBodyValidator  webhttp.JSONBodyValidator
+	disableTotpUsecase usecases.DisableTotpUsecase
+}
+
+func NewDisableTotpHandler(jsonBodyValidator webhttp.JSONBodyValidator, disableTotpUsecase usecases.DisableTotpUsecase) DisableTotpHandler {
+	return DisableTotpHandler{jsonBodyValidator, disableTotpUsecase}
+}
+
+func (d *DisableTotpHandler) Handle(c echo.Context) error {
+	var input DisableTotpHandlerInput
+
+	if err := c.Bind(&input); err != nil {
+		return echo.ErrUnsupportedMediaType
+	}
+
+	if fieldErrors := d.jsonBodyValidator.Validate(input); len(fieldErrors) > 0 {
+		return webhttp.NewValidationError(fieldErrors)
+	}
+
+	token := c.Get("customer").(*jwt.Token)
+	claims := token.Claims.(*usecases.JwtAccessTokenClaims)
+
+	err := d.disableTotpUsecase.Execute(c.Request().Context(), usecases.DisableTotpUsecaseInput{
+		CustomerId: uuid.MustParse(claims.Subject),
+		Code:       input.Code.(string),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return c.NoContent(204)
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
+	"github.com/labstack/echo/v4"
+)
+
+type EnrollTotpHandler struct {
+	enrollTotpUsecase usecases.EnrollTotpUsecase
+}
+
+func NewEnrollTotpHandler(enrollTotpUsecase usecases.EnrollTotpUsecase) EnrollTotpHandler {
+	return EnrollTotpHandler{enrollTotpUsecase}
+}
+
+func (e *EnrollTotpHandler) Handle(c echo.Context) error {
+	token := c.Get("customer").(*jwt.Token)
+	claims := token.Claims.(*usecases.JwtAccessTokenClaims)
+
+	output, err := e.enrollTotpUsecase.Execute(c.Request().Context(), usecases.EnrollTotpUsecaseInput{
+		CustomerId: uuid.MustParse(claims.Subject),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, map[string]any{
+		"data": map[string]any{
+			"secret":     output.Secret,
+			"otpauthUri": output.Uri,
+		},
+	})
+}
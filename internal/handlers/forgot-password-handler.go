@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
+	webhttp "github.com/gsaaraujo/pay-bank-api/internal/web-http"
+	"github.com/labstack/echo/v4"
+)
+
+type ForgotPasswordHandlerInput struct {
+	Email any `validate:"required,string,notEmpty"`
+}
+
+type ForgotPasswordHandler struct {
+	jsonBodyValidator     webhttp.JSONBodyValidator
+	forgotPasswordUsecase usecases.ForgotPasswordUsecase
+}
+
+func NewForgotPasswordHandler(jsonBodyValidator webhttp.JSONBodyValidator,
+	forgotPasswordUsecase usecases.ForgotPasswordUsecase) ForgotPasswordHandler {
+	return ForgotPasswordHandler{jsonBodyValidator, forgotPasswordUsecase}
+}
+
+func (f *ForgotPasswordHandler) Handle(c echo.Context) error {
+	var input ForgotPasswordHandlerInput
+
+	if err := c.Bind(&input); err != nil {
+		return echo.ErrUnsupportedMediaType
+	}
+
+	if fieldErrors := f.jsonBodyValidator.Validate(input); len(fieldErrors) > 0 {
+		return webhttp.NewValidationError(fieldErrors)
+	}
+
+	err := f.forgotPasswordUsecase.Execute(c.Request().Context(), usecases.ForgotPasswordUsecaseInput{
+		Email: input.Email.(string),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return c.NoContent(202)
+}
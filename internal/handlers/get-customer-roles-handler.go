@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
+	webhttp "github.com/gsaaraujo/pay-bank-api/internal/web-http"
+	"github.com/labstack/echo/v4"
+)
+
+type GetCustomerRolesHandlerInput struct {
+	CustomerId any `validate:"required,uuid4"`
+}
+
+type GetCustomerRolesHandler struct {
+	jsonBodyValidator       webhttp.JSONBodyValidator
+	getCustomerRolesUsecase usecases.GetCustomerRolesUsecase
+}
+
+func NewGetCustomerRolesHandler(jsonBodyValidator webhttp.JSONBodyValidator,
+	getCustomerRolesUsecase usecases.GetCustomerRolesUsecase) GetCustomerRolesHandler {
+	return GetCustomerRolesHandler{jsonBodyValidator, getCustomerRolesUsecase}
+}
+
+func (g *GetCustomerRolesHandler) Handle(c echo.Context) error {
+	input := GetCustomerRolesHandlerInput{
+		CustomerId: c.Param("customerId"),
+	}
+
+	if fieldErrors := g.jsonBodyValidator.Validate(input); len(fieldErrors) > 0 {
+		return webhttp.NewValidationError(fieldErrors)
+	}
+
+	output, err := g.getCustomerRolesUsecase.Execute(c.Request().Context(), usecases.GetCustomerRolesUsecaseInput{
+		CustomerId: uuid.MustParse(input.CustomerId.(string)),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, map[string]any{
+		"data": map[string]any{
+			"roles": output.Roles,
+		},
+	})
+}
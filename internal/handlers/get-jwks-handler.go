@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"sort"
+
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
+	"github.com/labstack/echo/v4"
+)
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+}
+
+// GetJWKSHandler publishes the access token verification keys as a JSON Web Key Set (RFC 7517,
+// RFC 8037), so other services can verify access tokens without holding any secret.
+type GetJWKSHandler struct {
+	accessTokenKeysGateway gateways.AccessTokenKeysGateway
+}
+
+func NewGetJWKSHandler(accessTokenKeysGateway gateways.AccessTokenKeysGateway) GetJWKSHandler {
+	return GetJWKSHandler{accessTokenKeysGateway}
+}
+
+func (g *GetJWKSHandler) Handle(c echo.Context) error {
+	verificationKeys, err := g.accessTokenKeysGateway.VerificationKeys(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	keys := []jwk{}
+
+	for keyId, publicKey := range verificationKeys {
+		keys = append(keys, jwk{
+			Kty: "OKP",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(publicKey),
+			Kid: keyId,
+			Use: "sig",
+			Alg: "EdDSA",
+		})
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Kid < keys[j].Kid
+	})
+
+	c.Response().Header().Set("Cache-Control", "public, max-age=300")
+
+	return c.JSON(200, map[string]any{
+		"keys": keys,
+	})
+}
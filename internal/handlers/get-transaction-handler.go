@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
+	webhttp "github.com/gsaaraujo/pay-bank-api/internal/web-http"
+	"github.com/labstack/echo/v4"
+)
+
+type GetTransactionHandlerInput struct {
+	TransactionId any `validate:"required,uuid4"`
+}
+
+type GetTransactionHandler struct {
+	jsonBodyValidator     webhttp.JSONBodyValidator
+	getTransactionUsecase usecases.GetTransactionUsecase
+}
+
+func NewGetTransactionHandler(jsonBodyValidator webhttp.JSONBodyValidator,
+	getTransactionUsecase usecases.GetTransactionUsecase) GetTransactionHandler {
+	return GetTransactionHandler{jsonBodyValidator, getTransactionUsecase}
+}
+
+func (g *GetTransactionHandler) Handle(c echo.Context) error {
+	input := GetTransactionHandlerInput{
+		TransactionId: c.Param("transactionId"),
+	}
+
+	if fieldErrors := g.jsonBodyValidator.Validate(input); len(fieldErrors) > 0 {
+		return webhttp.NewValidationError(fieldErrors)
+	}
+
+	token := c.Get("customer").(*jwt.Token)
+	claims := token.Claims.(*usecases.JwtAccessTokenClaims)
+
+	output, err := g.getTransactionUsecase.Execute(c.Request().Context(), usecases.GetTransactionUsecaseInput{
+		CustomerId:    uuid.MustParse(claims.Subject),
+		TransactionId: uuid.MustParse(input.TransactionId.(string)),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, map[string]any{
+		"data": transaction{
+			Id:        output.Id,
+			Direction: output.Direction,
+			CustomerSender: customer{
+				Id:   output.CustomerSender.Id,
+				Name: output.CustomerSender.Name,
+			},
+			CustomerReceiver: customer{
+				Id:   output.CustomerReceiver.Id,
+				Name: output.CustomerReceiver.Name,
+			},
+			AccountSender:   account{Id: output.AccountSenderId},
+			AccountReceiver: account{Id: output.AccountReceiverId},
+			Amount:          output.Amount,
+			CreatedAt:       output.CreatedAt.UTC().Format(time.RFC3339),
+		},
+	})
+}
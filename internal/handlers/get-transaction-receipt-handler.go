@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
+	webhttp "github.com/gsaaraujo/pay-bank-api/internal/web-http"
+	"github.com/labstack/echo/v4"
+)
+
+type GetTransactionReceiptHandlerInput struct {
+	TransactionId any `validate:"required,uuid4"`
+}
+
+type GetTransactionReceiptHandler struct {
+	jsonBodyValidator            webhttp.JSONBodyValidator
+	getTransactionReceiptUsecase usecases.GetTransactionReceiptUsecase
+}
+
+func NewGetTransactionReceiptHandler(jsonBodyValidator webhttp.JSONBodyValidator,
+	getTransactionReceiptUsecase usecases.GetTransactionReceiptUsecase) GetTransactionReceiptHandler {
+	return GetTransactionReceiptHandler{jsonBodyValidator, getTransactionReceiptUsecase}
+}
+
+// Handle answers with the receipt as JSON, or as a PDF document when the Accept header lists
+// application/pdf.
+func (g *GetTransactionReceiptHandler) Handle(c echo.Context) error {
+	input := GetTransactionReceiptHandlerInput{
+		TransactionId: c.Param("transactionId"),
+	}
+
+	if fieldErrors := g.jsonBodyValidator.Validate(input); len(fieldErrors) > 0 {
+		return webhttp.NewValidationError(fieldErrors)
+	}
+
+	token := c.Get("customer").(*jwt.Token)
+	claims := token.Claims.(*usecases.JwtAccessTokenClaims)
+
+	output, err := g.getTransactionReceiptUsecase.Execute(c.Request().Context(), usecases.GetTransactionReceiptUsecaseInput{
+		CustomerId:    uuid.MustParse(claims.Subject),
+		TransactionId: uuid.MustParse(input.TransactionId.(string)),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if acceptsPDF(c) {
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="receipt-%s.pdf"`, output.TransactionId))
+		return c.Blob(200, webhttp.MIMEApplicationPDF, webhttp.NewTextPDF("Transfer receipt", []string{
+			"Transaction: " + output.TransactionId.String(),
+			"Date: " + output.CreatedAt.Format(time.RFC3339),
+			"Amount: " + formatCents(output.Amount),
+			"",
+			"From: " + output.Sender.Name,
+			"Customer: " + output.Sender.CustomerId.String(),
+			"Account: " + output.Sender.AccountId.String(),
+			"",
+			"To: " + output.Receiver.Name,
+			"Customer: " + output.Receiver.CustomerId.String(),
+			"Account: " + output.Receiver.AccountId.String(),
+			"",
+			"Authentication hash:",
+			output.AuthenticationHash,
+		}))
+	}
+
+	return c.JSON(200, map[string]any{
+		"data": map[string]any{
+			"transactionId": output.TransactionId,
+			"sender": map[string]any{
+				"customerId": output.Sender.CustomerId,
+				"name":       output.Sender.Name,
+				"accountId":  output.Sender.AccountId,
+			},
+			"receiver": map[string]any{
+				"customerId": output.Receiver.CustomerId,
+				"name":       output.Receiver.Name,
+				"accountId":  output.Receiver.AccountId,
+			},
+			"amount":             output.Amount,
+			"createdAt":          output.CreatedAt.Format(time.RFC3339),
+			"authenticationHash": output.AuthenticationHash,
+		},
+	})
+}
+
+func acceptsPDF(c echo.Context) bool {
+	for mediaRange := range strings.SplitSeq(c.Request().Header.Get(echo.HeaderAccept), ",") {
+		mediaType, _, _ := strings.Cut(mediaRange, ";")
+
+		if strings.EqualFold(strings.TrimSpace(mediaType), webhttp.MIMEApplicationPDF) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// formatCents prints an amount in cents with two decimal places, such as 2500 as "25.00".
+func formatCents(amount int64) string {
+	return fmt.Sprintf("%d.%02d", amount/100, amount%100)
+}
@@ -1,14 +1,24 @@
 package handlers
 
 import (
-	"context"
+	"strconv"
+	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
 	"github.com/gsaaraujo/pay-bank-api/internal/utils"
-	"github.com/jackc/pgx/v5/pgxpool"
+	webhttp "github.com/gsaaraujo/pay-bank-api/internal/web-http"
 	"github.com/labstack/echo/v4"
 )
 
+type GetTransactionsHistoryHandlerInput struct {
+	Limit  any `validate:"omitempty,integer,positive"`
+	Cursor any `validate:"omitempty,string,notEmpty"`
+	From   any `validate:"omitempty,timeRFC3339|date"`
+	To     any `validate:"omitempty,timeRFC3339|date"`
+}
+
 type customer struct {
 	Id   uuid.UUID `json:"id"`
 	Name string    `json:"name"`
@@ -20,53 +30,132 @@ type account struct {
 
 type transaction struct {
 	Id               uuid.UUID `json:"id"`
+	Direction        string    `json:"direction"`
 	CustomerSender   customer  `json:"customerSender"`
 	CustomerReceiver customer  `json:"customerReceiver"`
 	AccountSender    account   `json:"accountSender"`
 	AccountReceiver  account   `json:"accountReceiver"`
 	Amount           int64     `json:"amount"`
+	CreatedAt        string    `json:"createdAt"`
 }
 
 type GetTransactionsHistoryHandler struct {
-	pgxPool *pgxpool.Pool
+	jsonBodyValidator             webhttp.JSONBodyValidator
+	getTransactionsHistoryUsecase usecases.GetTransactionsHistoryUsecase
 }
 
-func NewGetTransactionsHistoryHandler(pgxPool *pgxpool.Pool) GetTransactionsHistoryHandler {
-	return GetTransactionsHistoryHandler{pgxPool}
+func NewGetTransactionsHistoryHandler(jsonBodyValidator webhttp.JSONBodyValidator,
+	getTransactionsHistoryUsecase usecases.GetTransactionsHistoryUsecase) GetTransactionsHistoryHandler {
+	return GetTransactionsHistoryHandler{jsonBodyValidator, getTransactionsHistoryUsecase}
 }
 
 func (g *GetTransactionsHistoryHandler) Handle(c echo.Context) error {
-	rows := utils.GetOrThrow(g.pgxPool.Query(context.TODO(), `
-		SELECT
-			t.id AS transaction_id,
-			cs.id AS customer_sender_id,
-			cs.name AS customer_sender_name,
-			cr.id AS customer_receiver_id,
-			cr.name AS customer_receiver_name,
-			asnd.id AS account_sender_id,
-			arec.id AS account_receiver_id,
-			t.amount
-		FROM transactions t
-		JOIN accounts as asnd
-			ON t.account_sender_id = asnd.id
-		JOIN customers cs
-			ON asnd.customer_id = cs.id
-		JOIN accounts as arec
-			ON t.account_receiver_id = arec.id
-		JOIN customers cr
-			ON arec.customer_id = cr.id;
-	`))
+	input := GetTransactionsHistoryHandlerInput{
+		Limit:  queryParamAsNumber(c, "limit"),
+		Cursor: queryParam(c, "cursor"),
+		From:   queryParam(c, "from"),
+		To:     queryParam(c, "to"),
+	}
+
+	if fieldErrors := g.jsonBodyValidator.Validate(input); len(fieldErrors) > 0 {
+		return webhttp.NewValidationError(fieldErrors)
+	}
+
+	token := c.Get("customer").(*jwt.Token)
+	claims := token.Claims.(*usecases.JwtAccessTokenClaims)
+
+	usecaseInput := usecases.GetTransactionsHistoryUsecaseInput{
+		CustomerId: uuid.MustParse(claims.Subject),
+	}
+
+	if input.Limit != nil {
+		usecaseInput.Limit = utils.NewPointer(int(input.Limit.(float64)))
+	}
+
+	if input.Cursor != nil {
+		usecaseInput.Cursor = utils.NewPointer(input.Cursor.(string))
+	}
+
+	if input.From != nil {
+		usecaseInput.From = utils.NewPointer(parseHistoryBound(input.From.(string), false))
+	}
+
+	if input.To != nil {
+		usecaseInput.To = utils.NewPointer(parseHistoryBound(input.To.(string), true))
+	}
+
+	output, err := g.getTransactionsHistoryUsecase.Execute(c.Request().Context(), usecaseInput)
+
+	if err != nil {
+		return err
+	}
 
 	transactions := []transaction{}
 
-	for rows.Next() {
-		item := transaction{}
-		utils.ThrowOnError(rows.Scan(&item.Id, &item.CustomerSender.Id, &item.CustomerSender.Name, &item.CustomerReceiver.Id, &item.CustomerReceiver.Name,
-			&item.AccountSender.Id, &item.AccountReceiver.Id, &item.Amount))
-		transactions = append(transactions, item)
+	for _, item := range output.Transactions {
+		transactions = append(transactions, transaction{
+			Id:        item.Id,
+			Direction: item.Direction,
+			CustomerSender: customer{
+				Id:   item.CustomerSender.Id,
+				Name: item.CustomerSender.Name,
+			},
+			CustomerReceiver: customer{
+				Id:   item.CustomerReceiver.Id,
+				Name: item.CustomerReceiver.Name,
+			},
+			AccountSender:   account{Id: item.AccountSenderId},
+			AccountReceiver: account{Id: item.AccountReceiverId},
+			Amount:          item.Amount,
+			CreatedAt:       item.CreatedAt.UTC().Format(time.RFC3339),
+		})
 	}
 
 	return c.JSON(200, map[string]any{
 		"data": transactions,
+		"pagination": map[string]any{
+			"nextCursor": output.NextCursor,
+		},
 	})
 }
+
+func queryParam(c echo.Context, name string) any {
+	value := c.QueryParam(name)
+
+	if value == "" {
+		return nil
+	}
+
+	return value
+}
+
+func queryParamAsNumber(c echo.Context, name string) any {
+	value := queryParam(c, name)
+
+	if value == nil {
+		return nil
+	}
+
+	number, err := strconv.ParseFloat(value.(string), 64)
+	if err != nil {
+		return value
+	}
+
+	return number
+}
+
+// parseHistoryBound accepts either an RFC 3339 timestamp or a yyyy-mm-dd date.
+// A date used as the upper bound covers the whole day.
+func parseHistoryBound(value string, upper bool) time.Time {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed
+	}
+
+	parsed := utils.GetOrThrow(time.Parse(time.DateOnly, value))
+
+	if upper {
+		return parsed.AddDate(0, 0, 1)
+	}
+
+	return parsed
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
+	webhttp "github.com/gsaaraujo/pay-bank-api/internal/web-http"
+	"github.com/labstack/echo/v4"
+)
+
+type GetTransferHandlerInput struct {
+	TransferId any `validate:"required,uuid4"`
+}
+
+type GetTransferHandler struct {
+	jsonBodyValidator  webhttp.JSONBodyValidator
+	getTransferUsecase usecases.GetTransferUsecase
+}
+
+func NewGetTransferHandler(jsonBodyValidator webhttp.JSONBodyValidator, getTransferUsecase usecases.GetTransferUsecase) GetTransferHandler {
+	return GetTransferHandler{jsonBodyValidator, getTransferUsecase}
+}
+
+func (g *GetTransferHandler) Handle(c echo.Context) error {
+	input := GetTransferHandlerInput{
+		TransferId: c.Param("transferId"),
+	}
+
+	if fieldErrors := g.jsonBodyValidator.Validate(input); len(fieldErrors) > 0 {
+		return webhttp.NewValidationError(fieldErrors)
+	}
+
+	token := c.Get("customer").(*jwt.Token)
+	claims := token.Claims.(*usecases.JwtAccessTokenClaims)
+
+	output, err := g.getTransferUsecase.Execute(c.Request().Context(), usecases.GetTransferUsecaseInput{
+		CustomerId: uuid.MustParse(claims.Subject),
+		TransferId: uuid.MustParse(input.TransferId.(string)),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	var failure map[string]any
+
+	if output.FailureCode != nil {
+		failure = map[string]any{
+			"code":   *output.FailureCode,
+			"reason": *output.FailureReason,
+		}
+	}
+
+	return c.JSON(200, map[string]any{
+		"data": map[string]any{
+			"id":                 output.Id,
+			"customerReceiverId": output.ReceiverCustomerId,
+			"amount":             output.Amount,
+			"status":             output.Status,
+			"transactionId":      output.TransactionId,
+			"failure":            failure,
+			"createdAt":          output.CreatedAt.UTC().Format(time.RFC3339),
+			"updatedAt":          output.UpdatedAt.UTC().Format(time.RFC3339),
+		},
+	})
+}
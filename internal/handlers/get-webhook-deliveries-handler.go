@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
+	webhttp "github.com/gsaaraujo/pay-bank-api/internal/web-http"
+	"github.com/labstack/echo/v4"
+)
+
+type GetWebhookDeliveriesHandlerInput struct {
+	WebhookId any `validate:"required,uuid4"`
+}
+
+type webhookDelivery struct {
+	Id                 uuid.UUID `json:"id"`
+	EventId            uuid.UUID `json:"eventId"`
+	EventType          string    `json:"eventType"`
+	Status             string    `json:"status"`
+	Attempts           int       `json:"attempts"`
+	NextAttemptAt      *string   `json:"nextAttemptAt"`
+	LastAttemptedAt    *string   `json:"lastAttemptedAt"`
+	LastResponseStatus *int      `json:"lastResponseStatus"`
+	LastError          *string   `json:"lastError"`
+	CreatedAt          string    `json:"createdAt"`
+	DeliveredAt        *string   `json:"deliveredAt"`
+}
+
+type GetWebhookDeliveriesHandler struct {
+	jsonBodyValidator           webhttp.JSONBodyValidator
+	getWebhookDeliveriesUsecase usecases.GetWebhookDeliveriesUsecase
+}
+
+func NewGetWebhookDeliveriesHandler(jsonBodyValidator webhttp.JSONBodyValidator,
+	getWebhookDeliveriesUsecase usecases.GetWebhookDeliveriesUsecase) GetWebhookDeliveriesHandler {
+	return GetWebhookDeliveriesHandler{jsonBodyValidator, getWebhookDeliveriesUsecase}
+}
+
+func (g *GetWebhookDeliveriesHandler) Handle(c echo.Context) error {
+	input := GetWebhookDeliveriesHandlerInput{
+		WebhookId: c.Param("webhookId"),
+	}
+
+	if fieldErrors := g.jsonBodyValidator.Validate(input); len(fieldErrors) > 0 {
+		return webhttp.NewValidationError(fieldErrors)
+	}
+
+	token := c.Get("customer").(*jwt.Token)
+	claims := token.Claims.(*usecases.JwtAccessTokenClaims)
+
+	output, err := g.getWebhookDeliveriesUsecase.Execute(c.Request().Context(), usecases.GetWebhookDeliveriesUsecaseInput{
+		CustomerId: uuid.MustParse(claims.Subject),
+		WebhookId:  uuid.MustParse(input.WebhookId.(string)),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	deliveries := []webhookDelivery{}
+
+	for _, item := range output.Deliveries {
+		deliveries = append(deliveries, webhookDelivery{
+			Id:                 item.Id,
+			EventId:            item.EventId,
+			EventType:          item.EventType,
+			Status:             item.Status,
+			Attempts:           item.Attempts,
+			NextAttemptAt:      formatOptionalTime(item.NextAttemptAt),
+			LastAttemptedAt:    formatOptionalTime(item.LastAttemptedAt),
+			LastResponseStatus: item.LastResponseStatus,
+			LastError:          item.LastError,
+			CreatedAt:          item.CreatedAt.UTC().Format(time.RFC3339),
+			DeliveredAt:        formatOptionalTime(item.DeliveredAt),
+		})
+	}
+
+	return c.JSON(200, map[string]any{
+		"data": deliveries,
+	})
+}
+
+func formatOptionalTime(value *time.Time) *string {
+	if value == nil {
+		return nil
+	}
+
+	formatted := value.UTC().Format(time.RFC3339)
+	return &formatted
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
+	"github.com/labstack/echo/v4"
+)
+
+type webhook struct {
+	Id        uuid.UUID `json:"id"`
+	Url       string    `json:"url"`
+	CreatedAt string    `json:"createdAt"`
+}
+
+type GetWebhooksHandler struct {
+	getWebhooksUsecase usecases.GetWebhooksUsecase
+}
+
+func NewGetWebhooksHandler(getWebhooksUsecase usecases.GetWebhooksUsecase) GetWebhooksHandler {
+	return GetWebhooksHandler{getWebhooksUsecase}
+}
+
+func (g *GetWebhooksHandler) Handle(c echo.Context) error {
+	token := c.Get("customer").(*jwt.Token)
+	claims := token.Claims.(*usecases.JwtAccessTokenClaims)
+
+	output, err := g.getWebhooksUsecase.Execute(c.Request().Context(), usecases.GetWebhooksUsecaseInput{
+		CustomerId: uuid.MustParse(claims.Subject),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	webhooks := []webhook{}
+
+	for _, item := range output.Webhooks {
+		webhooks = append(webhooks, webhook{
+			Id:        item.Id,
+			Url:       item.Url,
+			CreatedAt: item.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+
+	return c.JSON(200, map[string]any{
+		"data": webhooks,
+	})
+}
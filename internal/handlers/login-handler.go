@@ -24,33 +24,38 @@ func (l *LoginHandler) Handle(c echo.Context) error {
 	var input LoginHandlerInput
 
 	if err := c.Bind(&input); err != nil {
-		return c.NoContent(415)
+		return echo.ErrUnsupportedMediaType
 	}
 
-	if messages := l.jsonBodyValidator.Validate(input); len(messages) > 0 {
-		return c.JSON(400, map[string]any{"message": messages})
+	if fieldErrors := l.jsonBodyValidator.Validate(input); len(fieldErrors) > 0 {
+		return webhttp.NewValidationError(fieldErrors)
 	}
 
-	loginUsecaseOutput, err := l.LoginUsecase.Execute(usecases.LoginUsecaseInput{
-		Email:    input.Email.(string),
-		Password: input.Password.(string),
+	loginUsecaseOutput, err := l.LoginUsecase.Execute(c.Request().Context(), usecases.LoginUsecaseInput{
+		Email:     input.Email.(string),
+		Password:  input.Password.(string),
+		IpAddress: c.RealIP(),
 	})
 
 	if err != nil {
-		switch err.Error() {
-		case "email address is invalid":
-			return c.JSON(409, map[string]any{"message": err.Error()})
-		case "email or password is incorrect":
-			return c.JSON(409, map[string]any{"message": err.Error()})
-		default:
-			return c.JSON(500, map[string]any{"message": "Internal Server Error"})
-		}
+		return err
+	}
+
+	if loginUsecaseOutput.TotpChallengeToken != "" {
+		return c.JSON(200, map[string]any{
+			"data": map[string]any{
+				"customerId":     loginUsecaseOutput.CustomerId,
+				"totpRequired":   true,
+				"challengeToken": loginUsecaseOutput.TotpChallengeToken,
+			},
+		})
 	}
 
 	return c.JSON(200, map[string]any{
 		"data": map[string]any{
-			"customerId":  loginUsecaseOutput.CustomerId,
-			"accessToken": loginUsecaseOutput.AccessToken,
+			"customerId":   loginUsecaseOutput.CustomerId,
+			"accessToken":  loginUsecaseOutput.AccessToken,
+			"refreshToken": loginUsecaseOutput.RefreshToken,
 		},
 	})
 }
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
+	"github.com/labstack/echo/v4"
+)
+
+type LogoutHandler struct {
+	logoutUsecase usecases.LogoutUsecase
+}
+
+func NewLogoutHandler(logoutUsecase usecases.LogoutUsecase) LogoutHandler {
+	return LogoutHandler{logoutUsecase}
+}
+
+func (l *LogoutHandler) Handle(c echo.Context) error {
+	token := c.Get("customer").(*jwt.Token)
+	claims := token.Claims.(*usecases.JwtAccessTokenClaims)
+
+	sessionId, err := uuid.Parse(claims.SessionId)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	err = l.logoutUsecase.Execute(c.Request().Context(), usecases.LogoutUsecaseInput{
+		SessionId: sessionId,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return c.NoContent(204)
+}
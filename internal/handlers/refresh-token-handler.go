@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
+	webhttp "github.com/gsaaraujo/pay-bank-api/internal/web-http"
+	"github.com/labstack/echo/v4"
+)
+
+type RefreshTokenHandlerInput struct {
+	RefreshToken any `validate:"required,string,notEmpty"`
+}
+
+type RefreshTokenHandler struct {
+	jsonBodyValidator   webhttp.JSONBodyValidator
+	refreshTokenUsecase usecases.RefreshTokenUsecase
+}
+
+func NewRefreshTokenHandler(jsonBodyValidator webhttp.JSONBodyValidator, refreshTokenUsecase usecases.RefreshTokenUsecase) RefreshTokenHandler {
+	return RefreshTokenHandler{jsonBodyValidator, refreshTokenUsecase}
+}
+
+func (r *RefreshTokenHandler) Handle(c echo.Context) error {
+	var input RefreshTokenHandlerInput
+
+	if err := c.Bind(&input); err != nil {
+		return echo.ErrUnsupportedMediaType
+	}
+
+	if fieldErrors := r.jsonBodyValidator.Validate(input); len(fieldErrors) > 0 {
+		return webhttp.NewValidationError(fieldErrors)
+	}
+
+	output, err := r.refreshTokenUsecase.Execute(c.Request().Context(), usecases.RefreshTokenUsecaseInput{
+		RefreshToken: input.RefreshToken.(string),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, map[string]any{
+		"data": map[string]any{
+			"customerId":   output.CustomerId,
+			"accessToken":  output.AccessToken,
+			"refreshToken": output.RefreshToken,
+		},
+	})
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
+	webhttp "github.com/gsaaraujo/pay-bank-api/internal/web-http"
+	"github.com/labstack/echo/v4"
+)
+
+type ResendEmailVerificationHandlerInput struct {
+	Email any `validate:"required,string,notEmpty"`
+}
+
+type ResendEmailVerificationHandler struct {
+	jsonBodyValidator              webhttp.JSONBodyValidator
+	resendEmailVerificationUsecase usecases.ResendEmailVerificationUsecase
+}
+
+func NewResendEmailVerificationHandler(jsonBodyValidator webhttp.JSONBodyValidator,
+	resendEmailVerificationUsecase usecases.ResendEmailVerificationUsecase) ResendEmailVerificationHandler {
+	return ResendEmailVerificationHandler{jsonBodyValidator, resendEmailVerificationUsecase}
+}
+
+func (r *ResendEmailVerificationHandler) Handle(c echo.Context) error {
+	var input ResendEmailVerificationHandlerInput
+
+	if err := c.Bind(&input); err != nil {
+		return echo.ErrUnsupportedMediaType
+	}
+
+	if fieldErrors := r.jsonBodyValidator.Validate(input); len(fieldErrors) > 0 {
+		return webhttp.NewValidationError(fieldErrors)
+	}
+
+	err := r.resendEmailVerificationUsecase.Execute(c.Request().Context(), usecases.ResendEmailVerificationUsecaseInput{
+		Email: input.Email.(string),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return c.NoContent(202)
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
+	webhttp "github.com/gsaaraujo/pay-bank-api/internal/web-http"
+	"github.com/labstack/echo/v4"
+)
+
+type ResetPasswordHandlerInput struct {
+	Token    any `validate:"required,string,notEmpty"`
+	Password any `validate:"required,string,notEmpty"`
+}
+
+type ResetPasswordHandler struct {
+	jsonBodyValidator    webhttp.JSONBodyValidator
+	resetPasswordUsecase usecases.ResetPasswordUsecase
+}
+
+func NewResetPasswordHandler(jsonBodyValidator webhttp.JSONBodyValidator,
+	resetPasswordUsecase usecases.ResetPasswordUsecase) ResetPasswordHandler {
+	return ResetPasswordHandler{jsonBodyValidator, resetPasswordUsecase}
+}
+
+func (r *ResetPasswordHandler) Handle(c echo.Context) error {
+	var input ResetPasswordHandlerInput
+
+	if err := c.Bind(&input); err != nil {
+		return echo.ErrUnsupportedMediaType
+	}
+
+	if fieldErrors := r.jsonBodyValidator.Validate(input); len(fieldErrors) > 0 {
+		return webhttp.NewValidationError(fieldErrors)
+	}
+
+	err := r.resetPasswordUsecase.Execute(c.Request().Context(), usecases.ResetPasswordUsecaseInput{
+		Token:    input.Token.(string),
+		Password: input.Password.(string),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return c.NoContent(204)
+}
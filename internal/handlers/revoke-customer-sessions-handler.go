@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
+	webhttp "github.com/gsaaraujo/pay-bank-api/internal/web-http"
+	"github.com/labstack/echo/v4"
+)
+
+type RevokeCustomerSessionsHandlerInput struct {
+	CustomerId any `validate:"required,uuid4"`
+}
+
+type RevokeCustomerSessionsHandler struct {
+	jsonBodyValidator             webhttp.JSONBodyValidator
+	revokeCustomerSessionsUsecase usecases.RevokeCustomerSessionsUsecase
+}
+
+func NewRevokeCustomerSessionsHandler(jsonBodyValidator webhttp.JSONBodyValidator,
+	revokeCustomerSessionsUsecase usecases.RevokeCustomerSessionsUsecase) RevokeCustomerSessionsHandler {
+	return RevokeCustomerSessionsHandler{jsonBodyValidator, revokeCustomerSessionsUsecase}
+}
+
+func (r *RevokeCustomerSessionsHandler) Handle(c echo.Context) error {
+	input := RevokeCustomerSessionsHandlerInput{
+		CustomerId: c.Param("customerId"),
+	}
+
+	if fieldErrors := r.jsonBodyValidator.Validate(input); len(fieldErrors) > 0 {
+		return webhttp.NewValidationError(fieldErrors)
+	}
+
+	err := r.revokeCustomerSessionsUsecase.Execute(c.Request().Context(), usecases.RevokeCustomerSessionsUsecaseInput{
+		CustomerId: uuid.MustParse(input.CustomerId.(string)),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return c.NoContent(204)
+}
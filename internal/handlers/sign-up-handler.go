@@ -25,32 +25,21 @@ func (r SignUpHandler) Handle(c echo.Context) error {
 	var input SignUpHandlerInput
 
 	if err := c.Bind(&input); err != nil {
-		return c.NoContent(415)
+		return echo.ErrUnsupportedMediaType
 	}
 
-	if messages := r.jsonBodyValidator.Validate(input); len(messages) > 0 {
-		return c.JSON(400, map[string]any{"message": messages})
+	if fieldErrors := r.jsonBodyValidator.Validate(input); len(fieldErrors) > 0 {
+		return webhttp.NewValidationError(fieldErrors)
 	}
 
-	err := r.signUpUsecase.Execute(usecases.SignUpUsecaseInput{
+	err := r.signUpUsecase.Execute(c.Request().Context(), usecases.SignUpUsecaseInput{
 		Name:     input.Name.(string),
 		Email:    input.Email.(string),
 		Password: input.Password.(string),
 	})
 
 	if err != nil {
-		switch err.Error() {
-		case "name must be at least 2 characters":
-			return c.JSON(409, map[string]any{"message": err.Error()})
-		case "email address is invalid":
-			return c.JSON(409, map[string]any{"message": err.Error()})
-		case "password must be at least 6 characters":
-			return c.JSON(409, map[string]any{"message": err.Error()})
-		case "this email address has already been taken by someone":
-			return c.JSON(409, map[string]any{"message": err.Error()})
-		default:
-			return c.JSON(500, map[string]any{"message": "Internal Server Error"})
-		}
+		return err
 	}
 
 	return c.NoContent(204)
@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"strings"
+
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	domainerrors "github.com/gsaaraujo/pay-bank-api/internal/domain-errors"
 	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
 	webhttp "github.com/gsaaraujo/pay-bank-api/internal/web-http"
 	"github.com/labstack/echo/v4"
@@ -11,60 +14,118 @@ import (
 type TransferHandlerInput struct {
 	CustomerReceiverId any `validate:"required,uuid4"`
 	Amount             any `validate:"required,integer,positive"`
+	TotpCode           any `validate:"omitempty,string,notEmpty"`
 }
 
 type TransferHandler struct {
-	jsonBodyValidator webhttp.JSONBodyValidator
-	transferUsecase   usecases.TransferUsecase
+	jsonBodyValidator     webhttp.JSONBodyValidator
+	transferUsecase       usecases.TransferUsecase
+	acceptTransferUsecase usecases.AcceptTransferUsecase
 }
 
-func NewTransferHandler(jsonBodyValidator webhttp.JSONBodyValidator, transferUsecase usecases.TransferUsecase) TransferHandler {
-	return TransferHandler{jsonBodyValidator, transferUsecase}
+func NewTransferHandler(jsonBodyValidator webhttp.JSONBodyValidator, transferUsecase usecases.TransferUsecase,
+	acceptTransferUsecase usecases.AcceptTransferUsecase) TransferHandler {
+	return TransferHandler{jsonBodyValidator, transferUsecase, acceptTransferUsecase}
 }
 
+// Handle transfers right away and answers 201 with the id of the transaction, unless the
+// request carries the "Prefer: respond-async" header: the transfer is then answered with 202
+// and processed in the background, its outcome available at the Location returned.
 func (t *TransferHandler) Handle(c echo.Context) error {
 	var input TransferHandlerInput
 
 	if err := c.Bind(&input); err != nil {
-		return c.NoContent(415)
+		return echo.ErrUnsupportedMediaType
 	}
 
-	if messages := t.jsonBodyValidator.Validate(input); len(messages) > 0 {
-		return c.JSON(400, map[string]any{"message": messages})
+	if fieldErrors := t.jsonBodyValidator.Validate(input); len(fieldErrors) > 0 {
+		return webhttp.NewValidationError(fieldErrors)
 	}
 
 	idempotencyKey := c.Request().Header.Get("Idempotency-Key")
 
 	if idempotencyKey == "" {
-		return c.JSON(400, map[string]any{"message": "idempotency-key header is required"})
+		return domainerrors.ErrIdempotencyKeyRequired
 	}
 
 	if err := uuid.Validate(idempotencyKey); err != nil {
-		return c.JSON(400, map[string]any{"message": "idempotency-key header must be uuidv4"})
+		return domainerrors.ErrIdempotencyKeyInvalid
 	}
 
 	token := c.Get("customer").(*jwt.Token)
 	claims := token.Claims.(*usecases.JwtAccessTokenClaims)
 
-	err := t.transferUsecase.Execute(usecases.TransferUsecaseInput{
+	totpCode, _ := input.TotpCode.(string)
+
+	if prefersRespondAsync(c) {
+		output, err := t.acceptTransferUsecase.Execute(c.Request().Context(), usecases.AcceptTransferUsecaseInput{
+			SenderCustomerId:   uuid.MustParse(claims.Subject),
+			ReceiverCustomerId: uuid.MustParse(input.CustomerReceiverId.(string)),
+			IdempotencyKey:     uuid.MustParse(idempotencyKey),
+			Amount:             int64(input.Amount.(float64)),
+			TotpCode:           totpCode,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return transferResponse(c, output.TransferId, output.Outcome)
+	}
+
+	output, err := t.transferUsecase.Execute(c.Request().Context(), usecases.TransferUsecaseInput{
 		SenderCustomerId:   uuid.MustParse(claims.Subject),
 		ReceiverCustomerId: uuid.MustParse(input.CustomerReceiverId.(string)),
 		IdempotencyKey:     uuid.MustParse(idempotencyKey),
 		Amount:             int64(input.Amount.(float64)),
+		TotpCode:           totpCode,
 	})
 
 	if err != nil {
-		switch err.Error() {
-		case "you cannot transfer to yourself":
-			return c.JSON(409, map[string]any{"message": err.Error()})
-		case "the amount to be transferred cannot be zero":
-			return c.JSON(409, map[string]any{"message": err.Error()})
-		case "the sender does not have enough balance to make the transfer":
-			return c.JSON(409, map[string]any{"message": err.Error()})
-		default:
-			return c.JSON(500, map[string]any{"message": "Internal Server Error"})
+		return err
+	}
+
+	return transferResponse(c, output.TransferId, output.Outcome)
+}
+
+// transferResponse answers with the outcome of the request that claimed the idempotency key,
+// which may have been made the other way when the key is reused. A completed transfer is
+// recorded as the transaction with the same id.
+func transferResponse(c echo.Context, transferId uuid.UUID, outcome string) error {
+	if outcome == usecases.TransferOutcomeAccepted {
+		c.Response().Header().Set("Location", "/v1/transfers/"+transferId.String())
+		c.Response().Header().Set("Preference-Applied", "respond-async")
+
+		return c.JSON(202, map[string]any{
+			"data": map[string]any{
+				"id":     transferId,
+				"status": "pending",
+			},
+		})
+	}
+
+	c.Response().Header().Set("Location", "/v1/transactions/"+transferId.String())
+
+	return c.JSON(201, map[string]any{
+		"data": map[string]any{
+			"id":            transferId,
+			"status":        "completed",
+			"transactionId": transferId,
+		},
+	})
+}
+
+// prefersRespondAsync tells whether the Prefer header, RFC 7240, asks for respond-async.
+func prefersRespondAsync(c echo.Context) bool {
+	for _, header := range c.Request().Header.Values("Prefer") {
+		for preference := range strings.SplitSeq(header, ",") {
+			token, _, _ := strings.Cut(preference, ";")
+
+			if strings.EqualFold(strings.TrimSpace(token), "respond-async") {
+				return true
+			}
 		}
 	}
 
-	return c.NoContent(204)
+	return false
 }
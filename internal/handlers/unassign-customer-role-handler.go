@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
+	webhttp "github.com/gsaaraujo/pay-bank-api/internal/web-http"
+	"github.com/labstack/echo/v4"
+)
+
+type UnassignCustomerRoleHandlerInput struct {
+	CustomerId any `validate:"required,uuid4"`
+	Role       any `validate:"required,oneof=support admin auditor"`
+}
+
+type UnassignCustomerRoleHandler struct {
+	jsonBodyValidator           webhttp.JSONBodyValidator
+	unassignCustomerRoleUsecase usecases.UnassignCustomerRoleUsecase
+}
+
+func NewUnassignCustomerRoleHandler(jsonBodyValidator webhttp.JSONBodyValidator,
+	unassignCustomerRoleUsecase usecases.UnassignCustomerRoleUsecase) UnassignCustomerRoleHandler {
+	return UnassignCustomerRoleHandler{jsonBodyValidator, unassignCustomerRoleUsecase}
+}
+
+func (u *UnassignCustomerRoleHandler) Handle(c echo.Context) error {
+	input := UnassignCustomerRoleHandlerInput{
+		CustomerId: c.Param("customerId"),
+		Role:       c.Param("role"),
+	}
+
+	if fieldErrors := u.jsonBodyValidator.Validate(input); len(fieldErrors) > 0 {
+		return webhttp.NewValidationError(fieldErrors)
+	}
+
+	err := u.unassignCustomerRoleUsecase.Execute(c.Request().Context(), usecases.UnassignCustomerRoleUsecaseInput{
+		CustomerId: uuid.MustParse(input.CustomerId.(string)),
+		Role:       input.Role.(string),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return c.NoContent(204)
+}
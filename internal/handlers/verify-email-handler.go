@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
+	webhttp "github.com/gsaaraujo/pay-bank-api/internal/web-http"
+	"github.com/labstack/echo/v4"
+)
+
+type VerifyEmailHandlerInput struct {
+	Token any `validate:"required,string,notEmpty"`
+}
+
+type VerifyEmailHandler struct {
+	jsonBodyValidator  webhttp.JSONBodyValidator
+	verifyEmailUsecase usecases.VerifyEmailUsecase
+}
+
+func NewVerifyEmailHandler(jsonBodyValidator webhttp.JSONBodyValidator, verifyEmailUsecase usecases.VerifyEmailUsecase) VerifyEmailHandler {
+	return VerifyEmailHandler{jsonBodyValidator, verifyEmailUsecase}
+}
+
+func (v *VerifyEmailHandler) Handle(c echo.Context) error {
+	input := VerifyEmailHandlerInput{
+		Token: queryParam(c, "token"),
+	}
+
+	if fieldErrors := v.jsonBodyValidator.Validate(input); len(fieldErrors) > 0 {
+		return webhttp.NewValidationError(fieldErrors)
+	}
+
+	err := v.verifyEmailUsecase.Execute(c.Request().Context(), usecases.VerifyEmailUsecaseInput{
+		Token: input.Token.(string),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return c.NoContent(204)
+}
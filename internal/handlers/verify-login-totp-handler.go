@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
+	webhttp "github.com/gsaaraujo/pay-bank-api/internal/web-http"
+	"github.com/labstack/echo/v4"
+)
+
+type VerifyLoginTotpHandlerInput struct {
+	ChallengeToken any `validate:"required,string,notEmpty"`
+	Code           any `validate:"required,string,notEmpty"`
+}
+
+type VerifyLoginTotpHandler struct {
+	jsonBodyValidator      webhttp.JSONBodyValidator
+	verifyLoginTotpUsecase usecases.VerifyLoginTotpUsecase
+}
+
+func NewVerifyLoginTotpHandler(jsonBodyValidator webhttp.JSONBodyValidator,
+	verifyLoginTotpUsecase usecases.VerifyLoginTotpUsecase) VerifyLoginTotpHandler {
+	return VerifyLoginTotpHandler{jsonBodyValidator, verifyLoginTotpUsecase}
+}
+
+func (v *VerifyLoginTotpHandler) Handle(c echo.Context) error {
+	var input VerifyLoginTotpHandlerInput
+
+	if err := c.Bind(&input); err != nil {
+		return echo.ErrUnsupportedMediaType
+	}
+
+	if fieldErrors := v.jsonBodyValidator.Validate(input); len(fieldErrors) > 0 {
+		return webhttp.NewValidationError(fieldErrors)
+	}
+
+	output, err := v.verifyLoginTotpUsecase.Execute(c.Request().Context(), usecases.VerifyLoginTotpUsecaseInput{
+		ChallengeToken: input.ChallengeToken.(string),
+		Code:           input.Code.(string),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, map[string]any{
+		"data": map[string]any{
+			"customerId":   output.CustomerId,
+			"accessToken":  output.AccessToken,
+			"refreshToken": output.RefreshToken,
+		},
+	})
+}
@@ -2,12 +2,12 @@ package internal
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"os"
 	"runtime/debug"
+	"time"
 
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
 	"github.com/gsaaraujo/pay-bank-api/internal/daos"
 	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
 	"github.com/gsaaraujo/pay-bank-api/internal/handlers"
@@ -16,14 +16,33 @@ import (
 	"github.com/gsaaraujo/pay-bank-api/internal/utils"
 	webhttp "github.com/gsaaraujo/pay-bank-api/internal/web-http"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 )
 
+// requestTimeout bounds every request, including the queries it runs. A request that exceeds
+// it is answered with 503 and its queries are cancelled.
+const requestTimeout = 10 * time.Second
+
+// secretsTTL is how long secrets are served from memory before being fetched again. They are
+// also refreshed in the background at half this interval, so rotated secrets take effect
+// without a restart.
+const secretsTTL = 5 * time.Minute
+
+// notificationTimeout bounds each notification sent in the background, after its request was
+// answered.
+const notificationTimeout = 30 * time.Second
+
+// requiredConfigKeys are checked before anything else starts, whatever CONFIG_PROVIDER is.
+var requiredConfigKeys = []string{"POSTGRES_URL", "REDIS_URL", "ACCESS_TOKEN_SIGNING_KEY_ID", "ACCESS_TOKEN_SIGNING_PRIVATE_KEY",
+	"RECEIPT_SIGNING_KEY"}
+
 type HttpServer struct {
-	echo   *echo.Echo
-	logger *slog.Logger
+	echo                 *echo.Echo
+	logger               *slog.Logger
+	asyncNotifierGateway *gateways.AsyncNotifierGateway
 }
 
 func NewHttpServer() *HttpServer {
@@ -36,53 +55,202 @@ func NewHttpServer() *HttpServer {
 func (h *HttpServer) Ready() {
 	defer func() {
 		if r := recover(); r != nil {
-			h.logger.Error(r.(string), "stack_trace", string(debug.Stack()))
+			h.logger.Error(fmt.Sprint(r), "stack_trace", string(debug.Stack()))
 			os.Exit(1)
 		}
 	}()
 
 	h.echo.HidePort = true
 	h.echo.HideBanner = true
+	h.echo.HTTPErrorHandler = webhttp.NewProblemHTTPErrorHandler(h.logger)
+	// Only X-Forwarded-For entries added by proxies in private networks, such as the load
+	// balancer, are trusted, so clients cannot pick the IP address their logins are counted on.
+	h.echo.IPExtractor = echo.ExtractIPFromXFFHeader()
 	h.echo.Use(middleware.RequestID())
 	h.echo.Use(middlewares.NewEchoRequestLoggerMiddleware(h.logger))
 	h.echo.Use(middlewares.NewEchoRecoverMiddleware(h.logger))
+	h.echo.Use(middleware.ContextTimeout(requestTimeout))
 
-	defaultConfig := utils.GetOrThrow(config.LoadDefaultConfig(context.TODO()))
-	secretsClient := secretsmanager.NewFromConfig(defaultConfig)
 	jsonBodyValidator := utils.GetOrThrow(webhttp.NewJSONBodyValidator())
 
-	awsSecretsGateway := gateways.NewAwsSecretsGateway(secretsClient)
-	postgresUrl := awsSecretsGateway.Get("POSTGRES_URL").(string)
-	accessTokenSigningKey := awsSecretsGateway.Get("ACCESS_TOKEN_SIGNING_KEY").(string)
+	configGateway := utils.GetOrThrow(gateways.NewConfigGateway(context.Background(), secretsTTL))
+	utils.ThrowOnError(gateways.ValidateConfig(context.Background(), configGateway, requiredConfigKeys))
+
+	if awsSecretsGateway, ok := configGateway.(*gateways.AwsSecretsGateway); ok {
+		awsSecretsGateway.StartRotation(context.Background(), secretsTTL/2, func(err error) {
+			h.logger.Error("secrets rotation failed", "error", err.Error())
+		})
+	}
+
+	// Malformed keys are reported at startup instead of on the first login.
+	accessTokenKeysGateway := gateways.NewAccessTokenKeysGateway(configGateway)
+	_ = utils.GetOrThrow(accessTokenKeysGateway.VerificationKeys(context.Background()))
+
+	postgresUrl := utils.GetOrThrow(configGateway.GetString(context.Background(), "POSTGRES_URL"))
+	redisUrl := utils.GetOrThrow(configGateway.GetString(context.Background(), "REDIS_URL"))
+
+	notifierGateway := utils.GetOrThrow(gateways.NewNotifierGateway(context.Background(), configGateway, h.logger))
+	h.asyncNotifierGateway = gateways.NewAsyncNotifierGateway(notifierGateway, h.logger, notificationTimeout)
+	passwordPolicyGateway := gateways.NewPasswordPolicyGateway(configGateway)
+	rateLimitPolicyGateway := gateways.NewRateLimitPolicyGateway(configGateway)
+	breachedPasswordsGateway := utils.GetOrThrow(gateways.NewBreachedPasswordsGateway(context.Background(), configGateway))
 
 	pgxPool := utils.GetOrThrow(pgxpool.New(context.Background(), postgresUrl))
+	redisClient := redis.NewClient(utils.GetOrThrow(redis.ParseURL(redisUrl)))
 
 	customerDAO := daos.NewCustomerDAO(pgxPool)
 	accountDAO := daos.NewAccountDAO(pgxPool)
 	transactionDAO := daos.NewTransactionDAO(pgxPool)
+	idempotencyKeyDAO := daos.NewIdempotencyKeyDAO(pgxPool)
+	ledgerDAO := daos.NewLedgerDAO(pgxPool)
+	refreshTokenDAO := daos.NewRefreshTokenDAO(pgxPool)
+	revokedAccessTokenDAO := daos.NewRevokedAccessTokenDAO(redisClient)
+	customerRoleDAO := daos.NewCustomerRoleDAO(pgxPool)
+	loginAttemptDAO := daos.NewLoginAttemptDAO(redisClient)
+	loginLockoutDAO := daos.NewLoginLockoutDAO(pgxPool)
+	customerTotpDAO := daos.NewCustomerTotpDAO(pgxPool)
+	totpRecoveryCodeDAO := daos.NewTotpRecoveryCodeDAO(pgxPool)
+	loginChallengeDAO := daos.NewLoginChallengeDAO(redisClient)
+	passwordResetTokenDAO := daos.NewPasswordResetTokenDAO(pgxPool)
+	emailVerificationTokenDAO := daos.NewEmailVerificationTokenDAO(pgxPool)
+	outboxEventDAO := daos.NewOutboxEventDAO(pgxPool)
+	webhookDAO := daos.NewWebhookDAO(pgxPool)
+	webhookDeliveryDAO := daos.NewWebhookDeliveryDAO(pgxPool)
+	transferDAO := daos.NewTransferDAO(pgxPool)
+	rateLimitDAO := daos.NewRateLimitDAO(redisClient)
 
-	loginUsecase := usecases.NewLoginUsecase(customerDAO, awsSecretsGateway)
-	signUpUsecase := usecases.NewSignUpUsecase(pgxPool, customerDAO)
-	transferUsecase := usecases.NewTransferUsecase(pgxPool, accountDAO, transactionDAO)
+	loginUsecase := usecases.NewLoginUsecase(customerDAO, customerRoleDAO, refreshTokenDAO, loginAttemptDAO, loginLockoutDAO,
+		customerTotpDAO, loginChallengeDAO, accessTokenKeysGateway)
+	verifyLoginTotpUsecase := usecases.NewVerifyLoginTotpUsecase(pgxPool, loginChallengeDAO, customerTotpDAO, totpRecoveryCodeDAO,
+		customerRoleDAO, refreshTokenDAO, loginAttemptDAO, loginLockoutDAO, accessTokenKeysGateway)
+	refreshTokenUsecase := usecases.NewRefreshTokenUsecase(pgxPool, refreshTokenDAO, customerRoleDAO, revokedAccessTokenDAO,
+		accessTokenKeysGateway)
+	logoutUsecase := usecases.NewLogoutUsecase(pgxPool, refreshTokenDAO, revokedAccessTokenDAO)
+	signUpUsecase := usecases.NewSignUpUsecase(pgxPool, customerDAO, emailVerificationTokenDAO, outboxEventDAO,
+		passwordPolicyGateway, breachedPasswordsGateway, h.asyncNotifierGateway)
+	verifyEmailUsecase := usecases.NewVerifyEmailUsecase(pgxPool, customerDAO, accountDAO, emailVerificationTokenDAO, ledgerDAO)
+	resendEmailVerificationUsecase := usecases.NewResendEmailVerificationUsecase(pgxPool, customerDAO, emailVerificationTokenDAO,
+		h.asyncNotifierGateway)
+	transferUsecase := usecases.NewTransferUsecase(pgxPool, customerDAO, accountDAO, transactionDAO, idempotencyKeyDAO, ledgerDAO, customerTotpDAO,
+		transferDAO, outboxEventDAO, webhookDeliveryDAO, loginAttemptDAO, loginLockoutDAO, configGateway)
+	acceptTransferUsecase := usecases.NewAcceptTransferUsecase(pgxPool, customerDAO, accountDAO, idempotencyKeyDAO, customerTotpDAO, transferDAO,
+		outboxEventDAO, loginAttemptDAO, loginLockoutDAO, configGateway)
+	getTransferUsecase := usecases.NewGetTransferUsecase(transferDAO)
+	getTransactionsHistoryUsecase := usecases.NewGetTransactionsHistoryUsecase(accountDAO, transactionDAO)
+	getTransactionUsecase := usecases.NewGetTransactionUsecase(accountDAO, transactionDAO)
+	getTransactionReceiptUsecase := usecases.NewGetTransactionReceiptUsecase(accountDAO, transactionDAO, configGateway)
+	getCustomerRolesUsecase := usecases.NewGetCustomerRolesUsecase(customerDAO, customerRoleDAO)
+	assignCustomerRoleUsecase := usecases.NewAssignCustomerRoleUsecase(customerDAO, customerRoleDAO)
+	unassignCustomerRoleUsecase := usecases.NewUnassignCustomerRoleUsecase(customerDAO, customerRoleDAO, revokedAccessTokenDAO)
+	revokeCustomerSessionsUsecase := usecases.NewRevokeCustomerSessionsUsecase(pgxPool, customerDAO, refreshTokenDAO,
+		revokedAccessTokenDAO)
+	enrollTotpUsecase := usecases.NewEnrollTotpUsecase(customerDAO, customerTotpDAO)
+	confirmTotpUsecase := usecases.NewConfirmTotpUsecase(pgxPool, customerTotpDAO, totpRecoveryCodeDAO)
+	disableTotpUsecase := usecases.NewDisableTotpUsecase(pgxPool, customerTotpDAO, totpRecoveryCodeDAO, loginAttemptDAO,
+		loginLockoutDAO)
+	forgotPasswordUsecase := usecases.NewForgotPasswordUsecase(pgxPool, customerDAO, passwordResetTokenDAO,
+		h.asyncNotifierGateway)
+	resetPasswordUsecase := usecases.NewResetPasswordUsecase(pgxPool, customerDAO, passwordResetTokenDAO, refreshTokenDAO,
+		revokedAccessTokenDAO, passwordPolicyGateway, breachedPasswordsGateway)
+	changePasswordUsecase := usecases.NewChangePasswordUsecase(pgxPool, customerDAO, refreshTokenDAO, revokedAccessTokenDAO,
+		passwordPolicyGateway, breachedPasswordsGateway)
+	createWebhookUsecase := usecases.NewCreateWebhookUsecase(webhookDAO, configGateway)
+	getWebhooksUsecase := usecases.NewGetWebhooksUsecase(webhookDAO)
+	deleteWebhookUsecase := usecases.NewDeleteWebhookUsecase(webhookDAO)
+	getWebhookDeliveriesUsecase := usecases.NewGetWebhookDeliveriesUsecase(webhookDAO, webhookDeliveryDAO)
 
 	loginHandler := handlers.NewLoginHandler(jsonBodyValidator, loginUsecase)
+	verifyLoginTotpHandler := handlers.NewVerifyLoginTotpHandler(jsonBodyValidator, verifyLoginTotpUsecase)
+	refreshTokenHandler := handlers.NewRefreshTokenHandler(jsonBodyValidator, refreshTokenUsecase)
+	logoutHandler := handlers.NewLogoutHandler(logoutUsecase)
 	signUpHandler := handlers.NewSignUpHandler(jsonBodyValidator, signUpUsecase)
-	transferHandler := handlers.NewTransferHandler(jsonBodyValidator, transferUsecase)
-	getTransactionsHistoryHandler := handlers.NewGetTransactionsHistoryHandler(pgxPool)
+	verifyEmailHandler := handlers.NewVerifyEmailHandler(jsonBodyValidator, verifyEmailUsecase)
+	resendEmailVerificationHandler := handlers.NewResendEmailVerificationHandler(jsonBodyValidator, resendEmailVerificationUsecase)
+	transferHandler := handlers.NewTransferHandler(jsonBodyValidator, transferUsecase, acceptTransferUsecase)
+	getTransferHandler := handlers.NewGetTransferHandler(jsonBodyValidator, getTransferUsecase)
+	getTransactionsHistoryHandler := handlers.NewGetTransactionsHistoryHandler(jsonBodyValidator, getTransactionsHistoryUsecase)
+	getTransactionHandler := handlers.NewGetTransactionHandler(jsonBodyValidator, getTransactionUsecase)
+	getTransactionReceiptHandler := handlers.NewGetTransactionReceiptHandler(jsonBodyValidator, getTransactionReceiptUsecase)
+	getJWKSHandler := handlers.NewGetJWKSHandler(accessTokenKeysGateway)
+	getCustomerRolesHandler := handlers.NewGetCustomerRolesHandler(jsonBodyValidator, getCustomerRolesUsecase)
+	assignCustomerRoleHandler := handlers.NewAssignCustomerRoleHandler(jsonBodyValidator, assignCustomerRoleUsecase)
+	unassignCustomerRoleHandler := handlers.NewUnassignCustomerRoleHandler(jsonBodyValidator, unassignCustomerRoleUsecase)
+	revokeCustomerSessionsHandler := handlers.NewRevokeCustomerSessionsHandler(jsonBodyValidator, revokeCustomerSessionsUsecase)
+	enrollTotpHandler := handlers.NewEnrollTotpHandler(enrollTotpUsecase)
+	confirmTotpHandler := handlers.NewConfirmTotpHandler(jsonBodyValidator, confirmTotpUsecase)
+	disableTotpHandler := handlers.NewDisableTotpHandler(jsonBodyValidator, disableTotpUsecase)
+	forgotPasswordHandler := handlers.NewForgotPasswordHandler(jsonBodyValidator, forgotPasswordUsecase)
+	resetPasswordHandler := handlers.NewResetPasswordHandler(jsonBodyValidator, resetPasswordUsecase)
+	changePasswordHandler := handlers.NewChangePasswordHandler(jsonBodyValidator, changePasswordUsecase)
+	createWebhookHandler := handlers.NewCreateWebhookHandler(jsonBodyValidator, createWebhookUsecase)
+	getWebhooksHandler := handlers.NewGetWebhooksHandler(getWebhooksUsecase)
+	deleteWebhookHandler := handlers.NewDeleteWebhookHandler(jsonBodyValidator, deleteWebhookUsecase)
+	getWebhookDeliveriesHandler := handlers.NewGetWebhookDeliveriesHandler(jsonBodyValidator, getWebhookDeliveriesUsecase)
 
-	jwtMiddleware := middlewares.NewEchoJWTMiddleware(accessTokenSigningKey)
+	jwtMiddleware := middlewares.NewEchoJWTMiddleware(accessTokenKeysGateway, revokedAccessTokenDAO)
+	loginRateLimitMiddleware := middlewares.NewEchoRateLimitMiddleware(gateways.RateLimitPolicyLogin, middlewares.RateLimitByIp,
+		rateLimitPolicyGateway, rateLimitDAO)
+	loginTotpRateLimitMiddleware := middlewares.NewEchoRateLimitMiddleware(gateways.RateLimitPolicyLoginTotp, middlewares.RateLimitByIp,
+		rateLimitPolicyGateway, rateLimitDAO)
+	disableTotpRateLimitMiddleware := middlewares.NewEchoRateLimitMiddleware(gateways.RateLimitPolicyDisableTotp,
+		middlewares.RateLimitByCustomer, rateLimitPolicyGateway, rateLimitDAO)
+	signUpRateLimitMiddleware := middlewares.NewEchoRateLimitMiddleware(gateways.RateLimitPolicySignUp, middlewares.RateLimitByIp,
+		rateLimitPolicyGateway, rateLimitDAO)
+	forgotPasswordRateLimitMiddleware := middlewares.NewEchoRateLimitMiddleware(gateways.RateLimitPolicyForgotPassword,
+		middlewares.RateLimitByIp, rateLimitPolicyGateway, rateLimitDAO)
+	resendEmailVerificationRateLimitMiddleware := middlewares.NewEchoRateLimitMiddleware(gateways.RateLimitPolicyResendEmailVerification,
+		middlewares.RateLimitByIp, rateLimitPolicyGateway, rateLimitDAO)
+	transferRateLimitMiddleware := middlewares.NewEchoRateLimitMiddleware(gateways.RateLimitPolicyTransfer, middlewares.RateLimitByCustomer,
+		rateLimitPolicyGateway, rateLimitDAO)
 
 	h.echo.GET("/health", func(c echo.Context) error {
 		return c.NoContent(204)
 	})
 
+	h.echo.GET("/.well-known/jwks.json", getJWKSHandler.Handle)
+
 	v1 := h.echo.Group("/v1")
 
-	v1.POST("/login", loginHandler.Handle)
-	v1.POST("/sign-up", signUpHandler.Handle)
+	v1.POST("/login", loginHandler.Handle, loginRateLimitMiddleware)
+	v1.POST("/login/totp", verifyLoginTotpHandler.Handle, loginTotpRateLimitMiddleware)
+	v1.POST("/token/refresh", refreshTokenHandler.Handle)
+	v1.POST("/sign-up", signUpHandler.Handle, signUpRateLimitMiddleware)
+	v1.GET("/verify-email", verifyEmailHandler.Handle)
+	v1.POST("/verify-email/resend", resendEmailVerificationHandler.Handle, resendEmailVerificationRateLimitMiddleware)
+	v1.POST("/password/forgot", forgotPasswordHandler.Handle, forgotPasswordRateLimitMiddleware)
+	v1.POST("/password/reset", resetPasswordHandler.Handle)
+
+	v1.POST("/logout", logoutHandler.Handle, jwtMiddleware)
+
+	// Customer routes share the /v1 prefix with the public ones, so their middlewares are set per
+	// route: a group would apply them to every unmatched /v1 path too.
+	customerOnly := []echo.MiddlewareFunc{jwtMiddleware, middlewares.NewEchoRoleMiddleware(usecases.RoleCustomer)}
 
-	v1.POST("/transfer", transferHandler.Handle, jwtMiddleware)
-	v1.GET("/transactions-history", getTransactionsHistoryHandler.Handle, jwtMiddleware)
+	v1.POST("/transfer", transferHandler.Handle, append(customerOnly, transferRateLimitMiddleware)...)
+	v1.GET("/transfers/:transferId", getTransferHandler.Handle, customerOnly...)
+	v1.GET("/transactions-history", getTransactionsHistoryHandler.Handle, customerOnly...)
+	v1.GET("/transactions/:transactionId", getTransactionHandler.Handle, customerOnly...)
+	v1.GET("/transactions/:transactionId/receipt", getTransactionReceiptHandler.Handle, customerOnly...)
+	v1.POST("/me/totp", enrollTotpHandler.Handle, customerOnly...)
+	v1.POST("/me/totp/confirm", confirmTotpHandler.Handle, customerOnly...)
+	v1.DELETE("/me/totp", disableTotpHandler.Handle, append(customerOnly, disableTotpRateLimitMiddleware)...)
+	v1.PUT("/me/password", changePasswordHandler.Handle, customerOnly...)
+	v1.POST("/me/webhooks", createWebhookHandler.Handle, customerOnly...)
+	v1.GET("/me/webhooks", getWebhooksHandler.Handle, customerOnly...)
+	v1.DELETE("/me/webhooks/:webhookId", deleteWebhookHandler.Handle, customerOnly...)
+	v1.GET("/me/webhooks/:webhookId/deliveries", getWebhookDeliveriesHandler.Handle, customerOnly...)
+
+	admin := v1.Group("/admin", jwtMiddleware,
+		middlewares.NewEchoRoleMiddleware(usecases.RoleSupport, usecases.RoleAdmin, usecases.RoleAuditor))
+
+	admin.GET("/customers/:customerId/roles", getCustomerRolesHandler.Handle,
+		middlewares.NewEchoRoleMiddleware(usecases.RoleAdmin, usecases.RoleAuditor))
+	admin.PUT("/customers/:customerId/roles/:role", assignCustomerRoleHandler.Handle,
+		middlewares.NewEchoRoleMiddleware(usecases.RoleAdmin))
+	admin.DELETE("/customers/:customerId/roles/:role", unassignCustomerRoleHandler.Handle,
+		middlewares.NewEchoRoleMiddleware(usecases.RoleAdmin))
+	admin.DELETE("/customers/:customerId/sessions", revokeCustomerSessionsHandler.Handle,
+		middlewares.NewEchoRoleMiddleware(usecases.RoleSupport, usecases.RoleAdmin))
 }
 
 func (h *HttpServer) Start() {
@@ -98,3 +266,8 @@ func (h *HttpServer) Start() {
 func (h *HttpServer) Echo() *echo.Echo {
 	return h.echo
 }
+
+// WaitForNotifications blocks until the notifications sent in the background so far are done.
+func (h *HttpServer) WaitForNotifications() {
+	h.asyncNotifierGateway.Wait()
+}
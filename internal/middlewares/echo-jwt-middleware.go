@@ -1,18 +1,75 @@
 package middlewares
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	domainerrors "github.com/gsaaraujo/pay-bank-api/internal/domain-errors"
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
 	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
 	echojwt "github.com/labstack/echo-jwt/v4"
 	"github.com/labstack/echo/v4"
 )
 
-func NewEchoJWTMiddleware(accessTokenSigningKey string) echo.MiddlewareFunc {
-	return echojwt.WithConfig(echojwt.Config{
-		SigningKey: []byte(accessTokenSigningKey),
+// NewEchoJWTMiddleware picks the verification key by the token kid on every request, so
+// rotated keys are honored as soon as the config gateway picks them up. Once the signature is
+// verified the token is checked against the revocation denylist.
+func NewEchoJWTMiddleware(accessTokenKeysGateway gateways.AccessTokenKeysGateway,
+	revokedAccessTokenDAO daos.RevokedAccessTokenDAO) echo.MiddlewareFunc {
+	jwtMiddleware := echojwt.WithConfig(echojwt.Config{
 		ContextKey: "customer",
 		NewClaimsFunc: func(c echo.Context) jwt.Claims {
 			return new(usecases.JwtAccessTokenClaims)
 		},
+		KeyFunc: func(token *jwt.Token) (any, error) {
+			if token.Method.Alg() != jwt.SigningMethodEdDSA.Alg() {
+				return nil, fmt.Errorf("unexpected jwt signing method %s", token.Method.Alg())
+			}
+
+			keyId, _ := token.Header["kid"].(string)
+
+			verificationKeys, err := accessTokenKeysGateway.VerificationKeys(context.Background())
+			if err != nil {
+				return nil, err
+			}
+
+			verificationKey, exists := verificationKeys[keyId]
+			if !exists {
+				return nil, fmt.Errorf("unknown jwt kid %q", keyId)
+			}
+
+			return verificationKey, nil
+		},
 	})
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return jwtMiddleware(func(c echo.Context) error {
+			token := c.Get("customer").(*jwt.Token)
+			claims := token.Claims.(*usecases.JwtAccessTokenClaims)
+
+			customerId, err := uuid.Parse(claims.Subject)
+			if err != nil || claims.ID == "" || claims.IssuedAt == nil || claims.ExpiresAt == nil {
+				return echo.ErrUnauthorized
+			}
+
+			sessionId, err := uuid.Parse(claims.SessionId)
+			if err != nil {
+				return echo.ErrUnauthorized
+			}
+
+			revoked, err := revokedAccessTokenDAO.IsRevoked(c.Request().Context(), sessionId, customerId, claims.IssuedAtExact())
+			if err != nil {
+				return err
+			}
+
+			if revoked {
+				return domainerrors.ErrAccessTokenRevoked
+			}
+
+			return next(c)
+		})
+	}
 }
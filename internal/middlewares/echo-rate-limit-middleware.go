@@ -0,0 +1,73 @@
+package middlewares
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	domainerrors "github.com/gsaaraujo/pay-bank-api/internal/domain-errors"
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
+	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
+	"github.com/labstack/echo/v4"
+)
+
+// RateLimitSubjectFunc tells who a request is counted against.
+type RateLimitSubjectFunc func(c echo.Context) string
+
+// RateLimitByIp counts requests against the client IP address, as trusted by the echo
+// IPExtractor.
+func RateLimitByIp(c echo.Context) string {
+	return "ip:" + c.RealIP()
+}
+
+// RateLimitByCustomer counts requests against the access token subject. It must run after
+// NewEchoJWTMiddleware.
+func RateLimitByCustomer(c echo.Context) string {
+	token := c.Get("customer").(*jwt.Token)
+	claims := token.Claims.(*usecases.JwtAccessTokenClaims)
+
+	return "customer:" + claims.Subject
+}
+
+// NewEchoRateLimitMiddleware answers 429 with Retry-After once the subject has made as many
+// requests as the policy allows in its sliding window. The policy is read on every request, so
+// changing its configuration takes effect without a restart. Every response carries the
+// RateLimit-Policy, RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers.
+func NewEchoRateLimitMiddleware(fallback gateways.RateLimitPolicy, subjectFunc RateLimitSubjectFunc,
+	rateLimitPolicyGateway gateways.RateLimitPolicyGateway, rateLimitDAO daos.RateLimitDAO) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			rateLimitPolicy, err := rateLimitPolicyGateway.Policy(c.Request().Context(), fallback)
+			if err != nil {
+				return err
+			}
+
+			if rateLimitPolicy.Limit == 0 {
+				return next(c)
+			}
+
+			rateLimitHit, err := rateLimitDAO.Hit(c.Request().Context(), rateLimitPolicy.Name, subjectFunc(c), rateLimitPolicy.Limit,
+				rateLimitPolicy.Window, time.Now().UTC())
+			if err != nil {
+				return err
+			}
+
+			reset := max(int(math.Ceil(rateLimitHit.Reset.Seconds())), 1)
+
+			header := c.Response().Header()
+			header.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", rateLimitPolicy.Limit, int(rateLimitPolicy.Window.Seconds())))
+			header.Set("RateLimit-Limit", strconv.Itoa(rateLimitPolicy.Limit))
+			header.Set("RateLimit-Remaining", strconv.Itoa(rateLimitHit.Remaining))
+			header.Set("RateLimit-Reset", strconv.Itoa(reset))
+
+			if !rateLimitHit.Allowed {
+				return domainerrors.NewRetryAfterError(domainerrors.ErrRateLimitExceeded, rateLimitHit.Reset)
+			}
+
+			return next(c)
+		}
+	}
+}
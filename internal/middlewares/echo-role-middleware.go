@@ -0,0 +1,28 @@
+package middlewares
+
+import (
+	"slices"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
+	"github.com/labstack/echo/v4"
+)
+
+// NewEchoRoleMiddleware lets the request through when the access token has at least one of
+// roles and answers 403 otherwise. It must run after NewEchoJWTMiddleware.
+func NewEchoRoleMiddleware(roles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			token := c.Get("customer").(*jwt.Token)
+			claims := token.Claims.(*usecases.JwtAccessTokenClaims)
+
+			for _, role := range claims.Roles {
+				if slices.Contains(roles, role) {
+					return next(c)
+				}
+			}
+
+			return echo.ErrForbidden
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package internal
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
+	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
+	"github.com/gsaaraujo/pay-bank-api/internal/utils"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const outboxRelayBatchSize = 100
+
+// OutboxRelayWorker publishes the events written to the outbox to RabbitMQ until its context
+// is cancelled. Several workers may run at once, each publishing different events.
+type OutboxRelayWorker struct {
+	logger *slog.Logger
+}
+
+func NewOutboxRelayWorker() *OutboxRelayWorker {
+	return &OutboxRelayWorker{
+		logger: slog.New(slog.NewJSONHandler(os.Stderr, nil)),
+	}
+}
+
+func (o *OutboxRelayWorker) Run(ctx context.Context) (exitCode int) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			o.logger.Error("outbox relay failed", "error", rec, "stack_trace", string(debug.Stack()))
+			exitCode = 2
+		}
+	}()
+
+	configGateway := utils.GetOrThrow(gateways.NewConfigGateway(ctx, time.Minute))
+	utils.ThrowOnError(gateways.ValidateConfig(ctx, configGateway, []string{"POSTGRES_URL", "RABBITMQ_URL"}))
+
+	postgresUrl := utils.GetOrThrow(configGateway.GetString(ctx, "POSTGRES_URL"))
+	rabbitmqUrl := utils.GetOrThrow(configGateway.GetString(ctx, "RABBITMQ_URL"))
+
+	pgxPool := utils.GetOrThrow(pgxpool.New(ctx, postgresUrl))
+	defer pgxPool.Close()
+
+	eventPublisherGateway := gateways.NewRabbitmqEventPublisherGateway(rabbitmqUrl, eventQueues)
+	defer func() {
+		_ = eventPublisherGateway.Close()
+	}()
+
+	relayOutboxEventsUsecase := usecases.NewRelayOutboxEventsUsecase(pgxPool, daos.NewOutboxEventDAO(pgxPool), eventPublisherGateway)
+
+	o.logger.Info("outbox relay started")
+
+	runPollLoop(ctx, o.logger, outboxRelayBatchSize, func(ctx context.Context) (int, error) {
+		output, err := relayOutboxEventsUsecase.Execute(ctx, usecases.RelayOutboxEventsUsecaseInput{
+			BatchSize: outboxRelayBatchSize,
+		})
+
+		if output.Published > 0 {
+			o.logger.Info("outbox events published", "count", output.Published)
+		}
+
+		return output.Published, err
+	})
+
+	o.logger.Info("outbox relay stopped")
+
+	return 0
+}
@@ -0,0 +1,45 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+const (
+	pollInterval   = time.Second
+	pollMaxBackoff = 30 * time.Second
+)
+
+// runPollLoop calls poll, which returns how many items it processed out of batchSize, until ctx
+// is cancelled. A full batch is followed by the next one right away, otherwise it waits for
+// pollInterval, and failures back off exponentially up to pollMaxBackoff.
+func runPollLoop(ctx context.Context, logger *slog.Logger, batchSize int, poll func(ctx context.Context) (int, error)) {
+	backoff := pollInterval
+
+	for {
+		processed, err := poll(ctx)
+		wait := pollInterval
+
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err != nil && !errors.Is(err, context.Canceled):
+			logger.Error("polling failed", "error", err.Error(), "retry_in", backoff.String())
+			wait = backoff
+			backoff = min(backoff*2, pollMaxBackoff)
+		case processed == batchSize:
+			backoff = pollInterval
+			continue
+		default:
+			backoff = pollInterval
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"log/slog"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
+	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
+	"github.com/gsaaraujo/pay-bank-api/internal/utils"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type reconcileReportDrift struct {
+	AccountId       uuid.UUID `json:"accountId"`
+	CustomerId      uuid.UUID `json:"customerId"`
+	CachedBalance   int64     `json:"cachedBalance"`
+	ExpectedBalance int64     `json:"expectedBalance"`
+	LedgerBalance   int64     `json:"ledgerBalance"`
+	Fixed           bool      `json:"fixed"`
+}
+
+type reconcileReportOrphanedTransaction struct {
+	TransactionId uuid.UUID `json:"transactionId"`
+	LedgerEntries int64     `json:"ledgerEntries"`
+}
+
+type reconcileReportMoneySupply struct {
+	TotalBalances int64 `json:"totalBalances"`
+	TotalGranted  int64 `json:"totalGranted"`
+	Balanced      bool  `json:"balanced"`
+}
+
+type reconcileReport struct {
+	Balanced             bool                                 `json:"balanced"`
+	CheckedAt            time.Time                            `json:"checkedAt"`
+	AccountsChecked      int                                  `json:"accountsChecked"`
+	Drifts               []reconcileReportDrift               `json:"drifts"`
+	OrphanedTransactions []reconcileReportOrphanedTransaction `json:"orphanedTransactions"`
+	MoneySupply          reconcileReportMoneySupply           `json:"moneySupply"`
+}
+
+// ReconcileCommand recomputes every account balance and checks the ledger invariants. It is
+// meant to run as a scheduled task: the report goes to stdout as JSON and the exit code is
+// non-zero whenever an invariant does not hold.
+type ReconcileCommand struct {
+	output io.Writer
+	logger *slog.Logger
+}
+
+func NewReconcileCommand() *ReconcileCommand {
+	return &ReconcileCommand{
+		output: os.Stdout,
+		logger: slog.New(slog.NewJSONHandler(os.Stderr, nil)),
+	}
+}
+
+func (r *ReconcileCommand) Run(args []string) (exitCode int) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			r.logger.Error("reconciliation failed", "error", rec, "stack_trace", string(debug.Stack()))
+			exitCode = 2
+		}
+	}()
+
+	flags := flag.NewFlagSet("reconcile", flag.ContinueOnError)
+	fix := flags.Bool("fix", false, "overwrite drifted cached balances with the recomputed value")
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	configGateway := utils.GetOrThrow(gateways.NewConfigGateway(context.Background(), time.Minute))
+	utils.ThrowOnError(gateways.ValidateConfig(context.Background(), configGateway, []string{"POSTGRES_URL"}))
+
+	postgresUrl := utils.GetOrThrow(configGateway.GetString(context.Background(), "POSTGRES_URL"))
+
+	pgxPool := utils.GetOrThrow(pgxpool.New(context.Background(), postgresUrl))
+	defer pgxPool.Close()
+
+	reconcileLedgerUsecase := usecases.NewReconcileLedgerUsecase(daos.NewReconciliationDAO(pgxPool))
+
+	output, err := reconcileLedgerUsecase.Execute(context.Background(), usecases.ReconcileLedgerUsecaseInput{
+		Fix: *fix,
+	})
+	if err != nil {
+		r.logger.Error("reconciliation failed", "error", err.Error())
+		return 2
+	}
+
+	report := reconcileReport{
+		Balanced:             output.Balanced(),
+		CheckedAt:            time.Now().UTC(),
+		AccountsChecked:      output.AccountsChecked,
+		Drifts:               []reconcileReportDrift{},
+		OrphanedTransactions: []reconcileReportOrphanedTransaction{},
+		MoneySupply: reconcileReportMoneySupply{
+			TotalBalances: output.TotalBalances,
+			TotalGranted:  output.TotalGranted,
+			Balanced:      output.TotalBalances == output.TotalGranted,
+		},
+	}
+
+	for _, drift := range output.Drifts {
+		report.Drifts = append(report.Drifts, reconcileReportDrift(drift))
+	}
+
+	for _, orphanedTransaction := range output.OrphanedTransactions {
+		report.OrphanedTransactions = append(report.OrphanedTransactions, reconcileReportOrphanedTransaction(orphanedTransaction))
+	}
+
+	encoder := json.NewEncoder(r.output)
+	encoder.SetIndent("", "  ")
+	utils.ThrowOnError(encoder.Encode(report))
+
+	if !report.Balanced {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,80 @@
+package internal
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
+	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
+	"github.com/gsaaraujo/pay-bank-api/internal/utils"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+// RevokeSessionsCommand signs a customer out of every device, e.g. after an account takeover.
+// Both the refresh tokens and the access tokens already issued stop working immediately.
+type RevokeSessionsCommand struct {
+	logger *slog.Logger
+}
+
+func NewRevokeSessionsCommand() *RevokeSessionsCommand {
+	return &RevokeSessionsCommand{
+		logger: slog.New(slog.NewJSONHandler(os.Stderr, nil)),
+	}
+}
+
+func (r *RevokeSessionsCommand) Run(args []string) (exitCode int) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			r.logger.Error("revoking sessions failed", "error", rec, "stack_trace", string(debug.Stack()))
+			exitCode = 2
+		}
+	}()
+
+	flags := flag.NewFlagSet("revoke-sessions", flag.ContinueOnError)
+	customerIdFlag := flags.String("customer-id", "", "id of the customer whose sessions are revoked")
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	customerId, err := uuid.Parse(*customerIdFlag)
+	if err != nil {
+		r.logger.Error("customer-id must be an uuid", "customer_id", *customerIdFlag)
+		return 2
+	}
+
+	configGateway := utils.GetOrThrow(gateways.NewConfigGateway(context.Background(), time.Minute))
+	utils.ThrowOnError(gateways.ValidateConfig(context.Background(), configGateway, []string{"POSTGRES_URL", "REDIS_URL"}))
+
+	postgresUrl := utils.GetOrThrow(configGateway.GetString(context.Background(), "POSTGRES_URL"))
+	redisUrl := utils.GetOrThrow(configGateway.GetString(context.Background(), "REDIS_URL"))
+
+	pgxPool := utils.GetOrThrow(pgxpool.New(context.Background(), postgresUrl))
+	defer pgxPool.Close()
+
+	redisClient := redis.NewClient(utils.GetOrThrow(redis.ParseURL(redisUrl)))
+	defer func() {
+		_ = redisClient.Close()
+	}()
+
+	revokeCustomerSessionsUsecase := usecases.NewRevokeCustomerSessionsUsecase(pgxPool, daos.NewCustomerDAO(pgxPool),
+		daos.NewRefreshTokenDAO(pgxPool), daos.NewRevokedAccessTokenDAO(redisClient))
+
+	err = revokeCustomerSessionsUsecase.Execute(context.Background(), usecases.RevokeCustomerSessionsUsecaseInput{
+		CustomerId: customerId,
+	})
+	if err != nil {
+		r.logger.Error("revoking sessions failed", "error", err.Error())
+		return 2
+	}
+
+	r.logger.Info("sessions revoked", "customer_id", customerId.String())
+	return 0
+}
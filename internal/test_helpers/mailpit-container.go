@@ -0,0 +1,53 @@
+package testhelpers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gsaaraujo/pay-bank-api/internal/utils"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// MailpitContainer is an SMTP server that keeps every email it receives and exposes them
+// through an HTTP API at ApiUrl.
+type MailpitContainer struct {
+	smtpHost string
+	smtpPort int
+	apiUrl   string
+}
+
+func NewMailpitContainer() MailpitContainer {
+	ctx := context.Background()
+
+	mailpitContainer := utils.GetOrThrow(testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		Started: true,
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        "axllent/mailpit:v1.27",
+			ExposedPorts: []string{"1025/tcp", "8025/tcp"},
+			WaitingFor:   wait.ForListeningPort("8025/tcp"),
+		},
+	}))
+
+	host := utils.GetOrThrow(mailpitContainer.Host(ctx))
+	smtpPort := utils.GetOrThrow(mailpitContainer.MappedPort(ctx, "1025/tcp"))
+	apiPort := utils.GetOrThrow(mailpitContainer.MappedPort(ctx, "8025/tcp"))
+
+	return MailpitContainer{
+		smtpHost: host,
+		smtpPort: smtpPort.Int(),
+		apiUrl:   fmt.Sprintf("http://%s:%s", host, apiPort.Port()),
+	}
+}
+
+func (m *MailpitContainer) SmtpHost() string {
+	return m.smtpHost
+}
+
+func (m *MailpitContainer) SmtpPort() int {
+	return m.smtpPort
+}
+
+func (m *MailpitContainer) ApiUrl() string {
+	return m.apiUrl
+}
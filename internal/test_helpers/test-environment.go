@@ -2,6 +2,8 @@ package testhelpers
 
 import (
 	"context"
+	"crypto/sha1"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -9,20 +11,38 @@ import (
 	"net/url"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
 	"github.com/gsaaraujo/pay-bank-api/internal"
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
 	"github.com/gsaaraujo/pay-bank-api/internal/utils"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rabbitmq/amqp091-go"
 	"github.com/redis/go-redis/v9"
 )
 
+// TestBreachedPasswords are in the breached passwords list of the test environment, even
+// though they follow the rest of the password policy.
+var TestBreachedPasswords = []string{"Password1", "Qwerty123"}
+
+// TestReceiptSigningKey signs the transaction receipts of the test environment.
+const TestReceiptSigningKey = "test-receipt-signing-key"
+
+// TestRateLimit is how many requests per minute every rate limit policy allows in the test
+// environment, high enough for the suites that do not reset Redis between tests.
+const TestRateLimit = 100
+
+var emailVerificationTokenRegexp = regexp.MustCompile(`verify your email address: (\S+)`)
+
 type TestEnvironment struct {
 	baseUrl                string
 	client                 *http.Client
+	httpServer             *internal.HttpServer
 	awsConfig              aws.Config
 	pgxPool                *pgxpool.Pool
 	redisClient            *redis.Client
@@ -32,6 +52,8 @@ type TestEnvironment struct {
 	wiremockContainerUrl   string
 	redisContainerUrl      string
 	rabbitmqContainerUrl   string
+	notificationsFile      string
+	breachedPasswordsFile  string
 }
 
 func NewTestEnvironment() *TestEnvironment {
@@ -45,6 +67,11 @@ func (t *TestEnvironment) Start() {
 	t.redisContainerUrl = NewRedisContainer().url
 	t.rabbitmqContainerUrl = NewRabbitmqContainer().url
 
+	tempDir := utils.GetOrThrow(os.MkdirTemp("", "pay-bank-api"))
+	t.notificationsFile = filepath.Join(tempDir, "notifications.jsonl")
+	t.breachedPasswordsFile = filepath.Join(tempDir, "breached-passwords.txt")
+	t.createBreachedPasswordsFile()
+
 	utils.ThrowOnError(os.Setenv("AWS_REGION", "us-east-1"))
 	utils.ThrowOnError(os.Setenv("AWS_ACCESS_KEY_ID", "test"))
 	utils.ThrowOnError(os.Setenv("AWS_SECRET_ACCESS_KEY", "test"))
@@ -79,12 +106,42 @@ func (t *TestEnvironment) createSecrets() {
 				"REDIS_URL": "%s",
 				"POSTGRES_URL": "%s",
 				"RABBITMQ_URL": "%s",
-				"ACCESS_TOKEN_SIGNING_KEY": "81c4a8d5b2554de4ba736e93255ba633"
+				"ACCESS_TOKEN_SIGNING_KEY_ID": "%s",
+				"ACCESS_TOKEN_SIGNING_PRIVATE_KEY": "%s",
+				"ACCESS_TOKEN_VERIFICATION_KEYS": "%s:%s",
+				"RECEIPT_SIGNING_KEY": "%s",
+				"TRANSFER_TOTP_THRESHOLD": 100000,
+				"NOTIFIER_PROVIDER": "file",
+				"NOTIFIER_FILE": "%s",
+				"BREACHED_PASSWORDS_FILE": "%s",
+				"WEBHOOK_ALLOW_HTTP": true,
+				"RATE_LIMIT_LOGIN_LIMIT": %d,
+				"RATE_LIMIT_LOGIN_TOTP_LIMIT": %d,
+				"RATE_LIMIT_DISABLE_TOTP_LIMIT": %d,
+				"RATE_LIMIT_SIGN_UP_LIMIT": %d,
+				"RATE_LIMIT_SIGN_UP_WINDOW_SECONDS": 60,
+				"RATE_LIMIT_FORGOT_PASSWORD_LIMIT": %d,
+				"RATE_LIMIT_RESEND_EMAIL_VERIFICATION_LIMIT": %d,
+				"RATE_LIMIT_TRANSFER_LIMIT": %d
 			}
-		`, t.redisContainerUrl, t.postgresContainerUrl, t.rabbitmqContainerUrl)),
+		`, t.redisContainerUrl, t.postgresContainerUrl, t.rabbitmqContainerUrl, TestAccessTokenSigningKeyId, TestAccessTokenSigningPrivateKey,
+			TestAccessTokenPreviousKeyId, TestAccessTokenPreviousPublicKey, TestReceiptSigningKey, t.notificationsFile, t.breachedPasswordsFile,
+			TestRateLimit, TestRateLimit, TestRateLimit, TestRateLimit, TestRateLimit, TestRateLimit,
+			TestRateLimit)),
 	}))
 }
 
+// createBreachedPasswordsFile lists TestBreachedPasswords in the Have I Been Pwned format.
+func (t *TestEnvironment) createBreachedPasswordsFile() {
+	var content strings.Builder
+
+	for _, password := range TestBreachedPasswords {
+		fmt.Fprintf(&content, "%X:%d\n", sha1.Sum([]byte(password)), 1000)
+	}
+
+	utils.ThrowOnError(os.WriteFile(t.breachedPasswordsFile, []byte(content.String()), 0o600))
+}
+
 func (t *TestEnvironment) runMigrations() error {
 	urlParsed := utils.GetOrThrow(url.Parse(t.postgresContainerUrl))
 
@@ -115,6 +172,7 @@ func (t *TestEnvironment) startHttpServer() {
 	httpServer.Ready()
 	server := httptest.NewServer(httpServer.Echo())
 
+	t.httpServer = httpServer
 	t.baseUrl = server.URL
 	t.client = server.Client()
 }
@@ -142,3 +200,74 @@ func (s *TestEnvironment) RedisClient() *redis.Client {
 func (s *TestEnvironment) RabbitmqConn() *amqp091.Connection {
 	return s.rabbitmqConn
 }
+
+func (s *TestEnvironment) RabbitmqContainerUrl() string {
+	return s.rabbitmqContainerUrl
+}
+
+// Notifications returns what the server sent through the notifier so far, oldest first. It
+// waits for the notifications still being sent in the background.
+func (s *TestEnvironment) Notifications() []gateways.Notification {
+	s.httpServer.WaitForNotifications()
+
+	content, err := os.ReadFile(s.notificationsFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return []gateways.Notification{}
+	}
+
+	utils.ThrowOnError(err)
+
+	notifications := []gateways.Notification{}
+
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		var notification gateways.Notification
+		utils.ThrowOnError(json.Unmarshal([]byte(line), &notification))
+		notifications = append(notifications, notification)
+	}
+
+	return notifications
+}
+
+// ClearNotifications forgets the notifications sent so far.
+func (s *TestEnvironment) ClearNotifications() {
+	s.httpServer.WaitForNotifications()
+
+	err := os.Remove(s.notificationsFile)
+	if !errors.Is(err, os.ErrNotExist) {
+		utils.ThrowOnError(err)
+	}
+}
+
+// BreakNotifier makes the notifier fail until ClearNotifications is called, by putting a
+// directory where it appends the notifications.
+func (s *TestEnvironment) BreakNotifier() {
+	s.ClearNotifications()
+	utils.ThrowOnError(os.Mkdir(s.notificationsFile, 0o700))
+}
+
+// EmailVerificationToken returns the token of the last verification email sent to email.
+func (s *TestEnvironment) EmailVerificationToken(email string) string {
+	notifications := s.Notifications()
+
+	for i := len(notifications) - 1; i >= 0; i-- {
+		if notifications[i].To != email {
+			continue
+		}
+
+		if token := emailVerificationTokenRegexp.FindStringSubmatch(notifications[i].Body); token != nil {
+			return token[1]
+		}
+	}
+
+	panic("no email verification was sent to " + email)
+}
+
+// VerifyEmail verifies the email address of a customer who signed up through the API.
+func (s *TestEnvironment) VerifyEmail(email string) {
+	response := utils.GetOrThrow(s.client.Get(s.baseUrl + "/v1/verify-email?token=" + url.QueryEscape(s.EmailVerificationToken(email))))
+	utils.ThrowOnError(response.Body.Close())
+
+	if response.StatusCode != 204 {
+		panic(fmt.Sprintf("verifying %s returned %d", email, response.StatusCode))
+	}
+}
@@ -1,20 +1,57 @@
 package testhelpers
 
 import (
+	"crypto/ed25519"
+	"encoding/base64"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
 	"github.com/gsaaraujo/pay-bank-api/internal/utils"
 )
 
+// The test environment signs with the first key and still accepts the previous one, whose
+// private seed is kept here only so tests can sign tokens with a rotated key.
+const (
+	TestAccessTokenSigningKeyId       = "test-key-2"
+	TestAccessTokenSigningPrivateKey  = "j1SDbI5Y7qVB8ZU/1CBEWz+KRr8LRW4SyonkVEj85LA="
+	TestAccessTokenSigningPublicKey   = "dfePGW/39XRonwbtbdxpzqsxQ7c0FIsh4+vacl8XPFY="
+	TestAccessTokenPreviousKeyId      = "test-key-1"
+	TestAccessTokenPreviousPrivateKey = "WfMvRG837OQzLm6v1XyrmTdw5Qy4eyCagrfep28jp5E="
+	TestAccessTokenPreviousPublicKey  = "V2a6t2pfc4ceM6PiiSPwcG5PFt9UFtCeabzboB1lyCE="
+)
+
 func TestGenerateAccessToken(customerId uuid.UUID) string {
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
-		Subject:   customerId.String(),
-		IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
-		ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(30 * time.Minute)),
+	return generateAccessToken(customerId, TestAccessTokenSigningKeyId, TestAccessTokenSigningPrivateKey, []string{usecases.RoleCustomer})
+}
+
+func TestGenerateAccessTokenWithKey(customerId uuid.UUID, keyId string, privateKey string) string {
+	return generateAccessToken(customerId, keyId, privateKey, []string{usecases.RoleCustomer})
+}
+
+func TestGenerateAccessTokenWithRoles(customerId uuid.UUID, roles ...string) string {
+	return generateAccessToken(customerId, TestAccessTokenSigningKeyId, TestAccessTokenSigningPrivateKey, roles)
+}
+
+func generateAccessToken(customerId uuid.UUID, keyId string, privateKey string, roles []string) string {
+	accessToken := jwt.NewWithClaims(jwt.SigningMethodEdDSA, usecases.JwtAccessTokenClaims{
+		Roles:     roles,
+		SessionId: uuid.NewString(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.Must(uuid.NewV7()).String(),
+			Subject:   customerId.String(),
+			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(30 * time.Minute)),
+		},
 	})
+	accessToken.Header["kid"] = keyId
 
-	acessTokenSigned := utils.GetOrThrow(accessToken.SignedString([]byte("81c4a8d5b2554de4ba736e93255ba633")))
+	seed := utils.GetOrThrow(base64.StdEncoding.DecodeString(privateKey))
+	acessTokenSigned := utils.GetOrThrow(accessToken.SignedString(ed25519.NewKeyFromSeed(seed)))
 	return acessTokenSigned
 }
+
+func TestAccessTokenVerificationKey() ed25519.PublicKey {
+	return utils.GetOrThrow(base64.StdEncoding.DecodeString(TestAccessTokenSigningPublicKey))
+}
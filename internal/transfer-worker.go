@@ -0,0 +1,157 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	domainerrors "github.com/gsaaraujo/pay-bank-api/internal/domain-errors"
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
+	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
+	"github.com/gsaaraujo/pay-bank-api/internal/utils"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	transferPrefetch = 10
+	// transferMaxAttempts is how many times a transfer message is handled before the transfer is
+	// marked as failed, when processing keeps failing for reasons other than a domain error.
+	transferMaxAttempts = 10
+	// transferRequeueAfter is how long a transfer may stay pending before its message is assumed
+	// lost and published again.
+	transferRequeueAfter     = 5 * time.Minute
+	transferRequeueBatchSize = 100
+)
+
+// transferQueue is shared by every transfer worker, each message going to one of them.
+var transferQueue = gateways.EventQueue{
+	Name:       "pay-bank.transfers",
+	EventTypes: []string{usecases.EventTypeTransferRequested},
+}
+
+// eventQueues are declared by the outbox relay too, so no event is published before its queue
+// exists.
+var eventQueues = []gateways.EventQueue{transferQueue}
+
+// TransferWorker processes the transfers accepted asynchronously until its context is
+// cancelled, reconnecting to RabbitMQ with exponential backoff whenever the connection is lost.
+// Meanwhile it requeues the transfers left pending for longer than transferRequeueAfter.
+type TransferWorker struct {
+	logger *slog.Logger
+}
+
+func NewTransferWorker() *TransferWorker {
+	return &TransferWorker{
+		logger: slog.New(slog.NewJSONHandler(os.Stderr, nil)),
+	}
+}
+
+func (t *TransferWorker) Run(ctx context.Context) (exitCode int) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			t.logger.Error("transfer processing failed", "error", rec, "stack_trace", string(debug.Stack()))
+			exitCode = 2
+		}
+	}()
+
+	configGateway := utils.GetOrThrow(gateways.NewConfigGateway(ctx, time.Minute))
+	utils.ThrowOnError(gateways.ValidateConfig(ctx, configGateway, []string{"POSTGRES_URL", "RABBITMQ_URL"}))
+
+	postgresUrl := utils.GetOrThrow(configGateway.GetString(ctx, "POSTGRES_URL"))
+	rabbitmqUrl := utils.GetOrThrow(configGateway.GetString(ctx, "RABBITMQ_URL"))
+
+	pgxPool := utils.GetOrThrow(pgxpool.New(ctx, postgresUrl))
+	defer pgxPool.Close()
+
+	processTransferUsecase := usecases.NewProcessTransferUsecase(pgxPool, daos.NewAccountDAO(pgxPool), daos.NewLedgerDAO(pgxPool),
+		daos.NewTransferDAO(pgxPool), daos.NewOutboxEventDAO(pgxPool), daos.NewWebhookDeliveryDAO(pgxPool))
+	failTransferUsecase := usecases.NewFailTransferUsecase(pgxPool, daos.NewTransferDAO(pgxPool))
+	requeuePendingTransfersUsecase := usecases.NewRequeuePendingTransfersUsecase(pgxPool, daos.NewTransferDAO(pgxPool),
+		daos.NewOutboxEventDAO(pgxPool))
+	eventConsumerGateway := gateways.NewRabbitmqEventConsumerGateway(rabbitmqUrl, transferPrefetch)
+
+	handler := func(ctx context.Context, event gateways.Event) error {
+		var transferRequestedEvent usecases.TransferRequestedEvent
+
+		// A message that cannot be read would fail on every redelivery, so it is dropped.
+		if err := json.Unmarshal(event.Payload, &transferRequestedEvent); err != nil {
+			t.logger.Error("dropping unreadable transfer message", "event_id", event.Id.String(), "error", err.Error())
+			return nil
+		}
+
+		output, err := processTransferUsecase.Execute(ctx, usecases.ProcessTransferUsecaseInput{
+			TransferId: transferRequestedEvent.TransferId,
+		})
+
+		var domainError *domainerrors.DomainError
+		if errors.As(err, &domainError) {
+			t.logger.Error("dropping transfer message", "transfer_id", transferRequestedEvent.TransferId.String(), "error", err.Error())
+			return nil
+		}
+
+		if err != nil && event.Attempt < transferMaxAttempts {
+			return err
+		}
+
+		if err != nil {
+			t.logger.Error("giving up on transfer", "transfer_id", transferRequestedEvent.TransferId.String(), "attempt", event.Attempt,
+				"error", err.Error())
+
+			return failTransferUsecase.Execute(ctx, usecases.FailTransferUsecaseInput{
+				TransferId: transferRequestedEvent.TransferId,
+			})
+		}
+
+		t.logger.Info("transfer processed", "transfer_id", transferRequestedEvent.TransferId.String(), "status", output.Status)
+
+		return nil
+	}
+
+	t.logger.Info("transfer processing started")
+
+	var requeueWaitGroup sync.WaitGroup
+	requeueWaitGroup.Go(func() {
+		runPollLoop(ctx, t.logger, transferRequeueBatchSize, func(ctx context.Context) (int, error) {
+			output, err := requeuePendingTransfersUsecase.Execute(ctx, usecases.RequeuePendingTransfersUsecaseInput{
+				PendingFor: transferRequeueAfter,
+				BatchSize:  transferRequeueBatchSize,
+			})
+
+			if output.Requeued > 0 {
+				t.logger.Info("pending transfers requeued", "count", output.Requeued)
+			}
+
+			return output.Requeued, err
+		})
+	})
+
+	backoff := pollInterval
+
+	for {
+		err := eventConsumerGateway.Consume(ctx, transferQueue, handler)
+
+		if ctx.Err() != nil {
+			break
+		}
+
+		t.logger.Error("consuming transfers failed", "error", err, "retry_in", backoff.String())
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(backoff):
+		}
+
+		backoff = min(backoff*2, pollMaxBackoff)
+	}
+
+	requeueWaitGroup.Wait()
+	t.logger.Info("transfer processing stopped")
+
+	return 0
+}
@@ -0,0 +1,140 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AcceptTransferUsecaseInput struct {
+	SenderCustomerId   uuid.UUID
+	ReceiverCustomerId uuid.UUID
+	IdempotencyKey     uuid.UUID
+	Amount             int64
+	// TotpCode is only checked when Amount is over the TRANSFER_TOTP_THRESHOLD.
+	TotpCode string
+}
+
+type AcceptTransferUsecaseOutput struct {
+	TransferId uuid.UUID
+	Replayed   bool
+	// Outcome is TransferOutcomeCompleted or TransferOutcomeAccepted. A
+	// replayed request reports the outcome of the first one, which may have been made the other
+	// way.
+	Outcome string
+}
+
+type AcceptTransferUsecase struct {
+	pgxPool           *pgxpool.Pool
+	customerDAO       daos.CustomerDAO
+	accountDAO        daos.AccountDAO
+	idempotencyKeyDAO daos.IdempotencyKeyDAO
+	customerTotpDAO   daos.CustomerTotpDAO
+	transferDAO       daos.TransferDAO
+	outboxEventDAO    daos.OutboxEventDAO
+	loginAttemptDAO   daos.LoginAttemptDAO
+	loginLockoutDAO   daos.LoginLockoutDAO
+	configGateway     gateways.ConfigGateway
+}
+
+func NewAcceptTransferUsecase(pgxPool *pgxpool.Pool, customerDAO daos.CustomerDAO, accountDAO daos.AccountDAO,
+	idempotencyKeyDAO daos.IdempotencyKeyDAO, customerTotpDAO daos.CustomerTotpDAO, transferDAO daos.TransferDAO, outboxEventDAO daos.OutboxEventDAO,
+	loginAttemptDAO daos.LoginAttemptDAO, loginLockoutDAO daos.LoginLockoutDAO,
+	configGateway gateways.ConfigGateway) AcceptTransferUsecase {
+	return AcceptTransferUsecase{pgxPool, customerDAO, accountDAO, idempotencyKeyDAO, customerTotpDAO, transferDAO, outboxEventDAO,
+		loginAttemptDAO, loginLockoutDAO, configGateway}
+}
+
+// Execute records the transfer as pending and queues it, through the outbox, for
+// ProcessTransferUsecase. Everything but the balance is checked here, including the two-factor
+// code, which would have expired by the time the transfer is processed.
+func (a *AcceptTransferUsecase) Execute(ctx context.Context, input AcceptTransferUsecaseInput) (AcceptTransferUsecaseOutput, error) {
+	if _, _, err := findTransferAccounts(ctx, a.customerDAO, a.accountDAO, input.SenderCustomerId, input.ReceiverCustomerId, input.Amount); err != nil {
+		return AcceptTransferUsecaseOutput{}, err
+	}
+
+	tx, err := a.pgxPool.Begin(ctx)
+	if err != nil {
+		return AcceptTransferUsecaseOutput{}, err
+	}
+
+	defer func() {
+		_ = tx.Rollback(context.WithoutCancel(ctx))
+	}()
+
+	idempotencyKey, err := claimTransferIdempotencyKey(ctx, tx, a.idempotencyKeyDAO, input.SenderCustomerId, input.IdempotencyKey,
+		input.ReceiverCustomerId, input.Amount)
+	if err != nil {
+		return AcceptTransferUsecaseOutput{}, err
+	}
+
+	if idempotencyKey != nil {
+		return AcceptTransferUsecaseOutput{
+			TransferId: *idempotencyKey.TransferId,
+			Replayed:   true,
+			Outcome:    *idempotencyKey.Outcome,
+		}, nil
+	}
+
+	err = verifyTransferTotp(ctx, tx, a.configGateway, a.customerTotpDAO, a.loginAttemptDAO, a.loginLockoutDAO, input.SenderCustomerId,
+		input.Amount, input.TotpCode)
+	if err != nil {
+		return AcceptTransferUsecaseOutput{}, err
+	}
+
+	transferSchema := daos.TransferSchema{
+		Id:                 uuid.New(),
+		SenderCustomerId:   input.SenderCustomerId,
+		ReceiverCustomerId: input.ReceiverCustomerId,
+		IdempotencyKey:     input.IdempotencyKey.String(),
+		Amount:             input.Amount,
+		Status:             daos.TransferStatusPending,
+		CreatedAt:          time.Now().UTC(),
+		UpdatedAt:          time.Now().UTC(),
+	}
+
+	if err := a.transferDAO.Create(ctx, tx, transferSchema); err != nil {
+		return AcceptTransferUsecaseOutput{}, err
+	}
+
+	outboxEventSchema, err := newOutboxEvent(EventTypeTransferRequested, transferSchema.Id, TransferRequestedEvent{
+		TransferId:         transferSchema.Id,
+		SenderCustomerId:   transferSchema.SenderCustomerId,
+		ReceiverCustomerId: transferSchema.ReceiverCustomerId,
+		Amount:             transferSchema.Amount,
+		RequestedAt:        transferSchema.CreatedAt,
+	})
+	if err != nil {
+		return AcceptTransferUsecaseOutput{}, err
+	}
+
+	if err := a.outboxEventDAO.Create(ctx, tx, outboxEventSchema); err != nil {
+		return AcceptTransferUsecaseOutput{}, err
+	}
+
+	output := AcceptTransferUsecaseOutput{
+		TransferId: transferSchema.Id,
+		Outcome:    TransferOutcomeAccepted,
+	}
+
+	err = a.idempotencyKeyDAO.UpdateResult(ctx, tx, daos.IdempotencyKeySchema{
+		CustomerId:     input.SenderCustomerId,
+		IdempotencyKey: input.IdempotencyKey.String(),
+		TransferId:     &output.TransferId,
+		Outcome:        &output.Outcome,
+		UpdatedAt:      time.Now().UTC(),
+	})
+	if err != nil {
+		return AcceptTransferUsecaseOutput{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return AcceptTransferUsecaseOutput{}, err
+	}
+
+	return output, nil
+}
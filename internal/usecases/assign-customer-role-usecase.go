@@ -0,0 +1,43 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	domainerrors "github.com/gsaaraujo/pay-bank-api/internal/domain-errors"
+)
+
+type AssignCustomerRoleUsecaseInput struct {
+	CustomerId uuid.UUID
+	Role       string
+}
+
+type AssignCustomerRoleUsecase struct {
+	customerDAO     daos.CustomerDAO
+	customerRoleDAO daos.CustomerRoleDAO
+}
+
+func NewAssignCustomerRoleUsecase(customerDAO daos.CustomerDAO, customerRoleDAO daos.CustomerRoleDAO) AssignCustomerRoleUsecase {
+	return AssignCustomerRoleUsecase{customerDAO, customerRoleDAO}
+}
+
+// Execute grants a staff role. It shows up in the customer's next access token, on login or
+// refresh.
+func (a *AssignCustomerRoleUsecase) Execute(ctx context.Context, input AssignCustomerRoleUsecaseInput) error {
+	customerSchema, err := a.customerDAO.FindOneById(ctx, input.CustomerId)
+	if err != nil {
+		return err
+	}
+
+	if customerSchema == nil {
+		return domainerrors.ErrCustomerNotFound
+	}
+
+	return a.customerRoleDAO.Create(ctx, daos.CustomerRoleSchema{
+		CustomerId: input.CustomerId,
+		Role:       input.Role,
+		CreatedAt:  time.Now().UTC(),
+	})
+}
@@ -0,0 +1,84 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	domainerrors "github.com/gsaaraujo/pay-bank-api/internal/domain-errors"
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type ChangePasswordUsecaseInput struct {
+	CustomerId      uuid.UUID
+	CurrentPassword string
+	NewPassword     string
+}
+
+type ChangePasswordUsecase struct {
+	pgxPool                  *pgxpool.Pool
+	customerDAO              daos.CustomerDAO
+	refreshTokenDAO          daos.RefreshTokenDAO
+	revokedAccessTokenDAO    daos.RevokedAccessTokenDAO
+	passwordPolicyGateway    gateways.PasswordPolicyGateway
+	breachedPasswordsGateway gateways.BreachedPasswordsGateway
+}
+
+func NewChangePasswordUsecase(pgxPool *pgxpool.Pool, customerDAO daos.CustomerDAO, refreshTokenDAO daos.RefreshTokenDAO,
+	revokedAccessTokenDAO daos.RevokedAccessTokenDAO, passwordPolicyGateway gateways.PasswordPolicyGateway,
+	breachedPasswordsGateway gateways.BreachedPasswordsGateway) ChangePasswordUsecase {
+	return ChangePasswordUsecase{pgxPool, customerDAO, refreshTokenDAO, revokedAccessTokenDAO, passwordPolicyGateway,
+		breachedPasswordsGateway}
+}
+
+// Execute replaces the password of a signed in customer, who has to know the current one, and
+// signs the customer out everywhere, the current session included.
+func (c *ChangePasswordUsecase) Execute(ctx context.Context, input ChangePasswordUsecaseInput) error {
+	customerSchema, err := c.customerDAO.FindOneById(ctx, input.CustomerId)
+	if err != nil {
+		return err
+	}
+
+	if customerSchema == nil {
+		return errors.New("customer was not found")
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(customerSchema.Password), []byte(input.CurrentPassword))
+	if err != nil {
+		return domainerrors.ErrCurrentPasswordIncorrect
+	}
+
+	err = validatePassword(ctx, c.passwordPolicyGateway, c.breachedPasswordsGateway, "newPassword", input.NewPassword,
+		customerSchema.Name, customerSchema.Email)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := hashPassword(input.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	tx, err := c.pgxPool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback(context.WithoutCancel(ctx))
+	}()
+
+	if err := c.customerDAO.UpdatePassword(ctx, tx, input.CustomerId, hashedPassword, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	if err := revokeAllSessions(ctx, tx, c.refreshTokenDAO, c.revokedAccessTokenDAO, input.CustomerId); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
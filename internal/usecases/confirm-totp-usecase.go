@@ -0,0 +1,88 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	domainerrors "github.com/gsaaraujo/pay-bank-api/internal/domain-errors"
+	"github.com/gsaaraujo/pay-bank-api/internal/utils"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ConfirmTotpUsecaseInput struct {
+	CustomerId uuid.UUID
+	Code       string
+}
+
+type ConfirmTotpUsecaseOutput struct {
+	RecoveryCodes []string
+}
+
+type ConfirmTotpUsecase struct {
+	pgxPool             *pgxpool.Pool
+	customerTotpDAO     daos.CustomerTotpDAO
+	totpRecoveryCodeDAO daos.TotpRecoveryCodeDAO
+}
+
+func NewConfirmTotpUsecase(pgxPool *pgxpool.Pool, customerTotpDAO daos.CustomerTotpDAO,
+	totpRecoveryCodeDAO daos.TotpRecoveryCodeDAO) ConfirmTotpUsecase {
+	return ConfirmTotpUsecase{pgxPool, customerTotpDAO, totpRecoveryCodeDAO}
+}
+
+// Execute enables two-factor authentication once the customer proves the authenticator works,
+// and returns the recovery codes. They are shown this one time only.
+func (c *ConfirmTotpUsecase) Execute(ctx context.Context, input ConfirmTotpUsecaseInput) (ConfirmTotpUsecaseOutput, error) {
+	tx, err := c.pgxPool.Begin(ctx)
+	if err != nil {
+		return ConfirmTotpUsecaseOutput{}, err
+	}
+
+	defer func() {
+		_ = tx.Rollback(context.WithoutCancel(ctx))
+	}()
+
+	customerTotpSchema, err := c.customerTotpDAO.FindOneByCustomerIdForUpdate(ctx, tx, input.CustomerId)
+	if err != nil {
+		return ConfirmTotpUsecaseOutput{}, err
+	}
+
+	if customerTotpSchema == nil {
+		return ConfirmTotpUsecaseOutput{}, domainerrors.ErrTotpNotEnrolled
+	}
+
+	if customerTotpSchema.ConfirmedAt != nil {
+		return ConfirmTotpUsecaseOutput{}, domainerrors.ErrTotpAlreadyEnabled
+	}
+
+	step, ok := utils.ValidateTotpCode(customerTotpSchema.Secret, input.Code, time.Now(), customerTotpSchema.LastUsedStep)
+	if !ok {
+		return ConfirmTotpUsecaseOutput{}, domainerrors.ErrTotpCodeInvalid
+	}
+
+	if err := c.customerTotpDAO.Confirm(ctx, tx, input.CustomerId, step, time.Now().UTC()); err != nil {
+		return ConfirmTotpUsecaseOutput{}, err
+	}
+
+	recoveryCodes, totpRecoveryCodesSchema, err := newTotpRecoveryCodes(input.CustomerId)
+	if err != nil {
+		return ConfirmTotpUsecaseOutput{}, err
+	}
+
+	if err := c.totpRecoveryCodeDAO.DeleteAllByCustomerId(ctx, tx, input.CustomerId); err != nil {
+		return ConfirmTotpUsecaseOutput{}, err
+	}
+
+	if err := c.totpRecoveryCodeDAO.CreateAll(ctx, tx, totpRecoveryCodesSchema); err != nil {
+		return ConfirmTotpUsecaseOutput{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return ConfirmTotpUsecaseOutput{}, err
+	}
+
+	return ConfirmTotpUsecaseOutput{
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
@@ -0,0 +1,73 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	domainerrors "github.com/gsaaraujo/pay-bank-api/internal/domain-errors"
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
+)
+
+type CreateWebhookUsecaseInput struct {
+	CustomerId uuid.UUID
+	Url        string
+}
+
+type CreateWebhookUsecaseOutput struct {
+	Webhook Webhook
+	Secret  string
+}
+
+type CreateWebhookUsecase struct {
+	webhookDAO    daos.WebhookDAO
+	configGateway gateways.ConfigGateway
+}
+
+func NewCreateWebhookUsecase(webhookDAO daos.WebhookDAO, configGateway gateways.ConfigGateway) CreateWebhookUsecase {
+	return CreateWebhookUsecase{webhookDAO, configGateway}
+}
+
+// Execute registers the webhook, which receives every event from then on. The secret deliveries
+// are signed with is only returned here.
+func (c *CreateWebhookUsecase) Execute(ctx context.Context, input CreateWebhookUsecaseInput) (CreateWebhookUsecaseOutput, error) {
+	if err := validateWebhookUrl(ctx, c.configGateway, input.Url); err != nil {
+		return CreateWebhookUsecaseOutput{}, err
+	}
+
+	webhooksSchema, err := c.webhookDAO.FindAllByCustomerId(ctx, input.CustomerId)
+	if err != nil {
+		return CreateWebhookUsecaseOutput{}, err
+	}
+
+	if len(webhooksSchema) >= webhookMaxPerCustomer {
+		return CreateWebhookUsecaseOutput{}, domainerrors.ErrWebhookLimitReached
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return CreateWebhookUsecaseOutput{}, err
+	}
+
+	webhookSchema := daos.WebhookSchema{
+		Id:         uuid.New(),
+		CustomerId: input.CustomerId,
+		Url:        input.Url,
+		Secret:     secret,
+		CreatedAt:  time.Now().UTC(),
+	}
+
+	if err := c.webhookDAO.Create(ctx, webhookSchema); err != nil {
+		return CreateWebhookUsecaseOutput{}, err
+	}
+
+	return CreateWebhookUsecaseOutput{
+		Webhook: Webhook{
+			Id:        webhookSchema.Id,
+			Url:       webhookSchema.Url,
+			CreatedAt: webhookSchema.CreatedAt,
+		},
+		Secret: secret,
+	}, nil
+}
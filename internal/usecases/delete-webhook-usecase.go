@@ -0,0 +1,46 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	domainerrors "github.com/gsaaraujo/pay-bank-api/internal/domain-errors"
+)
+
+type DeleteWebhookUsecaseInput struct {
+	CustomerId uuid.UUID
+	WebhookId  uuid.UUID
+}
+
+type DeleteWebhookUsecase struct {
+	webhookDAO daos.WebhookDAO
+}
+
+func NewDeleteWebhookUsecase(webhookDAO daos.WebhookDAO) DeleteWebhookUsecase {
+	return DeleteWebhookUsecase{webhookDAO}
+}
+
+// Execute deletes the webhook with its deliveries, so pending ones are never attempted. A
+// webhook of another customer is reported as not found.
+func (d *DeleteWebhookUsecase) Execute(ctx context.Context, input DeleteWebhookUsecaseInput) error {
+	webhookSchema, err := d.webhookDAO.FindOneById(ctx, input.WebhookId)
+	if err != nil {
+		return err
+	}
+
+	if webhookSchema == nil || webhookSchema.CustomerId != input.CustomerId {
+		return domainerrors.ErrWebhookNotFound
+	}
+
+	deleted, err := d.webhookDAO.Delete(ctx, input.WebhookId)
+	if err != nil {
+		return err
+	}
+
+	if !deleted {
+		return domainerrors.ErrWebhookNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
+	"github.com/gsaaraujo/pay-bank-api/internal/utils"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type DeliverWebhooksUsecaseInput struct {
+	BatchSize int
+}
+
+type DeliverWebhooksUsecaseOutput struct {
+	Delivered int
+	Failed    int
+}
+
+type DeliverWebhooksUsecase struct {
+	pgxPool            *pgxpool.Pool
+	webhookDAO         daos.WebhookDAO
+	webhookDeliveryDAO daos.WebhookDeliveryDAO
+	webhookGateway     gateways.WebhookGateway
+}
+
+func NewDeliverWebhooksUsecase(pgxPool *pgxpool.Pool, webhookDAO daos.WebhookDAO, webhookDeliveryDAO daos.WebhookDeliveryDAO,
+	webhookGateway gateways.WebhookGateway) DeliverWebhooksUsecase {
+	return DeliverWebhooksUsecase{pgxPool, webhookDAO, webhookDeliveryDAO, webhookGateway}
+}
+
+// Execute attempts up to BatchSize due deliveries, one at a time. A 2xx response delivers it;
+// anything else is retried with exponential backoff until webhookDeliveryMaxAttempts is
+// reached, when the delivery is dead lettered.
+func (d *DeliverWebhooksUsecase) Execute(ctx context.Context, input DeliverWebhooksUsecaseInput) (DeliverWebhooksUsecaseOutput, error) {
+	output := DeliverWebhooksUsecaseOutput{}
+
+	for range input.BatchSize {
+		attempted, delivered, err := d.deliverNext(ctx)
+		if err != nil {
+			return output, err
+		}
+
+		if !attempted {
+			break
+		}
+
+		if delivered {
+			output.Delivered++
+		} else {
+			output.Failed++
+		}
+	}
+
+	return output, nil
+}
+
+// deliverNext keeps the delivery locked while it is attempted, so no other worker attempts it
+// at the same time.
+func (d *DeliverWebhooksUsecase) deliverNext(ctx context.Context) (bool, bool, error) {
+	tx, err := d.pgxPool.Begin(ctx)
+	if err != nil {
+		return false, false, err
+	}
+
+	defer func() {
+		_ = tx.Rollback(context.WithoutCancel(ctx))
+	}()
+
+	webhookDeliverySchema, err := d.webhookDeliveryDAO.FindOneDueForUpdate(ctx, tx, time.Now().UTC())
+	if err != nil {
+		return false, false, err
+	}
+
+	if webhookDeliverySchema == nil {
+		return false, false, nil
+	}
+
+	webhookSchema, err := d.webhookDAO.FindOneById(ctx, webhookDeliverySchema.WebhookId)
+	if err != nil {
+		return false, false, err
+	}
+
+	if webhookSchema == nil {
+		return false, false, errors.New("webhook was not found")
+	}
+
+	attemptedAt := time.Now().UTC()
+
+	statusCode, err := d.webhookGateway.Send(ctx, gateways.WebhookRequest{
+		Url:       webhookSchema.Url,
+		Secret:    webhookSchema.Secret,
+		MessageId: webhookDeliverySchema.Id,
+		Timestamp: attemptedAt,
+		Payload:   webhookDeliverySchema.Payload,
+	})
+
+	// An attempt cut short by shutting down is not counted.
+	if ctx.Err() != nil {
+		return false, false, ctx.Err()
+	}
+
+	delivered := err == nil && statusCode >= 200 && statusCode < 300
+
+	webhookDeliverySchema.Attempts++
+	webhookDeliverySchema.LastAttemptedAt = &attemptedAt
+	webhookDeliverySchema.LastResponseStatus = nil
+	webhookDeliverySchema.LastError = nil
+	webhookDeliverySchema.NextAttemptAt = nil
+
+	if err != nil {
+		webhookDeliverySchema.LastError = utils.NewPointer(err.Error())
+	} else {
+		webhookDeliverySchema.LastResponseStatus = &statusCode
+	}
+
+	switch {
+	case delivered:
+		webhookDeliverySchema.Status = daos.WebhookDeliveryStatusDelivered
+		webhookDeliverySchema.DeliveredAt = &attemptedAt
+	case webhookDeliverySchema.Attempts >= webhookDeliveryMaxAttempts:
+		webhookDeliverySchema.Status = daos.WebhookDeliveryStatusDeadLettered
+	default:
+		webhookDeliverySchema.NextAttemptAt = utils.NewPointer(attemptedAt.Add(webhookRetryDelay(webhookDeliverySchema.Attempts)))
+	}
+
+	// The outcome is recorded even if ctx is cancelled from here on, since the webhook has
+	// already been called.
+	if err := d.webhookDeliveryDAO.UpdateAttempt(context.WithoutCancel(ctx), tx, *webhookDeliverySchema); err != nil {
+		return false, false, err
+	}
+
+	if err := tx.Commit(context.WithoutCancel(ctx)); err != nil {
+		return false, false, err
+	}
+
+	return true, delivered, nil
+}
@@ -0,0 +1,86 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	domainerrors "github.com/gsaaraujo/pay-bank-api/internal/domain-errors"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type DisableTotpUsecaseInput struct {
+	CustomerId uuid.UUID
+	Code       string
+}
+
+type DisableTotpUsecase struct {
+	pgxPool             *pgxpool.Pool
+	customerTotpDAO     daos.CustomerTotpDAO
+	totpRecoveryCodeDAO daos.TotpRecoveryCodeDAO
+	loginAttemptDAO     daos.LoginAttemptDAO
+	loginLockoutDAO     daos.LoginLockoutDAO
+}
+
+func NewDisableTotpUsecase(pgxPool *pgxpool.Pool, customerTotpDAO daos.CustomerTotpDAO, totpRecoveryCodeDAO daos.TotpRecoveryCodeDAO,
+	loginAttemptDAO daos.LoginAttemptDAO, loginLockoutDAO daos.LoginLockoutDAO) DisableTotpUsecase {
+	return DisableTotpUsecase{pgxPool, customerTotpDAO, totpRecoveryCodeDAO, loginAttemptDAO, loginLockoutDAO}
+}
+
+// Execute removes the authenticator and its recovery codes. It takes a current code or a
+// recovery code, so a stolen access token alone cannot turn two-factor authentication off, and
+// wrong codes count towards the lockout of the customer, as they do when logging in.
+func (d *DisableTotpUsecase) Execute(ctx context.Context, input DisableTotpUsecaseInput) error {
+	attempt, err := reserveTotpAttempt(ctx, d.loginAttemptDAO, d.loginLockoutDAO, input.CustomerId)
+	if err != nil {
+		return err
+	}
+
+	tx, err := d.pgxPool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback(context.WithoutCancel(ctx))
+	}()
+
+	customerTotpSchema, err := d.customerTotpDAO.FindOneByCustomerIdForUpdate(ctx, tx, input.CustomerId)
+	if err != nil {
+		return err
+	}
+
+	if customerTotpSchema == nil || customerTotpSchema.ConfirmedAt == nil {
+		return domainerrors.ErrTotpNotEnabled
+	}
+
+	accepted, err := useTotpCode(ctx, tx, d.customerTotpDAO, customerTotpSchema, input.Code)
+	if err != nil {
+		return err
+	}
+
+	if !accepted {
+		accepted, err = useTotpRecoveryCode(ctx, tx, d.totpRecoveryCodeDAO, input.CustomerId, input.Code)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !accepted {
+		return attempt.fail(ctx)
+	}
+
+	if err := d.totpRecoveryCodeDAO.DeleteAllByCustomerId(ctx, tx, input.CustomerId); err != nil {
+		return err
+	}
+
+	if err := d.customerTotpDAO.DeleteOneByCustomerId(ctx, tx, input.CustomerId); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	return attempt.succeed(ctx)
+}
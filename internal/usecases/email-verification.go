@@ -0,0 +1,50 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
+	"github.com/jackc/pgx/v5"
+)
+
+const emailVerificationTokenTTL = 24 * time.Hour
+
+// newEmailVerificationToken replaces any verification token sent to the customer before with a
+// new one, which is returned so it can be sent once tx commits.
+func newEmailVerificationToken(ctx context.Context, tx pgx.Tx, emailVerificationTokenDAO daos.EmailVerificationTokenDAO,
+	customerId uuid.UUID) (string, error) {
+	token, err := newOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	if err := emailVerificationTokenDAO.MarkAllAsUsedByCustomerId(ctx, tx, customerId, time.Now().UTC()); err != nil {
+		return "", err
+	}
+
+	err = emailVerificationTokenDAO.Create(ctx, tx, daos.EmailVerificationTokenSchema{
+		Id:         uuid.New(),
+		CustomerId: customerId,
+		TokenHash:  hashOpaqueToken(token),
+		ExpiresAt:  time.Now().UTC().Add(emailVerificationTokenTTL),
+		CreatedAt:  time.Now().UTC(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+func sendEmailVerification(ctx context.Context, notifierGateway gateways.NotifierGateway, email string, token string) error {
+	return notifierGateway.Send(ctx, gateways.Notification{
+		To:      email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Use this token to verify your email address: %s\n\nIt expires in %d hours. If you did not sign up, ignore this message.",
+			token, int(emailVerificationTokenTTL.Hours())),
+	})
+}
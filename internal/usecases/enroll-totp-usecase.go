@@ -0,0 +1,72 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	domainerrors "github.com/gsaaraujo/pay-bank-api/internal/domain-errors"
+	"github.com/gsaaraujo/pay-bank-api/internal/utils"
+)
+
+type EnrollTotpUsecaseInput struct {
+	CustomerId uuid.UUID
+}
+
+type EnrollTotpUsecaseOutput struct {
+	Secret string
+	Uri    string
+}
+
+type EnrollTotpUsecase struct {
+	customerDAO     daos.CustomerDAO
+	customerTotpDAO daos.CustomerTotpDAO
+}
+
+func NewEnrollTotpUsecase(customerDAO daos.CustomerDAO, customerTotpDAO daos.CustomerTotpDAO) EnrollTotpUsecase {
+	return EnrollTotpUsecase{customerDAO, customerTotpDAO}
+}
+
+// Execute starts the enrollment of an authenticator. Two-factor authentication is only enabled
+// once a first code is confirmed; enrolling again before that replaces the secret.
+func (e *EnrollTotpUsecase) Execute(ctx context.Context, input EnrollTotpUsecaseInput) (EnrollTotpUsecaseOutput, error) {
+	customerSchema, err := e.customerDAO.FindOneById(ctx, input.CustomerId)
+	if err != nil {
+		return EnrollTotpUsecaseOutput{}, err
+	}
+
+	if customerSchema == nil {
+		return EnrollTotpUsecaseOutput{}, errors.New("customer was not found")
+	}
+
+	customerTotpSchema, err := e.customerTotpDAO.FindOneByCustomerId(ctx, input.CustomerId)
+	if err != nil {
+		return EnrollTotpUsecaseOutput{}, err
+	}
+
+	if customerTotpSchema != nil && customerTotpSchema.ConfirmedAt != nil {
+		return EnrollTotpUsecaseOutput{}, domainerrors.ErrTotpAlreadyEnabled
+	}
+
+	secret, err := utils.GenerateTotpSecret()
+	if err != nil {
+		return EnrollTotpUsecaseOutput{}, err
+	}
+
+	err = e.customerTotpDAO.CreateOrReplacePending(ctx, daos.CustomerTotpSchema{
+		CustomerId: input.CustomerId,
+		Secret:     secret,
+		CreatedAt:  time.Now().UTC(),
+		UpdatedAt:  time.Now().UTC(),
+	})
+	if err != nil {
+		return EnrollTotpUsecaseOutput{}, err
+	}
+
+	return EnrollTotpUsecaseOutput{
+		Secret: secret,
+		Uri:    utils.TotpUri(totpIssuer, customerSchema.Email, secret),
+	}, nil
+}
@@ -0,0 +1,61 @@
+package usecases
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+)
+
+// Event types double as routing keys on the events exchange.
+const (
+	EventTypeCustomerSignedUp  = "customer.signed_up"
+	EventTypeTransferRequested = "transfer.requested"
+	EventTypeTransferCompleted = "transfer.completed"
+)
+
+type CustomerSignedUpEvent struct {
+	CustomerId uuid.UUID `json:"customerId"`
+	AccountId  uuid.UUID `json:"accountId"`
+	Name       string    `json:"name"`
+	Email      string    `json:"email"`
+	SignedUpAt time.Time `json:"signedUpAt"`
+}
+
+// TransferRequestedEvent is the message of a transfer accepted to be processed
+// asynchronously.
+type TransferRequestedEvent struct {
+	TransferId         uuid.UUID `json:"transferId"`
+	SenderCustomerId   uuid.UUID `json:"senderCustomerId"`
+	ReceiverCustomerId uuid.UUID `json:"receiverCustomerId"`
+	Amount             int64     `json:"amount"`
+	RequestedAt        time.Time `json:"requestedAt"`
+}
+
+type TransferCompletedEvent struct {
+	TransactionId      uuid.UUID `json:"transactionId"`
+	SenderCustomerId   uuid.UUID `json:"senderCustomerId"`
+	SenderAccountId    uuid.UUID `json:"senderAccountId"`
+	ReceiverCustomerId uuid.UUID `json:"receiverCustomerId"`
+	ReceiverAccountId  uuid.UUID `json:"receiverAccountId"`
+	Amount             int64     `json:"amount"`
+	CompletedAt        time.Time `json:"completedAt"`
+}
+
+// newOutboxEvent wraps a domain event so it can be written to the outbox in the transaction of
+// the change it describes. aggregateId is the entity the event is about.
+func newOutboxEvent(eventType string, aggregateId uuid.UUID, event any) (daos.OutboxEventSchema, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return daos.OutboxEventSchema{}, err
+	}
+
+	return daos.OutboxEventSchema{
+		Id:          uuid.New(),
+		EventType:   eventType,
+		AggregateId: aggregateId,
+		Payload:     payload,
+		CreatedAt:   time.Now().UTC(),
+	}, nil
+}
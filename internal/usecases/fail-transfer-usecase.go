@@ -0,0 +1,62 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	domainerrors "github.com/gsaaraujo/pay-bank-api/internal/domain-errors"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type FailTransferUsecaseInput struct {
+	TransferId uuid.UUID
+}
+
+type FailTransferUsecase struct {
+	pgxPool     *pgxpool.Pool
+	transferDAO daos.TransferDAO
+}
+
+func NewFailTransferUsecase(pgxPool *pgxpool.Pool, transferDAO daos.TransferDAO) FailTransferUsecase {
+	return FailTransferUsecase{pgxPool, transferDAO}
+}
+
+// Execute gives up on a transfer that ProcessTransferUsecase kept failing to process, for
+// reasons other than a domain error, marking it as failed with ErrTransferProcessingFailed. A
+// transfer that is no longer pending is left as is.
+func (f *FailTransferUsecase) Execute(ctx context.Context, input FailTransferUsecaseInput) error {
+	tx, err := f.pgxPool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback(context.WithoutCancel(ctx))
+	}()
+
+	transferSchema, err := f.transferDAO.FindOneByIdForUpdate(ctx, tx, input.TransferId)
+	if err != nil {
+		return err
+	}
+
+	if transferSchema == nil {
+		return domainerrors.ErrTransferNotFound
+	}
+
+	if transferSchema.Status != daos.TransferStatusPending {
+		return nil
+	}
+
+	transferSchema.Status = daos.TransferStatusFailed
+	transferSchema.FailureCode = &domainerrors.ErrTransferProcessingFailed.Code
+	transferSchema.FailureReason = &domainerrors.ErrTransferProcessingFailed.Message
+	transferSchema.UpdatedAt = time.Now().UTC()
+
+	if err := f.transferDAO.UpdateStatus(ctx, tx, *transferSchema); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
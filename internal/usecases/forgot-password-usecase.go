@@ -0,0 +1,85 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const passwordResetTokenTTL = 30 * time.Minute
+
+type ForgotPasswordUsecaseInput struct {
+	Email string
+}
+
+type ForgotPasswordUsecase struct {
+	pgxPool               *pgxpool.Pool
+	customerDAO           daos.CustomerDAO
+	passwordResetTokenDAO daos.PasswordResetTokenDAO
+	notifierGateway       gateways.NotifierGateway
+}
+
+func NewForgotPasswordUsecase(pgxPool *pgxpool.Pool, customerDAO daos.CustomerDAO, passwordResetTokenDAO daos.PasswordResetTokenDAO,
+	notifierGateway gateways.NotifierGateway) ForgotPasswordUsecase {
+	return ForgotPasswordUsecase{pgxPool, customerDAO, passwordResetTokenDAO, notifierGateway}
+}
+
+// Execute sends a single use reset token to the customer, replacing any token sent before. It
+// succeeds whether or not the email belongs to a customer, so it cannot be used to find out
+// which emails are registered. The notifier should send in the background, such as
+// gateways.AsyncNotifierGateway, so the response time does not tell them apart either.
+func (f *ForgotPasswordUsecase) Execute(ctx context.Context, input ForgotPasswordUsecaseInput) error {
+	customerSchema, err := f.customerDAO.FindOneByEmail(ctx, input.Email)
+	if err != nil {
+		return err
+	}
+
+	if customerSchema == nil {
+		return nil
+	}
+
+	token, err := newOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	tx, err := f.pgxPool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback(context.WithoutCancel(ctx))
+	}()
+
+	if err := f.passwordResetTokenDAO.MarkAllAsUsedByCustomerId(ctx, tx, customerSchema.Id, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	err = f.passwordResetTokenDAO.Create(ctx, tx, daos.PasswordResetTokenSchema{
+		Id:         uuid.New(),
+		CustomerId: customerSchema.Id,
+		TokenHash:  hashOpaqueToken(token),
+		ExpiresAt:  time.Now().UTC().Add(passwordResetTokenTTL),
+		CreatedAt:  time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	return f.notifierGateway.Send(ctx, gateways.Notification{
+		To:      customerSchema.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Use this token to choose a new password: %s\n\nIt expires in %d minutes. If you did not ask for it, ignore this message.",
+			token, int(passwordResetTokenTTL.Minutes())),
+	})
+}
@@ -0,0 +1,46 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	domainerrors "github.com/gsaaraujo/pay-bank-api/internal/domain-errors"
+)
+
+type GetCustomerRolesUsecaseInput struct {
+	CustomerId uuid.UUID
+}
+
+type GetCustomerRolesUsecaseOutput struct {
+	Roles []string
+}
+
+type GetCustomerRolesUsecase struct {
+	customerDAO     daos.CustomerDAO
+	customerRoleDAO daos.CustomerRoleDAO
+}
+
+func NewGetCustomerRolesUsecase(customerDAO daos.CustomerDAO, customerRoleDAO daos.CustomerRoleDAO) GetCustomerRolesUsecase {
+	return GetCustomerRolesUsecase{customerDAO, customerRoleDAO}
+}
+
+func (g *GetCustomerRolesUsecase) Execute(ctx context.Context, input GetCustomerRolesUsecaseInput) (GetCustomerRolesUsecaseOutput, error) {
+	customerSchema, err := g.customerDAO.FindOneById(ctx, input.CustomerId)
+	if err != nil {
+		return GetCustomerRolesUsecaseOutput{}, err
+	}
+
+	if customerSchema == nil {
+		return GetCustomerRolesUsecaseOutput{}, domainerrors.ErrCustomerNotFound
+	}
+
+	roles, err := findCustomerRoles(ctx, g.customerRoleDAO, input.CustomerId)
+	if err != nil {
+		return GetCustomerRolesUsecaseOutput{}, err
+	}
+
+	return GetCustomerRolesUsecaseOutput{
+		Roles: roles,
+	}, nil
+}
@@ -0,0 +1,110 @@
+package usecases
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
+)
+
+type GetTransactionReceiptUsecaseInput struct {
+	CustomerId    uuid.UUID
+	TransactionId uuid.UUID
+}
+
+type GetTransactionReceiptUsecaseOutputParty struct {
+	CustomerId uuid.UUID
+	Name       string
+	AccountId  uuid.UUID
+}
+
+type GetTransactionReceiptUsecaseOutput struct {
+	TransactionId      uuid.UUID
+	Sender             GetTransactionReceiptUsecaseOutputParty
+	Receiver           GetTransactionReceiptUsecaseOutputParty
+	Amount             int64
+	CreatedAt          time.Time
+	AuthenticationHash string
+}
+
+type GetTransactionReceiptUsecase struct {
+	accountDAO     daos.AccountDAO
+	transactionDAO daos.TransactionDAO
+	configGateway  gateways.ConfigGateway
+}
+
+func NewGetTransactionReceiptUsecase(accountDAO daos.AccountDAO, transactionDAO daos.TransactionDAO,
+	configGateway gateways.ConfigGateway) GetTransactionReceiptUsecase {
+	return GetTransactionReceiptUsecase{accountDAO, transactionDAO, configGateway}
+}
+
+// Execute issues the receipt of a transaction to its sender or its receiver. CreatedAt is
+// truncated to the second, as printed on the receipt, so the authentication hash can be
+// checked again from the receipt alone.
+func (g *GetTransactionReceiptUsecase) Execute(ctx context.Context,
+	input GetTransactionReceiptUsecaseInput) (GetTransactionReceiptUsecaseOutput, error) {
+	transactionSchema, _, err := findCustomerTransaction(ctx, g.accountDAO, g.transactionDAO, input.CustomerId, input.TransactionId)
+	if err != nil {
+		return GetTransactionReceiptUsecaseOutput{}, err
+	}
+
+	receipt := GetTransactionReceiptUsecaseOutput{
+		TransactionId: transactionSchema.Id,
+		Sender: GetTransactionReceiptUsecaseOutputParty{
+			CustomerId: transactionSchema.CustomerSenderId,
+			Name:       transactionSchema.CustomerSenderName,
+			AccountId:  transactionSchema.AccountSenderId,
+		},
+		Receiver: GetTransactionReceiptUsecaseOutputParty{
+			CustomerId: transactionSchema.CustomerReceiverId,
+			Name:       transactionSchema.CustomerReceiverName,
+			AccountId:  transactionSchema.AccountReceiverId,
+		},
+		Amount:    transactionSchema.Amount,
+		CreatedAt: transactionSchema.CreatedAt.UTC().Truncate(time.Second),
+	}
+
+	signingKey, err := g.configGateway.GetString(ctx, "RECEIPT_SIGNING_KEY")
+	if err != nil {
+		return GetTransactionReceiptUsecaseOutput{}, err
+	}
+
+	receipt.AuthenticationHash = signReceipt(signingKey, receipt)
+
+	return receipt, nil
+}
+
+// receiptSignatureVersion prefixes both the signed message and the authentication hash. Any
+// change to the message format needs a new version, so receipts already issued stay verifiable.
+const receiptSignatureVersion = "v1"
+
+// signReceipt returns "v1," followed by the hex HMAC-SHA256, keyed by RECEIPT_SIGNING_KEY, of
+// the fields below joined by "|", in this order:
+//
+//	v1|<transaction id>|<sender customer id>|<sender account id>|<receiver customer id>|<receiver account id>|<amount in cents>|<created at, unix seconds>
+//
+// Names are left out since customers may change them after the receipt is issued.
+func signReceipt(signingKey string, receipt GetTransactionReceiptUsecaseOutput) string {
+	message := strings.Join([]string{
+		receiptSignatureVersion,
+		receipt.TransactionId.String(),
+		receipt.Sender.CustomerId.String(),
+		receipt.Sender.AccountId.String(),
+		receipt.Receiver.CustomerId.String(),
+		receipt.Receiver.AccountId.String(),
+		strconv.FormatInt(receipt.Amount, 10),
+		strconv.FormatInt(receipt.CreatedAt.Unix(), 10),
+	}, "|")
+
+	mac := hmac.New(sha256.New, []byte(signingKey))
+	mac.Write([]byte(message))
+
+	return receiptSignatureVersion + "," + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,64 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+)
+
+type GetTransactionUsecaseInput struct {
+	CustomerId    uuid.UUID
+	TransactionId uuid.UUID
+}
+
+type GetTransactionUsecaseOutputCustomer struct {
+	Id   uuid.UUID
+	Name string
+}
+
+type GetTransactionUsecaseOutput struct {
+	Id                uuid.UUID
+	Direction         string
+	CustomerSender    GetTransactionUsecaseOutputCustomer
+	CustomerReceiver  GetTransactionUsecaseOutputCustomer
+	AccountSenderId   uuid.UUID
+	AccountReceiverId uuid.UUID
+	Amount            int64
+	CreatedAt         time.Time
+}
+
+type GetTransactionUsecase struct {
+	accountDAO     daos.AccountDAO
+	transactionDAO daos.TransactionDAO
+}
+
+func NewGetTransactionUsecase(accountDAO daos.AccountDAO, transactionDAO daos.TransactionDAO) GetTransactionUsecase {
+	return GetTransactionUsecase{accountDAO, transactionDAO}
+}
+
+// Execute only shows a transaction to its sender and its receiver.
+func (g *GetTransactionUsecase) Execute(ctx context.Context, input GetTransactionUsecaseInput) (GetTransactionUsecaseOutput, error) {
+	transactionSchema, direction, err := findCustomerTransaction(ctx, g.accountDAO, g.transactionDAO, input.CustomerId, input.TransactionId)
+	if err != nil {
+		return GetTransactionUsecaseOutput{}, err
+	}
+
+	return GetTransactionUsecaseOutput{
+		Id:        transactionSchema.Id,
+		Direction: direction,
+		CustomerSender: GetTransactionUsecaseOutputCustomer{
+			Id:   transactionSchema.CustomerSenderId,
+			Name: transactionSchema.CustomerSenderName,
+		},
+		CustomerReceiver: GetTransactionUsecaseOutputCustomer{
+			Id:   transactionSchema.CustomerReceiverId,
+			Name: transactionSchema.CustomerReceiverName,
+		},
+		AccountSenderId:   transactionSchema.AccountSenderId,
+		AccountReceiverId: transactionSchema.AccountReceiverId,
+		Amount:            transactionSchema.Amount,
+		CreatedAt:         transactionSchema.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,167 @@
+package usecases
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	domainerrors "github.com/gsaaraujo/pay-bank-api/internal/domain-errors"
+)
+
+type GetTransactionsHistoryUsecaseInput struct {
+	CustomerId uuid.UUID
+	Limit      *int
+	Cursor     *string
+	From       *time.Time
+	To         *time.Time
+}
+
+type GetTransactionsHistoryUsecaseOutputCustomer struct {
+	Id   uuid.UUID
+	Name string
+}
+
+type GetTransactionsHistoryUsecaseOutputTransaction struct {
+	Id                uuid.UUID
+	Direction         string
+	CustomerSender    GetTransactionsHistoryUsecaseOutputCustomer
+	CustomerReceiver  GetTransactionsHistoryUsecaseOutputCustomer
+	AccountSenderId   uuid.UUID
+	AccountReceiverId uuid.UUID
+	Amount            int64
+	CreatedAt         time.Time
+}
+
+type GetTransactionsHistoryUsecaseOutput struct {
+	Transactions []GetTransactionsHistoryUsecaseOutputTransaction
+	NextCursor   *string
+}
+
+type GetTransactionsHistoryUsecase struct {
+	accountDAO     daos.AccountDAO
+	transactionDAO daos.TransactionDAO
+}
+
+func NewGetTransactionsHistoryUsecase(accountDAO daos.AccountDAO, transactionDAO daos.TransactionDAO) GetTransactionsHistoryUsecase {
+	return GetTransactionsHistoryUsecase{accountDAO, transactionDAO}
+}
+
+func (g *GetTransactionsHistoryUsecase) Execute(ctx context.Context, input GetTransactionsHistoryUsecaseInput) (GetTransactionsHistoryUsecaseOutput, error) {
+	limit := 20
+
+	if input.Limit != nil {
+		limit = *input.Limit
+	}
+
+	if limit < 1 || limit > 100 {
+		return GetTransactionsHistoryUsecaseOutput{}, domainerrors.ErrHistoryLimitOutOfRange
+	}
+
+	if input.From != nil && input.To != nil && !input.From.Before(*input.To) {
+		return GetTransactionsHistoryUsecaseOutput{}, domainerrors.ErrHistoryRangeInvalid
+	}
+
+	filter := daos.TransactionHistoryFilter{
+		From:  input.From,
+		To:    input.To,
+		Limit: limit + 1,
+	}
+
+	if input.Cursor != nil {
+		beforeCreatedAt, beforeId, err := decodeTransactionsHistoryCursor(*input.Cursor)
+		if err != nil {
+			return GetTransactionsHistoryUsecaseOutput{}, domainerrors.ErrHistoryCursorInvalid
+		}
+
+		filter.BeforeCreatedAt = &beforeCreatedAt
+		filter.BeforeId = &beforeId
+	}
+
+	account, err := g.accountDAO.FindOneByCustomerId(ctx, input.CustomerId)
+	if err != nil {
+		return GetTransactionsHistoryUsecaseOutput{}, err
+	}
+
+	if account == nil {
+		return GetTransactionsHistoryUsecaseOutput{}, errors.New("customer account was not found")
+	}
+
+	transactionsHistorySchema, err := g.transactionDAO.FindAllHistoryByAccountId(ctx, account.Id, filter)
+	if err != nil {
+		return GetTransactionsHistoryUsecaseOutput{}, err
+	}
+
+	transactions := []GetTransactionsHistoryUsecaseOutputTransaction{}
+
+	var nextCursor *string
+
+	if len(transactionsHistorySchema) > limit {
+		transactionsHistorySchema = transactionsHistorySchema[:limit]
+		last := transactionsHistorySchema[limit-1]
+		cursor := encodeTransactionsHistoryCursor(last.CreatedAt, last.Id)
+		nextCursor = &cursor
+	}
+
+	for _, item := range transactionsHistorySchema {
+		direction := "received"
+
+		if item.AccountSenderId == account.Id {
+			direction = "sent"
+		}
+
+		transactions = append(transactions, GetTransactionsHistoryUsecaseOutputTransaction{
+			Id:        item.Id,
+			Direction: direction,
+			CustomerSender: GetTransactionsHistoryUsecaseOutputCustomer{
+				Id:   item.CustomerSenderId,
+				Name: item.CustomerSenderName,
+			},
+			CustomerReceiver: GetTransactionsHistoryUsecaseOutputCustomer{
+				Id:   item.CustomerReceiverId,
+				Name: item.CustomerReceiverName,
+			},
+			AccountSenderId:   item.AccountSenderId,
+			AccountReceiverId: item.AccountReceiverId,
+			Amount:            item.Amount,
+			CreatedAt:         item.CreatedAt,
+		})
+	}
+
+	return GetTransactionsHistoryUsecaseOutput{
+		Transactions: transactions,
+		NextCursor:   nextCursor,
+	}, nil
+}
+
+func encodeTransactionsHistoryCursor(createdAt time.Time, id uuid.UUID) string {
+	return base64.RawURLEncoding.EncodeToString(fmt.Appendf(nil, "%s,%s", createdAt.UTC().Format(time.RFC3339Nano), id))
+}
+
+func decodeTransactionsHistoryCursor(cursor string) (time.Time, uuid.UUID, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, uuid.Nil, err
+	}
+
+	createdAtRaw, idRaw, found := strings.Cut(string(decoded), ",")
+	if !found {
+		return time.Time{}, uuid.Nil, errors.New("cursor is malformed")
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, createdAtRaw)
+	if err != nil {
+		return time.Time{}, uuid.Nil, err
+	}
+
+	id, err := uuid.Parse(idRaw)
+	if err != nil {
+		return time.Time{}, uuid.Nil, err
+	}
+
+	return createdAt, id, nil
+}
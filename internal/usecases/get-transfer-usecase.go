@@ -0,0 +1,66 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	domainerrors "github.com/gsaaraujo/pay-bank-api/internal/domain-errors"
+)
+
+type GetTransferUsecaseInput struct {
+	CustomerId uuid.UUID
+	TransferId uuid.UUID
+}
+
+type GetTransferUsecaseOutput struct {
+	Id                 uuid.UUID
+	ReceiverCustomerId uuid.UUID
+	Amount             int64
+	Status             string
+	TransactionId      *uuid.UUID
+	FailureCode        *string
+	FailureReason      *string
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+type GetTransferUsecase struct {
+	transferDAO daos.TransferDAO
+}
+
+func NewGetTransferUsecase(transferDAO daos.TransferDAO) GetTransferUsecase {
+	return GetTransferUsecase{transferDAO}
+}
+
+// Execute only shows a transfer to its sender; to anyone else it is not found.
+func (g *GetTransferUsecase) Execute(ctx context.Context, input GetTransferUsecaseInput) (GetTransferUsecaseOutput, error) {
+	transferSchema, err := g.transferDAO.FindOneById(ctx, input.TransferId)
+	if err != nil {
+		return GetTransferUsecaseOutput{}, err
+	}
+
+	if transferSchema == nil || transferSchema.SenderCustomerId != input.CustomerId {
+		return GetTransferUsecaseOutput{}, domainerrors.ErrTransferNotFound
+	}
+
+	// A completed transfer is recorded as the transaction with the same id.
+	var transactionId *uuid.UUID
+
+	if transferSchema.Status == daos.TransferStatusCompleted {
+		transactionId = &transferSchema.Id
+	}
+
+	return GetTransferUsecaseOutput{
+		Id:                 transferSchema.Id,
+		ReceiverCustomerId: transferSchema.ReceiverCustomerId,
+		Amount:             transferSchema.Amount,
+		Status:             transferSchema.Status,
+		TransactionId:      transactionId,
+		FailureCode:        transferSchema.FailureCode,
+		FailureReason:      transferSchema.FailureReason,
+		CreatedAt:          transferSchema.CreatedAt,
+		UpdatedAt:          transferSchema.UpdatedAt,
+	}, nil
+}
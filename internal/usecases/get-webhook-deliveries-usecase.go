@@ -0,0 +1,85 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	domainerrors "github.com/gsaaraujo/pay-bank-api/internal/domain-errors"
+)
+
+// webhookDeliveriesLimit is how many of the latest deliveries are listed.
+const webhookDeliveriesLimit = 100
+
+type GetWebhookDeliveriesUsecaseInput struct {
+	CustomerId uuid.UUID
+	WebhookId  uuid.UUID
+}
+
+type GetWebhookDeliveriesUsecaseOutputDelivery struct {
+	Id                 uuid.UUID
+	EventId            uuid.UUID
+	EventType          string
+	Status             string
+	Attempts           int
+	NextAttemptAt      *time.Time
+	LastAttemptedAt    *time.Time
+	LastResponseStatus *int
+	LastError          *string
+	CreatedAt          time.Time
+	DeliveredAt        *time.Time
+}
+
+type GetWebhookDeliveriesUsecaseOutput struct {
+	Deliveries []GetWebhookDeliveriesUsecaseOutputDelivery
+}
+
+type GetWebhookDeliveriesUsecase struct {
+	webhookDAO         daos.WebhookDAO
+	webhookDeliveryDAO daos.WebhookDeliveryDAO
+}
+
+func NewGetWebhookDeliveriesUsecase(webhookDAO daos.WebhookDAO, webhookDeliveryDAO daos.WebhookDeliveryDAO) GetWebhookDeliveriesUsecase {
+	return GetWebhookDeliveriesUsecase{webhookDAO, webhookDeliveryDAO}
+}
+
+// Execute lists the latest deliveries of the webhook, newest first, with the outcome of their
+// last attempt. A webhook of another customer is reported as not found.
+func (g *GetWebhookDeliveriesUsecase) Execute(ctx context.Context, input GetWebhookDeliveriesUsecaseInput) (GetWebhookDeliveriesUsecaseOutput, error) {
+	webhookSchema, err := g.webhookDAO.FindOneById(ctx, input.WebhookId)
+	if err != nil {
+		return GetWebhookDeliveriesUsecaseOutput{}, err
+	}
+
+	if webhookSchema == nil || webhookSchema.CustomerId != input.CustomerId {
+		return GetWebhookDeliveriesUsecaseOutput{}, domainerrors.ErrWebhookNotFound
+	}
+
+	webhookDeliveriesSchema, err := g.webhookDeliveryDAO.FindAllByWebhookId(ctx, input.WebhookId, webhookDeliveriesLimit)
+	if err != nil {
+		return GetWebhookDeliveriesUsecaseOutput{}, err
+	}
+
+	deliveries := []GetWebhookDeliveriesUsecaseOutputDelivery{}
+
+	for _, webhookDeliverySchema := range webhookDeliveriesSchema {
+		deliveries = append(deliveries, GetWebhookDeliveriesUsecaseOutputDelivery{
+			Id:                 webhookDeliverySchema.Id,
+			EventId:            webhookDeliverySchema.EventId,
+			EventType:          webhookDeliverySchema.EventType,
+			Status:             webhookDeliverySchema.Status,
+			Attempts:           webhookDeliverySchema.Attempts,
+			NextAttemptAt:      webhookDeliverySchema.NextAttemptAt,
+			LastAttemptedAt:    webhookDeliverySchema.LastAttemptedAt,
+			LastResponseStatus: webhookDeliverySchema.LastResponseStatus,
+			LastError:          webhookDeliverySchema.LastError,
+			CreatedAt:          webhookDeliverySchema.CreatedAt,
+			DeliveredAt:        webhookDeliverySchema.DeliveredAt,
+		})
+	}
+
+	return GetWebhookDeliveriesUsecaseOutput{
+		Deliveries: deliveries,
+	}, nil
+}
@@ -0,0 +1,45 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+)
+
+type GetWebhooksUsecaseInput struct {
+	CustomerId uuid.UUID
+}
+
+type GetWebhooksUsecaseOutput struct {
+	Webhooks []Webhook
+}
+
+type GetWebhooksUsecase struct {
+	webhookDAO daos.WebhookDAO
+}
+
+func NewGetWebhooksUsecase(webhookDAO daos.WebhookDAO) GetWebhooksUsecase {
+	return GetWebhooksUsecase{webhookDAO}
+}
+
+func (g *GetWebhooksUsecase) Execute(ctx context.Context, input GetWebhooksUsecaseInput) (GetWebhooksUsecaseOutput, error) {
+	webhooksSchema, err := g.webhookDAO.FindAllByCustomerId(ctx, input.CustomerId)
+	if err != nil {
+		return GetWebhooksUsecaseOutput{}, err
+	}
+
+	webhooks := []Webhook{}
+
+	for _, webhookSchema := range webhooksSchema {
+		webhooks = append(webhooks, Webhook{
+			Id:        webhookSchema.Id,
+			Url:       webhookSchema.Url,
+			CreatedAt: webhookSchema.CreatedAt,
+		})
+	}
+
+	return GetWebhooksUsecaseOutput{
+		Webhooks: webhooks,
+	}, nil
+}
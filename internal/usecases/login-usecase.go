@@ -1,73 +1,202 @@
 package usecases
 
 import (
-	"errors"
+	"context"
 	"net/mail"
+	"strings"
 	"time"
 
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	domainerrors "github.com/gsaaraujo/pay-bank-api/internal/domain-errors"
 	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
-	"github.com/gsaaraujo/pay-bank-api/internal/utils"
 	"golang.org/x/crypto/bcrypt"
 )
 
-type JwtAccessTokenClaims struct {
-	Roles []string `json:"roles"`
-	jwt.RegisteredClaims
+// Failed logins are counted per email and per IP address, and a subject reaching its limit is
+// locked out as described by loginLockoutPolicy.
+const (
+	loginMaxFailuresByEmail = 5
+	loginMaxFailuresByIp    = 20
+)
+
+var loginLockoutPolicy = daos.LoginLockoutPolicy{
+	FailuresWindow: 24 * time.Hour,
+	LockoutBase:    time.Minute,
+	LockoutMax:     time.Hour,
 }
 
+// A customer with two-factor authentication enabled gets a challenge token instead of the
+// session tokens. It is exchanged for them with a TOTP or recovery code within loginChallengeTTL,
+// and at most loginChallengeMaxAttempts codes can be tried against it.
+const (
+	loginChallengeTTL         = 5 * time.Minute
+	loginChallengeMaxAttempts = 5
+)
+
 type LoginUsecaseInput struct {
-	Email    string
-	Password string
+	Email     string
+	Password  string
+	IpAddress string
 }
 
+// LoginUsecaseOutput carries either the session tokens or, when two-factor authentication is
+// enabled, only the TotpChallengeToken.
 type LoginUsecaseOutput struct {
-	CustomerId  uuid.UUID
-	AccessToken string
+	CustomerId         uuid.UUID
+	AccessToken        string
+	RefreshToken       string
+	TotpChallengeToken string
 }
 
 type LoginUsecase struct {
-	customerDAO       daos.CustomerDAO
-	awsSecretsGateway gateways.AwsSecretsGateway
+	customerDAO            daos.CustomerDAO
+	customerRoleDAO        daos.CustomerRoleDAO
+	refreshTokenDAO        daos.RefreshTokenDAO
+	loginAttemptDAO        daos.LoginAttemptDAO
+	loginLockoutDAO        daos.LoginLockoutDAO
+	customerTotpDAO        daos.CustomerTotpDAO
+	loginChallengeDAO      daos.LoginChallengeDAO
+	accessTokenKeysGateway gateways.AccessTokenKeysGateway
 }
 
-func NewLoginUsecase(customerDAO daos.CustomerDAO, awsSecretsGateway gateways.AwsSecretsGateway) LoginUsecase {
-	return LoginUsecase{customerDAO, awsSecretsGateway}
+func NewLoginUsecase(customerDAO daos.CustomerDAO, customerRoleDAO daos.CustomerRoleDAO, refreshTokenDAO daos.RefreshTokenDAO,
+	loginAttemptDAO daos.LoginAttemptDAO, loginLockoutDAO daos.LoginLockoutDAO, customerTotpDAO daos.CustomerTotpDAO,
+	loginChallengeDAO daos.LoginChallengeDAO, accessTokenKeysGateway gateways.AccessTokenKeysGateway) LoginUsecase {
+	return LoginUsecase{customerDAO, customerRoleDAO, refreshTokenDAO, loginAttemptDAO, loginLockoutDAO, customerTotpDAO,
+		loginChallengeDAO, accessTokenKeysGateway}
 }
 
-func (l *LoginUsecase) Execute(input LoginUsecaseInput) (LoginUsecaseOutput, error) {
+func (l *LoginUsecase) Execute(ctx context.Context, input LoginUsecaseInput) (LoginUsecaseOutput, error) {
 	_, err := mail.ParseAddress(input.Email)
 	if err != nil {
-		return LoginUsecaseOutput{}, errors.New("email address is invalid")
+		return LoginUsecaseOutput{}, domainerrors.ErrEmailAddressInvalid
 	}
 
-	customerSchema := l.customerDAO.FindOneByEmail(input.Email)
+	emailSubject := loginSubject{daos.LoginLockoutSubjectTypeEmail, strings.ToLower(input.Email), loginMaxFailuresByEmail}
+	ipSubject := loginSubject{daos.LoginLockoutSubjectTypeIp, input.IpAddress, loginMaxFailuresByIp}
+	subjects := []loginSubject{emailSubject, ipSubject}
+
+	// The attempt is counted as failed before the password is checked, and refunded if it is
+	// right, so that concurrent attempts cannot all get in before the lockout.
+	reservations, lockoutRemaining, err := reserveLoginAttempt(ctx, l.loginAttemptDAO, subjects)
+	if err != nil {
+		return LoginUsecaseOutput{}, err
+	}
+
+	if lockoutRemaining > 0 {
+		return LoginUsecaseOutput{}, domainerrors.NewRetryAfterError(domainerrors.ErrLoginLocked, lockoutRemaining)
+	}
+
+	customerSchema, err := l.customerDAO.FindOneByEmail(ctx, input.Email)
+	if err != nil {
+		return LoginUsecaseOutput{}, err
+	}
 
 	if customerSchema == nil {
-		return LoginUsecaseOutput{}, errors.New("email or password is incorrect")
+		return LoginUsecaseOutput{}, l.registerFailure(ctx, subjects, reservations)
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(customerSchema.Password), []byte(input.Password))
 	if err != nil {
-		return LoginUsecaseOutput{}, errors.New("email or password is incorrect")
+		return LoginUsecaseOutput{}, l.registerFailure(ctx, subjects, reservations)
+	}
+
+	if err := l.loginAttemptDAO.RefundAttempt(ctx, reservations...); err != nil {
+		return LoginUsecaseOutput{}, err
+	}
+
+	if err := l.loginAttemptDAO.DeleteFailures(ctx, emailSubject.key()); err != nil {
+		return LoginUsecaseOutput{}, err
+	}
+
+	customerTotpSchema, err := l.customerTotpDAO.FindOneByCustomerId(ctx, customerSchema.Id)
+	if err != nil {
+		return LoginUsecaseOutput{}, err
 	}
 
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, JwtAccessTokenClaims{
-		Roles: []string{"customer"},
-		RegisteredClaims: jwt.RegisteredClaims{
-			Subject:   customerSchema.Id.String(),
-			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
-			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(30 * time.Minute)),
-		},
-	})
+	if customerTotpSchema != nil && customerTotpSchema.ConfirmedAt != nil {
+		challengeToken, err := newOpaqueToken()
+		if err != nil {
+			return LoginUsecaseOutput{}, err
+		}
+
+		if err := l.loginChallengeDAO.Create(ctx, hashOpaqueToken(challengeToken), customerSchema.Id, loginChallengeTTL); err != nil {
+			return LoginUsecaseOutput{}, err
+		}
+
+		return LoginUsecaseOutput{
+			CustomerId:         customerSchema.Id,
+			TotpChallengeToken: challengeToken,
+		}, nil
+	}
 
-	accessTokenSigningKey := l.awsSecretsGateway.Get("ACCESS_TOKEN_SIGNING_KEY").(string)
-	acessTokenSigned := utils.GetOrThrow(accessToken.SignedString([]byte(accessTokenSigningKey)))
+	accessToken, refreshToken, err := startSession(ctx, l.customerRoleDAO, l.refreshTokenDAO, l.accessTokenKeysGateway, customerSchema.Id)
+	if err != nil {
+		return LoginUsecaseOutput{}, err
+	}
 
 	return LoginUsecaseOutput{
-		CustomerId:  customerSchema.Id,
-		AccessToken: acessTokenSigned,
+		CustomerId:   customerSchema.Id,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
 	}, nil
 }
+
+type loginSubject struct {
+	subjectType string
+	subject     string
+	maxFailures int64
+}
+
+func (l loginSubject) key() string {
+	return l.subjectType + ":" + l.subject
+}
+
+// reserveLoginAttempt reserves an attempt for every subject, returning the longest remaining
+// lockout instead when any of them is locked out.
+func reserveLoginAttempt(ctx context.Context, loginAttemptDAO daos.LoginAttemptDAO,
+	subjects []loginSubject) ([]daos.LoginAttemptReservation, time.Duration, error) {
+	limits := make([]daos.LoginAttemptLimit, len(subjects))
+
+	for i, subject := range subjects {
+		limits[i] = daos.LoginAttemptLimit{Subject: subject.key(), MaxFailures: subject.maxFailures}
+	}
+
+	return loginAttemptDAO.ReserveAttempt(ctx, loginLockoutPolicy, limits...)
+}
+
+// registerFailure keeps a record of the lockouts the failed attempt caused and returns the
+// error for the attempt itself.
+func (l *LoginUsecase) registerFailure(ctx context.Context, subjects []loginSubject, reservations []daos.LoginAttemptReservation) error {
+	if err := recordLoginLockouts(ctx, l.loginLockoutDAO, subjects, reservations); err != nil {
+		return err
+	}
+
+	return domainerrors.ErrEmailOrPasswordIncorrect
+}
+
+// recordLoginLockouts keeps a record of the lockouts caused by a failed attempt, whose
+// reservations are in the order of subjects.
+func recordLoginLockouts(ctx context.Context, loginLockoutDAO daos.LoginLockoutDAO, subjects []loginSubject,
+	reservations []daos.LoginAttemptReservation) error {
+	for i, reservation := range reservations {
+		if reservation.Lockout == 0 {
+			continue
+		}
+
+		err := loginLockoutDAO.Create(ctx, daos.LoginLockoutSchema{
+			Id:             uuid.New(),
+			SubjectType:    subjects[i].subjectType,
+			Subject:        subjects[i].subject,
+			FailedAttempts: reservation.Failures,
+			LockedUntil:    time.Now().UTC().Add(reservation.Lockout),
+			CreatedAt:      time.Now().UTC(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
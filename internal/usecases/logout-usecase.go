@@ -0,0 +1,49 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type LogoutUsecaseInput struct {
+	SessionId uuid.UUID
+}
+
+type LogoutUsecase struct {
+	pgxPool               *pgxpool.Pool
+	refreshTokenDAO       daos.RefreshTokenDAO
+	revokedAccessTokenDAO daos.RevokedAccessTokenDAO
+}
+
+func NewLogoutUsecase(pgxPool *pgxpool.Pool, refreshTokenDAO daos.RefreshTokenDAO,
+	revokedAccessTokenDAO daos.RevokedAccessTokenDAO) LogoutUsecase {
+	return LogoutUsecase{pgxPool, refreshTokenDAO, revokedAccessTokenDAO}
+}
+
+// Execute ends the session the access token belongs to: the refresh tokens of its family can
+// no longer be exchanged and every access token issued with its sid, not only the one used to
+// log out, is denied until it expires.
+func (l *LogoutUsecase) Execute(ctx context.Context, input LogoutUsecaseInput) error {
+	tx, err := l.pgxPool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback(context.WithoutCancel(ctx))
+	}()
+
+	if err := l.refreshTokenDAO.RevokeFamily(ctx, tx, input.SessionId, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	if err := l.revokedAccessTokenDAO.CreateForSession(ctx, input.SessionId, accessTokenTTL); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,112 @@
+package usecases
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	domainerrors "github.com/gsaaraujo/pay-bank-api/internal/domain-errors"
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// personalInfoMinLength keeps short name parts, such as "Li" or "de", from rejecting most
+// passwords.
+const personalInfoMinLength = 3
+
+// validatePassword holds the rules every new password must follow, on sign up, reset or change.
+// Every rule broken is reported at once in a PasswordPolicyError on field.
+func validatePassword(ctx context.Context, passwordPolicyGateway gateways.PasswordPolicyGateway,
+	breachedPasswordsGateway gateways.BreachedPasswordsGateway, field string, password string, name string, email string) error {
+	passwordPolicy, err := passwordPolicyGateway.Policy(ctx)
+	if err != nil {
+		return err
+	}
+
+	violations := []*domainerrors.DomainError{}
+
+	if utf8.RuneCountInString(password) < passwordPolicy.MinLength {
+		violations = append(violations, domainerrors.NewPasswordTooShort(passwordPolicy.MinLength))
+	}
+
+	if passwordPolicy.RequireUppercase && !strings.ContainsFunc(password, unicode.IsUpper) {
+		violations = append(violations, domainerrors.ErrPasswordMissingUppercase)
+	}
+
+	if passwordPolicy.RequireLowercase && !strings.ContainsFunc(password, unicode.IsLower) {
+		violations = append(violations, domainerrors.ErrPasswordMissingLowercase)
+	}
+
+	if passwordPolicy.RequireDigit && !strings.ContainsFunc(password, unicode.IsDigit) {
+		violations = append(violations, domainerrors.ErrPasswordMissingDigit)
+	}
+
+	if passwordPolicy.RequireSymbol && !strings.ContainsFunc(password, isPasswordSymbol) {
+		violations = append(violations, domainerrors.ErrPasswordMissingSymbol)
+	}
+
+	if passwordPolicy.RejectPersonalInfo && containsPersonalInfo(password, name, email) {
+		violations = append(violations, domainerrors.ErrPasswordContainsPersonalInfo)
+	}
+
+	breached, err := isPasswordBreached(ctx, breachedPasswordsGateway, password)
+	if err != nil {
+		return err
+	}
+
+	if breached {
+		violations = append(violations, domainerrors.ErrPasswordBreached)
+	}
+
+	if len(violations) > 0 {
+		return domainerrors.NewPasswordPolicyError(field, violations)
+	}
+
+	return nil
+}
+
+func isPasswordSymbol(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsSpace(r)
+}
+
+// containsPersonalInfo tells whether the password contains, ignoring case, a part of the name
+// or the email address local part.
+func containsPersonalInfo(password string, name string, email string) bool {
+	password = strings.ToLower(password)
+	localPart, _, _ := strings.Cut(email, "@")
+
+	personalInfo := strings.FieldsFunc(strings.ToLower(name+" "+localPart), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	return slices.ContainsFunc(personalInfo, func(info string) bool {
+		return utf8.RuneCountInString(info) >= personalInfoMinLength && strings.Contains(password, info)
+	})
+}
+
+// isPasswordBreached only hands the first 5 characters of the SHA-1 to the gateway, so the list
+// may live behind a remote service without it learning the password.
+func isPasswordBreached(ctx context.Context, breachedPasswordsGateway gateways.BreachedPasswordsGateway, password string) (bool, error) {
+	hash := sha1.Sum([]byte(password))
+	hexHash := strings.ToUpper(hex.EncodeToString(hash[:]))
+
+	hashSuffixes, err := breachedPasswordsGateway.FindHashSuffixes(ctx, hexHash[:5])
+	if err != nil {
+		return false, err
+	}
+
+	return slices.Contains(hashSuffixes, hexHash[5:]), nil
+}
+
+func hashPassword(password string) (string, error) {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hashedPassword), nil
+}
@@ -0,0 +1,118 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	domainerrors "github.com/gsaaraujo/pay-bank-api/internal/domain-errors"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ProcessTransferUsecaseInput struct {
+	TransferId uuid.UUID
+}
+
+type ProcessTransferUsecaseOutput struct {
+	Status string
+}
+
+type ProcessTransferUsecase struct {
+	pgxPool            *pgxpool.Pool
+	accountDAO         daos.AccountDAO
+	ledgerDAO          daos.LedgerDAO
+	transferDAO        daos.TransferDAO
+	outboxEventDAO     daos.OutboxEventDAO
+	webhookDeliveryDAO daos.WebhookDeliveryDAO
+}
+
+func NewProcessTransferUsecase(pgxPool *pgxpool.Pool, accountDAO daos.AccountDAO, ledgerDAO daos.LedgerDAO, transferDAO daos.TransferDAO,
+	outboxEventDAO daos.OutboxEventDAO, webhookDeliveryDAO daos.WebhookDeliveryDAO) ProcessTransferUsecase {
+	return ProcessTransferUsecase{pgxPool, accountDAO, ledgerDAO, transferDAO, outboxEventDAO, webhookDeliveryDAO}
+}
+
+// Execute completes a transfer accepted by AcceptTransferUsecase, or marks it as failed with
+// the domain error that prevented it. A transfer that is no longer pending is left as is, so
+// processing the same message twice is harmless.
+func (p *ProcessTransferUsecase) Execute(ctx context.Context, input ProcessTransferUsecaseInput) (ProcessTransferUsecaseOutput, error) {
+	tx, err := p.pgxPool.Begin(ctx)
+	if err != nil {
+		return ProcessTransferUsecaseOutput{}, err
+	}
+
+	defer func() {
+		_ = tx.Rollback(context.WithoutCancel(ctx))
+	}()
+
+	transferSchema, err := p.transferDAO.FindOneByIdForUpdate(ctx, tx, input.TransferId)
+	if err != nil {
+		return ProcessTransferUsecaseOutput{}, err
+	}
+
+	if transferSchema == nil {
+		return ProcessTransferUsecaseOutput{}, domainerrors.ErrTransferNotFound
+	}
+
+	if transferSchema.Status != daos.TransferStatusPending {
+		return ProcessTransferUsecaseOutput{Status: transferSchema.Status}, nil
+	}
+
+	senderAccount, err := p.accountDAO.FindOneByCustomerId(ctx, transferSchema.SenderCustomerId)
+	if err != nil {
+		return ProcessTransferUsecaseOutput{}, err
+	}
+
+	receiverAccount, err := p.accountDAO.FindOneByCustomerId(ctx, transferSchema.ReceiverCustomerId)
+	if err != nil {
+		return ProcessTransferUsecaseOutput{}, err
+	}
+
+	if senderAccount == nil || receiverAccount == nil {
+		return ProcessTransferUsecaseOutput{}, errors.New("transfer account was not found")
+	}
+
+	// The savepoint discards whatever the transfer wrote before failing, while keeping the lock
+	// on the transfer to mark it as failed.
+	savepoint, err := tx.Begin(ctx)
+	if err != nil {
+		return ProcessTransferUsecaseOutput{}, err
+	}
+
+	err = executeTransfer(ctx, savepoint, p.accountDAO, p.ledgerDAO, p.outboxEventDAO, p.webhookDeliveryDAO, *transferSchema, senderAccount.Id,
+		receiverAccount.Id)
+
+	var domainError *domainerrors.DomainError
+
+	switch {
+	case err == nil:
+		if err := savepoint.Commit(ctx); err != nil {
+			return ProcessTransferUsecaseOutput{}, err
+		}
+
+		transferSchema.Status = daos.TransferStatusCompleted
+	case errors.As(err, &domainError):
+		if err := savepoint.Rollback(ctx); err != nil {
+			return ProcessTransferUsecaseOutput{}, err
+		}
+
+		transferSchema.Status = daos.TransferStatusFailed
+		transferSchema.FailureCode = &domainError.Code
+		transferSchema.FailureReason = &domainError.Message
+	default:
+		return ProcessTransferUsecaseOutput{}, err
+	}
+
+	transferSchema.UpdatedAt = time.Now().UTC()
+
+	if err := p.transferDAO.UpdateStatus(ctx, tx, *transferSchema); err != nil {
+		return ProcessTransferUsecaseOutput{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return ProcessTransferUsecaseOutput{}, err
+	}
+
+	return ProcessTransferUsecaseOutput{Status: transferSchema.Status}, nil
+}
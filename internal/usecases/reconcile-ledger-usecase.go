@@ -0,0 +1,109 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+)
+
+type ReconcileLedgerUsecaseInput struct {
+	Fix bool
+}
+
+type ReconcileLedgerUsecaseOutputDrift struct {
+	AccountId       uuid.UUID
+	CustomerId      uuid.UUID
+	CachedBalance   int64
+	ExpectedBalance int64
+	LedgerBalance   int64
+	Fixed           bool
+}
+
+type ReconcileLedgerUsecaseOutputOrphanedTransaction struct {
+	TransactionId uuid.UUID
+	LedgerEntries int64
+}
+
+type ReconcileLedgerUsecaseOutput struct {
+	AccountsChecked      int
+	Drifts               []ReconcileLedgerUsecaseOutputDrift
+	OrphanedTransactions []ReconcileLedgerUsecaseOutputOrphanedTransaction
+	TotalBalances        int64
+	TotalGranted         int64
+}
+
+// Balanced reports whether every invariant holds. Drifts that were fixed still count as a
+// mismatch, since the fact that they happened needs to be looked into.
+func (r ReconcileLedgerUsecaseOutput) Balanced() bool {
+	return len(r.Drifts) == 0 && len(r.OrphanedTransactions) == 0 && r.TotalBalances == r.TotalGranted
+}
+
+type ReconcileLedgerUsecase struct {
+	reconciliationDAO daos.ReconciliationDAO
+}
+
+func NewReconcileLedgerUsecase(reconciliationDAO daos.ReconciliationDAO) ReconcileLedgerUsecase {
+	return ReconcileLedgerUsecase{reconciliationDAO}
+}
+
+func (r *ReconcileLedgerUsecase) Execute(ctx context.Context, input ReconcileLedgerUsecaseInput) (ReconcileLedgerUsecaseOutput, error) {
+	accountsReconciliationSchema, err := r.reconciliationDAO.FindAllAccounts(ctx)
+	if err != nil {
+		return ReconcileLedgerUsecaseOutput{}, err
+	}
+
+	drifts := []ReconcileLedgerUsecaseOutputDrift{}
+
+	for _, account := range accountsReconciliationSchema {
+		expectedBalance := account.InitialGrant + account.Inbound - account.Outbound
+
+		if account.CachedBalance == expectedBalance && account.LedgerBalance == expectedBalance {
+			continue
+		}
+
+		drift := ReconcileLedgerUsecaseOutputDrift{
+			AccountId:       account.AccountId,
+			CustomerId:      account.CustomerId,
+			CachedBalance:   account.CachedBalance,
+			ExpectedBalance: expectedBalance,
+			LedgerBalance:   account.LedgerBalance,
+		}
+
+		if input.Fix && account.CachedBalance != expectedBalance {
+			drift.Fixed, err = r.reconciliationDAO.UpdateBalance(ctx, account.AccountId, account.CachedBalance, expectedBalance)
+			if err != nil {
+				return ReconcileLedgerUsecaseOutput{}, err
+			}
+		}
+
+		drifts = append(drifts, drift)
+	}
+
+	orphanedTransactionsSchema, err := r.reconciliationDAO.FindAllOrphanedTransactions(ctx)
+	if err != nil {
+		return ReconcileLedgerUsecaseOutput{}, err
+	}
+
+	orphanedTransactions := []ReconcileLedgerUsecaseOutputOrphanedTransaction{}
+
+	for _, orphanedTransaction := range orphanedTransactionsSchema {
+		orphanedTransactions = append(orphanedTransactions, ReconcileLedgerUsecaseOutputOrphanedTransaction{
+			TransactionId: orphanedTransaction.TransactionId,
+			LedgerEntries: orphanedTransaction.LedgerEntries,
+		})
+	}
+
+	moneySupply, err := r.reconciliationDAO.FindMoneySupply(ctx)
+	if err != nil {
+		return ReconcileLedgerUsecaseOutput{}, err
+	}
+
+	return ReconcileLedgerUsecaseOutput{
+		AccountsChecked:      len(accountsReconciliationSchema),
+		Drifts:               drifts,
+		OrphanedTransactions: orphanedTransactions,
+		TotalBalances:        moneySupply.TotalBalances,
+		TotalGranted:         moneySupply.TotalGranted,
+	}, nil
+}
@@ -0,0 +1,105 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	domainerrors "github.com/gsaaraujo/pay-bank-api/internal/domain-errors"
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RefreshTokenUsecaseInput struct {
+	RefreshToken string
+}
+
+type RefreshTokenUsecaseOutput struct {
+	CustomerId   uuid.UUID
+	AccessToken  string
+	RefreshToken string
+}
+
+type RefreshTokenUsecase struct {
+	pgxPool                *pgxpool.Pool
+	refreshTokenDAO        daos.RefreshTokenDAO
+	customerRoleDAO        daos.CustomerRoleDAO
+	revokedAccessTokenDAO  daos.RevokedAccessTokenDAO
+	accessTokenKeysGateway gateways.AccessTokenKeysGateway
+}
+
+func NewRefreshTokenUsecase(pgxPool *pgxpool.Pool, refreshTokenDAO daos.RefreshTokenDAO, customerRoleDAO daos.CustomerRoleDAO,
+	revokedAccessTokenDAO daos.RevokedAccessTokenDAO, accessTokenKeysGateway gateways.AccessTokenKeysGateway) RefreshTokenUsecase {
+	return RefreshTokenUsecase{pgxPool, refreshTokenDAO, customerRoleDAO, revokedAccessTokenDAO, accessTokenKeysGateway}
+}
+
+// Execute exchanges a refresh token for a new access token and a new refresh token. Each
+// refresh token can be exchanged once; presenting one that was already rotated means it
+// leaked, so the whole family is revoked, along with the access tokens of its session as on
+// logout, and the customer has to log in again. Roles are read
+// again on every refresh, so role changes reach the customer within one access token lifetime.
+func (r *RefreshTokenUsecase) Execute(ctx context.Context, input RefreshTokenUsecaseInput) (RefreshTokenUsecaseOutput, error) {
+	tx, err := r.pgxPool.Begin(ctx)
+	if err != nil {
+		return RefreshTokenUsecaseOutput{}, err
+	}
+
+	defer func() {
+		_ = tx.Rollback(context.WithoutCancel(ctx))
+	}()
+
+	current, err := r.refreshTokenDAO.FindOneByTokenHashForUpdate(ctx, tx, hashOpaqueToken(input.RefreshToken))
+	if err != nil {
+		return RefreshTokenUsecaseOutput{}, err
+	}
+
+	if current == nil || current.RevokedAt != nil || !time.Now().Before(current.ExpiresAt) {
+		return RefreshTokenUsecaseOutput{}, domainerrors.ErrRefreshTokenInvalid
+	}
+
+	if current.ReplacedBy != nil {
+		if err := r.refreshTokenDAO.RevokeFamily(ctx, tx, current.FamilyId, time.Now().UTC()); err != nil {
+			return RefreshTokenUsecaseOutput{}, err
+		}
+
+		if err := tx.Commit(ctx); err != nil {
+			return RefreshTokenUsecaseOutput{}, err
+		}
+
+		if err := r.revokedAccessTokenDAO.CreateForSession(ctx, current.FamilyId, accessTokenTTL); err != nil {
+			return RefreshTokenUsecaseOutput{}, err
+		}
+
+		return RefreshTokenUsecaseOutput{}, domainerrors.ErrRefreshTokenReused
+	}
+
+	refreshToken, next, err := newRefreshToken(current.CustomerId, current.FamilyId)
+	if err != nil {
+		return RefreshTokenUsecaseOutput{}, err
+	}
+
+	if err := r.refreshTokenDAO.Rotate(ctx, tx, current.Id, next); err != nil {
+		return RefreshTokenUsecaseOutput{}, err
+	}
+
+	roles, err := findCustomerRoles(ctx, r.customerRoleDAO, current.CustomerId)
+	if err != nil {
+		return RefreshTokenUsecaseOutput{}, err
+	}
+
+	accessToken, err := issueAccessToken(ctx, r.accessTokenKeysGateway, current.CustomerId, current.FamilyId, roles)
+	if err != nil {
+		return RefreshTokenUsecaseOutput{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return RefreshTokenUsecaseOutput{}, err
+	}
+
+	return RefreshTokenUsecaseOutput{
+		CustomerId:   current.CustomerId,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
@@ -0,0 +1,82 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RelayOutboxEventsUsecaseInput struct {
+	BatchSize int
+}
+
+type RelayOutboxEventsUsecaseOutput struct {
+	Published int
+}
+
+type RelayOutboxEventsUsecase struct {
+	pgxPool               *pgxpool.Pool
+	outboxEventDAO        daos.OutboxEventDAO
+	eventPublisherGateway gateways.EventPublisherGateway
+}
+
+func NewRelayOutboxEventsUsecase(pgxPool *pgxpool.Pool, outboxEventDAO daos.OutboxEventDAO,
+	eventPublisherGateway gateways.EventPublisherGateway) RelayOutboxEventsUsecase {
+	return RelayOutboxEventsUsecase{pgxPool, outboxEventDAO, eventPublisherGateway}
+}
+
+// Execute publishes the oldest unpublished events, in order, and marks the confirmed ones as
+// published. An event is only marked once the broker confirmed it, so a crash in between
+// publishes it again: delivery is at least once and consumers must dedupe by message id.
+func (r *RelayOutboxEventsUsecase) Execute(ctx context.Context, input RelayOutboxEventsUsecaseInput) (RelayOutboxEventsUsecaseOutput, error) {
+	tx, err := r.pgxPool.Begin(ctx)
+	if err != nil {
+		return RelayOutboxEventsUsecaseOutput{}, err
+	}
+
+	defer func() {
+		_ = tx.Rollback(context.WithoutCancel(ctx))
+	}()
+
+	outboxEventsSchema, err := r.outboxEventDAO.FindAllUnpublishedForUpdate(ctx, tx, input.BatchSize)
+	if err != nil {
+		return RelayOutboxEventsUsecaseOutput{}, err
+	}
+
+	publishedIds := []uuid.UUID{}
+	var publishErr error
+
+	for _, outboxEventSchema := range outboxEventsSchema {
+		publishErr = r.eventPublisherGateway.Publish(ctx, gateways.Event{
+			Id:         outboxEventSchema.Id,
+			Type:       outboxEventSchema.EventType,
+			Payload:    outboxEventSchema.Payload,
+			OccurredAt: outboxEventSchema.CreatedAt,
+		})
+		if publishErr != nil {
+			break
+		}
+
+		publishedIds = append(publishedIds, outboxEventSchema.Id)
+	}
+
+	// The events confirmed before a failure are still marked, so they are not published again
+	// on the next run.
+	if len(publishedIds) > 0 {
+		err := r.outboxEventDAO.MarkAllAsPublished(context.WithoutCancel(ctx), tx, publishedIds, time.Now().UTC())
+		if err != nil {
+			return RelayOutboxEventsUsecaseOutput{}, errors.Join(publishErr, err)
+		}
+
+		if err := tx.Commit(context.WithoutCancel(ctx)); err != nil {
+			return RelayOutboxEventsUsecaseOutput{}, errors.Join(publishErr, err)
+		}
+	}
+
+	return RelayOutboxEventsUsecaseOutput{Published: len(publishedIds)}, publishErr
+}
@@ -0,0 +1,80 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RequeuePendingTransfersUsecaseInput struct {
+	// PendingFor is how long a transfer must have been pending, since it was accepted or last
+	// requeued, to be requeued.
+	PendingFor time.Duration
+	BatchSize  int
+}
+
+type RequeuePendingTransfersUsecaseOutput struct {
+	Requeued int
+}
+
+type RequeuePendingTransfersUsecase struct {
+	pgxPool        *pgxpool.Pool
+	transferDAO    daos.TransferDAO
+	outboxEventDAO daos.OutboxEventDAO
+}
+
+func NewRequeuePendingTransfersUsecase(pgxPool *pgxpool.Pool, transferDAO daos.TransferDAO,
+	outboxEventDAO daos.OutboxEventDAO) RequeuePendingTransfersUsecase {
+	return RequeuePendingTransfersUsecase{pgxPool, transferDAO, outboxEventDAO}
+}
+
+// Execute writes a new transfer requested event for the transfers pending for longer than
+// input.PendingFor, whose message may have been lost, and touches them so they are requeued
+// again only once as much time has passed. A transfer whose first message was merely slow is
+// processed once all the same, since ProcessTransferUsecase ignores transfers no longer pending.
+func (r *RequeuePendingTransfersUsecase) Execute(ctx context.Context,
+	input RequeuePendingTransfersUsecaseInput) (RequeuePendingTransfersUsecaseOutput, error) {
+	tx, err := r.pgxPool.Begin(ctx)
+	if err != nil {
+		return RequeuePendingTransfersUsecaseOutput{}, err
+	}
+
+	defer func() {
+		_ = tx.Rollback(context.WithoutCancel(ctx))
+	}()
+
+	transfersSchema, err := r.transferDAO.FindAllPendingForUpdate(ctx, tx, time.Now().UTC().Add(-input.PendingFor), input.BatchSize)
+	if err != nil {
+		return RequeuePendingTransfersUsecaseOutput{}, err
+	}
+
+	for _, transferSchema := range transfersSchema {
+		outboxEventSchema, err := newOutboxEvent(EventTypeTransferRequested, transferSchema.Id, TransferRequestedEvent{
+			TransferId:         transferSchema.Id,
+			SenderCustomerId:   transferSchema.SenderCustomerId,
+			ReceiverCustomerId: transferSchema.ReceiverCustomerId,
+			Amount:             transferSchema.Amount,
+			RequestedAt:        transferSchema.CreatedAt,
+		})
+		if err != nil {
+			return RequeuePendingTransfersUsecaseOutput{}, err
+		}
+
+		if err := r.outboxEventDAO.Create(ctx, tx, outboxEventSchema); err != nil {
+			return RequeuePendingTransfersUsecaseOutput{}, err
+		}
+
+		transferSchema.UpdatedAt = time.Now().UTC()
+		if err := r.transferDAO.UpdateStatus(ctx, tx, transferSchema); err != nil {
+			return RequeuePendingTransfersUsecaseOutput{}, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return RequeuePendingTransfersUsecaseOutput{}, err
+	}
+
+	return RequeuePendingTransfersUsecaseOutput{Requeued: len(transfersSchema)}, nil
+}
@@ -0,0 +1,59 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ResendEmailVerificationUsecaseInput struct {
+	Email string
+}
+
+type ResendEmailVerificationUsecase struct {
+	pgxPool                   *pgxpool.Pool
+	customerDAO               daos.CustomerDAO
+	emailVerificationTokenDAO daos.EmailVerificationTokenDAO
+	notifierGateway           gateways.NotifierGateway
+}
+
+func NewResendEmailVerificationUsecase(pgxPool *pgxpool.Pool, customerDAO daos.CustomerDAO,
+	emailVerificationTokenDAO daos.EmailVerificationTokenDAO, notifierGateway gateways.NotifierGateway) ResendEmailVerificationUsecase {
+	return ResendEmailVerificationUsecase{pgxPool, customerDAO, emailVerificationTokenDAO, notifierGateway}
+}
+
+// Execute sends a new verification token to a customer still pending verification, replacing
+// the previous one. Like ForgotPasswordUsecase, it succeeds for any email so it cannot be used
+// to find out which emails are registered, and its notifier should send in the background.
+func (r *ResendEmailVerificationUsecase) Execute(ctx context.Context, input ResendEmailVerificationUsecaseInput) error {
+	customerSchema, err := r.customerDAO.FindOneByEmail(ctx, input.Email)
+	if err != nil {
+		return err
+	}
+
+	if customerSchema == nil || customerSchema.Status != daos.CustomerStatusPendingVerification {
+		return nil
+	}
+
+	tx, err := r.pgxPool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback(context.WithoutCancel(ctx))
+	}()
+
+	token, err := newEmailVerificationToken(ctx, tx, r.emailVerificationTokenDAO, customerSchema.Id)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	return sendEmailVerification(ctx, r.notifierGateway, customerSchema.Email, token)
+}
@@ -0,0 +1,92 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	domainerrors "github.com/gsaaraujo/pay-bank-api/internal/domain-errors"
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ResetPasswordUsecaseInput struct {
+	Token    string
+	Password string
+}
+
+type ResetPasswordUsecase struct {
+	pgxPool                  *pgxpool.Pool
+	customerDAO              daos.CustomerDAO
+	passwordResetTokenDAO    daos.PasswordResetTokenDAO
+	refreshTokenDAO          daos.RefreshTokenDAO
+	revokedAccessTokenDAO    daos.RevokedAccessTokenDAO
+	passwordPolicyGateway    gateways.PasswordPolicyGateway
+	breachedPasswordsGateway gateways.BreachedPasswordsGateway
+}
+
+func NewResetPasswordUsecase(pgxPool *pgxpool.Pool, customerDAO daos.CustomerDAO, passwordResetTokenDAO daos.PasswordResetTokenDAO,
+	refreshTokenDAO daos.RefreshTokenDAO, revokedAccessTokenDAO daos.RevokedAccessTokenDAO, passwordPolicyGateway gateways.PasswordPolicyGateway,
+	breachedPasswordsGateway gateways.BreachedPasswordsGateway) ResetPasswordUsecase {
+	return ResetPasswordUsecase{pgxPool, customerDAO, passwordResetTokenDAO, refreshTokenDAO, revokedAccessTokenDAO,
+		passwordPolicyGateway, breachedPasswordsGateway}
+}
+
+// Execute sets a new password with a token sent by ForgotPasswordUsecase and signs the customer
+// out everywhere, since whoever had the old password may still hold a session.
+func (r *ResetPasswordUsecase) Execute(ctx context.Context, input ResetPasswordUsecaseInput) error {
+	tx, err := r.pgxPool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback(context.WithoutCancel(ctx))
+	}()
+
+	passwordResetTokenSchema, err := r.passwordResetTokenDAO.FindOneByTokenHashForUpdate(ctx, tx, hashOpaqueToken(input.Token))
+	if err != nil {
+		return err
+	}
+
+	if passwordResetTokenSchema == nil || passwordResetTokenSchema.UsedAt != nil || !time.Now().Before(passwordResetTokenSchema.ExpiresAt) {
+		return domainerrors.ErrPasswordResetTokenInvalid
+	}
+
+	customerId := passwordResetTokenSchema.CustomerId
+
+	customerSchema, err := r.customerDAO.FindOneById(ctx, customerId)
+	if err != nil {
+		return err
+	}
+
+	if customerSchema == nil {
+		return errors.New("customer was not found")
+	}
+
+	err = validatePassword(ctx, r.passwordPolicyGateway, r.breachedPasswordsGateway, "password", input.Password, customerSchema.Name,
+		customerSchema.Email)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := hashPassword(input.Password)
+	if err != nil {
+		return err
+	}
+
+	if err := r.customerDAO.UpdatePassword(ctx, tx, customerId, hashedPassword, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	if err := r.passwordResetTokenDAO.MarkAllAsUsedByCustomerId(ctx, tx, customerId, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	if err := revokeAllSessions(ctx, tx, r.refreshTokenDAO, r.revokedAccessTokenDAO, customerId); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
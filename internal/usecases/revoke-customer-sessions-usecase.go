@@ -0,0 +1,53 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	domainerrors "github.com/gsaaraujo/pay-bank-api/internal/domain-errors"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RevokeCustomerSessionsUsecaseInput struct {
+	CustomerId uuid.UUID
+}
+
+type RevokeCustomerSessionsUsecase struct {
+	pgxPool               *pgxpool.Pool
+	customerDAO           daos.CustomerDAO
+	refreshTokenDAO       daos.RefreshTokenDAO
+	revokedAccessTokenDAO daos.RevokedAccessTokenDAO
+}
+
+func NewRevokeCustomerSessionsUsecase(pgxPool *pgxpool.Pool, customerDAO daos.CustomerDAO, refreshTokenDAO daos.RefreshTokenDAO,
+	revokedAccessTokenDAO daos.RevokedAccessTokenDAO) RevokeCustomerSessionsUsecase {
+	return RevokeCustomerSessionsUsecase{pgxPool, customerDAO, refreshTokenDAO, revokedAccessTokenDAO}
+}
+
+// Execute signs the customer out of every device, for when an account is reported compromised.
+func (r *RevokeCustomerSessionsUsecase) Execute(ctx context.Context, input RevokeCustomerSessionsUsecaseInput) error {
+	customerSchema, err := r.customerDAO.FindOneById(ctx, input.CustomerId)
+	if err != nil {
+		return err
+	}
+
+	if customerSchema == nil {
+		return domainerrors.ErrCustomerNotFound
+	}
+
+	tx, err := r.pgxPool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback(context.WithoutCancel(ctx))
+	}()
+
+	if err := revokeAllSessions(ctx, tx, r.refreshTokenDAO, r.revokedAccessTokenDAO, input.CustomerId); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,32 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+)
+
+const (
+	RoleCustomer = "customer"
+	RoleSupport  = "support"
+	RoleAdmin    = "admin"
+	RoleAuditor  = "auditor"
+)
+
+// findCustomerRoles returns the roles that go in the access token: customer, which every
+// customer has, followed by the staff roles assigned in customer_roles.
+func findCustomerRoles(ctx context.Context, customerRoleDAO daos.CustomerRoleDAO, customerId uuid.UUID) ([]string, error) {
+	customerRolesSchema, err := customerRoleDAO.FindAllByCustomerId(ctx, customerId)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := []string{RoleCustomer}
+
+	for _, customerRoleSchema := range customerRolesSchema {
+		roles = append(roles, customerRoleSchema.Role)
+	}
+
+	return roles, nil
+}
@@ -0,0 +1,154 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	accessTokenTTL  = 30 * time.Minute
+	refreshTokenTTL = 30 * 24 * time.Hour
+)
+
+// JwtAccessTokenClaims carries the SessionId (sid) of the refresh token family it was issued
+// with, so logging out ends both. Its ID (jti) is a UUIDv7, whose timestamp tells when the token
+// was issued more precisely than iat, see IssuedAtExact.
+type JwtAccessTokenClaims struct {
+	Roles     []string `json:"roles"`
+	SessionId string   `json:"sid"`
+	jwt.RegisteredClaims
+}
+
+// IssuedAtExact returns when the token was issued, to a fraction of a microsecond, read from its
+// jti. iat only has second precision, which cannot tell a token issued right after a revocation
+// of every token of the customer from one issued right before it. A jti that is not a UUIDv7
+// falls back to iat, which must have been checked to be present.
+func (j *JwtAccessTokenClaims) IssuedAtExact() time.Time {
+	tokenId, err := uuid.Parse(j.ID)
+	if err != nil || tokenId.Version() != 7 {
+		return j.IssuedAt.Time
+	}
+
+	// uuid.NewV7 writes the milliseconds since the epoch in the first 48 bits and, in the 12
+	// after the version, the nanoseconds into that millisecond divided by 256.
+	milliseconds := int64(binary.BigEndian.Uint64(tokenId[:8]) >> 16)
+	fraction := int64(binary.BigEndian.Uint16(tokenId[6:8]) & 0x0fff)
+
+	return time.UnixMilli(milliseconds).Add(time.Duration(fraction << 8)).UTC()
+}
+
+func issueAccessToken(ctx context.Context, accessTokenKeysGateway gateways.AccessTokenKeysGateway, customerId uuid.UUID,
+	sessionId uuid.UUID, roles []string) (string, error) {
+	signingKey, err := accessTokenKeysGateway.SigningKey(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	tokenId, err := uuid.NewV7()
+	if err != nil {
+		return "", err
+	}
+
+	accessToken := jwt.NewWithClaims(jwt.SigningMethodEdDSA, JwtAccessTokenClaims{
+		Roles:     roles,
+		SessionId: sessionId.String(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenId.String(),
+			Subject:   customerId.String(),
+			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(accessTokenTTL)),
+		},
+	})
+
+	accessToken.Header["kid"] = signingKey.Id
+
+	return accessToken.SignedString(signingKey.PrivateKey)
+}
+
+// startSession opens a new session for the customer, a refresh token family along with the
+// first access token of it.
+func startSession(ctx context.Context, customerRoleDAO daos.CustomerRoleDAO, refreshTokenDAO daos.RefreshTokenDAO,
+	accessTokenKeysGateway gateways.AccessTokenKeysGateway, customerId uuid.UUID) (string, string, error) {
+	roles, err := findCustomerRoles(ctx, customerRoleDAO, customerId)
+	if err != nil {
+		return "", "", err
+	}
+
+	refreshToken, refreshTokenSchema, err := newRefreshToken(customerId, uuid.New())
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := refreshTokenDAO.Create(ctx, refreshTokenSchema); err != nil {
+		return "", "", err
+	}
+
+	accessToken, err := issueAccessToken(ctx, accessTokenKeysGateway, customerId, refreshTokenSchema.FamilyId, roles)
+	if err != nil {
+		return "", "", err
+	}
+
+	return accessToken, refreshToken, nil
+}
+
+// revokeAllSessions signs the customer out everywhere: every refresh token is revoked in tx and
+// every access token issued up to now is denied until the longest lived of them would have
+// expired. The denial is written before tx commits, so the change that asked for it, such as a
+// new password, is never committed while the sessions still work; if tx fails to commit after
+// all, the customer only has to refresh.
+func revokeAllSessions(ctx context.Context, tx pgx.Tx, refreshTokenDAO daos.RefreshTokenDAO,
+	revokedAccessTokenDAO daos.RevokedAccessTokenDAO, customerId uuid.UUID) error {
+	revokedAt := time.Now().UTC()
+
+	if err := refreshTokenDAO.RevokeAllByCustomerId(ctx, tx, customerId, revokedAt); err != nil {
+		return err
+	}
+
+	return revokedAccessTokenDAO.CreateForCustomer(ctx, customerId, revokedAt, accessTokenTTL)
+}
+
+// newRefreshToken returns an opaque random token along with the schema to store it. The token
+// itself is never persisted, only its hash.
+func newRefreshToken(customerId uuid.UUID, familyId uuid.UUID) (string, daos.RefreshTokenSchema, error) {
+	refreshToken, err := newOpaqueToken()
+	if err != nil {
+		return "", daos.RefreshTokenSchema{}, err
+	}
+
+	return refreshToken, daos.RefreshTokenSchema{
+		Id:         uuid.New(),
+		FamilyId:   familyId,
+		CustomerId: customerId,
+		TokenHash:  hashOpaqueToken(refreshToken),
+		ExpiresAt:  time.Now().UTC().Add(refreshTokenTTL),
+		CreatedAt:  time.Now().UTC(),
+	}, nil
+}
+
+// newOpaqueToken returns 256 random bits, base64url encoded. Such tokens are looked up by
+// hashOpaqueToken, so only their hash needs to be stored.
+func newOpaqueToken() (string, error) {
+	randomBytes := make([]byte, 32)
+
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(randomBytes), nil
+}
+
+func hashOpaqueToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
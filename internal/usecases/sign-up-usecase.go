@@ -2,15 +2,14 @@ package usecases
 
 import (
 	"context"
-	"errors"
 	"net/mail"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/gsaaraujo/pay-bank-api/internal/daos"
-	"github.com/gsaaraujo/pay-bank-api/internal/utils"
+	domainerrors "github.com/gsaaraujo/pay-bank-api/internal/domain-errors"
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
 	"github.com/jackc/pgx/v5/pgxpool"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type SignUpUsecaseInput struct {
@@ -20,52 +19,105 @@ type SignUpUsecaseInput struct {
 }
 
 type SignUpUsecase struct {
-	pgxPool     *pgxpool.Pool
-	customerDAO daos.CustomerDAO
+	pgxPool                   *pgxpool.Pool
+	customerDAO               daos.CustomerDAO
+	emailVerificationTokenDAO daos.EmailVerificationTokenDAO
+	outboxEventDAO            daos.OutboxEventDAO
+	passwordPolicyGateway     gateways.PasswordPolicyGateway
+	breachedPasswordsGateway  gateways.BreachedPasswordsGateway
+	notifierGateway           gateways.NotifierGateway
 }
 
-func NewSignUpUsecase(pgxPool *pgxpool.Pool, customerDAO daos.CustomerDAO) SignUpUsecase {
-	return SignUpUsecase{pgxPool, customerDAO}
+func NewSignUpUsecase(pgxPool *pgxpool.Pool, customerDAO daos.CustomerDAO, emailVerificationTokenDAO daos.EmailVerificationTokenDAO,
+	outboxEventDAO daos.OutboxEventDAO, passwordPolicyGateway gateways.PasswordPolicyGateway,
+	breachedPasswordsGateway gateways.BreachedPasswordsGateway, notifierGateway gateways.NotifierGateway) SignUpUsecase {
+	return SignUpUsecase{pgxPool, customerDAO, emailVerificationTokenDAO, outboxEventDAO, passwordPolicyGateway, breachedPasswordsGateway,
+		notifierGateway}
 }
 
-func (s SignUpUsecase) Execute(input SignUpUsecaseInput) error {
+// Execute creates the customer pending verification, with an empty account, and sends the
+// token that verifies the email address. The sign up bonus is only granted once it is verified.
+// The notifier should send in the background: the customer is already created by then, and a
+// failed email can be sent again through ResendEmailVerificationUsecase.
+func (s SignUpUsecase) Execute(ctx context.Context, input SignUpUsecaseInput) error {
 	if len(input.Name) < 2 {
-		return errors.New("name must be at least 2 characters")
+		return domainerrors.ErrNameTooShort
 	}
 
 	_, err := mail.ParseAddress(input.Email)
 	if err != nil {
-		return errors.New("email address is invalid")
+		return domainerrors.ErrEmailAddressInvalid
 	}
 
-	if len(input.Password) < 6 {
-		return errors.New("password must be at least 6 characters")
+	err = validatePassword(ctx, s.passwordPolicyGateway, s.breachedPasswordsGateway, "password", input.Password, input.Name,
+		input.Email)
+	if err != nil {
+		return err
 	}
 
-	customerSchema := s.customerDAO.FindOneByEmail(input.Email)
+	customerSchema, err := s.customerDAO.FindOneByEmail(ctx, input.Email)
+	if err != nil {
+		return err
+	}
 
 	if customerSchema != nil {
-		return errors.New("this email address has already been taken by someone")
+		return domainerrors.ErrEmailAddressAlreadyTaken
 	}
 
-	hashedPassword := utils.GetOrThrow(bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost))
+	hashedPassword, err := hashPassword(input.Password)
+	if err != nil {
+		return err
+	}
 
-	tx := utils.GetOrThrow(s.pgxPool.Begin(context.TODO()))
+	tx, err := s.pgxPool.Begin(ctx)
+	if err != nil {
+		return err
+	}
 
 	defer func() {
-		_ = tx.Rollback(context.TODO())
+		_ = tx.Rollback(context.WithoutCancel(ctx))
 	}()
 
 	customerId := uuid.New()
+	accountId := uuid.New()
 
-	_ = utils.GetOrThrow(tx.Exec(context.Background(),
-		"INSERT INTO customers (id, name, email, password, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6)",
-		customerId, input.Name, input.Email, string(hashedPassword), time.Now().UTC(), time.Now().UTC()))
+	_, err = tx.Exec(ctx,
+		"INSERT INTO customers (id, name, email, password, status, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7)",
+		customerId, input.Name, input.Email, hashedPassword, daos.CustomerStatusPendingVerification, time.Now().UTC(), time.Now().UTC())
+	if err != nil {
+		return err
+	}
 
-	_ = utils.GetOrThrow(tx.Exec(context.Background(),
+	_, err = tx.Exec(ctx,
 		"INSERT INTO accounts (id, customer_id, balance, created_at, updated_at) VALUES ($1, $2, $3, $4, $5)",
-		uuid.New(), customerId, 100000, time.Now().UTC(), time.Now().UTC()))
+		accountId, customerId, 0, time.Now().UTC(), time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	outboxEventSchema, err := newOutboxEvent(EventTypeCustomerSignedUp, customerId, CustomerSignedUpEvent{
+		CustomerId: customerId,
+		AccountId:  accountId,
+		Name:       input.Name,
+		Email:      input.Email,
+		SignedUpAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.outboxEventDAO.Create(ctx, tx, outboxEventSchema); err != nil {
+		return err
+	}
+
+	token, err := newEmailVerificationToken(ctx, tx, s.emailVerificationTokenDAO, customerId)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
 
-	utils.ThrowOnError(tx.Commit(context.TODO()))
-	return nil
+	return sendEmailVerification(ctx, s.notifierGateway, input.Email, token)
 }
@@ -0,0 +1,134 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	domainerrors "github.com/gsaaraujo/pay-bank-api/internal/domain-errors"
+	"github.com/gsaaraujo/pay-bank-api/internal/utils"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	totpIssuer               = "PayBank"
+	totpRecoveryCodesCount   = 10
+	totpRecoveryCodeAlphabet = "abcdefghijklmnopqrstuvwxyz234567"
+	// totpMaxFailures is how many wrong codes a customer may submit, whatever the endpoint,
+	// before being locked out as logins are.
+	totpMaxFailures = 10
+)
+
+// totpAttempt is a code counted as wrong before it is checked, so that concurrent guesses
+// cannot get past totpMaxFailures. It stays counted unless succeed is called.
+type totpAttempt struct {
+	loginAttemptDAO daos.LoginAttemptDAO
+	loginLockoutDAO daos.LoginLockoutDAO
+	subject         loginSubject
+	reservations    []daos.LoginAttemptReservation
+}
+
+// reserveTotpAttempt fails with ErrTotpLocked while the customer is locked out.
+func reserveTotpAttempt(ctx context.Context, loginAttemptDAO daos.LoginAttemptDAO, loginLockoutDAO daos.LoginLockoutDAO,
+	customerId uuid.UUID) (totpAttempt, error) {
+	subject := loginSubject{daos.LoginLockoutSubjectTypeTotp, customerId.String(), totpMaxFailures}
+
+	reservations, lockoutRemaining, err := reserveLoginAttempt(ctx, loginAttemptDAO, []loginSubject{subject})
+	if err != nil {
+		return totpAttempt{}, err
+	}
+
+	if lockoutRemaining > 0 {
+		return totpAttempt{}, domainerrors.NewRetryAfterError(domainerrors.ErrTotpLocked, lockoutRemaining)
+	}
+
+	return totpAttempt{loginAttemptDAO, loginLockoutDAO, subject, reservations}, nil
+}
+
+// succeed refunds the attempt and starts the customer's counter over.
+func (t totpAttempt) succeed(ctx context.Context) error {
+	if err := t.loginAttemptDAO.RefundAttempt(ctx, t.reservations...); err != nil {
+		return err
+	}
+
+	return t.loginAttemptDAO.DeleteFailures(ctx, t.subject.key())
+}
+
+// fail keeps a record of the lockout the wrong code caused, if any, and returns the error for
+// the code itself.
+func (t totpAttempt) fail(ctx context.Context) error {
+	if err := recordLoginLockouts(ctx, t.loginLockoutDAO, []loginSubject{t.subject}, t.reservations); err != nil {
+		return err
+	}
+
+	return domainerrors.ErrTotpCodeInvalid
+}
+
+// useTotpCode accepts a code of the customer's authenticator and records its step, so the same
+// code cannot be used again. customerTotpSchema must have been read with FOR UPDATE in tx.
+func useTotpCode(ctx context.Context, tx pgx.Tx, customerTotpDAO daos.CustomerTotpDAO, customerTotpSchema *daos.CustomerTotpSchema,
+	code string) (bool, error) {
+	step, ok := utils.ValidateTotpCode(customerTotpSchema.Secret, code, time.Now(), customerTotpSchema.LastUsedStep)
+	if !ok {
+		return false, nil
+	}
+
+	if err := customerTotpDAO.UpdateLastUsedStep(ctx, tx, customerTotpSchema.CustomerId, step, time.Now().UTC()); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// useTotpRecoveryCode spends one of the customer's recovery codes.
+func useTotpRecoveryCode(ctx context.Context, tx pgx.Tx, totpRecoveryCodeDAO daos.TotpRecoveryCodeDAO, customerId uuid.UUID,
+	code string) (bool, error) {
+	return totpRecoveryCodeDAO.MarkOneAsUsed(ctx, tx, customerId, hashTotpRecoveryCode(code), time.Now().UTC())
+}
+
+// newTotpRecoveryCodes returns codes of 10 lowercase base32 characters, formatted as
+// "xxxxx-xxxxx", along with the schemas to store them.
+func newTotpRecoveryCodes(customerId uuid.UUID) ([]string, []daos.TotpRecoveryCodeSchema, error) {
+	recoveryCodes := make([]string, 0, totpRecoveryCodesCount)
+	totpRecoveryCodesSchema := make([]daos.TotpRecoveryCodeSchema, 0, totpRecoveryCodesCount)
+
+	for range totpRecoveryCodesCount {
+		randomBytes := make([]byte, 10)
+
+		if _, err := rand.Read(randomBytes); err != nil {
+			return nil, nil, err
+		}
+
+		var recoveryCode strings.Builder
+
+		for i, randomByte := range randomBytes {
+			if i == 5 {
+				recoveryCode.WriteByte('-')
+			}
+
+			recoveryCode.WriteByte(totpRecoveryCodeAlphabet[randomByte&31])
+		}
+
+		recoveryCodes = append(recoveryCodes, recoveryCode.String())
+		totpRecoveryCodesSchema = append(totpRecoveryCodesSchema, daos.TotpRecoveryCodeSchema{
+			Id:         uuid.New(),
+			CustomerId: customerId,
+			CodeHash:   hashTotpRecoveryCode(recoveryCode.String()),
+			CreatedAt:  time.Now().UTC(),
+		})
+	}
+
+	return recoveryCodes, totpRecoveryCodesSchema, nil
+}
+
+// hashTotpRecoveryCode ignores case and the dash, so codes can be typed either way.
+func hashTotpRecoveryCode(recoveryCode string) string {
+	normalized := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(recoveryCode)), "-", "")
+	hash := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(hash[:])
+}
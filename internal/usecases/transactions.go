@@ -0,0 +1,43 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	domainerrors "github.com/gsaaraujo/pay-bank-api/internal/domain-errors"
+)
+
+// findCustomerTransaction returns a transaction along with its direction for the customer,
+// "sent" or "received". A transaction the customer took no part in is not found, so its
+// existence is not disclosed.
+func findCustomerTransaction(ctx context.Context, accountDAO daos.AccountDAO, transactionDAO daos.TransactionDAO, customerId uuid.UUID,
+	transactionId uuid.UUID) (*daos.TransactionHistorySchema, string, error) {
+	account, err := accountDAO.FindOneByCustomerId(ctx, customerId)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if account == nil {
+		return nil, "", errors.New("customer account was not found")
+	}
+
+	transactionSchema, err := transactionDAO.FindOneHistoryById(ctx, transactionId)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if transactionSchema == nil {
+		return nil, "", domainerrors.ErrTransactionNotFound
+	}
+
+	switch account.Id {
+	case transactionSchema.AccountSenderId:
+		return transactionSchema, "sent", nil
+	case transactionSchema.AccountReceiverId:
+		return transactionSchema, "received", nil
+	default:
+		return nil, "", domainerrors.ErrTransactionNotFound
+	}
+}
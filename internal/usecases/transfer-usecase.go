@@ -2,12 +2,11 @@ package usecases
 
 import (
 	"context"
-	"errors"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/gsaaraujo/pay-bank-api/internal/daos"
-	"github.com/gsaaraujo/pay-bank-api/internal/utils"
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -16,59 +15,121 @@ type TransferUsecaseInput struct {
 	ReceiverCustomerId uuid.UUID
 	IdempotencyKey     uuid.UUID
 	Amount             int64
+	// TotpCode is only checked when Amount is over the TRANSFER_TOTP_THRESHOLD.
+	TotpCode string
+}
+
+type TransferUsecaseOutput struct {
+	TransferId uuid.UUID
+	Replayed   bool
+	// Outcome is TransferOutcomeCompleted or TransferOutcomeAccepted. A
+	// replayed request reports the outcome of the first one, which may have been made the other
+	// way.
+	Outcome string
 }
 
 type TransferUsecase struct {
-	pgxPool        *pgxpool.Pool
-	accountDAO     daos.AccountDAO
-	transactionDAO daos.TransactionDAO
+	pgxPool            *pgxpool.Pool
+	customerDAO        daos.CustomerDAO
+	accountDAO         daos.AccountDAO
+	transactionDAO     daos.TransactionDAO
+	idempotencyKeyDAO  daos.IdempotencyKeyDAO
+	ledgerDAO          daos.LedgerDAO
+	customerTotpDAO    daos.CustomerTotpDAO
+	transferDAO        daos.TransferDAO
+	outboxEventDAO     daos.OutboxEventDAO
+	webhookDeliveryDAO daos.WebhookDeliveryDAO
+	loginAttemptDAO    daos.LoginAttemptDAO
+	loginLockoutDAO    daos.LoginLockoutDAO
+	configGateway      gateways.ConfigGateway
 }
 
-func NewTransferUsecase(pgxPool *pgxpool.Pool, accountDAO daos.AccountDAO, transactionDAO daos.TransactionDAO) TransferUsecase {
-	return TransferUsecase{pgxPool, accountDAO, transactionDAO}
+func NewTransferUsecase(pgxPool *pgxpool.Pool, customerDAO daos.CustomerDAO, accountDAO daos.AccountDAO, transactionDAO daos.TransactionDAO,
+	idempotencyKeyDAO daos.IdempotencyKeyDAO, ledgerDAO daos.LedgerDAO, customerTotpDAO daos.CustomerTotpDAO, transferDAO daos.TransferDAO,
+	outboxEventDAO daos.OutboxEventDAO, webhookDeliveryDAO daos.WebhookDeliveryDAO, loginAttemptDAO daos.LoginAttemptDAO,
+	loginLockoutDAO daos.LoginLockoutDAO, configGateway gateways.ConfigGateway) TransferUsecase {
+	return TransferUsecase{pgxPool, customerDAO, accountDAO, transactionDAO, idempotencyKeyDAO, ledgerDAO, customerTotpDAO, transferDAO,
+		outboxEventDAO, webhookDeliveryDAO, loginAttemptDAO, loginLockoutDAO, configGateway}
 }
 
-func (t *TransferUsecase) Execute(input TransferUsecaseInput) error {
-	if input.SenderCustomerId == input.ReceiverCustomerId {
-		return errors.New("you cannot transfer to yourself")
+// Execute transfers right away. The transfer is recorded as completed, so it can be looked up
+// like the ones accepted by AcceptTransferUsecase; a failed attempt records nothing.
+func (t *TransferUsecase) Execute(ctx context.Context, input TransferUsecaseInput) (TransferUsecaseOutput, error) {
+	senderAccount, receiverAccount, err := findTransferAccounts(ctx, t.customerDAO, t.accountDAO, input.SenderCustomerId,
+		input.ReceiverCustomerId, input.Amount)
+	if err != nil {
+		return TransferUsecaseOutput{}, err
 	}
 
-	if input.Amount == 0 {
-		return errors.New("the amount to be transferred cannot be zero")
+	tx, err := t.pgxPool.Begin(ctx)
+	if err != nil {
+		return TransferUsecaseOutput{}, err
 	}
 
-	transaction := t.transactionDAO.FindOneByIdempotencyKey(input.IdempotencyKey)
+	defer func() {
+		_ = tx.Rollback(context.WithoutCancel(ctx))
+	}()
 
-	if transaction != nil {
-		return nil
+	idempotencyKey, err := claimTransferIdempotencyKey(ctx, tx, t.idempotencyKeyDAO, input.SenderCustomerId, input.IdempotencyKey,
+		input.ReceiverCustomerId, input.Amount)
+	if err != nil {
+		return TransferUsecaseOutput{}, err
 	}
 
-	senderAccount := t.accountDAO.FindOneByCustomerId(input.SenderCustomerId)
-	receiverAccount := t.accountDAO.FindOneByCustomerId(input.ReceiverCustomerId)
+	if idempotencyKey != nil {
+		return TransferUsecaseOutput{
+			TransferId: *idempotencyKey.TransferId,
+			Replayed:   true,
+			Outcome:    *idempotencyKey.Outcome,
+		}, nil
+	}
 
-	if senderAccount == nil {
-		panic("sender account was not found")
+	err = verifyTransferTotp(ctx, tx, t.configGateway, t.customerTotpDAO, t.loginAttemptDAO, t.loginLockoutDAO, input.SenderCustomerId,
+		input.Amount, input.TotpCode)
+	if err != nil {
+		return TransferUsecaseOutput{}, err
 	}
 
-	if receiverAccount == nil {
-		panic("receiver account was not found")
+	transferSchema := daos.TransferSchema{
+		Id:                 uuid.New(),
+		SenderCustomerId:   input.SenderCustomerId,
+		ReceiverCustomerId: input.ReceiverCustomerId,
+		IdempotencyKey:     input.IdempotencyKey.String(),
+		Amount:             input.Amount,
+		Status:             daos.TransferStatusCompleted,
+		CreatedAt:          time.Now().UTC(),
+		UpdatedAt:          time.Now().UTC(),
 	}
 
-	if senderAccount.Balance < input.Amount {
-		return errors.New("the sender does not have enough balance to make the transfer")
+	if err := t.transferDAO.Create(ctx, tx, transferSchema); err != nil {
+		return TransferUsecaseOutput{}, err
 	}
 
-	tx := utils.GetOrThrow(t.pgxPool.Begin(context.TODO()))
-	defer func() {
-		_ = tx.Rollback(context.TODO())
-	}()
+	err = executeTransfer(ctx, tx, t.accountDAO, t.ledgerDAO, t.outboxEventDAO, t.webhookDeliveryDAO, transferSchema, senderAccount.Id,
+		receiverAccount.Id)
+	if err != nil {
+		return TransferUsecaseOutput{}, err
+	}
+
+	output := TransferUsecaseOutput{
+		TransferId: transferSchema.Id,
+		Outcome:    TransferOutcomeCompleted,
+	}
 
-	_ = utils.GetOrThrow(tx.Exec(context.TODO(), "UPDATE accounts SET balance = balance - $1 WHERE id = $2", input.Amount, senderAccount.Id))
-	_ = utils.GetOrThrow(tx.Exec(context.TODO(), "UPDATE accounts SET balance = balance + $1 WHERE id = $2", input.Amount, receiverAccount.Id))
-	_ = utils.GetOrThrow(tx.Exec(context.TODO(),
-		"INSERT INTO transactions (id, account_sender_id, account_receiver_id, idempotency_key, amount, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7)",
-		uuid.New(), senderAccount.Id, receiverAccount.Id, input.IdempotencyKey, input.Amount, time.Now().UTC(), time.Now().UTC()))
+	err = t.idempotencyKeyDAO.UpdateResult(ctx, tx, daos.IdempotencyKeySchema{
+		CustomerId:     input.SenderCustomerId,
+		IdempotencyKey: input.IdempotencyKey.String(),
+		TransferId:     &output.TransferId,
+		Outcome:        &output.Outcome,
+		UpdatedAt:      time.Now().UTC(),
+	})
+	if err != nil {
+		return TransferUsecaseOutput{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return TransferUsecaseOutput{}, err
+	}
 
-	utils.ThrowOnError(tx.Commit(context.TODO()))
-	return nil
+	return output, nil
 }
@@ -0,0 +1,228 @@
+package usecases
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	domainerrors "github.com/gsaaraujo/pay-bank-api/internal/domain-errors"
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
+	"github.com/jackc/pgx/v5"
+)
+
+// A transfer request either completes the transfer right away or accepts it to be processed
+// later. The outcome is kept with the idempotency key, so retries are answered the same way.
+const (
+	TransferOutcomeCompleted = daos.IdempotencyKeyOutcomeCompleted
+	TransferOutcomeAccepted  = daos.IdempotencyKeyOutcomeAccepted
+)
+
+// findTransferAccounts runs the checks that do not depend on balances, so a transfer accepted
+// to be processed later only fails for lack of balance.
+func findTransferAccounts(ctx context.Context, customerDAO daos.CustomerDAO, accountDAO daos.AccountDAO, senderCustomerId uuid.UUID,
+	receiverCustomerId uuid.UUID, amount int64) (*daos.AccountSchema, *daos.AccountSchema, error) {
+	if senderCustomerId == receiverCustomerId {
+		return nil, nil, domainerrors.ErrTransferToYourself
+	}
+
+	if amount == 0 {
+		return nil, nil, domainerrors.ErrTransferAmountZero
+	}
+
+	senderCustomer, err := customerDAO.FindOneById(ctx, senderCustomerId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if senderCustomer == nil {
+		return nil, nil, errors.New("sender customer was not found")
+	}
+
+	if senderCustomer.Status != daos.CustomerStatusActive {
+		return nil, nil, domainerrors.ErrEmailNotVerified
+	}
+
+	senderAccount, err := accountDAO.FindOneByCustomerId(ctx, senderCustomerId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	receiverAccount, err := accountDAO.FindOneByCustomerId(ctx, receiverCustomerId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if senderAccount == nil {
+		return nil, nil, errors.New("sender account was not found")
+	}
+
+	if receiverAccount == nil {
+		return nil, nil, errors.New("receiver account was not found")
+	}
+
+	return senderAccount, receiverAccount, nil
+}
+
+// claimTransferIdempotencyKey returns nil when the key is claimed by this request, or the key
+// as stored by the request that claimed it first, whose response must be replayed.
+func claimTransferIdempotencyKey(ctx context.Context, tx pgx.Tx, idempotencyKeyDAO daos.IdempotencyKeyDAO, senderCustomerId uuid.UUID,
+	idempotencyKey uuid.UUID, receiverCustomerId uuid.UUID, amount int64) (*daos.IdempotencyKeySchema, error) {
+	requestFingerprint := transferRequestFingerprint(receiverCustomerId, amount)
+
+	claimed, err := idempotencyKeyDAO.CreateIfNotExists(ctx, tx, daos.IdempotencyKeySchema{
+		CustomerId:         senderCustomerId,
+		IdempotencyKey:     idempotencyKey.String(),
+		RequestFingerprint: requestFingerprint,
+		CreatedAt:          time.Now().UTC(),
+		UpdatedAt:          time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if claimed {
+		return nil, nil
+	}
+
+	idempotencyKeySchema, err := idempotencyKeyDAO.FindOneByCustomerIdAndIdempotencyKey(ctx, tx, senderCustomerId, idempotencyKey.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if idempotencyKeySchema.RequestFingerprint != requestFingerprint {
+		return nil, domainerrors.ErrIdempotencyKeyReused
+	}
+
+	return idempotencyKeySchema, nil
+}
+
+func transferRequestFingerprint(receiverCustomerId uuid.UUID, amount int64) string {
+	hash := sha256.Sum256(fmt.Appendf(nil, "%s:%d", receiverCustomerId, amount))
+	return hex.EncodeToString(hash[:])
+}
+
+// verifyTransferTotp requires a fresh code of the sender's authenticator for transfers over the
+// TRANSFER_TOTP_THRESHOLD, in cents. Without the key no transfer requires one. Retries of a
+// transfer are replayed before getting here, so they do not need a new code. Wrong codes count
+// towards the lockout of the customer, as they do when logging in, and the outcome is recorded
+// outside tx, so it stands even when the transfer is rolled back.
+func verifyTransferTotp(ctx context.Context, tx pgx.Tx, configGateway gateways.ConfigGateway, customerTotpDAO daos.CustomerTotpDAO,
+	loginAttemptDAO daos.LoginAttemptDAO, loginLockoutDAO daos.LoginLockoutDAO, senderCustomerId uuid.UUID, amount int64,
+	totpCode string) error {
+	threshold, err := configGateway.GetInt(ctx, "TRANSFER_TOTP_THRESHOLD")
+	if errors.Is(err, gateways.ErrConfigKeyNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if amount <= int64(threshold) {
+		return nil
+	}
+
+	customerTotpSchema, err := customerTotpDAO.FindOneByCustomerIdForUpdate(ctx, tx, senderCustomerId)
+	if err != nil {
+		return err
+	}
+
+	if customerTotpSchema == nil || customerTotpSchema.ConfirmedAt == nil {
+		return domainerrors.ErrTransferRequiresTotp
+	}
+
+	if totpCode == "" {
+		return domainerrors.ErrTotpCodeRequired
+	}
+
+	attempt, err := reserveTotpAttempt(ctx, loginAttemptDAO, loginLockoutDAO, senderCustomerId)
+	if err != nil {
+		return err
+	}
+
+	accepted, err := useTotpCode(ctx, tx, customerTotpDAO, customerTotpSchema, totpCode)
+	if err != nil {
+		return err
+	}
+
+	if !accepted {
+		return attempt.fail(ctx)
+	}
+
+	return attempt.succeed(ctx)
+}
+
+// executeTransfer records the transfer in the ledger, under a transaction sharing its id, and
+// recomputes both cached balances from it. The receiver's webhooks and the outbox are told in
+// tx as well. ErrInsufficientBalance is returned before anything is written.
+func executeTransfer(ctx context.Context, tx pgx.Tx, accountDAO daos.AccountDAO, ledgerDAO daos.LedgerDAO, outboxEventDAO daos.OutboxEventDAO,
+	webhookDeliveryDAO daos.WebhookDeliveryDAO, transferSchema daos.TransferSchema, senderAccountId uuid.UUID, receiverAccountId uuid.UUID) error {
+	lockedAccounts, err := accountDAO.FindAllByIdsForUpdate(ctx, tx, []uuid.UUID{senderAccountId, receiverAccountId})
+	if err != nil {
+		return err
+	}
+
+	for _, lockedAccount := range lockedAccounts {
+		if lockedAccount.Id == senderAccountId && lockedAccount.Balance < transferSchema.Amount {
+			return domainerrors.ErrInsufficientBalance
+		}
+	}
+
+	_, err = tx.Exec(ctx,
+		"INSERT INTO transactions (id, account_sender_id, account_receiver_id, idempotency_key, amount, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7)",
+		transferSchema.Id, senderAccountId, receiverAccountId, transferSchema.IdempotencyKey, transferSchema.Amount, time.Now().UTC(), time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	err = ledgerDAO.CreateAll(ctx, tx, []daos.LedgerEntrySchema{
+		{
+			Id:        uuid.New(),
+			JournalId: transferSchema.Id,
+			AccountId: &senderAccountId,
+			Direction: daos.LedgerDirectionDebit,
+			Amount:    transferSchema.Amount,
+			CreatedAt: time.Now().UTC(),
+		},
+		{
+			Id:        uuid.New(),
+			JournalId: transferSchema.Id,
+			AccountId: &receiverAccountId,
+			Direction: daos.LedgerDirectionCredit,
+			Amount:    transferSchema.Amount,
+			CreatedAt: time.Now().UTC(),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = accountDAO.UpdateBalancesFromLedger(ctx, tx, []uuid.UUID{senderAccountId, receiverAccountId}, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	outboxEventSchema, err := newOutboxEvent(EventTypeTransferCompleted, transferSchema.Id, TransferCompletedEvent{
+		TransactionId:      transferSchema.Id,
+		SenderCustomerId:   transferSchema.SenderCustomerId,
+		SenderAccountId:    senderAccountId,
+		ReceiverCustomerId: transferSchema.ReceiverCustomerId,
+		ReceiverAccountId:  receiverAccountId,
+		Amount:             transferSchema.Amount,
+		CompletedAt:        time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := outboxEventDAO.Create(ctx, tx, outboxEventSchema); err != nil {
+		return err
+	}
+
+	return createWebhookDeliveries(ctx, tx, webhookDeliveryDAO, transferSchema.ReceiverCustomerId, outboxEventSchema)
+}
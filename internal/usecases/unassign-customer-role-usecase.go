@@ -0,0 +1,45 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	domainerrors "github.com/gsaaraujo/pay-bank-api/internal/domain-errors"
+)
+
+type UnassignCustomerRoleUsecaseInput struct {
+	CustomerId uuid.UUID
+	Role       string
+}
+
+type UnassignCustomerRoleUsecase struct {
+	customerDAO           daos.CustomerDAO
+	customerRoleDAO       daos.CustomerRoleDAO
+	revokedAccessTokenDAO daos.RevokedAccessTokenDAO
+}
+
+func NewUnassignCustomerRoleUsecase(customerDAO daos.CustomerDAO, customerRoleDAO daos.CustomerRoleDAO,
+	revokedAccessTokenDAO daos.RevokedAccessTokenDAO) UnassignCustomerRoleUsecase {
+	return UnassignCustomerRoleUsecase{customerDAO, customerRoleDAO, revokedAccessTokenDAO}
+}
+
+// Execute takes a staff role away. The access tokens already issued still carry it, so they
+// are revoked; the customer stays signed in and the next refresh issues a token without it.
+func (u *UnassignCustomerRoleUsecase) Execute(ctx context.Context, input UnassignCustomerRoleUsecaseInput) error {
+	customerSchema, err := u.customerDAO.FindOneById(ctx, input.CustomerId)
+	if err != nil {
+		return err
+	}
+
+	if customerSchema == nil {
+		return domainerrors.ErrCustomerNotFound
+	}
+
+	if err := u.customerRoleDAO.DeleteOneByCustomerIdAndRole(ctx, input.CustomerId, input.Role); err != nil {
+		return err
+	}
+
+	return u.revokedAccessTokenDAO.CreateForCustomer(ctx, input.CustomerId, time.Now().UTC(), accessTokenTTL)
+}
@@ -0,0 +1,113 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	domainerrors "github.com/gsaaraujo/pay-bank-api/internal/domain-errors"
+	"github.com/gsaaraujo/pay-bank-api/internal/utils"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const signUpBonusAmount = 100000
+
+type VerifyEmailUsecaseInput struct {
+	Token string
+}
+
+type VerifyEmailUsecase struct {
+	pgxPool                   *pgxpool.Pool
+	customerDAO               daos.CustomerDAO
+	accountDAO                daos.AccountDAO
+	emailVerificationTokenDAO daos.EmailVerificationTokenDAO
+	ledgerDAO                 daos.LedgerDAO
+}
+
+func NewVerifyEmailUsecase(pgxPool *pgxpool.Pool, customerDAO daos.CustomerDAO, accountDAO daos.AccountDAO,
+	emailVerificationTokenDAO daos.EmailVerificationTokenDAO, ledgerDAO daos.LedgerDAO) VerifyEmailUsecase {
+	return VerifyEmailUsecase{pgxPool, customerDAO, accountDAO, emailVerificationTokenDAO, ledgerDAO}
+}
+
+// Execute activates the customer the token was sent to and credits the sign up bonus. The
+// bonus is only credited by the call that activates the customer, so it is granted once even
+// when two tokens are used concurrently.
+func (v *VerifyEmailUsecase) Execute(ctx context.Context, input VerifyEmailUsecaseInput) error {
+	tx, err := v.pgxPool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback(context.WithoutCancel(ctx))
+	}()
+
+	emailVerificationTokenSchema, err := v.emailVerificationTokenDAO.FindOneByTokenHashForUpdate(ctx, tx, hashOpaqueToken(input.Token))
+	if err != nil {
+		return err
+	}
+
+	if emailVerificationTokenSchema == nil || emailVerificationTokenSchema.UsedAt != nil ||
+		!time.Now().Before(emailVerificationTokenSchema.ExpiresAt) {
+		return domainerrors.ErrEmailVerificationTokenInvalid
+	}
+
+	customerId := emailVerificationTokenSchema.CustomerId
+
+	if err := v.emailVerificationTokenDAO.MarkAllAsUsedByCustomerId(ctx, tx, customerId, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	activated, err := v.customerDAO.Activate(ctx, tx, customerId, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	if activated {
+		if err := v.creditSignUpBonus(ctx, tx, customerId); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (v *VerifyEmailUsecase) creditSignUpBonus(ctx context.Context, tx pgx.Tx, customerId uuid.UUID) error {
+	accountSchema, err := v.accountDAO.FindOneByCustomerId(ctx, customerId)
+	if err != nil {
+		return err
+	}
+
+	if accountSchema == nil {
+		return errors.New("account was not found")
+	}
+
+	journalId := uuid.New()
+
+	err = v.ledgerDAO.CreateAll(ctx, tx, []daos.LedgerEntrySchema{
+		{
+			Id:            uuid.New(),
+			JournalId:     journalId,
+			SystemAccount: utils.NewPointer(daos.LedgerSystemAccountSignUpBonus),
+			Direction:     daos.LedgerDirectionDebit,
+			Amount:        signUpBonusAmount,
+			CreatedAt:     time.Now().UTC(),
+		},
+		{
+			Id:        uuid.New(),
+			JournalId: journalId,
+			AccountId: &accountSchema.Id,
+			Direction: daos.LedgerDirectionCredit,
+			Amount:    signUpBonusAmount,
+			CreatedAt: time.Now().UTC(),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return v.accountDAO.UpdateBalancesFromLedger(ctx, tx, []uuid.UUID{accountSchema.Id}, time.Now().UTC())
+}
@@ -0,0 +1,138 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	domainerrors "github.com/gsaaraujo/pay-bank-api/internal/domain-errors"
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type VerifyLoginTotpUsecaseInput struct {
+	ChallengeToken string
+	Code           string
+}
+
+type VerifyLoginTotpUsecaseOutput struct {
+	CustomerId   uuid.UUID
+	AccessToken  string
+	RefreshToken string
+}
+
+type VerifyLoginTotpUsecase struct {
+	pgxPool                *pgxpool.Pool
+	loginChallengeDAO      daos.LoginChallengeDAO
+	customerTotpDAO        daos.CustomerTotpDAO
+	totpRecoveryCodeDAO    daos.TotpRecoveryCodeDAO
+	customerRoleDAO        daos.CustomerRoleDAO
+	refreshTokenDAO        daos.RefreshTokenDAO
+	loginAttemptDAO        daos.LoginAttemptDAO
+	loginLockoutDAO        daos.LoginLockoutDAO
+	accessTokenKeysGateway gateways.AccessTokenKeysGateway
+}
+
+func NewVerifyLoginTotpUsecase(pgxPool *pgxpool.Pool, loginChallengeDAO daos.LoginChallengeDAO, customerTotpDAO daos.CustomerTotpDAO,
+	totpRecoveryCodeDAO daos.TotpRecoveryCodeDAO, customerRoleDAO daos.CustomerRoleDAO, refreshTokenDAO daos.RefreshTokenDAO,
+	loginAttemptDAO daos.LoginAttemptDAO, loginLockoutDAO daos.LoginLockoutDAO,
+	accessTokenKeysGateway gateways.AccessTokenKeysGateway) VerifyLoginTotpUsecase {
+	return VerifyLoginTotpUsecase{pgxPool, loginChallengeDAO, customerTotpDAO, totpRecoveryCodeDAO, customerRoleDAO, refreshTokenDAO,
+		loginAttemptDAO, loginLockoutDAO, accessTokenKeysGateway}
+}
+
+// Execute completes a login that was answered with a challenge, taking a code of the
+// authenticator or one of the recovery codes. The challenge is spent on success, or once
+// loginChallengeMaxAttempts wrong codes were tried. Wrong codes also count towards the lockout
+// of the customer, so that new challenges do not allow more guesses.
+func (v *VerifyLoginTotpUsecase) Execute(ctx context.Context, input VerifyLoginTotpUsecaseInput) (VerifyLoginTotpUsecaseOutput, error) {
+	challengeHash := hashOpaqueToken(input.ChallengeToken)
+
+	customerId, err := v.loginChallengeDAO.FindCustomerId(ctx, challengeHash)
+	if err != nil {
+		return VerifyLoginTotpUsecaseOutput{}, err
+	}
+
+	if customerId == nil {
+		return VerifyLoginTotpUsecaseOutput{}, domainerrors.ErrLoginChallengeInvalid
+	}
+
+	attempts, err := v.loginChallengeDAO.IncrementAttempts(ctx, challengeHash)
+	if err != nil {
+		return VerifyLoginTotpUsecaseOutput{}, err
+	}
+
+	if attempts == 0 {
+		return VerifyLoginTotpUsecaseOutput{}, domainerrors.ErrLoginChallengeInvalid
+	}
+
+	if attempts > loginChallengeMaxAttempts {
+		if err := v.loginChallengeDAO.Delete(ctx, challengeHash); err != nil {
+			return VerifyLoginTotpUsecaseOutput{}, err
+		}
+
+		return VerifyLoginTotpUsecaseOutput{}, domainerrors.ErrLoginChallengeInvalid
+	}
+
+	attempt, err := reserveTotpAttempt(ctx, v.loginAttemptDAO, v.loginLockoutDAO, *customerId)
+	if err != nil {
+		return VerifyLoginTotpUsecaseOutput{}, err
+	}
+
+	tx, err := v.pgxPool.Begin(ctx)
+	if err != nil {
+		return VerifyLoginTotpUsecaseOutput{}, err
+	}
+
+	defer func() {
+		_ = tx.Rollback(context.WithoutCancel(ctx))
+	}()
+
+	customerTotpSchema, err := v.customerTotpDAO.FindOneByCustomerIdForUpdate(ctx, tx, *customerId)
+	if err != nil {
+		return VerifyLoginTotpUsecaseOutput{}, err
+	}
+
+	if customerTotpSchema == nil || customerTotpSchema.ConfirmedAt == nil {
+		return VerifyLoginTotpUsecaseOutput{}, domainerrors.ErrLoginChallengeInvalid
+	}
+
+	accepted, err := useTotpCode(ctx, tx, v.customerTotpDAO, customerTotpSchema, input.Code)
+	if err != nil {
+		return VerifyLoginTotpUsecaseOutput{}, err
+	}
+
+	if !accepted {
+		accepted, err = useTotpRecoveryCode(ctx, tx, v.totpRecoveryCodeDAO, *customerId, input.Code)
+		if err != nil {
+			return VerifyLoginTotpUsecaseOutput{}, err
+		}
+	}
+
+	if !accepted {
+		return VerifyLoginTotpUsecaseOutput{}, attempt.fail(ctx)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return VerifyLoginTotpUsecaseOutput{}, err
+	}
+
+	if err := attempt.succeed(ctx); err != nil {
+		return VerifyLoginTotpUsecaseOutput{}, err
+	}
+
+	if err := v.loginChallengeDAO.Delete(ctx, challengeHash); err != nil {
+		return VerifyLoginTotpUsecaseOutput{}, err
+	}
+
+	accessToken, refreshToken, err := startSession(ctx, v.customerRoleDAO, v.refreshTokenDAO, v.accessTokenKeysGateway, *customerId)
+	if err != nil {
+		return VerifyLoginTotpUsecaseOutput{}, err
+	}
+
+	return VerifyLoginTotpUsecaseOutput{
+		CustomerId:   *customerId,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
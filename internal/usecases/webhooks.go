@@ -0,0 +1,104 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/netip"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	domainerrors "github.com/gsaaraujo/pay-bank-api/internal/domain-errors"
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	webhookMaxPerCustomer = 10
+	webhookUrlMaxLength   = 2048
+	// webhookDeliveryMaxAttempts with webhookRetryBaseDelay retry a delivery after 30s, 1m, 2m
+	// and so on, dead lettering it about an hour after the first attempt.
+	webhookDeliveryMaxAttempts = 8
+	webhookRetryBaseDelay      = 30 * time.Second
+)
+
+type Webhook struct {
+	Id        uuid.UUID
+	Url       string
+	CreatedAt time.Time
+}
+
+// WebhookPayload is the body of every webhook delivery.
+type WebhookPayload struct {
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	Data      any       `json:"data"`
+}
+
+// createWebhookDeliveries schedules the event for every webhook of the customer. It must be
+// called in the transaction of the change the event describes, with the event written to the
+// outbox, whose id it shares.
+func createWebhookDeliveries(ctx context.Context, tx pgx.Tx, webhookDeliveryDAO daos.WebhookDeliveryDAO, customerId uuid.UUID,
+	outboxEventSchema daos.OutboxEventSchema) error {
+	payload, err := json.Marshal(WebhookPayload{
+		Type:      outboxEventSchema.EventType,
+		Timestamp: outboxEventSchema.CreatedAt,
+		Data:      json.RawMessage(outboxEventSchema.Payload),
+	})
+	if err != nil {
+		return err
+	}
+
+	return webhookDeliveryDAO.CreateAllByCustomerId(ctx, tx, customerId, outboxEventSchema.Id, outboxEventSchema.EventType, payload,
+		outboxEventSchema.CreatedAt)
+}
+
+// validateWebhookUrl only accepts https URLs, unless WEBHOOK_ALLOW_HTTP is true, which is
+// meant for local environments. A host that is an IP address must be public, while host names
+// are checked by the gateway on every delivery, once resolved.
+func validateWebhookUrl(ctx context.Context, configGateway gateways.ConfigGateway, rawUrl string) error {
+	allowHttp, err := configGateway.GetBool(ctx, "WEBHOOK_ALLOW_HTTP")
+	if errors.Is(err, gateways.ErrConfigKeyNotFound) {
+		allowHttp = false
+	} else if err != nil {
+		return err
+	}
+
+	if len(rawUrl) > webhookUrlMaxLength {
+		return domainerrors.ErrWebhookUrlInvalid
+	}
+
+	parsedUrl, err := url.Parse(rawUrl)
+	if err != nil || parsedUrl.Host == "" || parsedUrl.User != nil || parsedUrl.Fragment != "" {
+		return domainerrors.ErrWebhookUrlInvalid
+	}
+
+	if parsedUrl.Scheme != "https" && (parsedUrl.Scheme != "http" || !allowHttp) {
+		return domainerrors.ErrWebhookUrlInvalid
+	}
+
+	if addr, err := netip.ParseAddr(parsedUrl.Hostname()); err == nil && !gateways.IsPublicAddress(addr) {
+		return domainerrors.ErrWebhookUrlInvalid
+	}
+
+	return nil
+}
+
+func newWebhookSecret() (string, error) {
+	randomBytes := make([]byte, 32)
+
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", err
+	}
+
+	return gateways.WebhookSecretPrefix + base64.StdEncoding.EncodeToString(randomBytes), nil
+}
+
+// webhookRetryDelay is how long to wait before the next attempt once attempts have failed.
+func webhookRetryDelay(attempts int) time.Duration {
+	return webhookRetryBaseDelay << (attempts - 1)
+}
@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TOTP codes follow RFC 6238 with the parameters every authenticator app supports by default:
+// HMAC-SHA1, 6 digits and a 30 second step.
+const (
+	TotpDigits = 6
+	TotpPeriod = 30 * time.Second
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateTotpSecret returns a random 160 bit secret, base32 encoded as authenticator apps
+// expect it.
+func GenerateTotpSecret() (string, error) {
+	secret := make([]byte, 20)
+
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+// TotpUri is the otpauth URI authenticator apps read from a QR code.
+func TotpUri(issuer string, accountName string, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(TotpDigits))
+	query.Set("period", fmt.Sprint(int(TotpPeriod.Seconds())))
+
+	return "otpauth://totp/" + url.PathEscape(issuer+":"+accountName) + "?" + query.Encode()
+}
+
+// TotpStep is the RFC 6238 time step at.
+func TotpStep(at time.Time) int64 {
+	return at.Unix() / int64(TotpPeriod.Seconds())
+}
+
+// TotpCode computes the code of secret for the given step.
+func TotpCode(secret string, step int64) (string, error) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", err
+	}
+
+	return hotp(key, uint64(step), TotpDigits), nil
+}
+
+// ValidateTotpCode checks code against the steps around at, tolerating one step of clock drift
+// each way, and returns the step it matched. Steps up to lastUsedStep are rejected, so a code
+// can only be used once.
+func ValidateTotpCode(secret string, code string, at time.Time, lastUsedStep int64) (int64, bool) {
+	currentStep := TotpStep(at)
+
+	for _, step := range []int64{currentStep - 1, currentStep, currentStep + 1} {
+		if step <= lastUsedStep {
+			continue
+		}
+
+		expectedCode, err := TotpCode(secret, step)
+		if err != nil {
+			return 0, false
+		}
+
+		if subtle.ConstantTimeCompare([]byte(expectedCode), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// hotp is the RFC 4226 HMAC-based one-time password.
+func hotp(key []byte, counter uint64, digits int) string {
+	mac := hmac.New(sha1.New, key)
+	_ = binary.Write(mac, binary.BigEndian, counter)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	truncated := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for range digits {
+		modulo *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, truncated%modulo)
+}
@@ -0,0 +1,86 @@
+package utils_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gsaaraujo/pay-bank-api/internal/utils"
+	"github.com/stretchr/testify/suite"
+)
+
+// rfc6238Secret is the base32 encoding of "12345678901234567890", the SHA1 key of the RFC 6238
+// test vectors.
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+type TotpSuite struct {
+	suite.Suite
+}
+
+func (t *TotpSuite) Test1() {
+	t.Run("given the RFC 6238 test vectors, when computing the codes, then returns their last 6 digits", func() {
+		for unixTime, expectedCode := range map[int64]string{
+			59:          "287082",
+			1111111109:  "081804",
+			1111111111:  "050471",
+			1234567890:  "005924",
+			2000000000:  "279037",
+			20000000000: "353130",
+		} {
+			code, err := utils.TotpCode(rfc6238Secret, utils.TotpStep(time.Unix(unixTime, 0)))
+			t.Require().NoError(err)
+			t.Equal(expectedCode, code, "time %d", unixTime)
+		}
+	})
+}
+
+func (t *TotpSuite) Test2() {
+	t.Run("when validating codes, then accepts one step of drift each way and returns the matched step", func() {
+		at := time.Unix(1111111111, 0)
+
+		for _, unixTime := range []int64{1111111081, 1111111111, 1111111141} {
+			code := utils.GetOrThrow(utils.TotpCode(rfc6238Secret, utils.TotpStep(time.Unix(unixTime, 0))))
+
+			step, ok := utils.ValidateTotpCode(rfc6238Secret, code, at, 0)
+			t.True(ok)
+			t.Equal(utils.TotpStep(time.Unix(unixTime, 0)), step)
+		}
+
+		code := utils.GetOrThrow(utils.TotpCode(rfc6238Secret, utils.TotpStep(at)+2))
+		_, ok := utils.ValidateTotpCode(rfc6238Secret, code, at, 0)
+		t.False(ok)
+
+		_, ok = utils.ValidateTotpCode(rfc6238Secret, "000000", at, 0)
+		t.False(ok)
+	})
+}
+
+func (t *TotpSuite) Test3() {
+	t.Run("given a code already used, when validating it again, then rejects it", func() {
+		at := time.Unix(1111111111, 0)
+		code := utils.GetOrThrow(utils.TotpCode(rfc6238Secret, utils.TotpStep(at)))
+
+		step, ok := utils.ValidateTotpCode(rfc6238Secret, code, at, 0)
+		t.Require().True(ok)
+
+		_, ok = utils.ValidateTotpCode(rfc6238Secret, code, at, step)
+		t.False(ok)
+	})
+}
+
+func (t *TotpSuite) Test4() {
+	t.Run("when generating a secret, then it produces valid codes and an otpauth uri", func() {
+		secret := utils.GetOrThrow(utils.GenerateTotpSecret())
+		t.Len(secret, 32)
+
+		code := utils.GetOrThrow(utils.TotpCode(secret, utils.TotpStep(time.Now())))
+		_, ok := utils.ValidateTotpCode(secret, code, time.Now(), 0)
+		t.True(ok)
+
+		t.Equal("otpauth://totp/PayBank:john.doe@gmail.com?algorithm=SHA1&digits=6&issuer=PayBank&period=30&secret="+secret,
+			utils.TotpUri("PayBank", "john.doe@gmail.com", secret))
+	})
+}
+
+func TestTotp(t *testing.T) {
+	suite.Run(t, new(TotpSuite))
+}
@@ -85,12 +85,13 @@ func isDate(fieldLevel validator.FieldLevel) bool {
 	return err == nil
 }
 
-func (j *JSONBodyValidator) Validate(body any) []string {
+// Validate returns one FieldError per failing rule, in struct field order.
+func (j *JSONBodyValidator) Validate(body any) []FieldError {
 	err := j.validate.Struct(body)
 
 	if err != nil {
 		validationErrors := err.(validator.ValidationErrors)
-		errorMessages := []string{}
+		fieldErrors := []FieldError{}
 
 		for _, validationError := range validationErrors {
 			tag := validationError.Tag()
@@ -98,26 +99,31 @@ func (j *JSONBodyValidator) Validate(body any) []string {
 
 			switch tag {
 			case "required":
-				errorMessages = append(errorMessages, fmt.Sprintf("%s is required", field))
+				fieldErrors = append(fieldErrors, FieldError{field, fmt.Sprintf("%s is required", field)})
+			case "oneof":
+				fieldErrors = append(fieldErrors, FieldError{field, fmt.Sprintf("%s must be one of %s", field,
+					strings.Join(strings.Fields(validationError.Param()), ", "))})
 			case "uuid4":
-				errorMessages = append(errorMessages, fmt.Sprintf("%s must be uuidv4", field))
+				fieldErrors = append(fieldErrors, FieldError{field, fmt.Sprintf("%s must be uuidv4", field)})
 			case "string":
-				errorMessages = append(errorMessages, fmt.Sprintf("%s must be string", field))
+				fieldErrors = append(fieldErrors, FieldError{field, fmt.Sprintf("%s must be string", field)})
 			case "integer":
-				errorMessages = append(errorMessages, fmt.Sprintf("%s must be integer", field))
+				fieldErrors = append(fieldErrors, FieldError{field, fmt.Sprintf("%s must be integer", field)})
 			case "notEmpty":
-				errorMessages = append(errorMessages, fmt.Sprintf("%s must not be empty", field))
+				fieldErrors = append(fieldErrors, FieldError{field, fmt.Sprintf("%s must not be empty", field)})
 			case "positive":
-				errorMessages = append(errorMessages, fmt.Sprintf("%s must be positive", field))
+				fieldErrors = append(fieldErrors, FieldError{field, fmt.Sprintf("%s must be positive", field)})
 			case "timeRFC3339":
-				errorMessages = append(errorMessages, fmt.Sprintf("%s must follow format yyyy-mm-ddThh:mm:ssZ", field))
+				fieldErrors = append(fieldErrors, FieldError{field, fmt.Sprintf("%s must follow format yyyy-mm-ddThh:mm:ssZ", field)})
 			case "date":
-				errorMessages = append(errorMessages, fmt.Sprintf("%s must follow format yyyy-mm-dd", field))
+				fieldErrors = append(fieldErrors, FieldError{field, fmt.Sprintf("%s must follow format yyyy-mm-dd", field)})
+			case "timeRFC3339|date":
+				fieldErrors = append(fieldErrors, FieldError{field, fmt.Sprintf("%s must follow format yyyy-mm-ddThh:mm:ssZ or yyyy-mm-dd", field)})
 			}
 		}
 
-		return errorMessages
+		return fieldErrors
 	}
 
-	return []string{}
+	return []FieldError{}
 }
@@ -0,0 +1,77 @@
+package webhttp
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const MIMEApplicationPDF = "application/pdf"
+
+// NewTextPDF renders a title followed by lines of text on a single A4 page, in the standard
+// Helvetica fonts every PDF reader ships with. It covers what receipts need and nothing more:
+// lines are not wrapped and characters outside Latin-1 are printed as "?".
+func NewTextPDF(title string, lines []string) []byte {
+	var content strings.Builder
+
+	fmt.Fprintf(&content, "BT /F2 18 Tf 56 780 Td (%s) Tj ET\n", pdfString(title))
+	content.WriteString("BT /F1 11 Tf 56 740 Td 18 TL\n")
+
+	for _, line := range lines {
+		fmt.Fprintf(&content, "(%s) Tj T*\n", pdfString(line))
+	}
+
+	content.WriteString("ET\n")
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] /Resources << /Font << /F1 4 0 R /F2 5 0 R >> >> /Contents 6 0 R >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+		fmt.Sprintf("<< /Title (%s) /Producer (pay-bank-api) >>", pdfString(title)),
+	}
+
+	var pdf bytes.Buffer
+	offsets := make([]int, 0, len(objects))
+
+	pdf.WriteString("%PDF-1.4\n")
+
+	for i, object := range objects {
+		offsets = append(offsets, pdf.Len())
+		fmt.Fprintf(&pdf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xrefOffset := pdf.Len()
+
+	fmt.Fprintf(&pdf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+
+	for _, offset := range offsets {
+		fmt.Fprintf(&pdf, "%010d 00000 n \n", offset)
+	}
+
+	fmt.Fprintf(&pdf, "trailer\n<< /Size %d /Root 1 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, len(objects), xrefOffset)
+
+	return pdf.Bytes()
+}
+
+// pdfString escapes text for a PDF literal string in WinAnsiEncoding, which matches Latin-1
+// outside of the 0x80 to 0x9F range.
+func pdfString(text string) string {
+	var escaped strings.Builder
+
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			escaped.WriteByte('\\')
+			escaped.WriteRune(r)
+		case (r >= 0x20 && r < 0x7F) || (r >= 0xA0 && r <= 0xFF):
+			escaped.WriteByte(byte(r))
+		default:
+			escaped.WriteByte('?')
+		}
+	}
+
+	return escaped.String()
+}
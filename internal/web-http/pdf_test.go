@@ -0,0 +1,94 @@
+package webhttp_test
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	webhttp "github.com/gsaaraujo/pay-bank-api/internal/web-http"
+	"github.com/stretchr/testify/suite"
+)
+
+var (
+	xrefEntryRegexp = regexp.MustCompile(`^(\d{10}) 00000 n $`)
+	startxrefRegexp = regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`)
+)
+
+type PdfSuite struct {
+	suite.Suite
+}
+
+func (p *PdfSuite) Test1() {
+	p.Run("when rendering text with parentheses and backslashes, then escapes them in the literal strings", func() {
+		pdf := webhttp.NewTextPDF(`Receipt (copy)`, []string{`C:\transfers`, `a) b( c\d`})
+
+		p.Require().Contains(string(pdf), `BT /F2 18 Tf 56 780 Td (Receipt \(copy\)) Tj ET`)
+		p.Require().Contains(string(pdf), `(C:\\transfers) Tj T*`)
+		p.Require().Contains(string(pdf), `(a\) b\( c\\d) Tj T*`)
+		p.Require().Contains(string(pdf), `/Title (Receipt \(copy\))`)
+	})
+
+	p.Run("when rendering text outside of Latin-1, then prints it as a question mark", func() {
+		pdf := webhttp.NewTextPDF("Receipt", []string{"R$ 10,00 \u20ac ação"})
+
+		p.Require().Contains(string(pdf), "(R$ 10,00 ? a\xe7\xe3o) Tj T*")
+	})
+}
+
+func (p *PdfSuite) Test2() {
+	p.Run("when rendering a pdf, then every xref offset points at its object", func() {
+		pdf := webhttp.NewTextPDF("Transfer receipt", []string{"Amount: 10.00", "Status (completed)"})
+
+		offsets := p.xrefOffsets(pdf)
+		p.Require().Len(offsets, 7)
+
+		for i, offset := range offsets {
+			p.Require().Less(offset, len(pdf))
+			p.Require().True(bytes.HasPrefix(pdf[offset:], fmt.Appendf(nil, "%d 0 obj\n", i+1)),
+				"xref entry %d points at %q", i+1, pdf[offset:min(offset+16, len(pdf))])
+		}
+	})
+}
+
+func (p *PdfSuite) Test3() {
+	p.Run("when rendering a pdf, then startxref points at the xref section", func() {
+		pdf := webhttp.NewTextPDF("Transfer receipt", []string{"Amount: 10.00"})
+
+		match := startxrefRegexp.FindSubmatch(pdf)
+		p.Require().NotNil(match)
+
+		startxref, err := strconv.Atoi(string(match[1]))
+		p.Require().NoError(err)
+		p.Require().Equal(bytes.LastIndex(pdf, []byte("\nxref\n"))+1, startxref)
+		p.Require().True(bytes.HasPrefix(pdf[startxref:], []byte("xref\n0 8\n0000000000 65535 f \n")))
+	})
+}
+
+// xrefOffsets returns the byte offsets listed in the xref section, in object number order.
+func (p *PdfSuite) xrefOffsets(pdf []byte) []int {
+	_, xref, found := bytes.Cut(pdf, []byte("\nxref\n"))
+	p.Require().True(found)
+
+	lines := bytes.Split(xref, []byte("\n"))
+	p.Require().Equal("0000000000 65535 f ", string(lines[1]))
+
+	var offsets []int
+	for _, line := range lines[2:] {
+		match := xrefEntryRegexp.FindSubmatch(line)
+		if match == nil {
+			break
+		}
+
+		offset, err := strconv.Atoi(string(match[1]))
+		p.Require().NoError(err)
+		offsets = append(offsets, offset)
+	}
+
+	return offsets
+}
+
+func TestPdf(t *testing.T) {
+	suite.Run(t, new(PdfSuite))
+}
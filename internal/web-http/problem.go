@@ -0,0 +1,215 @@
+package webhttp
+
+import (
+	"errors"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+
+	domainerrors "github.com/gsaaraujo/pay-bank-api/internal/domain-errors"
+	"github.com/labstack/echo/v4"
+)
+
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// statusCodes is the single place where domain errors are mapped to HTTP status codes.
+var statusCodes = map[*domainerrors.DomainError]int{
+	domainerrors.ErrNameTooShort:              409,
+	domainerrors.ErrEmailAddressInvalid:       409,
+	domainerrors.ErrEmailAddressAlreadyTaken:  409,
+	domainerrors.ErrEmailOrPasswordIncorrect:  409,
+	domainerrors.ErrRefreshTokenInvalid:       401,
+	domainerrors.ErrRefreshTokenReused:        401,
+	domainerrors.ErrAccessTokenRevoked:        401,
+	domainerrors.ErrCustomerNotFound:          404,
+	domainerrors.ErrLoginLocked:               429,
+	domainerrors.ErrLoginChallengeInvalid:     401,
+	domainerrors.ErrPasswordResetTokenInvalid: 400,
+	domainerrors.ErrCurrentPasswordIncorrect:  409,
+	domainerrors.ErrRateLimitExceeded:         429,
+
+	domainerrors.ErrEmailVerificationTokenInvalid: 400,
+	domainerrors.ErrEmailNotVerified:              403,
+
+	domainerrors.ErrTotpAlreadyEnabled: 409,
+	domainerrors.ErrTotpNotEnrolled:    409,
+	domainerrors.ErrTotpNotEnabled:     409,
+	domainerrors.ErrTotpCodeRequired:   403,
+	domainerrors.ErrTotpCodeInvalid:    409,
+	domainerrors.ErrTotpLocked:         429,
+
+	domainerrors.ErrTransferToYourself:     409,
+	domainerrors.ErrTransferAmountZero:     409,
+	domainerrors.ErrInsufficientBalance:    409,
+	domainerrors.ErrTransferRequiresTotp:   403,
+	domainerrors.ErrIdempotencyKeyRequired: 400,
+	domainerrors.ErrIdempotencyKeyInvalid:  400,
+	domainerrors.ErrIdempotencyKeyReused:   422,
+	domainerrors.ErrTransferNotFound:       404,
+
+	domainerrors.ErrWebhookUrlInvalid:   400,
+	domainerrors.ErrWebhookLimitReached: 409,
+	domainerrors.ErrWebhookNotFound:     404,
+
+	domainerrors.ErrTransactionNotFound: 404,
+
+	domainerrors.ErrHistoryLimitOutOfRange: 400,
+	domainerrors.ErrHistoryRangeInvalid:    400,
+	domainerrors.ErrHistoryCursorInvalid:   400,
+}
+
+// Problem is an RFC 9457 problem details document. Code is an extension member carrying the
+// same stable machine code as the domain error, so clients never have to parse Type.
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     string       `json:"code"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError is returned by handlers when the request does not pass JSONBodyValidator.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func NewValidationError(fieldErrors []FieldError) *ValidationError {
+	return &ValidationError{fieldErrors}
+}
+
+func (v *ValidationError) Error() string {
+	return "request validation failed"
+}
+
+// NewProblemHTTPErrorHandler renders every error returned by handlers and middlewares as
+// application/problem+json. Anything that is not a domain, validation or echo error is a bug
+// and is reported as a 500 without leaking its message.
+func NewProblemHTTPErrorHandler(logger *slog.Logger) echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		if c.Response().Committed {
+			return
+		}
+
+		problem := newProblem(err)
+		problem.Instance = c.Response().Header().Get(echo.HeaderXRequestID)
+
+		var retryAfterError *domainerrors.RetryAfterError
+		if errors.As(err, &retryAfterError) {
+			retryAfter := int(math.Ceil(retryAfterError.RetryAfter.Seconds()))
+			c.Response().Header().Set(echo.HeaderRetryAfter, strconv.Itoa(max(retryAfter, 1)))
+		}
+
+		if problem.Status >= 500 {
+			logger.LogAttrs(c.Request().Context(), slog.LevelError, err.Error(),
+				slog.String("request_id", problem.Instance),
+			)
+		}
+
+		if c.Request().Method == http.MethodHead {
+			err = c.NoContent(problem.Status)
+		} else {
+			c.Response().Header().Set(echo.HeaderContentType, MIMEApplicationProblemJSON)
+			err = c.JSON(problem.Status, problem)
+		}
+
+		if err != nil {
+			logger.Error(err.Error())
+		}
+	}
+}
+
+func newProblem(err error) Problem {
+	var domainError *domainerrors.DomainError
+	var validationError *ValidationError
+	var passwordPolicyError *domainerrors.PasswordPolicyError
+	var httpError *echo.HTTPError
+
+	if errors.As(err, &domainError) {
+		if status, ok := statusCodes[domainError]; ok {
+			return Problem{
+				Type:   "/problems/" + domainError.Code,
+				Title:  http.StatusText(status),
+				Status: status,
+				Detail: domainError.Message,
+				Code:   domainError.Code,
+			}
+		}
+	}
+
+	if errors.As(err, &validationError) {
+		return Problem{
+			Type:   "/problems/validation_failed",
+			Title:  http.StatusText(400),
+			Status: 400,
+			Detail: "the request has one or more invalid fields",
+			Code:   "validation_failed",
+			Errors: validationError.Errors,
+		}
+	}
+
+	if errors.As(err, &passwordPolicyError) {
+		fieldErrors := make([]FieldError, 0, len(passwordPolicyError.Violations))
+
+		for _, violation := range passwordPolicyError.Violations {
+			fieldErrors = append(fieldErrors, FieldError{passwordPolicyError.Field, violation.Message})
+		}
+
+		return Problem{
+			Type:   "/problems/validation_failed",
+			Title:  http.StatusText(400),
+			Status: 400,
+			Detail: "the request has one or more invalid fields",
+			Code:   "validation_failed",
+			Errors: fieldErrors,
+		}
+	}
+
+	if errors.As(err, &httpError) {
+		return Problem{
+			Type:   "about:blank",
+			Title:  http.StatusText(httpError.Code),
+			Status: httpError.Code,
+			Code:   httpStatusCode(httpError.Code),
+		}
+	}
+
+	return Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(500),
+		Status: 500,
+		Code:   "internal_server_error",
+	}
+}
+
+func httpStatusCode(status int) string {
+	switch status {
+	case 400:
+		return "bad_request"
+	case 401:
+		return "unauthorized"
+	case 403:
+		return "forbidden"
+	case 404:
+		return "not_found"
+	case 405:
+		return "method_not_allowed"
+	case 415:
+		return "unsupported_media_type"
+	case 429:
+		return "too_many_requests"
+	case 500:
+		return "internal_server_error"
+	case 503:
+		return "service_unavailable"
+	default:
+		return "http_error"
+	}
+}
@@ -0,0 +1,81 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"github.com/gsaaraujo/pay-bank-api/internal/daos"
+	"github.com/gsaaraujo/pay-bank-api/internal/gateways"
+	"github.com/gsaaraujo/pay-bank-api/internal/usecases"
+	"github.com/gsaaraujo/pay-bank-api/internal/utils"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	webhookDeliveryBatchSize = 20
+	// webhookTimeout bounds each attempt, so a slow webhook only delays the ones after it.
+	webhookTimeout = 10 * time.Second
+)
+
+// WebhookDeliveryWorker delivers the due webhook deliveries until its context is cancelled.
+// Several workers may run at once, each attempting different deliveries.
+type WebhookDeliveryWorker struct {
+	logger *slog.Logger
+}
+
+func NewWebhookDeliveryWorker() *WebhookDeliveryWorker {
+	return &WebhookDeliveryWorker{
+		logger: slog.New(slog.NewJSONHandler(os.Stderr, nil)),
+	}
+}
+
+func (w *WebhookDeliveryWorker) Run(ctx context.Context) (exitCode int) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			w.logger.Error("webhook delivery failed", "error", rec, "stack_trace", string(debug.Stack()))
+			exitCode = 2
+		}
+	}()
+
+	configGateway := utils.GetOrThrow(gateways.NewConfigGateway(ctx, time.Minute))
+	utils.ThrowOnError(gateways.ValidateConfig(ctx, configGateway, []string{"POSTGRES_URL"}))
+
+	postgresUrl := utils.GetOrThrow(configGateway.GetString(ctx, "POSTGRES_URL"))
+
+	// WEBHOOK_ALLOW_PRIVATE_NETWORKS lets local environments deliver to webhooks on their own
+	// network.
+	allowPrivateNetworks, err := configGateway.GetBool(ctx, "WEBHOOK_ALLOW_PRIVATE_NETWORKS")
+	if errors.Is(err, gateways.ErrConfigKeyNotFound) {
+		allowPrivateNetworks = false
+	} else {
+		utils.ThrowOnError(err)
+	}
+
+	pgxPool := utils.GetOrThrow(pgxpool.New(ctx, postgresUrl))
+	defer pgxPool.Close()
+
+	deliverWebhooksUsecase := usecases.NewDeliverWebhooksUsecase(pgxPool, daos.NewWebhookDAO(pgxPool), daos.NewWebhookDeliveryDAO(pgxPool),
+		gateways.NewHttpWebhookGateway(webhookTimeout, allowPrivateNetworks))
+
+	w.logger.Info("webhook delivery started")
+
+	runPollLoop(ctx, w.logger, webhookDeliveryBatchSize, func(ctx context.Context) (int, error) {
+		output, err := deliverWebhooksUsecase.Execute(ctx, usecases.DeliverWebhooksUsecaseInput{
+			BatchSize: webhookDeliveryBatchSize,
+		})
+
+		if output.Delivered > 0 || output.Failed > 0 {
+			w.logger.Info("webhooks attempted", "delivered", output.Delivered, "failed", output.Failed)
+		}
+
+		return output.Delivered + output.Failed, err
+	})
+
+	w.logger.Info("webhook delivery stopped")
+
+	return 0
+}
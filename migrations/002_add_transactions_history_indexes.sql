@@ -0,0 +1,5 @@
+CREATE INDEX IF NOT EXISTS transactions_account_sender_id_created_at_id_idx
+  ON transactions (account_sender_id, created_at DESC, id DESC);
+
+CREATE INDEX IF NOT EXISTS transactions_account_receiver_id_created_at_id_idx
+  ON transactions (account_receiver_id, created_at DESC, id DESC);
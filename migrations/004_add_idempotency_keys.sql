@@ -0,0 +1,28 @@
+-- Retried transfers could be recorded twice before this index existed. The first transaction of
+-- each key keeps it; the others get their id appended, so the index can be built while the
+-- duplicates stay in the history and can still be told apart by their original key.
+UPDATE transactions SET idempotency_key = duplicates.idempotency_key || ':duplicate:' || duplicates.id
+FROM (
+  SELECT id, idempotency_key, ROW_NUMBER() OVER (
+    PARTITION BY account_sender_id, idempotency_key ORDER BY created_at, id
+  ) AS position
+  FROM transactions
+) AS duplicates
+WHERE transactions.id = duplicates.id AND duplicates.position > 1;
+
+CREATE UNIQUE INDEX IF NOT EXISTS transactions_account_sender_id_idempotency_key_idx
+  ON transactions (account_sender_id, idempotency_key);
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+  customer_id UUID NOT NULL,
+  idempotency_key TEXT NOT NULL,
+  request_fingerprint TEXT NOT NULL,
+  transaction_id UUID,
+  response_status INTEGER,
+  response_body TEXT,
+  created_at TIMESTAMPTZ NOT NULL,
+  updated_at TIMESTAMPTZ NOT NULL,
+  PRIMARY KEY (customer_id, idempotency_key),
+  FOREIGN KEY (customer_id) REFERENCES customers(id),
+  FOREIGN KEY (transaction_id) REFERENCES transactions(id)
+);
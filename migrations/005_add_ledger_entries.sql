@@ -0,0 +1,68 @@
+CREATE TABLE IF NOT EXISTS ledger_entries (
+  id UUID PRIMARY KEY,
+  journal_id UUID NOT NULL,
+  account_id UUID,
+  system_account VARCHAR(50),
+  direction VARCHAR(6) NOT NULL,
+  amount INTEGER NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL,
+  FOREIGN KEY (account_id) REFERENCES accounts(id),
+  CHECK (direction IN ('debit', 'credit')),
+  CHECK (amount > 0),
+  CHECK ((account_id IS NULL) <> (system_account IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS ledger_entries_account_id_created_at_idx
+  ON ledger_entries (account_id, created_at);
+
+CREATE INDEX IF NOT EXISTS ledger_entries_journal_id_idx
+  ON ledger_entries (journal_id);
+
+-- Every journal must balance: the sum of its debits has to equal the sum of its credits.
+-- The check is deferred to commit time so that all entries of a journal can be inserted first.
+CREATE OR REPLACE FUNCTION ledger_entries_check_journal_balanced() RETURNS TRIGGER AS $$
+BEGIN
+  IF (
+    SELECT COALESCE(SUM(CASE WHEN direction = 'debit' THEN amount ELSE -amount END), 0)
+    FROM ledger_entries
+    WHERE journal_id = NEW.journal_id
+  ) <> 0 THEN
+    RAISE EXCEPTION 'ledger journal % is not balanced', NEW.journal_id;
+  END IF;
+
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER ledger_entries_journal_balanced
+  AFTER INSERT ON ledger_entries
+  DEFERRABLE INITIALLY DEFERRED
+  FOR EACH ROW EXECUTE FUNCTION ledger_entries_check_journal_balanced();
+
+-- Backfill: every existing transaction becomes a journal, and whatever part of the current
+-- balance is not explained by transactions is posted as an opening balance. An account whose
+-- balance is below what its transactions explain gets a negative opening, posted the other way
+-- around, so every ledger matches accounts.balance right after migrating.
+INSERT INTO ledger_entries (id, journal_id, account_id, system_account, direction, amount, created_at)
+SELECT gen_random_uuid(), t.id, t.account_sender_id, NULL, 'debit', t.amount, t.created_at FROM transactions t
+UNION ALL
+SELECT gen_random_uuid(), t.id, t.account_receiver_id, NULL, 'credit', t.amount, t.created_at FROM transactions t;
+
+WITH openings AS (
+  SELECT
+    a.id AS account_id,
+    gen_random_uuid() AS journal_id,
+    a.balance
+      - COALESCE((SELECT SUM(amount) FROM transactions WHERE account_receiver_id = a.id), 0)
+      + COALESCE((SELECT SUM(amount) FROM transactions WHERE account_sender_id = a.id), 0) AS amount,
+    a.created_at
+  FROM accounts a
+)
+INSERT INTO ledger_entries (id, journal_id, account_id, system_account, direction, amount, created_at)
+SELECT gen_random_uuid(), journal_id, NULL, 'opening_balance', 'debit', amount, created_at FROM openings WHERE amount > 0
+UNION ALL
+SELECT gen_random_uuid(), journal_id, account_id, NULL, 'credit', amount, created_at FROM openings WHERE amount > 0
+UNION ALL
+SELECT gen_random_uuid(), journal_id, NULL, 'opening_balance', 'credit', -amount, created_at FROM openings WHERE amount < 0
+UNION ALL
+SELECT gen_random_uuid(), journal_id, account_id, NULL, 'debit', -amount, created_at FROM openings WHERE amount < 0;
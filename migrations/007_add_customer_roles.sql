@@ -0,0 +1,8 @@
+-- Every customer implicitly has the customer role, only the staff roles are stored.
+CREATE TABLE IF NOT EXISTS customer_roles (
+  customer_id UUID NOT NULL,
+  role VARCHAR(20) NOT NULL CHECK (role IN ('support', 'admin', 'auditor')),
+  created_at TIMESTAMPTZ NOT NULL,
+  PRIMARY KEY (customer_id, role),
+  FOREIGN KEY (customer_id) REFERENCES customers(id) ON DELETE CASCADE
+);
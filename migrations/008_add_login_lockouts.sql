@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS login_lockouts (
+  id UUID PRIMARY KEY,
+  subject_type VARCHAR(10) NOT NULL CHECK (subject_type IN ('email', 'ip')),
+  subject TEXT NOT NULL,
+  failed_attempts INTEGER NOT NULL,
+  locked_until TIMESTAMPTZ NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS login_lockouts_subject_idx ON login_lockouts (subject_type, subject, created_at DESC);
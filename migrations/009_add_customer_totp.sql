@@ -0,0 +1,22 @@
+-- A row without confirmed_at is an enrollment that has not been confirmed with a first code
+-- yet, it does not protect anything until then. last_used_step keeps codes single use.
+CREATE TABLE IF NOT EXISTS customer_totp (
+  customer_id UUID PRIMARY KEY,
+  secret VARCHAR(64) NOT NULL,
+  last_used_step BIGINT NOT NULL DEFAULT 0,
+  confirmed_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL,
+  updated_at TIMESTAMPTZ NOT NULL,
+  FOREIGN KEY (customer_id) REFERENCES customers(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS totp_recovery_codes (
+  id UUID PRIMARY KEY,
+  customer_id UUID NOT NULL,
+  code_hash TEXT NOT NULL,
+  used_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL,
+  FOREIGN KEY (customer_id) REFERENCES customers(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS totp_recovery_codes_customer_id_code_hash_idx ON totp_recovery_codes (customer_id, code_hash);
@@ -0,0 +1,19 @@
+-- Customers signed up before email verification existed are considered verified.
+ALTER TABLE customers
+  ADD COLUMN IF NOT EXISTS status VARCHAR(30) NOT NULL DEFAULT 'active'
+  CHECK (status IN ('pending_verification', 'active'));
+
+ALTER TABLE customers ALTER COLUMN status DROP DEFAULT;
+
+CREATE TABLE IF NOT EXISTS email_verification_tokens (
+  id UUID PRIMARY KEY,
+  customer_id UUID NOT NULL,
+  token_hash TEXT NOT NULL,
+  expires_at TIMESTAMPTZ NOT NULL,
+  used_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL,
+  FOREIGN KEY (customer_id) REFERENCES customers(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS email_verification_tokens_token_hash_idx ON email_verification_tokens (token_hash);
+CREATE INDEX IF NOT EXISTS email_verification_tokens_customer_id_idx ON email_verification_tokens (customer_id);
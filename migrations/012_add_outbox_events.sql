@@ -0,0 +1,12 @@
+-- Domain events are written here in the same transaction as the change they describe, then
+-- published by the outbox relay worker, which sets published_at.
+CREATE TABLE IF NOT EXISTS outbox_events (
+  id UUID PRIMARY KEY,
+  event_type VARCHAR(100) NOT NULL,
+  aggregate_id UUID NOT NULL,
+  payload JSONB NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL,
+  published_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS outbox_events_unpublished_idx ON outbox_events (created_at, id) WHERE published_at IS NULL;
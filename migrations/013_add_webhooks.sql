@@ -0,0 +1,34 @@
+-- The secret is kept in plain text because every delivery is signed with it.
+CREATE TABLE IF NOT EXISTS webhooks (
+  id UUID PRIMARY KEY,
+  customer_id UUID NOT NULL,
+  url TEXT NOT NULL,
+  secret TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL,
+  FOREIGN KEY (customer_id) REFERENCES customers(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS webhooks_customer_id_idx ON webhooks (customer_id);
+
+-- One row per event and webhook. Pending deliveries are attempted once next_attempt_at is due,
+-- until they are delivered or, after the last attempt, dead lettered.
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+  id UUID PRIMARY KEY,
+  webhook_id UUID NOT NULL,
+  event_id UUID NOT NULL,
+  event_type VARCHAR(100) NOT NULL,
+  payload JSONB NOT NULL,
+  status VARCHAR(30) NOT NULL CHECK (status IN ('pending', 'delivered', 'dead_lettered')),
+  attempts INT NOT NULL,
+  next_attempt_at TIMESTAMPTZ,
+  last_attempted_at TIMESTAMPTZ,
+  last_response_status INT,
+  last_error TEXT,
+  created_at TIMESTAMPTZ NOT NULL,
+  delivered_at TIMESTAMPTZ,
+  UNIQUE (webhook_id, event_id),
+  FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_due_idx ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS webhook_deliveries_webhook_id_idx ON webhook_deliveries (webhook_id, created_at);
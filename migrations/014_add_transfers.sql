@@ -0,0 +1,32 @@
+-- Every transfer requested, whatever its outcome. A completed transfer shares its id with the
+-- transaction it created.
+CREATE TABLE IF NOT EXISTS transfers (
+  id UUID PRIMARY KEY,
+  sender_customer_id UUID NOT NULL,
+  receiver_customer_id UUID NOT NULL,
+  idempotency_key TEXT NOT NULL,
+  amount INTEGER NOT NULL,
+  status VARCHAR(30) NOT NULL CHECK (status IN ('pending', 'completed', 'failed')),
+  failure_code VARCHAR(100),
+  failure_reason TEXT,
+  created_at TIMESTAMPTZ NOT NULL,
+  updated_at TIMESTAMPTZ NOT NULL,
+  FOREIGN KEY (sender_customer_id) REFERENCES customers(id),
+  FOREIGN KEY (receiver_customer_id) REFERENCES customers(id)
+);
+
+CREATE INDEX IF NOT EXISTS transfers_sender_customer_id_idx ON transfers (sender_customer_id, created_at);
+
+INSERT INTO transfers (id, sender_customer_id, receiver_customer_id, idempotency_key, amount, status, created_at, updated_at)
+SELECT transactions.id, sender.customer_id, receiver.customer_id, transactions.idempotency_key, transactions.amount, 'completed',
+  transactions.created_at, transactions.updated_at
+FROM transactions
+JOIN accounts sender ON sender.id = transactions.account_sender_id
+JOIN accounts receiver ON receiver.id = transactions.account_receiver_id
+ON CONFLICT (id) DO NOTHING;
+
+-- Idempotency keys now point to the transfer, which exists before its transaction when the
+-- transfer is processed asynchronously.
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS transfer_id UUID REFERENCES transfers(id);
+UPDATE idempotency_keys SET transfer_id = transaction_id WHERE transaction_id IS NOT NULL;
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS transaction_id;
@@ -0,0 +1,6 @@
+-- Idempotency keys keep what happened to the transfer instead of the HTTP response, which the
+-- handler rebuilds on retries.
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS outcome VARCHAR(20) CHECK (outcome IN ('completed', 'accepted'));
+UPDATE idempotency_keys SET outcome = CASE WHEN response_status = 202 THEN 'accepted' ELSE 'completed' END WHERE transfer_id IS NOT NULL;
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS response_status;
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS response_body;
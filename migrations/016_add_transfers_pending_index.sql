@@ -0,0 +1,3 @@
+-- Lets the transfer workers find the transfers left pending, say because their message was
+-- lost, without scanning every transfer ever made.
+CREATE INDEX IF NOT EXISTS transfers_pending_updated_at_idx ON transfers (updated_at) WHERE status = 'pending';
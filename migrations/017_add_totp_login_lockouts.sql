@@ -0,0 +1,3 @@
+-- Wrong two-factor codes lock the customer out too, with the customer id as subject.
+ALTER TABLE login_lockouts DROP CONSTRAINT IF EXISTS login_lockouts_subject_type_check;
+ALTER TABLE login_lockouts ADD CONSTRAINT login_lockouts_subject_type_check CHECK (subject_type IN ('email', 'ip', 'totp'));
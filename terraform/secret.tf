@@ -11,7 +11,19 @@ resource "aws_secretsmanager_secret_version" "api" {
   secret_id = aws_secretsmanager_secret.api.id
 
   secret_string = jsonencode({
-    POSTGRES_URL             = "postgres://${aws_db_instance.rds.username}:${var.db_password}@${aws_db_instance.rds.endpoint}:${aws_db_instance.rds.port}/${aws_db_instance.rds.db_name}"
-    ACCESS_TOKEN_SIGNING_KEY = var.access_token_signing_key
+    POSTGRES_URL                     = "postgres://${aws_db_instance.rds.username}:${var.db_password}@${aws_db_instance.rds.endpoint}:${aws_db_instance.rds.port}/${aws_db_instance.rds.db_name}"
+    REDIS_URL                        = var.redis_url
+    ACCESS_TOKEN_SIGNING_KEY_ID      = var.access_token_signing_key_id
+    ACCESS_TOKEN_SIGNING_PRIVATE_KEY = var.access_token_signing_private_key
+    ACCESS_TOKEN_VERIFICATION_KEYS   = var.access_token_verification_keys
+    RECEIPT_SIGNING_KEY              = var.receipt_signing_key
+    TRANSFER_TOTP_THRESHOLD          = var.transfer_totp_threshold
+    PASSWORD_MIN_LENGTH              = var.password_min_length
+    NOTIFIER_PROVIDER                = "smtp"
+    SMTP_HOST                        = var.smtp_host
+    SMTP_PORT                        = var.smtp_port
+    SMTP_FROM                        = var.smtp_from
+    SMTP_USERNAME                    = var.smtp_username
+    SMTP_PASSWORD                    = var.smtp_password
   })
 }
@@ -12,7 +12,61 @@ variable "db_password" {
   sensitive = true
 }
 
-variable "access_token_signing_key" {
+variable "redis_url" {
+  type      = string
+  sensitive = true
+}
+
+variable "access_token_signing_key_id" {
+  type = string
+}
+
+variable "access_token_signing_private_key" {
+  type      = string
+  sensitive = true
+}
+
+variable "access_token_verification_keys" {
+  type    = string
+  default = ""
+}
+
+# Key of the HMAC that authenticates transaction receipts.
+variable "receipt_signing_key" {
+  type      = string
+  sensitive = true
+}
+
+# Transfers above this amount, in cents, require a TOTP code.
+variable "transfer_totp_threshold" {
+  type    = number
+  default = 100000
+}
+
+# Minimum number of characters of new passwords.
+variable "password_min_length" {
+  type    = number
+  default = 8
+}
+
+variable "smtp_host" {
+  type = string
+}
+
+variable "smtp_port" {
+  type    = number
+  default = 587
+}
+
+variable "smtp_from" {
+  type = string
+}
+
+variable "smtp_username" {
+  type = string
+}
+
+variable "smtp_password" {
   type      = string
   sensitive = true
 }